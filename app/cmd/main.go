@@ -7,7 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"os"
@@ -36,8 +36,15 @@ func main() {
 	dbTimeout, dbCancel := context.WithTimeout(context.Background(), time.Duration(cfg.PostgreSQL.ConnectionTimeout)*time.Second)
 	defer dbCancel()
 
-	var dbConn *pgx.Conn
-	dbConn, err := pgx.Connect(dbTimeout, dsn)
+	/// Пул соединений: запросы HTTP и фоновые обработчики выполняются параллельно, одно соединение pgx.Conn для этого не подходит \\\
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		logger.Fatalf("cannot parse database config: %v", err)
+	}
+	poolConfig.MaxConns = cfg.PostgreSQL.MaxConnections
+
+	var dbConn *pgxpool.Pool
+	dbConn, err = pgxpool.ConnectConfig(dbTimeout, poolConfig)
 	if err != nil {
 		logger.Fatalf("cannot connect to database: %v", err)
 	}
@@ -68,15 +75,7 @@ func main() {
 	/// Закрытие БД и освобождение связанных ресурсов \\\
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer func() {
-		dbCloseCtx, dbCloseCancel := context.WithTimeout(
-			context.Background(),
-			time.Duration(cfg.PostgreSQL.ShutdownTimeout)*time.Second,
-		)
-		defer dbCloseCancel()
-		err := dbConn.Close(dbCloseCtx)
-		if err != nil {
-			logger.Errorf("failed to close database connection: %v", err)
-		}
+		dbConn.Close()
 		logger.Info("closed database connection")
 		cancel()
 	}()
@@ -25,6 +25,7 @@ type Config struct {
 		RequestTimeout    int    `yaml:"request_timeout" env-default:"5"`
 		ConnectionTimeout int    `yaml:"connection_timeout" env-default:"10"`
 		ShutdownTimeout   int    `yaml:"shutdown_timeout" env-default:"5"`
+		MaxConnections    int32  `yaml:"max_connections" env-default:"10"`
 	} `yaml:"postgresql"`
 	JWT struct {
 		AccessExpirationMinutes int16  `yaml:"access_expiration_minutes"`
@@ -32,6 +33,112 @@ type Config struct {
 		AccessTokenSecretKey    string `yaml:"access_token_secret_key"`
 		RefreshTokenSecretKey   string `yaml:"refresh_token_secret_key"`
 	} `yaml:"jwt"`
+	Admin struct {
+		APIKey   string `yaml:"api_key" env:"ADMIN_API_KEY"`
+		StaffKey string `yaml:"staff_key" env:"STAFF_API_KEY"`
+	} `yaml:"admin"`
+	Attachments struct {
+		Root         string   `yaml:"root" env-default:"./attachments"`
+		MaxSizeMB    int64    `yaml:"max_size_mb" env-default:"10"`
+		AllowedTypes []string `yaml:"allowed_types" env-default:"image/png,image/jpeg,application/pdf"`
+	} `yaml:"attachments"`
+	Photos struct {
+		MinSide   int `yaml:"min_side" env-default:"64"`
+		MaxWidth  int `yaml:"max_width" env-default:"4096"`
+		MaxHeight int `yaml:"max_height" env-default:"4096"`
+	} `yaml:"photos"`
+	Records struct {
+		SlotMinutes          int `yaml:"slot_minutes" env-default:"30"`
+		RescheduleMinHours   int `yaml:"reschedule_min_hours" env-default:"24"`
+		MaxReschedules       int `yaml:"max_reschedules" env-default:"3"`
+		MaxSeriesOccurrences int `yaml:"max_series_occurrences" env-default:"52"`
+	} `yaml:"records"`
+	Waitlist struct {
+		HoldMinutes  int `yaml:"hold_minutes" env-default:"30"`
+		SweepSeconds int `yaml:"sweep_seconds" env-default:"60"`
+	} `yaml:"waitlist"`
+	Reminders struct {
+		OffsetsMinutes []int  `yaml:"offsets_minutes" env-default:"1440,120"`
+		SweepSeconds   int    `yaml:"sweep_seconds" env-default:"60"`
+		MaxAttempts    int    `yaml:"max_attempts" env-default:"3"`
+		RetryMinutes   int    `yaml:"retry_minutes" env-default:"5"`
+		BatchSize      int    `yaml:"batch_size" env-default:"100"`
+		TimeZone       string `yaml:"time_zone" env-default:"Europe/Moscow"`
+	} `yaml:"reminders"`
+	SMTP struct {
+		Host     string `yaml:"host" env:"SMTP_HOST"`
+		Port     int    `yaml:"port" env:"SMTP_PORT" env-default:"587"`
+		Username string `yaml:"username" env:"SMTP_USERNAME"`
+		Password string `yaml:"password" env:"SMTP_PASSWORD"`
+		From     string `yaml:"from" env-default:"no-reply@hospital-record.local"`
+	} `yaml:"smtp"`
+	SMS struct {
+		URL            string `yaml:"url" env:"SMS_URL"`
+		APIKey         string `yaml:"api_key" env:"SMS_API_KEY"`
+		Sender         string `yaml:"sender" env-default:"Hospital"`
+		TimeoutSeconds int    `yaml:"timeout_seconds" env-default:"10"`
+	} `yaml:"sms"`
+	Outbox struct {
+		PollSeconds     int      `yaml:"poll_seconds" env-default:"2"`
+		BatchSize       int      `yaml:"batch_size" env-default:"100"`
+		RetrySeconds    int      `yaml:"retry_seconds" env-default:"5"`
+		MaxRetryMinutes int      `yaml:"max_retry_minutes" env-default:"60"`
+		RetentionDays   int      `yaml:"retention_days" env-default:"7"`
+		SinkURLs        []string `yaml:"sink_urls"`
+		SinkTimeout     int      `yaml:"sink_timeout" env-default:"10"`
+	} `yaml:"outbox"`
+	Webhooks struct {
+		PollSeconds     int `yaml:"poll_seconds" env-default:"5"`
+		BatchSize       int `yaml:"batch_size" env-default:"50"`
+		MaxAttempts     int `yaml:"max_attempts" env-default:"8"`
+		RetrySeconds    int `yaml:"retry_seconds" env-default:"30"`
+		MaxRetryMinutes int `yaml:"max_retry_minutes" env-default:"360"`
+		TimeoutSeconds  int `yaml:"timeout_seconds" env-default:"10"`
+	} `yaml:"webhooks"`
+	Realtime struct {
+		HeartbeatSeconds int    `yaml:"heartbeat_seconds" env-default:"15"`
+		ReplayLimit      int    `yaml:"replay_limit" env-default:"1000"`
+		ClientBuffer     int    `yaml:"client_buffer" env-default:"64"`
+		TimeZone         string `yaml:"time_zone" env-default:"Europe/Moscow"`
+	} `yaml:"realtime"`
+	Reception struct {
+		TimeZone     string `yaml:"time_zone" env-default:"Europe/Moscow"`
+		ElderlyAge   uint8  `yaml:"elderly_age" env-default:"65"`
+		DisplayLimit int    `yaml:"display_limit" env-default:"10"`
+	} `yaml:"reception"`
+	Facilities struct {
+		TimeZone string `yaml:"time_zone" env-default:"Europe/Moscow"`
+	} `yaml:"facilities"`
+	Referrals struct {
+		RequiredSpecializations []int64 `yaml:"required_specializations"`
+		ValidityDays            int     `yaml:"validity_days" env-default:"30"`
+		TimeZone                string  `yaml:"time_zone" env-default:"Europe/Moscow"`
+	} `yaml:"referrals"`
+	SickLeaves struct {
+		NumberPrefix  string `yaml:"number_prefix" env-default:"LN"`
+		MaxPeriodDays int    `yaml:"max_period_days" env-default:"15"`
+		TimeZone      string `yaml:"time_zone" env-default:"Europe/Moscow"`
+	} `yaml:"sick_leaves"`
+	Documents struct {
+		ClinicName string `yaml:"clinic_name" env-default:"HospitalRecord"`
+		TimeZone   string `yaml:"time_zone" env-default:"Europe/Moscow"`
+	} `yaml:"documents"`
+	Insurance struct {
+		RequireRegistered bool   `yaml:"require_registered" env-default:"false"`
+		TimeZone          string `yaml:"time_zone" env-default:"Europe/Moscow"`
+	} `yaml:"insurance"`
+	FHIR struct {
+		BaseURL  string `yaml:"base_url" env-default:"http://localhost:3000/fhir"`
+		TimeZone string `yaml:"time_zone" env-default:"Europe/Moscow"`
+		PageSize int    `yaml:"page_size" env-default:"50"`
+		MaxPage  int    `yaml:"max_page" env-default:"200"`
+	} `yaml:"fhir"`
+	Calendar struct {
+		BaseURL string `yaml:"base_url" env-default:"http://localhost:3000"`
+	} `yaml:"calendar"`
+	Reviews struct {
+		Moderation bool `yaml:"moderation" env-default:"false"`
+	} `yaml:"reviews"`
 }
 
 /// Функция для получения конфигурации приложения из файла config.yml \\\
@@ -18,6 +18,67 @@ var (
 	ErrRepeatedPortfolioId  = errors.New("this portfolio id is already in use")
 	ErrInvalidRequestBody   = errors.New("invalid request body")
 	ErrInvalidCredentials   = errors.New("invalid request body")
+	ErrInvalidDoseNumber    = errors.New("dose number must be greater than zero")
+	ErrInvalidScheduleAge   = errors.New("invalid age range of the immunization schedule item")
+	ErrRepeatedDose         = errors.New("this dose of the vaccine is already recorded for the patient")
+	ErrUnauthorized         = errors.New("missing or invalid access token")
+	ErrAttachmentTooLarge   = errors.New("attachment exceeds the maximum allowed size")
+	ErrAttachmentType       = errors.New("attachment content type is not allowed")
+	ErrInvalidOwnerType     = errors.New("unknown attachment owner type")
+	ErrPhotoFormat          = errors.New("photo must be a PNG or JPEG image")
+	ErrPhotoDimensions      = errors.New("photo dimensions are out of the allowed range")
+	ErrInvalidPhotoSize     = errors.New("unknown photo size")
+	ErrInvalidReviewRating  = errors.New("review rating must be from 1 to 5")
+	ErrRepeatedReview       = errors.New("this visit has already been reviewed")
+	ErrVisitNotCompleted    = errors.New("only a completed visit of the patient can be reviewed")
+	ErrInvalidReviewStatus  = errors.New("review status must be approved or rejected")
+	ErrNoSpecialization     = errors.New("doctor must have a primary specialization")
+	ErrWrongSpecialization  = errors.New("doctor does not have this specialization")
+	ErrInvalidPortfolio     = errors.New("invalid portfolio entry")
+	ErrSlotTaken            = errors.New("this time slot is already booked")
+	ErrInvalidWaitlist      = errors.New("waitlist entry requires a doctor or specialization and a valid date range")
+	ErrOfferNotPending      = errors.New("the offer is no longer pending")
+	ErrOfferExpired         = errors.New("the offer has expired")
+	ErrRescheduleRequired   = errors.New("time and doctor of a record can only be changed by rescheduling")
+	ErrRescheduleTooLate    = errors.New("it is too late to reschedule this visit")
+	ErrRescheduleLimit      = errors.New("this booking has been rescheduled too many times")
+	ErrInvalidReschedule    = errors.New("visit must be rescheduled to a new time in the future")
+	ErrInvalidRecurrence    = errors.New("invalid recurrence rule of the appointment series")
+	ErrInvalidSeriesScope   = errors.New("series scope must be one or remaining")
+	ErrAdminUnauthorized    = errors.New("missing or invalid admin key")
+	ErrStaffUnauthorized    = errors.New("missing or invalid staff key")
+	ErrInvalidStreamFilter  = errors.New("invalid stream filter")
+	ErrInvalidWebhook       = errors.New("webhook requires an absolute http or https url and known event types")
+	ErrInvalidTicket        = errors.New("ticket requires a hospital address, a known category and a record for today")
+	ErrTicketState          = errors.New("ticket status does not allow this action")
+	ErrTicketIssued         = errors.New("an active ticket has already been issued for this record")
+	ErrInvalidFacility      = errors.New("invalid hospital, department, office or schedule data")
+	ErrScheduleConflict     = errors.New("office or doctor is already scheduled at this time")
+	ErrUnknownOffice        = errors.New("office does not exist")
+	ErrNoOfficeHours        = errors.New("the doctor has no office hours at this time")
+	ErrInvalidInpatient     = errors.New("invalid ward, bed or admission data")
+	ErrBedOccupied          = errors.New("bed is already occupied")
+	ErrPatientAdmitted      = errors.New("patient is already admitted")
+	ErrAdmissionState       = errors.New("admission status does not allow this action")
+	ErrInvalidReferral      = errors.New("referral requires a patient, a doctor, a specialization, a reason, a known urgency and a valid period")
+	ErrReferralRequired     = errors.New("a referral is required to book this specialization")
+	ErrReferralNotValid     = errors.New("referral is used, cancelled, expired or issued for another patient or specialization")
+	ErrReferralState        = errors.New("referral status does not allow this action")
+	ErrInvalidSickLeave     = errors.New("sick leave requires a past visit, a diagnosis and a period within the allowed length")
+	ErrSickLeaveState       = errors.New("sick leave status does not allow this action")
+	ErrInvalidPolicyNumber  = errors.New("policy number must be 16 digits with a valid check digit")
+	ErrPolicyNotValid       = errors.New("insurance policy is expired, not yet valid or missing from the insurer registry")
+	ErrInvalidInsurance     = errors.New("insurer requires a name and a region, a policy requires a known type and a valid period")
+	ErrInvalidBilling       = errors.New("invalid price, invoice item, discount or payment data")
+	ErrPriceExists          = errors.New("a price with this code or an active price for this specialization already exists")
+	ErrRepeatedInvoice      = errors.New("an invoice has already been issued for this record")
+	ErrInvoiceState         = errors.New("invoice is already paid and cannot be changed")
+	ErrOverpayment          = errors.New("payments would exceed the invoice total")
+	ErrNotBillable          = errors.New("visit has no price in the price list and no items to bill")
+	ErrInvalidClaim         = errors.New("claim requires an insurer, a valid period and a rejection reason when rejected")
+	ErrClaimState           = errors.New("claim status does not allow this action")
+	ErrEmptyClaim           = errors.New("no completed unclaimed records of the insurer's patients in this period")
+	ErrInvalidFHIRSearch    = errors.New("unsupported FHIR search parameter or value")
 )
 
 type AppError struct {
@@ -0,0 +1,33 @@
+package attachment
+
+import "time"
+
+/// Типы владельцев вложений \\\
+
+const (
+	OwnerDoctor    = "doctor"
+	OwnerPatient   = "patient"
+	OwnerRecord    = "record"
+	OwnerLabResult = "lab_result"
+)
+
+/// Структура с метаданными вложения, содержимое хранится в BlobStore по ключу BlobKey \\\
+
+type Attachment struct {
+	ID          int64     `json:"id" example:"1567"`
+	BlobKey     string    `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	FileName    string    `json:"file_name" example:"blood_test.pdf"`
+	ContentType string    `json:"content_type" example:"application/pdf"`
+	Size        int64     `json:"size" example:"102400"`
+	OwnerType   string    `json:"owner_type" example:"record"`
+	OwnerID     int64     `json:"owner_id" example:"1"`
+	UploadedBy  *int64    `json:"uploaded_by,omitempty" example:"1"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type UploadAttachmentDTO struct {
+	FileName   string `json:"file_name" example:"blood_test.pdf"`
+	OwnerType  string `json:"owner_type" example:"record"`
+	OwnerID    int64  `json:"owner_id" example:"1"`
+	UploadedBy *int64 `json:"uploaded_by,omitempty" example:"1"`
+}
@@ -0,0 +1,344 @@
+package attachment
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+)
+
+const (
+	attachmentsURL               = "/hospital_record/attachments"
+	attachmentURL                = "/hospital_record/attachments/:id"
+	attachmentDownloadURL        = "/hospital_record/attachments/:id/download"
+	patientAttachmentsURL        = "/hospital_record/patient_attachments"
+	patientAttachmentURL         = "/hospital_record/patient_attachments/:id"
+	patientAttachmentDownloadURL = "/hospital_record/patient_attachments/:id/download"
+)
+
+/// Запас на служебные части multipart запроса сверх максимального размера файла \\\
+
+const multipartOverhead = 1 << 20
+
+/// Структура Handler представляющая собой обработчик объекта attachmentService для вложений \\\
+
+type Handler struct {
+	logger            logger.Logger
+	attachmentService Service
+	authorize         handler.Middleware
+	staff             handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, attachmentService Service, authorize, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:            logger,
+		attachmentService: attachmentService,
+		authorize:         authorize,
+		staff:             staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для вложений \\\
+/// Сотрудники работают с вложениями любых владельцев, пациент по токену доступа - только со своими и своих записей \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, attachmentsURL, h.staff(h.UploadAttachment))
+	router.HandlerFunc(http.MethodGet, attachmentsURL, h.staff(h.GetAttachmentsByOwner))
+	router.HandlerFunc(http.MethodGet, attachmentURL, h.staff(h.GetAttachmentById))
+	router.HandlerFunc(http.MethodGet, attachmentDownloadURL, h.staff(h.DownloadAttachment))
+	router.HandlerFunc(http.MethodDelete, attachmentURL, h.staff(h.DeleteAttachment))
+	router.HandlerFunc(http.MethodPost, patientAttachmentsURL, h.authorize(h.UploadPatientAttachment))
+	router.HandlerFunc(http.MethodGet, patientAttachmentsURL, h.authorize(h.GetPatientAttachmentsByOwner))
+	router.HandlerFunc(http.MethodGet, patientAttachmentURL, h.authorize(h.ownAttachment(h.GetAttachmentById)))
+	router.HandlerFunc(http.MethodGet, patientAttachmentDownloadURL, h.authorize(h.ownAttachment(h.DownloadAttachment)))
+	router.HandlerFunc(http.MethodDelete, patientAttachmentURL, h.authorize(h.ownAttachment(h.DeleteAttachment)))
+}
+
+/// Функция UploadAttachment загружает вложение из form-data с полями file, owner_type и owner_id \\\
+
+func (h *Handler) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: UPLOAD ATTACHMENT")
+
+	if !h.parseForm(w, r) {
+		return
+	}
+	ownerId, err := strconv.ParseInt(r.FormValue("owner_id"), 10, 64)
+	if err != nil || ownerId < 1 {
+		response.BadRequest(w, "owner_id must have type int64", "")
+		return
+	}
+	h.upload(w, r, "file", r.FormValue("owner_type"), ownerId)
+}
+
+/// Функция UploadPatientAttachment загружает вложение авторизованного пациента: владелец - сам пациент или его запись \\\
+
+func (h *Handler) UploadPatientAttachment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: UPLOAD PATIENT ATTACHMENT")
+
+	if !h.parseForm(w, r) {
+		return
+	}
+	ownerId, err := strconv.ParseInt(r.FormValue("owner_id"), 10, 64)
+	if err != nil || ownerId < 1 {
+		response.BadRequest(w, "owner_id must have type int64", "")
+		return
+	}
+	if !h.ownOwner(w, r, r.FormValue("owner_type"), ownerId) {
+		return
+	}
+	h.upload(w, r, "file", r.FormValue("owner_type"), ownerId)
+}
+
+/// Функция parseForm разбирает form-data запроса, ограничивая размер его тела \\\
+
+func (h *Handler) parseForm(w http.ResponseWriter, r *http.Request) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, h.attachmentService.MaxSize()+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			response.Error(w, http.StatusRequestEntityTooLarge, apperror.ErrAttachmentTooLarge.Error(), "")
+			return false
+		}
+		response.BadRequest(w, err.Error(), "")
+		return false
+	}
+	return true
+}
+
+/// Функция upload читает файл из поля field запроса и сохраняет его как вложение владельца \\\
+
+func (h *Handler) upload(w http.ResponseWriter, r *http.Request, field, ownerType string, ownerId int64) {
+	/// Принимает объект r типа form-data \\\
+	file, header, err := r.FormFile(field)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	defer file.Close()
+
+	input := UploadAttachmentDTO{
+		FileName:  header.Filename,
+		OwnerType: ownerType,
+		OwnerID:   ownerId,
+	}
+	if user, ok := auth.FromContext(r.Context()); ok {
+		input.UploadedBy = &user.ID
+	}
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Upload передавая ей полученные значения и содержимое файла \\\
+	attachment, err := h.attachmentService.Upload(r.Context(), &input, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrAttachmentTooLarge):
+			response.Error(w, http.StatusRequestEntityTooLarge, err.Error(), "")
+		case errors.Is(err, apperror.ErrAttachmentType):
+			response.Error(w, http.StatusUnsupportedMediaType, err.Error(), "")
+		case errors.Is(err, apperror.ErrInvalidOwnerType):
+			response.BadRequest(w, err.Error(), "")
+		default:
+			response.InternalError(w, fmt.Sprintf("cannot upload attachment: %v", err), "")
+		}
+		return
+	}
+	h.logger.Info("ATTACHMENT UPLOADED")
+	response.JSON(w, http.StatusCreated, attachment)
+}
+
+/// Функция GetAttachmentsByOwner получает все вложения владельца по параметрам owner_type и owner_id \\\
+
+func (h *Handler) GetAttachmentsByOwner(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET ATTACHMENTS BY OWNER")
+
+	/// Извлечение параметров owner_type и owner_id из URL \\\
+	ownerType := r.URL.Query().Get("owner_type")
+	ownerId, err := strconv.ParseInt(r.URL.Query().Get("owner_id"), 10, 64)
+	if err != nil || ownerId < 1 {
+		response.BadRequest(w, "owner_id must have type int64", "")
+		return
+	}
+
+	/// Вызов функции GetByOwner передавая ей тип и id владельца \\\
+	attachments, err := h.attachmentService.GetByOwner(r.Context(), ownerType, ownerId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidOwnerType) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT ATTACHMENTS BY OWNER")
+	response.JSON(w, http.StatusOK, attachments)
+}
+
+/// Функция GetPatientAttachmentsByOwner получает вложения авторизованного пациента или его записи \\\
+
+func (h *Handler) GetPatientAttachmentsByOwner(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PATIENT ATTACHMENTS BY OWNER")
+
+	ownerId, err := strconv.ParseInt(r.URL.Query().Get("owner_id"), 10, 64)
+	if err != nil || ownerId < 1 {
+		response.BadRequest(w, "owner_id must have type int64", "")
+		return
+	}
+	if !h.ownOwner(w, r, r.URL.Query().Get("owner_type"), ownerId) {
+		return
+	}
+	h.GetAttachmentsByOwner(w, r)
+}
+
+/// Функция ownOwner проверяет, что владелец вложений - авторизованный пациент или его запись, и отвечает ошибкой, если нет \\\
+
+func (h *Handler) ownOwner(w http.ResponseWriter, r *http.Request, ownerType string, ownerId int64) bool {
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return false
+	}
+	err := h.attachmentService.CheckPatientOwner(r.Context(), user.ID, ownerType, ownerId)
+	return h.accessAllowed(w, err)
+}
+
+/// Функция ownAttachment пропускает запрос к вложению, только если оно принадлежит авторизованному пациенту или его записи \\\
+
+func (h *Handler) ownAttachment(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := auth.FromContext(r.Context())
+		if !ok {
+			response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+			return
+		}
+		id, err := handler.ReadIdParam64(r)
+		if err != nil {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		err = h.attachmentService.CheckPatientAttachment(r.Context(), user.ID, id)
+		if !h.accessAllowed(w, err) {
+			return
+		}
+		next(w, r)
+	}
+}
+
+/// Функция accessAllowed отвечает ошибкой проверки владельца, чужие вложения для пациента не существуют \\\
+
+func (h *Handler) accessAllowed(w http.ResponseWriter, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidOwnerType):
+		response.BadRequest(w, err.Error(), "")
+	default:
+		h.logger.Error(err)
+		response.InternalError(w, err.Error(), "")
+	}
+	return false
+}
+
+/// Функция GetAttachmentById получает метаданные вложения по его id \\\
+
+func (h *Handler) GetAttachmentById(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET ATTACHMENT BY ID")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetById передавая ей id вложения \\\
+	attachment, err := h.attachmentService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		h.logger.Error(err)
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT ATTACHMENT BY ID")
+	response.JSON(w, http.StatusOK, attachment)
+}
+
+/// Функция DownloadAttachment отдает содержимое вложения по его id \\\
+
+func (h *Handler) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DOWNLOAD ATTACHMENT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции Open передавая ей id вложения \\\
+	attachment, content, err := h.attachmentService.Open(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		h.logger.Error(err)
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	defer content.Close()
+
+	/// Заголовки ответа: тип определен при загрузке, файл отдается как вложение \\\
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Errorf("failed to send attachment: %v", err)
+		return
+	}
+	h.logger.Info("ATTACHMENT DOWNLOADED")
+}
+
+/// Функция DeleteAttachment удаляет вложение по его id \\\
+
+func (h *Handler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE ATTACHMENT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции Delete передавая ей полученное значение id \\\
+	err = h.attachmentService.Delete(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
+	}
+	h.logger.Info("ATTACHMENT DELETED")
+	response.JSON(w, http.StatusOK, "ATTACHMENT DELETED")
+}
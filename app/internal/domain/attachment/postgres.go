@@ -0,0 +1,218 @@
+package attachment
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &AttachmentStorage{}
+
+/// Структура AttachmentStorage содержащая поля для работы с БД \\\
+
+type AttachmentStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр AttachmentStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &AttachmentStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция Create для сущности AttachmentStorage сохраняет метаданные вложения в БД \\\
+
+func (a *AttachmentStorage) Create(attachment *Attachment) (*Attachment, error) {
+	a.logger.Info("POSTGRES: CREATE ATTACHMENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := a.conn.QueryRow(ctx,
+		`INSERT INTO attachment (blob_key, file_name, content_type, size, owner_type, owner_id, uploaded_by)
+			 VALUES($1,$2,$3,$4,$5,$6,$7)
+			 RETURNING id, created_at`,
+		attachment.BlobKey, attachment.FileName, attachment.ContentType, attachment.Size,
+		attachment.OwnerType, attachment.OwnerID, attachment.UploadedBy)
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&attachment.ID, &attachment.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create attachment query: %v", err)
+		a.logger.Error(err)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+/// Функция FindById для сущности AttachmentStorage получает метаданные вложения из БД по id \\\
+
+func (a *AttachmentStorage) FindById(id int64) (*Attachment, error) {
+	a.logger.Info("POSTGRES: GET ATTACHMENT BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := a.conn.QueryRow(ctx,
+		`SELECT * FROM attachment
+			 WHERE id = $1`, id)
+
+	attachment := &Attachment{}
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&attachment.ID, &attachment.BlobKey, &attachment.FileName, &attachment.ContentType,
+		&attachment.Size, &attachment.OwnerType, &attachment.OwnerID, &attachment.UploadedBy,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find attachment by id query: %v", err)
+		a.logger.Error(err)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+/// Функция FindByOwner для сущности AttachmentStorage получает все вложения владельца из БД \\\
+
+func (a *AttachmentStorage) FindByOwner(ownerType string, ownerId int64) ([]Attachment, error) {
+	a.logger.Info("POSTGRES: GET ATTACHMENTS BY OWNER")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := a.conn.Query(ctx,
+		`SELECT * FROM attachment
+			 WHERE owner_type = $1 AND owner_id = $2
+			 ORDER BY created_at DESC`, ownerType, ownerId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		a.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения всех вложений \\\
+	attachments := make([]Attachment, 0)
+
+	for rows.Next() {
+		var attachment Attachment
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&attachment.ID, &attachment.BlobKey, &attachment.FileName, &attachment.ContentType,
+			&attachment.Size, &attachment.OwnerType, &attachment.OwnerID, &attachment.UploadedBy,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find attachments by owner query: %v", err)
+			a.logger.Error(err)
+			return nil, err
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+/// Функция CountByBlobKey для сущности AttachmentStorage считает количество вложений и миниатюр фотографий, ссылающихся на один файл \\\
+
+func (a *AttachmentStorage) CountByBlobKey(key string) (int64, error) {
+	a.logger.Info("POSTGRES: COUNT ATTACHMENTS BY BLOB KEY")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД, миниатюры фотографий докторов хранятся в том же хранилище файлов \\\
+	var count int64
+	err := a.conn.QueryRow(ctx,
+		`SELECT (SELECT count(*) FROM attachment WHERE blob_key = $1)
+			  + (SELECT count(*) FROM doctor_photo_variant WHERE blob_key = $1)`, key).Scan(&count)
+	if err != nil {
+		err = fmt.Errorf("failed to execute count attachments by blob key query: %v", err)
+		a.logger.Error(err)
+		return 0, err
+	}
+	return count, nil
+}
+
+/// Функция FindVariantBlobKeys для сущности AttachmentStorage получает ключи файлов миниатюр фотографии, построенных из вложения \\\
+
+func (a *AttachmentStorage) FindVariantBlobKeys(id int64) ([]string, error) {
+	a.logger.Info("POSTGRES: GET PHOTO VARIANT BLOB KEYS BY ATTACHMENT ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := a.conn.Query(ctx,
+		`SELECT blob_key FROM doctor_photo_variant
+			 WHERE attachment_id = $1`, id)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		a.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err = rows.Scan(&key); err != nil {
+			err = fmt.Errorf("failed to scan photo variant blob key: %v", err)
+			a.logger.Error(err)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+/// Функция Delete для сущности AttachmentStorage удаляет метаданные вложения из БД \\\
+
+func (a *AttachmentStorage) Delete(id int64) error {
+	a.logger.Info("POSTGRES: DELETE ATTACHMENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), a.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := a.conn.Exec(ctx,
+		`DELETE FROM attachment WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
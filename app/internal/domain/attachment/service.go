@@ -0,0 +1,308 @@
+package attachment
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/blobstore"
+	"HospitalRecord/app/pkg/logger"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+)
+
+/// Размер начала файла, по которому определяется его тип \\\
+
+const sniffLen = 512
+
+/// Интерфейс Service реализизирующий service и методы для обработки вложений \\\
+
+type Service interface {
+	Upload(ctx context.Context, input *UploadAttachmentDTO, content io.Reader) (*Attachment, error)
+	GetById(ctx context.Context, id int64) (*Attachment, error)
+	GetByOwner(ctx context.Context, ownerType string, ownerId int64) (*[]Attachment, error)
+	Open(ctx context.Context, id int64) (*Attachment, io.ReadCloser, error)
+	Delete(id int64) error
+	CheckPatientOwner(ctx context.Context, patientId int64, ownerType string, ownerId int64) error
+	CheckPatientAttachment(ctx context.Context, patientId, id int64) error
+	Release(key string)
+	MaxSize() int64
+}
+
+/// Структура  service реализизирующая инфтерфейс Service вложений \\\
+
+type service struct {
+	logger       logger.Logger
+	storage      Storage
+	blobs        blobstore.BlobStore
+	doc          doctor.Storage
+	users        user.Storage
+	records      record.Storage
+	maxSize      int64
+	allowedTypes map[string]bool
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, users user.Storage, records record.Storage, storage Storage, blobs blobstore.BlobStore, cfg *config.Config, logger logger.Logger) Service {
+	allowedTypes := make(map[string]bool)
+	for _, t := range cfg.Attachments.AllowedTypes {
+		allowedTypes[strings.ToLower(strings.TrimSpace(t))] = true
+	}
+	return &service{
+		logger:       logger,
+		storage:      storage,
+		blobs:        blobs,
+		doc:          doc,
+		users:        users,
+		records:      records,
+		maxSize:      cfg.Attachments.MaxSizeMB << 20,
+		allowedTypes: allowedTypes,
+	}
+}
+
+/// Функция MaxSize возвращает максимальный размер вложения в байтах \\\
+
+func (s *service) MaxSize() int64 {
+	return s.maxSize
+}
+
+/// Функция Upload сохраняет вложение через интерфейс Service принимая входные данные input и содержимое файла content \\\
+
+func (s *service) Upload(ctx context.Context, input *UploadAttachmentDTO, content io.Reader) (*Attachment, error) {
+	s.logger.Info("SERVICE: UPLOAD ATTACHMENT")
+
+	/// Проверка что владелец вложения существует \\\
+	if err := s.checkOwner(input.OwnerType, input.OwnerID); err != nil {
+		return nil, err
+	}
+
+	/// Определение типа файла по его содержимому, а не по имени или заголовкам клиента \\\
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil || !s.allowedTypes[contentType] {
+		return nil, apperror.ErrAttachmentType
+	}
+
+	/// Сохранение файла с ограничением размера \\\
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), content), s.maxSize+1)
+	key, size, err := s.blobs.Put(body)
+	if err != nil {
+		return nil, err
+	}
+	if size > s.maxSize {
+		s.Release(key)
+		return nil, apperror.ErrAttachmentTooLarge
+	}
+
+	/// Создание структуры a на основе полученных данных \\\
+	a := Attachment{
+		BlobKey:     key,
+		FileName:    sanitizeFileName(input.FileName),
+		ContentType: contentType,
+		Size:        size,
+		OwnerType:   input.OwnerType,
+		OwnerID:     input.OwnerID,
+		UploadedBy:  input.UploadedBy,
+	}
+
+	/// Вызов функции Create в хранилище вложений \\\
+	attachment, err := s.storage.Create(&a)
+	if err != nil {
+		s.Release(key)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+/// Функция GetById осуществялет поиск метаданных вложения через интерфейс Service принимая входные данные id \\\
+
+func (s *service) GetById(ctx context.Context, id int64) (*Attachment, error) {
+	s.logger.Info("SERVICE: GET ATTACHMENT BY ID")
+
+	/// Вызов функции FindById в хранилище вложений \\\
+	attachment, err := s.storage.FindById(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			return nil, err
+		}
+		s.logger.Warnf("cannot find attachment by id: %v", err)
+		return nil, err
+	}
+	return attachment, nil
+}
+
+/// Функция GetByOwner осуществялет поиск всех вложений владельца через интерфейс Service \\\
+
+func (s *service) GetByOwner(ctx context.Context, ownerType string, ownerId int64) (*[]Attachment, error) {
+	s.logger.Info("SERVICE: GET ATTACHMENTS BY OWNER")
+
+	if !validOwnerType(ownerType) {
+		return nil, apperror.ErrInvalidOwnerType
+	}
+
+	/// Вызов функции FindByOwner в хранилище вложений \\\
+	attachments, err := s.storage.FindByOwner(ownerType, ownerId)
+	if err != nil {
+		s.logger.Warnf("cannot find attachments by owner: %v", err)
+		return nil, err
+	}
+	return &attachments, nil
+}
+
+/// Функция Open возвращает метаданные и содержимое вложения по его id \\\
+
+func (s *service) Open(ctx context.Context, id int64) (*Attachment, io.ReadCloser, error) {
+	s.logger.Info("SERVICE: OPEN ATTACHMENT")
+
+	attachment, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	/// Открытие файла в хранилище файлов \\\
+	content, err := s.blobs.Open(attachment.BlobKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrBlobNotFound) {
+			s.logger.Errorf("attachment %d references missing blob %s", attachment.ID, attachment.BlobKey)
+			return nil, nil, apperror.ErrEmptyString
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+/// Функция Delete удаляет вложение через интерфейс Service принимая входные данные id \\\
+
+func (s *service) Delete(id int64) error {
+	s.logger.Info("SERVICE: DELETE ATTACHMENT")
+
+	/// Вызов функции FindById в хранилище вложений \\\
+	attachment, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("failed to get attachment: %v", err)
+		}
+		return err
+	}
+
+	/// Миниатюры фотографии удаляются каскадом вместе с вложением, поэтому ключи их файлов читаются заранее \\\
+	variantKeys, err := s.storage.FindVariantBlobKeys(id)
+	if err != nil {
+		return err
+	}
+
+	/// Вызов функции Delete в хранилище вложений \\\
+	err = s.storage.Delete(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("failed to delete attachment: %v", err)
+		}
+		return err
+	}
+	s.Release(attachment.BlobKey)
+	for _, key := range variantKeys {
+		s.Release(key)
+	}
+	return nil
+}
+
+/// Функция Release удаляет файл из хранилища, если на него больше не ссылается ни одно вложение или миниатюра фотографии \\\
+
+func (s *service) Release(key string) {
+	count, err := s.storage.CountByBlobKey(key)
+	if err != nil || count > 0 {
+		return
+	}
+	if err := s.blobs.Delete(key); err != nil {
+		s.logger.Warnf("failed to delete blob %s: %v", key, err)
+	}
+}
+
+/// Функция checkOwner проверяет тип владельца вложения и его существование \\\
+
+func (s *service) checkOwner(ownerType string, ownerId int64) error {
+	var err error
+	switch ownerType {
+	case OwnerDoctor:
+		_, err = s.doc.FindById(ownerId)
+	case OwnerPatient:
+		_, err = s.users.FindById(ownerId)
+	case OwnerRecord:
+		_, err = s.records.FindRecordById(ownerId)
+	case OwnerLabResult:
+		/// Результаты анализов пока не хранятся в БД, поэтому проверяется только id \\\
+		if ownerId < 1 {
+			err = apperror.ErrEmptyString
+		}
+	default:
+		err = apperror.ErrInvalidOwnerType
+	}
+	return err
+}
+
+/// Функция CheckPatientOwner проверяет, что пациент может работать с вложениями владельца: это он сам или его запись на прием \\\
+/// Вложения докторов, результатов анализов и чужие вложения для пациента не существуют \\\
+
+func (s *service) CheckPatientOwner(ctx context.Context, patientId int64, ownerType string, ownerId int64) error {
+	switch ownerType {
+	case OwnerPatient:
+		if ownerId == patientId {
+			return nil
+		}
+	case OwnerRecord:
+		r, err := s.records.FindRecordById(ownerId)
+		if err != nil {
+			return err
+		}
+		if r.PatientsID == patientId {
+			return nil
+		}
+	case OwnerDoctor, OwnerLabResult:
+	default:
+		return apperror.ErrInvalidOwnerType
+	}
+	return apperror.ErrEmptyString
+}
+
+/// Функция CheckPatientAttachment проверяет, что вложение принадлежит пациенту или его записи на прием \\\
+
+func (s *service) CheckPatientAttachment(ctx context.Context, patientId, id int64) error {
+	attachment, err := s.GetById(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.CheckPatientOwner(ctx, patientId, attachment.OwnerType, attachment.OwnerID)
+}
+
+/// Функция validOwnerType проверяет, что тип владельца вложения известен \\\
+
+func validOwnerType(ownerType string) bool {
+	switch ownerType {
+	case OwnerDoctor, OwnerPatient, OwnerRecord, OwnerLabResult:
+		return true
+	}
+	return false
+}
+
+/// Функция sanitizeFileName оставляет от имени файла клиента только его базовую часть без путей \\\
+
+func sanitizeFileName(name string) string {
+	name = path.Base(strings.ReplaceAll(strings.TrimSpace(name), "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "attachment"
+	}
+	return name
+}
@@ -0,0 +1,10 @@
+package attachment
+
+type Storage interface {
+	Create(attachment *Attachment) (*Attachment, error)
+	FindById(id int64) (*Attachment, error)
+	FindByOwner(ownerType string, ownerId int64) ([]Attachment, error)
+	CountByBlobKey(key string) (int64, error)
+	FindVariantBlobKeys(id int64) ([]string, error)
+	Delete(id int64) error
+}
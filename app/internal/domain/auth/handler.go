@@ -111,10 +111,14 @@ func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	/// Вызов функции Register передавая ей полученные значения и ссылку на структуру input \\\
 	user, err := h.authService.Register(r.Context(), &input)
 	if err != nil {
-		if errors.Is(err, apperror.ErrRepeatedEmail) {
+		if errors.Is(err, apperror.ErrRepeatedEmail) || errors.Is(err, apperror.ErrInvalidPolicyNumber) {
 			response.BadRequest(w, err.Error(), "")
 			return
 		}
+		if errors.Is(err, apperror.ErrPolicyNotValid) {
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+			return
+		}
 		response.InternalError(w, fmt.Sprintf("cannot create user: %v", err), "")
 		return
 	}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"net/http"
+	"strings"
+)
+
+type contextKey string
+
+const userContextKey contextKey = "user"
+
+/// Функция NewMiddleware возвращает Middleware, пропускающий только запросы с действительным токеном доступа \\\
+/// Данные пользователя из токена сохраняются в контексте запроса и доступны через FromContext \\\
+
+func NewMiddleware(cfg *config.Config) handler.Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			/// Извлечение токена из заголовка Authorization: Bearer <token> \\\
+			header := r.Header.Get("Authorization")
+			tokenString := strings.TrimPrefix(header, "Bearer ")
+			if header == "" || tokenString == header {
+				response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+				return
+			}
+
+			user, err := ParseAccessToken(cfg, tokenString)
+			if err != nil {
+				response.Unauthorized(w, apperror.ErrUnauthorized.Error(), err.Error())
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userContextKey, user)
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+/// Функция NewAdminMiddleware возвращает Middleware, пропускающий только запросы с ключом администратора в заголовке X-Admin-Key \\\
+/// Пока ключ не задан в конфигурации, административные запросы отклоняются \\\
+
+func NewAdminMiddleware(cfg *config.Config) handler.Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-Admin-Key")
+			if cfg.Admin.APIKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(cfg.Admin.APIKey)) != 1 {
+				response.Unauthorized(w, apperror.ErrAdminUnauthorized.Error(), "")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+/// Функция NewStaffMiddleware возвращает Middleware, пропускающий только запросы сотрудников с ключом в заголовке X-Staff-Key \\\
+/// EventSource в браузере не умеет передавать заголовки, поэтому ключ также принимается в параметре staff_key \\\
+
+func NewStaffMiddleware(cfg *config.Config) handler.Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-Staff-Key")
+			if key == "" {
+				key = r.URL.Query().Get("staff_key")
+			}
+			if cfg.Admin.StaffKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(cfg.Admin.StaffKey)) != 1 {
+				response.Unauthorized(w, apperror.ErrStaffUnauthorized.Error(), "")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+/// Функция ParseAccessToken проверяет подпись токена доступа и возвращает данные пользователя из него \\\
+
+func ParseAccessToken(cfg *config.Config, tokenString string) (*AccessToken, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(cfg.JWT.AccessTokenSecretKey), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, apperror.ErrUnauthorized
+	}
+
+	/// Данные пользователя хранятся в поле user в виде вложенного объекта \\\
+	raw, err := json.Marshal(claims["user"])
+	if err != nil {
+		return nil, err
+	}
+	user := &AccessToken{}
+	if err := json.Unmarshal(raw, user); err != nil || user.ID == 0 {
+		return nil, apperror.ErrUnauthorized
+	}
+	return user, nil
+}
+
+/// Функция FromContext возвращает пользователя, сохраненного в контексте запроса функцией NewMiddleware \\\
+
+func FromContext(ctx context.Context) (*AccessToken, bool) {
+	user, ok := ctx.Value(userContextKey).(*AccessToken)
+	return user, ok
+}
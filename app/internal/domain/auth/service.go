@@ -25,18 +25,20 @@ type Service interface {
 /// Структура  service реализизирующая инфтерфейс Service пользователей \\\
 
 type service struct {
-	logger  logger.Logger
-	storage user.Storage
-	cfg     *config.Config
+	logger   logger.Logger
+	storage  user.Storage
+	policies user.Policies
+	cfg      *config.Config
 }
 
 /// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
 
-func NewService(storage user.Storage, logger logger.Logger, cfg *config.Config) Service {
+func NewService(storage user.Storage, policies user.Policies, logger logger.Logger, cfg *config.Config) Service {
 	return &service{
-		logger:  logger,
-		storage: storage,
-		cfg:     cfg,
+		logger:   logger,
+		storage:  storage,
+		policies: policies,
+		cfg:      cfg,
 	}
 }
 
@@ -117,6 +119,11 @@ func (s *service) AuthByPolicyNumber(ctx context.Context, input *AuthByPolicyNum
 func (s *service) Register(ctx context.Context, input *Register) (*RegisterResponse, error) {
 	s.logger.Info("SERVICE: REGISTER USER")
 
+	/// Проверка контрольной цифры и действия полиса на день регистрации, как при создании пациента \\\
+	if err := s.policies.Check(input.PolicyNumber, time.Now()); err != nil {
+		return nil, err
+	}
+
 	/// Проверка на повтаряющийся адрес электронной почты \\\
 	/// Вызов функции FindByEmail в хранилище пользователей  \\\
 	checkEmail, err := s.storage.FindByEmail(input.Email)
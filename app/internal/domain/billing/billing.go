@@ -0,0 +1,136 @@
+package billing
+
+import "time"
+
+/// Все суммы хранятся в копейках, чтобы избежать ошибок округления \\\
+
+/// Статусы счета \\\
+
+const (
+	InvoiceIssued        = "issued"
+	InvoicePartiallyPaid = "partially_paid"
+	InvoicePaid          = "paid"
+)
+
+/// Способы оплаты, оплата страховой компанией - по договору ДМС \\\
+
+const (
+	PaymentCash      = "cash"
+	PaymentCard      = "card"
+	PaymentInsurance = "insurance"
+)
+
+/// Структура позиции прейскуранта, позиция со специализацией - стоимость приема по этой специализации \\\
+/// Позиции без специализации - процедуры, которые добавляются в счет вручную \\\
+
+type Price struct {
+	ID               int64     `json:"id" example:"1"`
+	Code             string    `json:"code" example:"B01.029.001"`
+	Name             string    `json:"name" example:"Priem vracha-oftalmologa"`
+	SpecializationID *int64    `json:"specialization_id,omitempty" example:"1"`
+	Amount           int64     `json:"amount" example:"150000"`
+	Active           bool      `json:"active" example:"true"`
+	CreatedAt        time.Time `json:"created_at" example:"2023-07-27T10:00:00Z"`
+}
+
+/// Структура счета пациента, счет по записи на прием выставляется один раз \\\
+/// Total - сумма позиций за вычетом скидки, Due - остаток к оплате \\\
+
+type Invoice struct {
+	ID              int64     `json:"id" example:"1"`
+	PatientID       int64     `json:"patient_id" example:"1"`
+	RecordID        *int64    `json:"record_id,omitempty" example:"1567"`
+	Status          string    `json:"status" example:"partially_paid"`
+	Subtotal        int64     `json:"subtotal" example:"250000"`
+	DiscountPercent int       `json:"discount_percent" example:"10"`
+	DiscountReason  *string   `json:"discount_reason,omitempty" example:"Pensioner"`
+	Total           int64     `json:"total" example:"225000"`
+	Paid            int64     `json:"paid" example:"100000"`
+	Due             int64     `json:"due" example:"125000"`
+	CreatedAt       time.Time `json:"created_at" example:"2023-07-27T16:00:00Z"`
+	Items           []Item    `json:"items"`
+	Payments        []Payment `json:"payments"`
+}
+
+/// Структура позиции счета, название и цена копируются из прейскуранта на момент выставления \\\
+
+type Item struct {
+	ID        int64  `json:"id" example:"1"`
+	PriceID   *int64 `json:"price_id,omitempty" example:"1"`
+	Name      string `json:"name" example:"Priem vracha-oftalmologa"`
+	Quantity  int    `json:"quantity" example:"1"`
+	UnitPrice int64  `json:"unit_price" example:"150000"`
+	Amount    int64  `json:"amount" example:"150000"`
+}
+
+/// Структура оплаты по счету, счет можно оплачивать частями \\\
+
+type Payment struct {
+	ID        int64     `json:"id" example:"1"`
+	InvoiceID int64     `json:"invoice_id" example:"1"`
+	Method    string    `json:"method" example:"card"`
+	Amount    int64     `json:"amount" example:"100000"`
+	Reference *string   `json:"reference,omitempty" example:"RRN 123456789012"`
+	CreatedAt time.Time `json:"created_at" example:"2023-07-27T16:05:00Z"`
+}
+
+/// Структура баланса пациента: выставлено, оплачено, долг и неоплаченные счета \\\
+
+type Balance struct {
+	PatientID int64     `json:"patient_id" example:"1"`
+	Invoiced  int64     `json:"invoiced" example:"225000"`
+	Paid      int64     `json:"paid" example:"100000"`
+	Due       int64     `json:"due" example:"125000"`
+	Unpaid    []Invoice `json:"unpaid"`
+}
+
+type CreatePriceDTO struct {
+	Code             string `json:"code" example:"B01.029.001"`
+	Name             string `json:"name" example:"Priem vracha-oftalmologa"`
+	SpecializationID *int64 `json:"specialization_id,omitempty" example:"1"`
+	Amount           int64  `json:"amount" example:"150000"`
+}
+
+type PartiallyUpdatePriceDTO struct {
+	ID     int64   `json:"-"`
+	Name   *string `json:"name,omitempty" example:"Priem vracha-oftalmologa"`
+	Amount *int64  `json:"amount,omitempty" example:"160000"`
+	Active *bool   `json:"active,omitempty" example:"false"`
+}
+
+/// Структура позиции для добавления в счет по прейскуранту \\\
+
+type ItemDTO struct {
+	PriceID  int64 `json:"price_id" example:"2"`
+	Quantity int   `json:"quantity" example:"1"`
+}
+
+/// Структура выставления счета по прошедшему приему: прием по прейскуранту специализации и процедуры \\\
+
+type CreateInvoiceDTO struct {
+	RecordID int64     `json:"-"`
+	Items    []ItemDTO `json:"items,omitempty"`
+}
+
+type DiscountDTO struct {
+	InvoiceID int64   `json:"-"`
+	Percent   int     `json:"percent" example:"10"`
+	Reason    *string `json:"reason,omitempty" example:"Pensioner"`
+}
+
+type PaymentDTO struct {
+	InvoiceID int64   `json:"-"`
+	Method    string  `json:"method" example:"card"`
+	Amount    int64   `json:"amount" example:"100000"`
+	Reference *string `json:"reference,omitempty" example:"RRN 123456789012"`
+}
+
+/// Функция validPaymentMethod проверяет способ оплаты \\\
+
+func validPaymentMethod(method string) bool {
+	switch method {
+	case PaymentCash, PaymentCard, PaymentInsurance:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,325 @@
+package billing
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	pricesURL          = "/hospital_record/prices"
+	priceURL           = "/hospital_record/prices/:id"
+	recordInvoiceURL   = "/hospital_record/records/:id/invoice"
+	invoiceURL         = "/hospital_record/invoices/:id"
+	invoiceItemsURL    = "/hospital_record/invoices/:id/items"
+	invoiceDiscountURL = "/hospital_record/invoices/:id/discount"
+	invoicePaymentsURL = "/hospital_record/invoices/:id/payments"
+	balanceURL         = "/hospital_record/balances/:id"
+	patientBalanceURL  = "/hospital_record/patient_balance"
+)
+
+/// Структура Handler представляющая собой обработчик объекта billingService для платных услуг \\\
+
+type Handler struct {
+	logger         logger.Logger
+	billingService Service
+	authorize      handler.Middleware
+	admin          handler.Middleware
+	staff          handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, billingService Service, authorize, admin, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:         logger,
+		billingService: billingService,
+		authorize:      authorize,
+		admin:          admin,
+		staff:          staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для платных услуг \\\
+/// Прейскурант ведет администратор, счета и оплаты - сотрудники, пациент по токену доступа видит свой баланс \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, pricesURL, h.admin(h.CreatePrice))
+	router.HandlerFunc(http.MethodGet, pricesURL, h.GetPrices)
+	router.HandlerFunc(http.MethodPatch, priceURL, h.admin(h.PartiallyUpdatePrice))
+	router.HandlerFunc(http.MethodPost, recordInvoiceURL, h.staff(h.CreateInvoice))
+	router.HandlerFunc(http.MethodGet, invoiceURL, h.staff(h.GetInvoice))
+	router.HandlerFunc(http.MethodPost, invoiceItemsURL, h.staff(h.AddItem))
+	router.HandlerFunc(http.MethodPost, invoiceDiscountURL, h.staff(h.ApplyDiscount))
+	router.HandlerFunc(http.MethodPost, invoicePaymentsURL, h.staff(h.AddPayment))
+	router.HandlerFunc(http.MethodGet, balanceURL, h.staff(h.GetBalance))
+	router.HandlerFunc(http.MethodGet, patientBalanceURL, h.authorize(h.GetPatientBalance))
+}
+
+/// Функция CreatePrice добавляет позицию прейскуранта \\\
+
+func (h *Handler) CreatePrice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE PRICE")
+
+	/// Чтение тела запроса в структуру CreatePriceDTO \\\
+	var input CreatePriceDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	price, err := h.billingService.CreatePrice(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("PRICE CREATED")
+	response.JSON(w, http.StatusCreated, price)
+}
+
+/// Функция GetPrices получает действующий прейскурант, с параметром all=true - вместе со снятыми позициями \\\
+
+func (h *Handler) GetPrices(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PRICES")
+
+	prices, err := h.billingService.GetPrices(r.Context(), r.URL.Query().Get("all") != "true")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT PRICES")
+	response.JSON(w, http.StatusOK, prices)
+}
+
+/// Функция PartiallyUpdatePrice меняет название, цену или действие позиции прейскуранта \\\
+
+func (h *Handler) PartiallyUpdatePrice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: PARTIALLY UPDATE PRICE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру PartiallyUpdatePriceDTO \\\
+	var input PartiallyUpdatePriceDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	price, err := h.billingService.PartiallyUpdatePrice(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("PRICE UPDATED")
+	response.JSON(w, http.StatusOK, price)
+}
+
+/// Функция CreateInvoice выставляет счет по прошедшему приему \\\
+
+func (h *Handler) CreateInvoice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE INVOICE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID записи из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру CreateInvoiceDTO, тело без процедур можно не передавать \\\
+	var input CreateInvoiceDTO
+	if r.ContentLength != 0 {
+		if err := response.ReadJSON(w, r, &input); err != nil {
+			response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+			return
+		}
+	}
+	input.RecordID = id
+
+	invoice, err := h.billingService.CreateInvoice(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("INVOICE CREATED")
+	response.JSON(w, http.StatusCreated, invoice)
+}
+
+/// Функция GetInvoice получает счет с позициями и оплатами \\\
+
+func (h *Handler) GetInvoice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET INVOICE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	invoice, err := h.billingService.GetInvoice(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT INVOICE")
+	response.JSON(w, http.StatusOK, invoice)
+}
+
+/// Функция AddItem добавляет в счет позицию прейскуранта \\\
+
+func (h *Handler) AddItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ADD INVOICE ITEM")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру ItemDTO \\\
+	var input ItemDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	invoice, err := h.billingService.AddItem(r.Context(), id, &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("INVOICE ITEM ADDED")
+	response.JSON(w, http.StatusOK, invoice)
+}
+
+/// Функция ApplyDiscount устанавливает скидку на счет \\\
+
+func (h *Handler) ApplyDiscount(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: APPLY INVOICE DISCOUNT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру DiscountDTO \\\
+	var input DiscountDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.InvoiceID = id
+
+	invoice, err := h.billingService.ApplyDiscount(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("INVOICE DISCOUNT APPLIED")
+	response.JSON(w, http.StatusOK, invoice)
+}
+
+/// Функция AddPayment принимает оплату по счету \\\
+
+func (h *Handler) AddPayment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ADD INVOICE PAYMENT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру PaymentDTO \\\
+	var input PaymentDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.InvoiceID = id
+
+	invoice, err := h.billingService.AddPayment(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("INVOICE PAYMENT ADDED")
+	response.JSON(w, http.StatusCreated, invoice)
+}
+
+/// Функция GetBalance получает баланс пациента по id \\\
+
+func (h *Handler) GetBalance(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PATIENT BALANCE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID пациента из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	balance, err := h.billingService.GetBalance(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT PATIENT BALANCE")
+	response.JSON(w, http.StatusOK, balance)
+}
+
+/// Функция GetPatientBalance получает баланс авторизованного пациента \\\
+
+func (h *Handler) GetPatientBalance(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET OWN BALANCE")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	balance, err := h.billingService.GetBalance(r.Context(), user.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT OWN BALANCE")
+	response.JSON(w, http.StatusOK, balance)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidBilling):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrVisitNotCompleted):
+		response.Error(w, http.StatusForbidden, err.Error(), "")
+	case errors.Is(err, apperror.ErrNotBillable):
+		response.Error(w, http.StatusUnprocessableEntity, err.Error(), "")
+	case errors.Is(err, apperror.ErrRepeatedInvoice), errors.Is(err, apperror.ErrInvoiceState),
+		errors.Is(err, apperror.ErrOverpayment), errors.Is(err, apperror.ErrPriceExists):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
@@ -0,0 +1,632 @@
+package billing
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &BillingStorage{}
+
+/// Пересчет счета после изменения позиций, скидки или оплат: сумма, итог со скидкой, оплачено и статус \\\
+/// Скидка округляется в пользу пациента до копейки вниз, счет с нулевым итогом сразу считается оплаченным \\\
+
+const recalculateInvoice = `
+UPDATE invoice i
+SET subtotal = s.subtotal,
+    total    = s.subtotal - s.subtotal * i.discount_percent / 100,
+    paid     = p.paid,
+    status   = CASE WHEN p.paid >= s.subtotal - s.subtotal * i.discount_percent / 100 THEN 'paid'
+                    WHEN p.paid > 0 THEN 'partially_paid'
+                    ELSE 'issued' END
+FROM (SELECT coalesce(sum(amount), 0) AS subtotal FROM invoice_item WHERE invoice_id = $1) s,
+     (SELECT coalesce(sum(amount), 0) AS paid FROM payment WHERE invoice_id = $1) p
+WHERE i.id = $1
+RETURNING i.*`
+
+/// Структура BillingStorage содержащая поля для работы с БД \\\
+
+type BillingStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр BillingStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &BillingStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция CreatePrice для сущности BillingStorage добавляет позицию прейскуранта \\\
+/// Повторный код или вторая действующая цена специализации возвращает apperror.ErrPriceExists \\\
+
+func (b *BillingStorage) CreatePrice(price *Price) (*Price, error) {
+	b.logger.Info("POSTGRES: CREATE PRICE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create price transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = lockSpecialization(ctx, tx, price.SpecializationID); err != nil {
+		return nil, err
+	}
+
+	/// Выполнение запроса к БД, код и действующая цена специализации уникальны (индекс service_price_specialization_idx) \\\
+	created, err := scanPrice(tx.QueryRow(ctx,
+		`INSERT INTO service_price (code, name, specialization_id, amount)
+			 VALUES($1,$2,$3,$4)
+			 ON CONFLICT DO NOTHING
+			 RETURNING *`, price.Code, price.Name, price.SpecializationID, price.Amount))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrPriceExists
+		}
+		err = fmt.Errorf("failed to execute create price query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create price transaction: %v", err)
+	}
+	return created, nil
+}
+
+/// Функция FindPrices для сущности BillingStorage получает прейскурант, activeOnly - только действующие позиции \\\
+
+func (b *BillingStorage) FindPrices(activeOnly bool) ([]Price, error) {
+	b.logger.Info("POSTGRES: GET PRICES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := b.conn.Query(ctx,
+		`SELECT * FROM service_price
+			 WHERE active OR NOT $1
+			 ORDER BY code`, activeOnly)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения позиций прейскуранта \\\
+	prices := make([]Price, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		price, err := scanPrice(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find prices query: %v", err)
+			b.logger.Error(err)
+			return nil, err
+		}
+		prices = append(prices, *price)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+/// Функция FindPriceById для сущности BillingStorage получает позицию прейскуранта по id \\\
+
+func (b *BillingStorage) FindPriceById(id int64) (*Price, error) {
+	b.logger.Info("POSTGRES: GET PRICE BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	price, err := scanPrice(b.conn.QueryRow(ctx,
+		`SELECT * FROM service_price
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find price by id query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	return price, nil
+}
+
+/// Функция FindSpecializationPrice для сущности BillingStorage получает действующую стоимость приема по специализации \\\
+
+func (b *BillingStorage) FindSpecializationPrice(specializationId int64) (*Price, error) {
+	b.logger.Info("POSTGRES: GET SPECIALIZATION PRICE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	price, err := scanPrice(b.conn.QueryRow(ctx,
+		`SELECT * FROM service_price
+			 WHERE specialization_id = $1 AND active`, specializationId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find specialization price query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	return price, nil
+}
+
+/// Функция PartiallyUpdatePrice для сущности BillingStorage частично обновляет позицию прейскуранта \\\
+/// Выставленные счета не меняются, цена в них скопирована на момент выставления \\\
+/// Включение цены при другой действующей цене той же специализации возвращает apperror.ErrPriceExists \\\
+
+func (b *BillingStorage) PartiallyUpdatePrice(input *PartiallyUpdatePriceDTO) (*Price, error) {
+	b.logger.Info("POSTGRES: PARTIALLY UPDATE PRICE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin update price transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if input.Active != nil && *input.Active {
+		var specializationId *int64
+		err = tx.QueryRow(ctx,
+			`SELECT specialization_id FROM service_price WHERE id = $1`, input.ID).Scan(&specializationId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, apperror.ErrEmptyString
+			}
+			return nil, fmt.Errorf("failed to find price specialization: %v", err)
+		}
+		if err = lockSpecialization(ctx, tx, specializationId); err != nil {
+			return nil, err
+		}
+		var exists bool
+		err = tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM service_price
+				 WHERE specialization_id = $1 AND active AND id <> $2)`, specializationId, input.ID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check active specialization price: %v", err)
+		}
+		if exists {
+			return nil, apperror.ErrPriceExists
+		}
+	}
+
+	/// Выполнение запроса к БД, незаполненные поля остаются прежними \\\
+	price, err := scanPrice(tx.QueryRow(ctx,
+		`UPDATE service_price SET name = coalesce($1, name), amount = coalesce($2, amount), active = coalesce($3, active)
+			 WHERE id = $4
+			 RETURNING *`, input.Name, input.Amount, input.Active, input.ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute partially update price query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit update price transaction: %v", err)
+	}
+	return price, nil
+}
+
+/// Функция CreateInvoice для сущности BillingStorage выставляет счет с позициями \\\
+/// Повторный счет по той же записи возвращает apperror.ErrRepeatedInvoice \\\
+
+func (b *BillingStorage) CreateInvoice(invoice *Invoice) (*Invoice, error) {
+	b.logger.Info("POSTGRES: CREATE INVOICE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create invoice transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	var id int64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO invoice (patient_id, record_id, status)
+			 VALUES($1,$2,$3)
+			 ON CONFLICT (record_id) DO NOTHING
+			 RETURNING id`, invoice.PatientID, invoice.RecordID, InvoiceIssued).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrRepeatedInvoice
+		}
+		err = fmt.Errorf("failed to execute create invoice query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	for i := range invoice.Items {
+		if err = insertItem(ctx, tx, id, &invoice.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+	created, err := scanInvoice(tx.QueryRow(ctx, recalculateInvoice, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to recalculate invoice: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create invoice transaction: %v", err)
+	}
+	return created, nil
+}
+
+/// Функция FindInvoiceById для сущности BillingStorage получает счет по id без позиций и оплат \\\
+
+func (b *BillingStorage) FindInvoiceById(id int64) (*Invoice, error) {
+	b.logger.Info("POSTGRES: GET INVOICE BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	invoice, err := scanInvoice(b.conn.QueryRow(ctx,
+		`SELECT * FROM invoice
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find invoice by id query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	return invoice, nil
+}
+
+/// Функция FindInvoices для сущности BillingStorage получает счета пациента, unpaidOnly - только неоплаченные \\\
+
+func (b *BillingStorage) FindInvoices(patientId int64, unpaidOnly bool) ([]Invoice, error) {
+	b.logger.Info("POSTGRES: GET INVOICES OF PATIENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := b.conn.Query(ctx,
+		`SELECT * FROM invoice
+			 WHERE patient_id = $1 AND (status <> $2 OR NOT $3)
+			 ORDER BY created_at, id`, patientId, InvoicePaid, unpaidOnly)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения счетов \\\
+	invoices := make([]Invoice, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find invoices query: %v", err)
+			b.logger.Error(err)
+			return nil, err
+		}
+		invoices = append(invoices, *invoice)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+/// Функция FindItems для сущности BillingStorage получает позиции счета \\\
+
+func (b *BillingStorage) FindItems(invoiceId int64) ([]Item, error) {
+	b.logger.Info("POSTGRES: GET INVOICE ITEMS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := b.conn.Query(ctx,
+		`SELECT id, price_id, name, quantity, unit_price, amount
+			 FROM invoice_item
+			 WHERE invoice_id = $1
+			 ORDER BY id`, invoiceId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения позиций счета \\\
+	items := make([]Item, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		var item Item
+		if err = rows.Scan(&item.ID, &item.PriceID, &item.Name, &item.Quantity, &item.UnitPrice, &item.Amount); err != nil {
+			err = fmt.Errorf("failed to execute find invoice items query: %v", err)
+			b.logger.Error(err)
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+/// Функция FindPayments для сущности BillingStorage получает оплаты по счету \\\
+
+func (b *BillingStorage) FindPayments(invoiceId int64) ([]Payment, error) {
+	b.logger.Info("POSTGRES: GET INVOICE PAYMENTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := b.conn.Query(ctx,
+		`SELECT * FROM payment
+			 WHERE invoice_id = $1
+			 ORDER BY created_at, id`, invoiceId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения оплат \\\
+	payments := make([]Payment, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		var p Payment
+		if err = rows.Scan(&p.ID, &p.InvoiceID, &p.Method, &p.Amount, &p.Reference, &p.CreatedAt); err != nil {
+			err = fmt.Errorf("failed to execute find invoice payments query: %v", err)
+			b.logger.Error(err)
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+/// Функция AddItem для сущности BillingStorage добавляет позицию в неоплаченный счет и пересчитывает его \\\
+
+func (b *BillingStorage) AddItem(invoiceId int64, item *Item) (*Invoice, error) {
+	b.logger.Info("POSTGRES: ADD INVOICE ITEM")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin add invoice item transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = lockInvoice(ctx, tx, invoiceId); err != nil {
+		return nil, err
+	}
+
+	/// Выполнение запроса к БД \\\
+	if err = insertItem(ctx, tx, invoiceId, item); err != nil {
+		return nil, err
+	}
+	invoice, err := scanInvoice(tx.QueryRow(ctx, recalculateInvoice, invoiceId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to recalculate invoice: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit add invoice item transaction: %v", err)
+	}
+	return invoice, nil
+}
+
+/// Функция ApplyDiscount для сущности BillingStorage устанавливает скидку на неоплаченный счет \\\
+/// Скидка не может уменьшить итог ниже уже оплаченной суммы \\\
+
+func (b *BillingStorage) ApplyDiscount(input *DiscountDTO) (*Invoice, error) {
+	b.logger.Info("POSTGRES: APPLY INVOICE DISCOUNT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin apply invoice discount transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	locked, err := lockInvoice(ctx, tx, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Итог со скидкой считается так же, как в recalculateInvoice, до изменения счета: ограничение paid <= total не должно сработать \\\
+	if locked.Paid > locked.Subtotal-locked.Subtotal*int64(input.Percent)/100 {
+		return nil, apperror.ErrOverpayment
+	}
+
+	/// Выполнение запроса к БД \\\
+	_, err = tx.Exec(ctx,
+		`UPDATE invoice SET discount_percent = $1, discount_reason = $2
+			 WHERE id = $3`, input.Percent, input.Reason, input.InvoiceID)
+	if err != nil {
+		err = fmt.Errorf("failed to execute apply invoice discount query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	invoice, err := scanInvoice(tx.QueryRow(ctx, recalculateInvoice, input.InvoiceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to recalculate invoice: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit apply invoice discount transaction: %v", err)
+	}
+	return invoice, nil
+}
+
+/// Функция AddPayment для сущности BillingStorage принимает оплату по неоплаченному счету \\\
+/// Оплата сверх остатка возвращает apperror.ErrOverpayment \\\
+
+func (b *BillingStorage) AddPayment(input *PaymentDTO) (*Invoice, error) {
+	b.logger.Info("POSTGRES: ADD INVOICE PAYMENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), b.requestTimeout)
+	defer cancel()
+
+	tx, err := b.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin add invoice payment transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	locked, err := lockInvoice(ctx, tx, input.InvoiceID)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Остаток проверяется до записи оплаты, ограничение paid <= total не должно сработать \\\
+	if input.Amount > locked.Due {
+		return nil, apperror.ErrOverpayment
+	}
+
+	/// Выполнение запроса к БД \\\
+	_, err = tx.Exec(ctx,
+		`INSERT INTO payment (invoice_id, method, amount, reference)
+			 VALUES($1,$2,$3,$4)`, input.InvoiceID, input.Method, input.Amount, input.Reference)
+	if err != nil {
+		err = fmt.Errorf("failed to execute add invoice payment query: %v", err)
+		b.logger.Error(err)
+		return nil, err
+	}
+	invoice, err := scanInvoice(tx.QueryRow(ctx, recalculateInvoice, input.InvoiceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to recalculate invoice: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit add invoice payment transaction: %v", err)
+	}
+	return invoice, nil
+}
+
+/// Функция lockInvoice блокирует счет, проверяет что он еще не оплачен, и возвращает его суммы на момент блокировки \\\
+
+func lockInvoice(ctx context.Context, tx pgx.Tx, id int64) (*Invoice, error) {
+	invoice, err := scanInvoice(tx.QueryRow(ctx,
+		`SELECT * FROM invoice WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to lock invoice: %v", err)
+	}
+	if invoice.Status == InvoicePaid {
+		return nil, apperror.ErrInvoiceState
+	}
+	return invoice, nil
+}
+
+/// Функция lockSpecialization блокирует специализацию до конца транзакции tx, чтобы две цены не стали действующими одновременно \\\
+/// Цены без специализации не блокируются, для них ограничение не действует \\\
+
+func lockSpecialization(ctx context.Context, tx pgx.Tx, specializationId *int64) error {
+	if specializationId == nil {
+		return nil
+	}
+	_, err := tx.Exec(ctx,
+		`SELECT id FROM specialization WHERE id = $1 FOR NO KEY UPDATE`, *specializationId)
+	if err != nil {
+		return fmt.Errorf("failed to lock specialization: %v", err)
+	}
+	return nil
+}
+
+/// Функция insertItem добавляет позицию в счет, сумма позиции - цена за единицу на количество \\\
+
+func insertItem(ctx context.Context, tx pgx.Tx, invoiceId int64, item *Item) error {
+	item.Amount = item.UnitPrice * int64(item.Quantity)
+	err := tx.QueryRow(ctx,
+		`INSERT INTO invoice_item (invoice_id, price_id, name, quantity, unit_price, amount)
+			 VALUES($1,$2,$3,$4,$5,$6)
+			 RETURNING id`,
+		invoiceId, item.PriceID, item.Name, item.Quantity, item.UnitPrice, item.Amount).Scan(&item.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert invoice item: %v", err)
+	}
+	return nil
+}
+
+/// Функция scanPrice сканирует строку таблицы service_price \\\
+
+func scanPrice(row pgx.Row) (*Price, error) {
+	price := &Price{}
+	err := row.Scan(&price.ID, &price.Code, &price.Name, &price.SpecializationID, &price.Amount, &price.Active, &price.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+/// Функция scanInvoice сканирует строку таблицы invoice, остаток к оплате вычисляется \\\
+
+func scanInvoice(row pgx.Row) (*Invoice, error) {
+	invoice := &Invoice{}
+	err := row.Scan(&invoice.ID, &invoice.PatientID, &invoice.RecordID, &invoice.Status, &invoice.Subtotal,
+		&invoice.DiscountPercent, &invoice.DiscountReason, &invoice.Total, &invoice.Paid, &invoice.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Due = invoice.Total - invoice.Paid
+	return invoice, nil
+}
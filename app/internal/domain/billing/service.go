@@ -0,0 +1,311 @@
+package billing
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+/// Имя подписчика outbox, под которым отмечается выставление счетов по обслуженным талонам \\\
+
+const subscriberName = "billing"
+
+/// Интерфейс Service реализизирующий service и методы платных услуг: прейскурант, счета, скидки и оплаты \\\
+
+type Service interface {
+	outbox.Subscriber
+	CreatePrice(ctx context.Context, input *CreatePriceDTO) (*Price, error)
+	GetPrices(ctx context.Context, activeOnly bool) (*[]Price, error)
+	PartiallyUpdatePrice(ctx context.Context, input *PartiallyUpdatePriceDTO) (*Price, error)
+	CreateInvoice(ctx context.Context, input *CreateInvoiceDTO) (*Invoice, error)
+	GetInvoice(ctx context.Context, id int64) (*Invoice, error)
+	AddItem(ctx context.Context, invoiceId int64, input *ItemDTO) (*Invoice, error)
+	ApplyDiscount(ctx context.Context, input *DiscountDTO) (*Invoice, error)
+	AddPayment(ctx context.Context, input *PaymentDTO) (*Invoice, error)
+	GetBalance(ctx context.Context, patientId int64) (*Balance, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service платных услуг \\\
+
+type service struct {
+	logger   logger.Logger
+	storage  Storage
+	records  record.Storage
+	patients user.Storage
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(records record.Storage, patients user.Storage, storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:   logger,
+		storage:  storage,
+		records:  records,
+		patients: patients,
+	}
+}
+
+/// Функция CreatePrice добавляет позицию прейскуранта \\\
+
+func (s *service) CreatePrice(ctx context.Context, input *CreatePriceDTO) (*Price, error) {
+	s.logger.Info("SERVICE: CREATE PRICE")
+
+	/// Проверка входных данных \\\
+	price := Price{
+		Code:             strings.TrimSpace(input.Code),
+		Name:             strings.TrimSpace(input.Name),
+		SpecializationID: input.SpecializationID,
+		Amount:           input.Amount,
+	}
+	if price.Code == "" || price.Name == "" || price.Amount < 0 {
+		return nil, apperror.ErrInvalidBilling
+	}
+	return s.storage.CreatePrice(&price)
+}
+
+/// Функция GetPrices возвращает прейскурант \\\
+
+func (s *service) GetPrices(ctx context.Context, activeOnly bool) (*[]Price, error) {
+	s.logger.Info("SERVICE: GET PRICES")
+
+	prices, err := s.storage.FindPrices(activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	return &prices, nil
+}
+
+/// Функция PartiallyUpdatePrice меняет название, цену или действие позиции прейскуранта \\\
+
+func (s *service) PartiallyUpdatePrice(ctx context.Context, input *PartiallyUpdatePriceDTO) (*Price, error) {
+	s.logger.Info("SERVICE: PARTIALLY UPDATE PRICE")
+
+	/// Проверка входных данных \\\
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, apperror.ErrInvalidBilling
+		}
+		input.Name = &name
+	}
+	if input.Amount != nil && *input.Amount < 0 {
+		return nil, apperror.ErrInvalidBilling
+	}
+	return s.storage.PartiallyUpdatePrice(input)
+}
+
+/// Функция CreateInvoice выставляет счет по прошедшему приему \\\
+
+func (s *service) CreateInvoice(ctx context.Context, input *CreateInvoiceDTO) (*Invoice, error) {
+	s.logger.Info("SERVICE: CREATE INVOICE")
+
+	rec, err := s.records.FindRecordById(input.RecordID)
+	if err != nil {
+		return nil, err
+	}
+	if !rec.TimeRecord.Before(time.Now()) {
+		return nil, apperror.ErrVisitNotCompleted
+	}
+	return s.invoice(rec, input.Items)
+}
+
+/// Функция GetInvoice возвращает счет с позициями и оплатами \\\
+
+func (s *service) GetInvoice(ctx context.Context, id int64) (*Invoice, error) {
+	s.logger.Info("SERVICE: GET INVOICE")
+
+	invoice, err := s.storage.FindInvoiceById(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(invoice)
+}
+
+/// Функция AddItem добавляет в неоплаченный счет позицию прейскуранта \\\
+
+func (s *service) AddItem(ctx context.Context, invoiceId int64, input *ItemDTO) (*Invoice, error) {
+	s.logger.Info("SERVICE: ADD INVOICE ITEM")
+
+	item, err := s.item(input)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.storage.AddItem(invoiceId, item)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(invoice)
+}
+
+/// Функция ApplyDiscount устанавливает скидку на неоплаченный счет в процентах \\\
+
+func (s *service) ApplyDiscount(ctx context.Context, input *DiscountDTO) (*Invoice, error) {
+	s.logger.Info("SERVICE: APPLY INVOICE DISCOUNT")
+
+	/// Проверка входных данных \\\
+	if input.Percent < 0 || input.Percent > 100 {
+		return nil, apperror.ErrInvalidBilling
+	}
+	invoice, err := s.storage.ApplyDiscount(input)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(invoice)
+}
+
+/// Функция AddPayment принимает полную или частичную оплату по счету \\\
+
+func (s *service) AddPayment(ctx context.Context, input *PaymentDTO) (*Invoice, error) {
+	s.logger.Info("SERVICE: ADD INVOICE PAYMENT")
+
+	/// Проверка входных данных \\\
+	if !validPaymentMethod(input.Method) || input.Amount <= 0 {
+		return nil, apperror.ErrInvalidBilling
+	}
+	invoice, err := s.storage.AddPayment(input)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(invoice)
+}
+
+/// Функция GetBalance возвращает баланс пациента по всем счетам и его неоплаченные счета \\\
+
+func (s *service) GetBalance(ctx context.Context, patientId int64) (*Balance, error) {
+	s.logger.Info("SERVICE: GET PATIENT BALANCE")
+
+	if _, err := s.patients.FindById(patientId); err != nil {
+		return nil, err
+	}
+	invoices, err := s.storage.FindInvoices(patientId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := Balance{PatientID: patientId, Unpaid: make([]Invoice, 0)}
+	for i := range invoices {
+		balance.Invoiced += invoices[i].Total
+		balance.Paid += invoices[i].Paid
+		if invoices[i].Status == InvoicePaid {
+			continue
+		}
+		invoice, err := s.load(&invoices[i])
+		if err != nil {
+			return nil, err
+		}
+		balance.Unpaid = append(balance.Unpaid, *invoice)
+	}
+	balance.Due = balance.Invoiced - balance.Paid
+	return &balance, nil
+}
+
+/// Функция Name возвращает имя подписчика outbox \\\
+
+func (s *service) Name() string {
+	return subscriberName
+}
+
+/// Функция Handle выставляет счет, когда пациента приняли по талону записи на прием \\\
+/// Прием без цены в прейскуранте и уже выставленный счет пропускаются \\\
+
+func (s *service) Handle(ctx context.Context, event outbox.Event) error {
+	if event.Type != outbox.TicketServed {
+		return nil
+	}
+	var payload outbox.TicketPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return err
+	}
+	if payload.RecordID == nil {
+		return nil
+	}
+
+	rec, err := s.records.FindRecordById(*payload.RecordID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			return nil
+		}
+		return err
+	}
+	_, err = s.invoice(rec, nil)
+	if errors.Is(err, apperror.ErrRepeatedInvoice) || errors.Is(err, apperror.ErrNotBillable) {
+		return nil
+	}
+	return err
+}
+
+/// Функция invoice выставляет счет по записи: прием по цене специализации записи и дополнительные процедуры \\\
+
+func (s *service) invoice(rec *record.Record, extra []ItemDTO) (*Invoice, error) {
+	items := make([]Item, 0, len(extra)+1)
+
+	price, err := s.storage.FindSpecializationPrice(rec.SpecializationID)
+	if err != nil && !errors.Is(err, apperror.ErrEmptyString) {
+		return nil, err
+	}
+	if price != nil {
+		items = append(items, Item{PriceID: &price.ID, Name: price.Name, Quantity: 1, UnitPrice: price.Amount})
+	}
+	for i := range extra {
+		item, err := s.item(&extra[i])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	if len(items) == 0 {
+		return nil, apperror.ErrNotBillable
+	}
+
+	recordId := rec.ID
+	invoice, err := s.storage.CreateInvoice(&Invoice{PatientID: rec.PatientsID, RecordID: &recordId, Items: items})
+	if err != nil {
+		return nil, err
+	}
+	return s.load(invoice)
+}
+
+/// Функция item составляет позицию счета из действующей позиции прейскуранта, без количества - одна единица \\\
+
+func (s *service) item(input *ItemDTO) (*Item, error) {
+	if input.Quantity == 0 {
+		input.Quantity = 1
+	}
+	if input.Quantity < 0 {
+		return nil, apperror.ErrInvalidBilling
+	}
+	price, err := s.storage.FindPriceById(input.PriceID)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			return nil, apperror.ErrInvalidBilling
+		}
+		return nil, err
+	}
+	if !price.Active {
+		return nil, apperror.ErrInvalidBilling
+	}
+	return &Item{PriceID: &price.ID, Name: price.Name, Quantity: input.Quantity, UnitPrice: price.Amount}, nil
+}
+
+/// Функция load заполняет позиции и оплаты счета \\\
+
+func (s *service) load(invoice *Invoice) (*Invoice, error) {
+	items, err := s.storage.FindItems(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.storage.FindPayments(invoice.ID)
+	if err != nil {
+		return nil, err
+	}
+	invoice.Items = items
+	invoice.Payments = payments
+	return invoice, nil
+}
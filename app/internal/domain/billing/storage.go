@@ -0,0 +1,17 @@
+package billing
+
+type Storage interface {
+	CreatePrice(price *Price) (*Price, error)
+	FindPrices(activeOnly bool) ([]Price, error)
+	FindPriceById(id int64) (*Price, error)
+	FindSpecializationPrice(specializationId int64) (*Price, error)
+	PartiallyUpdatePrice(input *PartiallyUpdatePriceDTO) (*Price, error)
+	CreateInvoice(invoice *Invoice) (*Invoice, error)
+	FindInvoiceById(id int64) (*Invoice, error)
+	FindInvoices(patientId int64, unpaidOnly bool) ([]Invoice, error)
+	FindItems(invoiceId int64) ([]Item, error)
+	FindPayments(invoiceId int64) ([]Payment, error)
+	AddItem(invoiceId int64, item *Item) (*Invoice, error)
+	ApplyDiscount(input *DiscountDTO) (*Invoice, error)
+	AddPayment(input *PaymentDTO) (*Invoice, error)
+}
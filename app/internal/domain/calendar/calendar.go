@@ -0,0 +1,34 @@
+package calendar
+
+import "time"
+
+/// Владельцы ленты календаря \\\
+
+const (
+	OwnerPatient = "patient"
+	OwnerDoctor  = "doctor"
+)
+
+/// Структура приема для календаря: действующая или отмененная запись на прием \\\
+
+type Appointment struct {
+	RecordID        int64
+	PatientID       int64
+	PatientName     string
+	DoctorID        int64
+	DoctorName      string
+	Specialization  string
+	HospitalAddress string
+	DoctorOffice    string
+	Tagging         string
+	TimeRecord      time.Time
+	Sequence        int
+	Cancelled       bool
+}
+
+/// Структура ссылки на ленту календаря, токен показывается только при выпуске \\\
+
+type FeedToken struct {
+	Token string `json:"token" example:"q3VvYyQe0sVx1m6bQ2lCkq3x7f0PpQ3sVbYkJmW9Z0c"`
+	URL   string `json:"url" example:"http://localhost:3000/hospital_record/calendar/q3VvYyQe0sVx1m6bQ2lCkq3x7f0PpQ3sVbYkJmW9Z0c"`
+}
@@ -0,0 +1,205 @@
+package calendar
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	recordICSURL         = "/hospital_record/records/:id/ics"
+	feedURL              = "/hospital_record/calendar/:token"
+	patientTokenURL      = "/hospital_record/calendar_tokens"
+	doctorTokenURL       = "/hospital_record/calendar_tokens/doctors/:id"
+	calendarContentType  = "text/calendar; charset=utf-8"
+	calendarCacheControl = "private, max-age=300"
+)
+
+/// Структура Handler представляющая собой обработчик объекта calendarService для календаря приемов \\\
+
+type Handler struct {
+	logger          logger.Logger
+	calendarService Service
+	authorize       handler.Middleware
+	staff           handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, calendarService Service, authorize, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:          logger,
+		calendarService: calendarService,
+		authorize:       authorize,
+		staff:           staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для календаря, токен ленты пациента выпускается по токену доступа \\\
+/// Ленты докторов содержат имена пациентов, поэтому их токенами управляют только сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, recordICSURL, h.authorize(h.GetRecordICS))
+	router.HandlerFunc(http.MethodGet, feedURL, h.GetFeed)
+	router.HandlerFunc(http.MethodPost, patientTokenURL, h.authorize(h.IssuePatientToken))
+	router.HandlerFunc(http.MethodDelete, patientTokenURL, h.authorize(h.RevokePatientToken))
+	router.HandlerFunc(http.MethodPost, doctorTokenURL, h.staff(h.IssueDoctorToken))
+	router.HandlerFunc(http.MethodDelete, doctorTokenURL, h.staff(h.RevokeDoctorToken))
+}
+
+/// Функция GetRecordICS отдает запись на прием авторизованного пациента файлом .ics \\\
+
+func (h *Handler) GetRecordICS(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET RECORD ICS")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции RecordICS передавая ей id пациента и записи \\\
+	body, err := h.calendarService.RecordICS(r.Context(), user.ID, id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="record-%d.ics"`, id))
+	h.writeCalendar(w, body)
+	h.logger.Info("GOT RECORD ICS")
+}
+
+/// Функция GetFeed отдает ленту предстоящих приемов по токену из ссылки \\\
+
+func (h *Handler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET CALENDAR FEED")
+
+	token := httprouter.ParamsFromContext(r.Context()).ByName("token")
+
+	/// Вызов функции Feed передавая ей токен ленты \\\
+	body, err := h.calendarService.Feed(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.writeCalendar(w, body)
+	h.logger.Info("GOT CALENDAR FEED")
+}
+
+/// Функция IssuePatientToken выпускает ссылку на ленту календаря авторизованного пациента \\\
+
+func (h *Handler) IssuePatientToken(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ISSUE PATIENT CALENDAR TOKEN")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+	h.issueToken(w, r, OwnerPatient, user.ID)
+}
+
+/// Функция RevokePatientToken отзывает ссылку на ленту календаря авторизованного пациента \\\
+
+func (h *Handler) RevokePatientToken(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: REVOKE PATIENT CALENDAR TOKEN")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+	h.revokeToken(w, r, OwnerPatient, user.ID)
+}
+
+/// Функция IssueDoctorToken выпускает ссылку на ленту календаря доктора по его id \\\
+
+func (h *Handler) IssueDoctorToken(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ISSUE DOCTOR CALENDAR TOKEN")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	h.issueToken(w, r, OwnerDoctor, id)
+}
+
+/// Функция RevokeDoctorToken отзывает ссылку на ленту календаря доктора по его id \\\
+
+func (h *Handler) RevokeDoctorToken(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: REVOKE DOCTOR CALENDAR TOKEN")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	h.revokeToken(w, r, OwnerDoctor, id)
+}
+
+/// Функция issueToken выпускает токен ленты владельца и отвечает ссылкой на ленту \\\
+
+func (h *Handler) issueToken(w http.ResponseWriter, r *http.Request, ownerType string, ownerId int64) {
+	token, err := h.calendarService.IssueToken(r.Context(), ownerType, ownerId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
+	}
+	h.logger.Info("CALENDAR TOKEN ISSUED")
+	response.JSON(w, http.StatusCreated, token)
+}
+
+/// Функция revokeToken отзывает токен ленты владельца \\\
+
+func (h *Handler) revokeToken(w http.ResponseWriter, r *http.Request, ownerType string, ownerId int64) {
+	err := h.calendarService.RevokeToken(r.Context(), ownerType, ownerId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
+	}
+	h.logger.Info("CALENDAR TOKEN REVOKED")
+	response.JSON(w, http.StatusOK, "CALENDAR TOKEN REVOKED")
+}
+
+/// Функция writeCalendar отправляет календарь клиенту с типом text/calendar \\\
+
+func (h *Handler) writeCalendar(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", calendarContentType)
+	w.Header().Set("Cache-Control", calendarCacheControl)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.logger.Warnf("failed to write calendar: %v", err)
+	}
+}
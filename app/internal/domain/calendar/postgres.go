@@ -0,0 +1,192 @@
+package calendar
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &CalendarStorage{}
+
+/// Запрос приемов: действующие записи и отмененные записи из record_cancellation, условие подставляется в обе части \\\
+/// Отмена увеличивает SEQUENCE, чтобы календарь принял ее как более позднюю версию события \\\
+
+const selectAppointments = `
+SELECT a.id, a.patients_id, p.surname || ' ' || p.name, a.doctor_id, d.surname || ' ' || d.name, s.name_specialization,
+       a.hospital_address, a.doctor_office, a.tagging, a.time_record,
+       (SELECT count(*) FROM record_reschedule rr WHERE rr.record_id = a.id)::int, false
+FROM record a
+INNER JOIN patients p ON p.id = a.patients_id
+INNER JOIN doctors d ON d.id = a.doctor_id
+INNER JOIN specialization s ON s.id = a.specialization_id
+WHERE %[1]s
+UNION ALL
+SELECT a.id, a.patients_id, p.surname || ' ' || p.name, a.doctor_id, d.surname || ' ' || d.name, s.name_specialization,
+       a.hospital_address, a.doctor_office, a.tagging, a.time_record,
+       a.sequence + 1, true
+FROM record_cancellation a
+INNER JOIN patients p ON p.id = a.patients_id
+INNER JOIN doctors d ON d.id = a.doctor_id
+INNER JOIN specialization s ON s.id = a.specialization_id
+WHERE %[1]s
+ORDER BY 10`
+
+/// Структура CalendarStorage содержащая поля для работы с БД \\\
+
+type CalendarStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр CalendarStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &CalendarStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция FindAppointment для сущности CalendarStorage получает прием по id записи, в том числе отмененной \\\
+
+func (c *CalendarStorage) FindAppointment(recordId int64) (*Appointment, error) {
+	c.logger.Info("POSTGRES: GET CALENDAR APPOINTMENT")
+
+	appointments, err := c.findAppointments("a.id = $1", recordId)
+	if err != nil {
+		return nil, err
+	}
+	if len(appointments) == 0 {
+		return nil, apperror.ErrEmptyString
+	}
+	return &appointments[0], nil
+}
+
+/// Функция FindUpcoming для сущности CalendarStorage получает приемы пациента или доктора начиная с since \\\
+
+func (c *CalendarStorage) FindUpcoming(ownerType string, ownerId int64, since time.Time) ([]Appointment, error) {
+	c.logger.Info("POSTGRES: GET UPCOMING CALENDAR APPOINTMENTS")
+
+	column := "a.patients_id"
+	if ownerType == OwnerDoctor {
+		column = "a.doctor_id"
+	}
+	return c.findAppointments(column+" = $1 AND a.time_record >= $2", ownerId, since)
+}
+
+/// Функция findAppointments выполняет запрос приемов с условием filter \\\
+
+func (c *CalendarStorage) findAppointments(filter string, args ...interface{}) ([]Appointment, error) {
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := c.conn.Query(ctx, fmt.Sprintf(selectAppointments, filter), args...)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения приемов \\\
+	appointments := make([]Appointment, 0)
+
+	for rows.Next() {
+		var a Appointment
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&a.RecordID, &a.PatientID, &a.PatientName, &a.DoctorID, &a.DoctorName, &a.Specialization,
+			&a.HospitalAddress, &a.DoctorOffice, &a.Tagging, &a.TimeRecord, &a.Sequence, &a.Cancelled)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find calendar appointments query: %v", err)
+			c.logger.Error(err)
+			return nil, err
+		}
+		appointments = append(appointments, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return appointments, nil
+}
+
+/// Функция SaveToken для сущности CalendarStorage сохраняет хеш токена ленты, прежний токен владельца перестает действовать \\\
+
+func (c *CalendarStorage) SaveToken(ownerType string, ownerId int64, tokenHash string) error {
+	c.logger.Info("POSTGRES: SAVE CALENDAR TOKEN")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	_, err := c.conn.Exec(ctx,
+		`INSERT INTO calendar_token (owner_type, owner_id, token_hash)
+			 VALUES($1,$2,$3)
+			 ON CONFLICT (owner_type, owner_id) DO UPDATE SET token_hash = excluded.token_hash, created_at = now()`,
+		ownerType, ownerId, tokenHash)
+	if err != nil {
+		err = fmt.Errorf("failed to execute save calendar token query: %v", err)
+		c.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+/// Функция FindTokenOwner для сущности CalendarStorage получает владельца ленты по хешу токена \\\
+
+func (c *CalendarStorage) FindTokenOwner(tokenHash string) (string, int64, error) {
+	c.logger.Info("POSTGRES: GET CALENDAR TOKEN OWNER")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	var ownerType string
+	var ownerId int64
+	err := c.conn.QueryRow(ctx,
+		`SELECT owner_type, owner_id FROM calendar_token
+			 WHERE token_hash = $1`, tokenHash).Scan(&ownerType, &ownerId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", 0, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find calendar token query: %v", err)
+		c.logger.Error(err)
+		return "", 0, err
+	}
+	return ownerType, ownerId, nil
+}
+
+/// Функция DeleteToken для сущности CalendarStorage отзывает токен ленты владельца \\\
+
+func (c *CalendarStorage) DeleteToken(ownerType string, ownerId int64) error {
+	c.logger.Info("POSTGRES: DELETE CALENDAR TOKEN")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := c.conn.Exec(ctx,
+		`DELETE FROM calendar_token WHERE owner_type = $1 AND owner_id = $2`, ownerType, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to delete calendar token: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
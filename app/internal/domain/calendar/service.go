@@ -0,0 +1,186 @@
+package calendar
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/pkg/ical"
+	"HospitalRecord/app/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/// Идентификатор продукта в PRODID и путь ленты календаря \\\
+
+const (
+	prodID   = "-//HospitalRecord//Appointments//EN"
+	feedPath = "/hospital_record/calendar/"
+)
+
+/// Интерфейс Service реализизирующий service и методы для экспорта приемов в календарь \\\
+
+type Service interface {
+	RecordICS(ctx context.Context, patientId, recordId int64) ([]byte, error)
+	Feed(ctx context.Context, token string) ([]byte, error)
+	IssueToken(ctx context.Context, ownerType string, ownerId int64) (*FeedToken, error)
+	RevokeToken(ctx context.Context, ownerType string, ownerId int64) error
+}
+
+/// Структура  service реализизирующая инфтерфейс Service календаря \\\
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+	doc     doctor.Storage
+	baseURL string
+	slot    time.Duration
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+		doc:     doc,
+		baseURL: strings.TrimRight(cfg.Calendar.BaseURL, "/"),
+		slot:    time.Duration(cfg.Records.SlotMinutes) * time.Minute,
+	}
+}
+
+/// Функция RecordICS возвращает один прием пациента в виде файла .ics, отмененный прием имеет STATUS:CANCELLED \\\
+/// Чужая запись для пациента не существует, поэтому возвращается apperror.ErrEmptyString \\\
+
+func (s *service) RecordICS(ctx context.Context, patientId, recordId int64) ([]byte, error) {
+	s.logger.Info("SERVICE: GET RECORD ICS")
+
+	appointment, err := s.storage.FindAppointment(recordId)
+	if err != nil {
+		return nil, err
+	}
+	if appointment.PatientID != patientId {
+		return nil, apperror.ErrEmptyString
+	}
+	cal := ical.Calendar{
+		ProdID: prodID,
+		Events: []ical.Event{s.event(appointment, OwnerPatient)},
+	}
+	return s.encode(&cal)
+}
+
+/// Функция Feed возвращает ленту предстоящих приемов владельца токена \\\
+
+func (s *service) Feed(ctx context.Context, token string) ([]byte, error) {
+	s.logger.Info("SERVICE: GET CALENDAR FEED")
+
+	ownerType, ownerId, err := s.storage.FindTokenOwner(hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	appointments, err := s.storage.FindUpcoming(ownerType, ownerId, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	/// Формирование событий календаря \\\
+	cal := ical.Calendar{
+		ProdID: prodID,
+		Name:   "Hospital appointments",
+		Events: make([]ical.Event, 0, len(appointments)),
+	}
+	for i := range appointments {
+		cal.Events = append(cal.Events, s.event(&appointments[i], ownerType))
+	}
+	return s.encode(&cal)
+}
+
+/// Функция IssueToken выпускает новый токен ленты календаря, прежний токен владельца перестает действовать \\\
+
+func (s *service) IssueToken(ctx context.Context, ownerType string, ownerId int64) (*FeedToken, error) {
+	s.logger.Info("SERVICE: ISSUE CALENDAR TOKEN")
+
+	/// Лента доктора выпускается только для существующего доктора \\\
+	if ownerType == OwnerDoctor {
+		if _, err := s.doc.FindById(ownerId); err != nil {
+			return nil, err
+		}
+	}
+
+	/// Генерация случайного токена, в БД хранится только его хеш \\\
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate calendar token: %v", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	if err := s.storage.SaveToken(ownerType, ownerId, hashToken(token)); err != nil {
+		return nil, err
+	}
+	return &FeedToken{
+		Token: token,
+		URL:   s.baseURL + feedPath + token,
+	}, nil
+}
+
+/// Функция RevokeToken отзывает токен ленты календаря владельца \\\
+
+func (s *service) RevokeToken(ctx context.Context, ownerType string, ownerId int64) error {
+	s.logger.Info("SERVICE: REVOKE CALENDAR TOKEN")
+
+	err := s.storage.DeleteToken(ownerType, ownerId)
+	if err != nil && !errors.Is(err, apperror.ErrEmptyString) {
+		s.logger.Warnf("failed to revoke calendar token: %v", err)
+	}
+	return err
+}
+
+/// Функция event преобразует прием в событие календаря с точки зрения пациента или доктора \\\
+
+func (s *service) event(a *Appointment, viewer string) ical.Event {
+	summary := fmt.Sprintf("Appointment: %s, Dr. %s", a.Specialization, a.DoctorName)
+	if viewer == OwnerDoctor {
+		summary = fmt.Sprintf("Appointment: %s, patient %s", a.Specialization, a.PatientName)
+	}
+	location := a.HospitalAddress
+	if a.DoctorOffice != "" {
+		location = fmt.Sprintf("%s, office %s", a.HospitalAddress, a.DoctorOffice)
+	}
+	status := ical.StatusConfirmed
+	if a.Cancelled {
+		status = ical.StatusCancelled
+	}
+	return ical.Event{
+		UID:         fmt.Sprintf("record-%d@hospital-record", a.RecordID),
+		Sequence:    a.Sequence,
+		Start:       a.TimeRecord,
+		End:         a.TimeRecord.Add(s.slot),
+		Summary:     summary,
+		Description: a.Tagging,
+		Location:    location,
+		Status:      status,
+	}
+}
+
+/// Функция encode кодирует календарь в формат RFC 5545 \\\
+
+func (s *service) encode(cal *ical.Calendar) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, cal, time.Now()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/// Функция hashToken возвращает sha256 токена в шестнадцатеричном виде \\\
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
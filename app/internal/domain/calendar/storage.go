@@ -0,0 +1,11 @@
+package calendar
+
+import "time"
+
+type Storage interface {
+	FindAppointment(recordId int64) (*Appointment, error)
+	FindUpcoming(ownerType string, ownerId int64, since time.Time) ([]Appointment, error)
+	SaveToken(ownerType string, ownerId int64, tokenHash string) error
+	FindTokenOwner(tokenHash string) (string, int64, error)
+	DeleteToken(ownerType string, ownerId int64) error
+}
@@ -0,0 +1,74 @@
+package claim
+
+import "time"
+
+/// Статусы реестра счетов страховщику: черновик, отправлен, принят или отклонен с причиной \\\
+/// Записи из отклоненного реестра можно включить в новый реестр \\\
+
+const (
+	StatusDraft     = "draft"
+	StatusSubmitted = "submitted"
+	StatusAccepted  = "accepted"
+	StatusRejected  = "rejected"
+)
+
+/// Виды позиций реестра: прием и процедура по счету записи \\\
+
+const (
+	KindVisit     = "visit"
+	KindProcedure = "procedure"
+)
+
+/// Форматы выгрузки реестра \\\
+
+const (
+	FormatXML = "xml"
+	FormatCSV = "csv"
+)
+
+/// Структура реестра счетов страховщику за период, суммы в копейках \\\
+
+type Batch struct {
+	ID              int64      `json:"id" example:"1"`
+	InsurerID       int64      `json:"insurer_id" example:"1"`
+	Insurer         string     `json:"insurer" example:"AO \"SOGAZ-Med\""`
+	PeriodFrom      time.Time  `json:"period_from" example:"2023-07-01T00:00:00Z"`
+	PeriodTo        time.Time  `json:"period_to" example:"2023-07-31T00:00:00Z"`
+	Status          string     `json:"status" example:"draft"`
+	RejectionReason *string    `json:"rejection_reason,omitempty" example:"Nevernyy nomer polisa v pozicii 3"`
+	Total           int64      `json:"total" example:"330000"`
+	CreatedAt       time.Time  `json:"created_at" example:"2023-08-01T09:00:00Z"`
+	SubmittedAt     *time.Time `json:"submitted_at,omitempty" example:"2023-08-01T10:00:00Z"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty" example:"2023-08-10T12:00:00Z"`
+	Items           []Item     `json:"items,omitempty"`
+}
+
+/// Структура позиции реестра, данные пациента и услуги копируются на момент формирования \\\
+
+type Item struct {
+	ID               int64     `json:"id" example:"1"`
+	RecordID         int64     `json:"record_id" example:"1567"`
+	Kind             string    `json:"kind" example:"visit"`
+	PatientID        int64     `json:"patient_id" example:"1"`
+	PolicyNumber     string    `json:"policy_number" example:"2194589700000056"`
+	DoctorID         int64     `json:"doctor_id" example:"1"`
+	SpecializationID int64     `json:"specialization_id" example:"1"`
+	ServiceCode      string    `json:"service_code" example:"B01.029.001"`
+	ServiceName      string    `json:"service_name" example:"Priem vracha-oftalmologa"`
+	Quantity         int       `json:"quantity" example:"1"`
+	Amount           int64     `json:"amount" example:"150000"`
+	TimeRecord       time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+}
+
+/// Структура формирования реестра по страховщику за период, даты включительно \\\
+
+type CreateBatchDTO struct {
+	InsurerID  int64     `json:"insurer_id" example:"1"`
+	PeriodFrom time.Time `json:"period_from" example:"2023-07-01T00:00:00Z"`
+	PeriodTo   time.Time `json:"period_to" example:"2023-07-31T00:00:00Z"`
+}
+
+type RejectBatchDTO struct {
+	ID     int64  `json:"-"`
+	Reason string `json:"reason" example:"Nevernyy nomer polisa v pozicii 3"`
+}
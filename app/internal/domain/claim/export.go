@@ -0,0 +1,141 @@
+package claim
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+/// Формат дат и времени выгрузки \\\
+
+const (
+	exportDate = "2006-01-02"
+	exportTime = "2006-01-02T15:04:05"
+)
+
+/// Структуры XML выгрузки реестра, суммы в рублях с копейками \\\
+
+type xmlBatch struct {
+	XMLName    xml.Name  `xml:"ClaimBatch"`
+	ID         int64     `xml:"id,attr"`
+	InsurerID  int64     `xml:"InsurerId"`
+	Insurer    string    `xml:"Insurer"`
+	PeriodFrom string    `xml:"PeriodFrom"`
+	PeriodTo   string    `xml:"PeriodTo"`
+	Status     string    `xml:"Status"`
+	Count      int       `xml:"ItemCount"`
+	Total      string    `xml:"Total"`
+	Items      []xmlItem `xml:"Items>Item"`
+}
+
+type xmlItem struct {
+	ID               int64  `xml:"id,attr"`
+	RecordID         int64  `xml:"RecordId"`
+	Kind             string `xml:"Kind"`
+	VisitTime        string `xml:"VisitTime"`
+	PolicyNumber     string `xml:"PolicyNumber"`
+	PatientID        int64  `xml:"PatientId"`
+	DoctorID         int64  `xml:"DoctorId"`
+	SpecializationID int64  `xml:"SpecializationId"`
+	ServiceCode      string `xml:"ServiceCode"`
+	ServiceName      string `xml:"ServiceName"`
+	Quantity         int    `xml:"Quantity"`
+	Amount           string `xml:"Amount"`
+}
+
+/// Колонки CSV выгрузки \\\
+
+var csvHeader = []string{
+	"batch_id", "insurer_id", "record_id", "kind", "visit_time", "policy_number", "patient_id",
+	"doctor_id", "specialization_id", "service_code", "service_name", "quantity", "amount",
+}
+
+/// Функция exportXML выгружает реестр с позициями в XML \\\
+
+func (s *service) exportXML(batch *Batch) ([]byte, error) {
+	doc := xmlBatch{
+		ID:         batch.ID,
+		InsurerID:  batch.InsurerID,
+		Insurer:    batch.Insurer,
+		PeriodFrom: batch.PeriodFrom.Format(exportDate),
+		PeriodTo:   batch.PeriodTo.Format(exportDate),
+		Status:     batch.Status,
+		Count:      len(batch.Items),
+		Total:      rubles(batch.Total),
+		Items:      make([]xmlItem, 0, len(batch.Items)),
+	}
+	for _, item := range batch.Items {
+		doc.Items = append(doc.Items, xmlItem{
+			ID:               item.ID,
+			RecordID:         item.RecordID,
+			Kind:             item.Kind,
+			VisitTime:        item.TimeRecord.In(s.location).Format(exportTime),
+			PolicyNumber:     item.PolicyNumber,
+			PatientID:        item.PatientID,
+			DoctorID:         item.DoctorID,
+			SpecializationID: item.SpecializationID,
+			ServiceCode:      item.ServiceCode,
+			ServiceName:      item.ServiceName,
+			Quantity:         item.Quantity,
+			Amount:           rubles(item.Amount),
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode claim batch xml: %v", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+/// Функция exportCSV выгружает позиции реестра в CSV, первая строка - заголовок \\\
+
+func (s *service) exportCSV(batch *Batch) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(csvHeader); err != nil {
+		return nil, fmt.Errorf("failed to write claim batch csv: %v", err)
+	}
+	for _, item := range batch.Items {
+		err := writer.Write([]string{
+			strconv.FormatInt(batch.ID, 10),
+			strconv.FormatInt(batch.InsurerID, 10),
+			strconv.FormatInt(item.RecordID, 10),
+			item.Kind,
+			item.TimeRecord.In(s.location).Format(exportTime),
+			item.PolicyNumber,
+			strconv.FormatInt(item.PatientID, 10),
+			strconv.FormatInt(item.DoctorID, 10),
+			strconv.FormatInt(item.SpecializationID, 10),
+			item.ServiceCode,
+			item.ServiceName,
+			strconv.Itoa(item.Quantity),
+			rubles(item.Amount),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to write claim batch csv: %v", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write claim batch csv: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+/// Функция rubles переводит сумму в копейках в рубли с двумя знаками после точки \\\
+
+func rubles(kopecks int64) string {
+	sign := ""
+	if kopecks < 0 {
+		sign = "-"
+		kopecks = -kopecks
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, kopecks/100, kopecks%100)
+}
@@ -0,0 +1,272 @@
+package claim
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+)
+
+const (
+	claimsURL      = "/hospital_record/claims"
+	claimURL       = "/hospital_record/claims/:id"
+	claimSubmitURL = "/hospital_record/claims/:id/submit"
+	claimAcceptURL = "/hospital_record/claims/:id/accept"
+	claimRejectURL = "/hospital_record/claims/:id/reject"
+	claimExportURL = "/hospital_record/claims/:id/export"
+)
+
+/// Типы содержимого выгрузки \\\
+
+var exportContentTypes = map[string]string{
+	FormatXML: "application/xml; charset=utf-8",
+	FormatCSV: "text/csv; charset=utf-8",
+}
+
+/// Структура Handler представляющая собой обработчик объекта claimService для реестров счетов страховщикам \\\
+
+type Handler struct {
+	logger       logger.Logger
+	claimService Service
+	admin        handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, claimService Service, admin handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:       logger,
+		claimService: claimService,
+		admin:        admin,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для реестров, реестры ведет администратор \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, claimsURL, h.admin(h.CreateBatch))
+	router.HandlerFunc(http.MethodGet, claimsURL, h.admin(h.GetBatches))
+	router.HandlerFunc(http.MethodGet, claimURL, h.admin(h.GetBatch))
+	router.HandlerFunc(http.MethodDelete, claimURL, h.admin(h.DeleteBatch))
+	router.HandlerFunc(http.MethodPost, claimSubmitURL, h.admin(h.SubmitBatch))
+	router.HandlerFunc(http.MethodPost, claimAcceptURL, h.admin(h.AcceptBatch))
+	router.HandlerFunc(http.MethodPost, claimRejectURL, h.admin(h.RejectBatch))
+	router.HandlerFunc(http.MethodGet, claimExportURL, h.admin(h.ExportBatch))
+}
+
+/// Функция CreateBatch формирует черновик реестра по страховщику за период \\\
+
+func (h *Handler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE CLAIM BATCH")
+
+	/// Чтение тела запроса в структуру CreateBatchDTO \\\
+	var input CreateBatchDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	batch, err := h.claimService.Create(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("CLAIM BATCH CREATED")
+	response.JSON(w, http.StatusCreated, batch)
+}
+
+/// Функция GetBatches получает реестры, параметры insurer_id и status фильтруют список \\\
+
+func (h *Handler) GetBatches(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET CLAIM BATCHES")
+
+	query := r.URL.Query()
+	var insurerId *int64
+	if raw := query.Get("insurer_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 1 {
+			response.BadRequest(w, "insurer_id must be a positive integer", "")
+			return
+		}
+		insurerId = &id
+	}
+	status := query.Get("status")
+	switch status {
+	case "", StatusDraft, StatusSubmitted, StatusAccepted, StatusRejected:
+	default:
+		response.BadRequest(w, "unknown claim status", "")
+		return
+	}
+
+	batches, err := h.claimService.GetAll(r.Context(), insurerId, status)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT CLAIM BATCHES")
+	response.JSON(w, http.StatusOK, batches)
+}
+
+/// Функция GetBatch получает реестр с позициями \\\
+
+func (h *Handler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET CLAIM BATCH")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	batch, err := h.claimService.Get(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT CLAIM BATCH")
+	response.JSON(w, http.StatusOK, batch)
+}
+
+/// Функция DeleteBatch удаляет черновик реестра \\\
+
+func (h *Handler) DeleteBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE CLAIM BATCH")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err = h.claimService.Delete(r.Context(), id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("CLAIM BATCH DELETED")
+	response.JSON(w, http.StatusOK, "CLAIM BATCH DELETED")
+}
+
+/// Функция SubmitBatch отмечает отправку реестра страховщику \\\
+
+func (h *Handler) SubmitBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: SUBMIT CLAIM BATCH")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	batch, err := h.claimService.Submit(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("CLAIM BATCH SUBMITTED")
+	response.JSON(w, http.StatusOK, batch)
+}
+
+/// Функция AcceptBatch отмечает, что страховщик принял реестр \\\
+
+func (h *Handler) AcceptBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ACCEPT CLAIM BATCH")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	batch, err := h.claimService.Accept(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("CLAIM BATCH ACCEPTED")
+	response.JSON(w, http.StatusOK, batch)
+}
+
+/// Функция RejectBatch отмечает, что страховщик отклонил реестр, с причиной отказа \\\
+
+func (h *Handler) RejectBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: REJECT CLAIM BATCH")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру RejectBatchDTO \\\
+	var input RejectBatchDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	batch, err := h.claimService.Reject(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("CLAIM BATCH REJECTED")
+	response.JSON(w, http.StatusOK, batch)
+}
+
+/// Функция ExportBatch выгружает реестр файлом, параметр format - xml (по умолчанию) или csv \\\
+
+func (h *Handler) ExportBatch(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: EXPORT CLAIM BATCH")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = FormatXML
+	}
+
+	body, err := h.claimService.Export(r.Context(), id, format)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", exportContentTypes[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="claim-%d.%s"`, id, format))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.logger.Warnf("failed to write claim export: %v", err)
+	}
+	h.logger.Info("CLAIM BATCH EXPORTED")
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidClaim):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrEmptyClaim):
+		response.Error(w, http.StatusUnprocessableEntity, err.Error(), "")
+	case errors.Is(err, apperror.ErrClaimState):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
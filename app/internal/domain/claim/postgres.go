@@ -0,0 +1,366 @@
+package claim
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &ClaimStorage{}
+
+/// Запрос реестра с названием страховщика \\\
+
+const selectBatch = `
+SELECT b.id, b.insurer_id, i.name, b.period_from, b.period_to, b.status, b.rejection_reason, b.total,
+       b.created_at, b.submitted_at, b.decided_at
+FROM claim_batch b
+INNER JOIN insurer i ON i.id = b.insurer_id`
+
+/// Приемы пациентов страховщика за период: прием состоялся, то есть талон пациента обслужен, как и в счетах \\\
+/// и запись не входит в другой реестр, кроме отклоненных. Стоимость приема - по прейскуранту специализации \\\
+/// Прием, по счету которого пациент платил сам, страховщику не выставляется, как и процедуры такого счета \\\
+
+const insertVisits = `
+INSERT INTO claim_item (batch_id, record_id, kind, patient_id, policy_number, doctor_id, specialization_id,
+                        service_code, service_name, quantity, amount, time_record)
+SELECT $1, r.id, 'visit', p.id, p.policy_number, r.doctor_id, r.specialization_id,
+       coalesce(sp.code, ''), coalesce(sp.name, s.name_specialization), 1, coalesce(sp.amount, 0), r.time_record
+FROM record r
+INNER JOIN patients p ON p.id = r.patients_id
+INNER JOIN insurance_policy ip ON ip.number = p.policy_number
+INNER JOIN specialization s ON s.id = r.specialization_id
+LEFT JOIN service_price sp ON sp.specialization_id = r.specialization_id AND sp.active
+WHERE ip.insurer_id = $2 AND r.time_record >= $3 AND r.time_record < $4 AND r.time_record < now()
+  AND EXISTS (SELECT 1 FROM reception_ticket t WHERE t.record_id = r.id AND t.status = 'served')
+  AND NOT EXISTS (SELECT 1 FROM invoice inv
+                  INNER JOIN payment pm ON pm.invoice_id = inv.id
+                  WHERE inv.record_id = r.id AND pm.method <> 'insurance')
+  AND NOT EXISTS (SELECT 1 FROM claim_item ci
+                  INNER JOIN claim_batch cb ON cb.id = ci.batch_id
+                  WHERE ci.record_id = r.id AND cb.id <> $1 AND cb.status <> 'rejected')`
+
+/// Процедуры из счетов по приемам реестра, кроме самого приема по прейскуранту специализации \\\
+/// Выставляются только счета, полностью оплаченные страховкой: неоплаченный счет остается в балансе пациента \\\
+
+const insertProcedures = `
+INSERT INTO claim_item (batch_id, record_id, kind, patient_id, policy_number, doctor_id, specialization_id,
+                        service_code, service_name, quantity, amount, time_record)
+SELECT v.batch_id, v.record_id, 'procedure', v.patient_id, v.policy_number, v.doctor_id, v.specialization_id,
+       coalesce(sp.code, ''), ii.name, ii.quantity, ii.amount, v.time_record
+FROM claim_item v
+INNER JOIN invoice inv ON inv.record_id = v.record_id
+INNER JOIN invoice_item ii ON ii.invoice_id = inv.id
+LEFT JOIN service_price sp ON sp.id = ii.price_id
+WHERE v.batch_id = $1 AND v.kind = 'visit' AND sp.specialization_id IS NULL AND inv.status = 'paid'
+  AND NOT EXISTS (SELECT 1 FROM payment pm WHERE pm.invoice_id = inv.id AND pm.method <> 'insurance')`
+
+/// Структура ClaimStorage содержащая поля для работы с БД \\\
+
+type ClaimStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр ClaimStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &ClaimStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция Create для сущности ClaimStorage формирует реестр по страховщику из приемов в интервале [from, to) \\\
+/// Реестры страховщика формируются по очереди, чтобы одна запись не попала в два реестра \\\
+
+func (c *ClaimStorage) Create(input *CreateBatchDTO, from, to time.Time) (*Batch, error) {
+	c.logger.Info("POSTGRES: CREATE CLAIM BATCH")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	tx, err := c.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create claim batch transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Блокировка страховщика \\\
+	var insurerId int64
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM insurer WHERE id = $1 FOR UPDATE`, input.InsurerID).Scan(&insurerId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to lock insurer: %v", err)
+	}
+
+	/// Выполнение запроса к БД \\\
+	var id int64
+	err = tx.QueryRow(ctx,
+		`INSERT INTO claim_batch (insurer_id, period_from, period_to, status)
+			 VALUES($1,$2,$3,$4)
+			 RETURNING id`, insurerId, input.PeriodFrom, input.PeriodTo, StatusDraft).Scan(&id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create claim batch query: %v", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+	visits, err := tx.Exec(ctx, insertVisits, id, insurerId, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert claim visits: %v", err)
+	}
+	if visits.RowsAffected() == 0 {
+		return nil, apperror.ErrEmptyClaim
+	}
+	if _, err = tx.Exec(ctx, insertProcedures, id); err != nil {
+		return nil, fmt.Errorf("failed to insert claim procedures: %v", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE claim_batch SET total = (SELECT coalesce(sum(amount), 0) FROM claim_item WHERE batch_id = $1)
+			 WHERE id = $1`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update claim batch total: %v", err)
+	}
+	batch, err := scanBatch(tx.QueryRow(ctx, selectBatch+` WHERE b.id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read created claim batch: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create claim batch transaction: %v", err)
+	}
+	return batch, nil
+}
+
+/// Функция FindById для сущности ClaimStorage получает реестр по id без позиций \\\
+
+func (c *ClaimStorage) FindById(id int64) (*Batch, error) {
+	c.logger.Info("POSTGRES: GET CLAIM BATCH BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	batch, err := scanBatch(c.conn.QueryRow(ctx, selectBatch+` WHERE b.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find claim batch by id query: %v", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+	return batch, nil
+}
+
+/// Функция FindAll для сущности ClaimStorage получает реестры, фильтры по страховщику и статусу необязательны \\\
+
+func (c *ClaimStorage) FindAll(insurerId *int64, status string) ([]Batch, error) {
+	c.logger.Info("POSTGRES: GET CLAIM BATCHES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := c.conn.Query(ctx, selectBatch+`
+			 WHERE ($1::bigint IS NULL OR b.insurer_id = $1) AND ($2 = '' OR b.status = $2)
+			 ORDER BY b.period_from DESC, b.id DESC`, insurerId, status)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения реестров \\\
+	batches := make([]Batch, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		batch, err := scanBatch(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find claim batches query: %v", err)
+			c.logger.Error(err)
+			return nil, err
+		}
+		batches = append(batches, *batch)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+/// Функция FindItems для сущности ClaimStorage получает позиции реестра по дате приема \\\
+
+func (c *ClaimStorage) FindItems(batchId int64) ([]Item, error) {
+	c.logger.Info("POSTGRES: GET CLAIM ITEMS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := c.conn.Query(ctx,
+		`SELECT id, record_id, kind, patient_id, policy_number, doctor_id, specialization_id,
+			        service_code, service_name, quantity, amount, time_record
+			 FROM claim_item
+			 WHERE batch_id = $1
+			 ORDER BY time_record, record_id, kind DESC, id`, batchId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения позиций реестра \\\
+	items := make([]Item, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		var i Item
+		err = rows.Scan(&i.ID, &i.RecordID, &i.Kind, &i.PatientID, &i.PolicyNumber, &i.DoctorID, &i.SpecializationID,
+			&i.ServiceCode, &i.ServiceName, &i.Quantity, &i.Amount, &i.TimeRecord)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find claim items query: %v", err)
+			c.logger.Error(err)
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+/// Функция Submit для сущности ClaimStorage отправляет черновик реестра страховщику \\\
+
+func (c *ClaimStorage) Submit(id int64) (*Batch, error) {
+	c.logger.Info("POSTGRES: SUBMIT CLAIM BATCH")
+
+	return c.transition(id, StatusDraft,
+		`UPDATE claim_batch SET status = $2, submitted_at = now()
+			 WHERE id = $1`, StatusSubmitted)
+}
+
+/// Функция Decide для сущности ClaimStorage отмечает решение страховщика по отправленному реестру \\\
+
+func (c *ClaimStorage) Decide(id int64, status string, reason *string) (*Batch, error) {
+	c.logger.Info("POSTGRES: DECIDE CLAIM BATCH")
+
+	return c.transition(id, StatusSubmitted,
+		`UPDATE claim_batch SET status = $2, rejection_reason = $3, decided_at = now()
+			 WHERE id = $1`, status, reason)
+}
+
+/// Функция Delete для сущности ClaimStorage удаляет черновик реестра вместе с позициями \\\
+
+func (c *ClaimStorage) Delete(id int64) error {
+	c.logger.Info("POSTGRES: DELETE CLAIM BATCH")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	tx, err := c.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin delete claim batch transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = lockBatch(ctx, tx, id, StatusDraft); err != nil {
+		return err
+	}
+
+	/// Выполнение запроса к БД \\\
+	if _, err = tx.Exec(ctx, `DELETE FROM claim_batch WHERE id = $1`, id); err != nil {
+		err = fmt.Errorf("failed to execute delete claim batch query: %v", err)
+		c.logger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit delete claim batch transaction: %v", err)
+	}
+	return nil
+}
+
+/// Функция transition переводит реестр из статуса from запросом query, аргументы запроса - id и args \\\
+
+func (c *ClaimStorage) transition(id int64, from, query string, args ...interface{}) (*Batch, error) {
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), c.requestTimeout)
+	defer cancel()
+
+	tx, err := c.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin claim batch transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = lockBatch(ctx, tx, id, from); err != nil {
+		return nil, err
+	}
+
+	/// Выполнение запроса к БД \\\
+	if _, err = tx.Exec(ctx, query, append([]interface{}{id}, args...)...); err != nil {
+		err = fmt.Errorf("failed to execute claim batch status query: %v", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+	batch, err := scanBatch(tx.QueryRow(ctx, selectBatch+` WHERE b.id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claim batch: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit claim batch transaction: %v", err)
+	}
+	return batch, nil
+}
+
+/// Функция lockBatch блокирует реестр и проверяет, что он в статусе status \\\
+
+func lockBatch(ctx context.Context, tx pgx.Tx, id int64, status string) error {
+	var current string
+	err := tx.QueryRow(ctx,
+		`SELECT status FROM claim_batch WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrEmptyString
+		}
+		return fmt.Errorf("failed to lock claim batch: %v", err)
+	}
+	if current != status {
+		return apperror.ErrClaimState
+	}
+	return nil
+}
+
+/// Функция scanBatch сканирует строку запроса selectBatch \\\
+
+func scanBatch(row pgx.Row) (*Batch, error) {
+	batch := &Batch{}
+	err := row.Scan(&batch.ID, &batch.InsurerID, &batch.Insurer, &batch.PeriodFrom, &batch.PeriodTo, &batch.Status,
+		&batch.RejectionReason, &batch.Total, &batch.CreatedAt, &batch.SubmittedAt, &batch.DecidedAt)
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
@@ -0,0 +1,170 @@
+package claim
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы реестров счетов страховщикам \\\
+
+type Service interface {
+	Create(ctx context.Context, input *CreateBatchDTO) (*Batch, error)
+	GetAll(ctx context.Context, insurerId *int64, status string) (*[]Batch, error)
+	Get(ctx context.Context, id int64) (*Batch, error)
+	Submit(ctx context.Context, id int64) (*Batch, error)
+	Accept(ctx context.Context, id int64) (*Batch, error)
+	Reject(ctx context.Context, input *RejectBatchDTO) (*Batch, error)
+	Delete(ctx context.Context, id int64) error
+	Export(ctx context.Context, id int64, format string) ([]byte, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service реестров \\\
+
+type service struct {
+	logger   logger.Logger
+	storage  Storage
+	location *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+/// Границы периода реестра считаются в часовом поясе полисов ОМС \\\
+
+func NewService(storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:   logger,
+		storage:  storage,
+		location: time.UTC,
+	}
+	if cfg.Insurance.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Insurance.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown claim time zone %q, using UTC: %v", cfg.Insurance.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция Create формирует черновик реестра по страховщику за период с первого по последний день включительно \\\
+
+func (s *service) Create(ctx context.Context, input *CreateBatchDTO) (*Batch, error) {
+	s.logger.Info("SERVICE: CREATE CLAIM BATCH")
+
+	/// Проверка входных данных \\\
+	input.PeriodFrom = s.date(input.PeriodFrom)
+	input.PeriodTo = s.date(input.PeriodTo)
+	if input.InsurerID <= 0 || input.PeriodTo.Before(input.PeriodFrom) {
+		return nil, apperror.ErrInvalidClaim
+	}
+
+	/// Интервал приемов [начало первого дня, начало дня после последнего) по местному времени \\\
+	from := time.Date(input.PeriodFrom.Year(), input.PeriodFrom.Month(), input.PeriodFrom.Day(), 0, 0, 0, 0, s.location)
+	to := time.Date(input.PeriodTo.Year(), input.PeriodTo.Month(), input.PeriodTo.Day()+1, 0, 0, 0, 0, s.location)
+
+	batch, err := s.storage.Create(input, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(batch)
+}
+
+/// Функция GetAll возвращает реестры, фильтры по страховщику и статусу необязательны \\\
+
+func (s *service) GetAll(ctx context.Context, insurerId *int64, status string) (*[]Batch, error) {
+	s.logger.Info("SERVICE: GET CLAIM BATCHES")
+
+	batches, err := s.storage.FindAll(insurerId, status)
+	if err != nil {
+		return nil, err
+	}
+	return &batches, nil
+}
+
+/// Функция Get возвращает реестр с позициями \\\
+
+func (s *service) Get(ctx context.Context, id int64) (*Batch, error) {
+	s.logger.Info("SERVICE: GET CLAIM BATCH")
+
+	batch, err := s.storage.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.load(batch)
+}
+
+/// Функция Submit отмечает, что черновик реестра отправлен страховщику \\\
+
+func (s *service) Submit(ctx context.Context, id int64) (*Batch, error) {
+	s.logger.Info("SERVICE: SUBMIT CLAIM BATCH")
+
+	return s.storage.Submit(id)
+}
+
+/// Функция Accept отмечает, что страховщик принял отправленный реестр \\\
+
+func (s *service) Accept(ctx context.Context, id int64) (*Batch, error) {
+	s.logger.Info("SERVICE: ACCEPT CLAIM BATCH")
+
+	return s.storage.Decide(id, StatusAccepted, nil)
+}
+
+/// Функция Reject отмечает, что страховщик отклонил отправленный реестр, причина обязательна \\\
+
+func (s *service) Reject(ctx context.Context, input *RejectBatchDTO) (*Batch, error) {
+	s.logger.Info("SERVICE: REJECT CLAIM BATCH")
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, apperror.ErrInvalidClaim
+	}
+	return s.storage.Decide(input.ID, StatusRejected, &reason)
+}
+
+/// Функция Delete удаляет черновик реестра, его записи можно включить в новый реестр \\\
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	s.logger.Info("SERVICE: DELETE CLAIM BATCH")
+
+	return s.storage.Delete(id)
+}
+
+/// Функция Export выгружает реестр с позициями в XML или CSV для загрузки на портал страховщика \\\
+
+func (s *service) Export(ctx context.Context, id int64, format string) ([]byte, error) {
+	s.logger.Info("SERVICE: EXPORT CLAIM BATCH")
+
+	if format != FormatXML && format != FormatCSV {
+		return nil, apperror.ErrInvalidClaim
+	}
+	batch, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatCSV {
+		return s.exportCSV(batch)
+	}
+	return s.exportXML(batch)
+}
+
+/// Функция load заполняет позиции реестра \\\
+
+func (s *service) load(batch *Batch) (*Batch, error) {
+	items, err := s.storage.FindItems(batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	batch.Items = items
+	return batch, nil
+}
+
+/// Функция date возвращает дату t по местному времени, даты периода хранятся без часового пояса \\\
+
+func (s *service) date(t time.Time) time.Time {
+	year, month, day := t.In(s.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
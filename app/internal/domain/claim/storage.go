@@ -0,0 +1,13 @@
+package claim
+
+import "time"
+
+type Storage interface {
+	Create(input *CreateBatchDTO, from, to time.Time) (*Batch, error)
+	FindById(id int64) (*Batch, error)
+	FindAll(insurerId *int64, status string) ([]Batch, error)
+	FindItems(batchId int64) ([]Item, error)
+	Submit(id int64) (*Batch, error)
+	Decide(id int64, status string, reason *string) (*Batch, error)
+	Delete(id int64) error
+}
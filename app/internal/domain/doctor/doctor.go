@@ -13,6 +13,7 @@ type Doctor struct {
 	Age                  int8    `json:"age" example:"28"`
 	RecordingIsAvailable bool    `json:"recording_is_available" example:"true"`
 	SpecializationID     int64   `json:"specialization_id" example:"1567"`
+	SpecializationIDs    []int64 `json:"specialization_ids" example:"1567,1568"`
 	PortfolioID          int64   `json:"portfolio_id" example:"1567"`
 }
 
@@ -22,10 +23,10 @@ type CreateDoctorDTO struct {
 	Patronymic           *string `json:"patronymic,omitempty" example:"Semenovich"`
 	ImageID              string  `json:"image_id" example:"1567"`
 	Gender               string  `json:"gender" example:"male"`
-	Rating               float32 `json:"rating" example:"4"`
 	Age                  int8    `json:"age" example:"28"`
 	RecordingIsAvailable bool    `json:"recording_is_available" example:"true"`
 	SpecializationID     int64   `json:"specialization_id" example:"1567"`
+	SpecializationIDs    []int64 `json:"specialization_ids" example:"1567,1568"`
 	PortfolioID          int64   `json:"portfolio_id" example:"1567"`
 }
 
@@ -36,16 +37,29 @@ type UpdateDoctorDTO struct {
 	Patronymic           *string `json:"patronymic" example:"Semenovich"`
 	ImageID              string  `json:"image_id" example:"1567"`
 	Gender               string  `json:"gender" example:"male"`
-	Rating               float32 `json:"rating" example:"4"`
 	Age                  uint8   `json:"age" example:"28"`
 	RecordingIsAvailable bool    `json:"recording_is_available" example:"true"`
 	SpecializationID     int64   `json:"specialization_id" example:"1567"`
+	SpecializationIDs    []int64 `json:"specialization_ids" example:"1567,1568"`
 	PortfolioID          int64   `json:"portfolio_id" example:"1567"`
 }
 
 type PartiallyUpdateDoctorDTO struct {
-	ID                   int64    `json:"id"`
-	ImageID              *string  `json:"image_id" example:"1567"`
-	Rating               *float32 `json:"rating" example:"4"`
-	RecordingIsAvailable *bool    `json:"recording_is_available" example:"true"`
+	ID                   int64   `json:"id"`
+	ImageID              *string `json:"image_id" example:"1567"`
+	RecordingIsAvailable *bool   `json:"recording_is_available" example:"true"`
+}
+
+/// Функция HasSpecialization проверяет, что у доктора есть специализация с данным id \\\
+
+func (d *Doctor) HasSpecialization(id int64) bool {
+	if d.SpecializationID == id {
+		return true
+	}
+	for _, s := range d.SpecializationIDs {
+		if s == id {
+			return true
+		}
+	}
+	return false
 }
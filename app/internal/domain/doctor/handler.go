@@ -8,9 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/julienschmidt/httprouter"
-	"io"
 	"net/http"
-	"os"
 )
 
 const (
@@ -19,7 +17,6 @@ const (
 	doctorsAvailableURL    = "/hospital_record/doctors/available/:id"
 	doctorURL              = "/hospital_record/doctors/profile/:id"
 	doctorByPortfolioIdURL = "/hospital_record/doctors/portfolio/:id"
-	doctorImageURL         = "/hospital_record/doctor/image"
 )
 
 /// Структура Handler представляющая собой обработчик объекта doctorService для докторов \\\
@@ -46,7 +43,6 @@ func (h *Handler) Register(router *httprouter.Router) {
 	router.HandlerFunc(http.MethodGet, doctorsAllURL, h.FindAllDoctors)
 	router.HandlerFunc(http.MethodGet, doctorsAvailableURL, h.FindAllAvailableDoctors)
 	router.HandlerFunc(http.MethodPost, doctorsURL, h.CreateDoctor)
-	router.HandlerFunc(http.MethodPost, doctorImageURL, h.CreateImage)
 	router.HandlerFunc(http.MethodPut, doctorURL, h.UpdateDoctor)
 	router.HandlerFunc(http.MethodPatch, doctorURL, h.PartiallyUpdateDoctor)
 	router.HandlerFunc(http.MethodDelete, doctorURL, h.DeleteDoctor)
@@ -144,38 +140,6 @@ func (h *Handler) FindAllAvailableDoctors(w http.ResponseWriter, r *http.Request
 	response.JSON(w, http.StatusOK, doctors)
 }
 
-/// Функция CreateImage создает изображение доктора в папке doctorimages \\\
-
-func (h *Handler) CreateImage(w http.ResponseWriter, r *http.Request) {
-	h.logger.Info("HANDLER: CREATE DOCTOR IMAGE")
-
-	/// Принимает объект r типа form-data \\\
-	file, header, err := r.FormFile("image")
-	if err != nil {
-		response.BadRequest(w, err.Error(), "")
-		return
-	}
-	defer file.Close()
-
-	/// Создаем путь и новый файл для сохранения изображений \\\
-	imagePath := "./doctorimages/" + header.Filename
-	out, err := os.Create(imagePath)
-	if err != nil {
-		response.InternalError(w, fmt.Sprintf("error saving image: %v", err), "")
-		return
-	}
-	defer out.Close()
-
-	/// Копируем загруженный файл в новый созданный файл на севрере по созданному путю \\\
-	_, err = io.Copy(out, file)
-	if err != nil {
-		response.InternalError(w, fmt.Sprintf("error coping image: %v", err), "")
-		return
-	}
-	h.logger.Info("DOCTOR IMAGE CREATED")
-	response.JSON(w, http.StatusCreated, "IMAGE CREATED")
-}
-
 /// Функция CreateDoctor создает доктора по полученным данным из input \\\
 
 func (h *Handler) CreateDoctor(w http.ResponseWriter, r *http.Request) {
@@ -193,7 +157,7 @@ func (h *Handler) CreateDoctor(w http.ResponseWriter, r *http.Request) {
 	/// Вызов функции Create передавая ей полученные значения и ссылку на структуру input \\\
 	doctor, err := h.doctorService.Create(r.Context(), &input)
 	if err != nil {
-		if errors.Is(err, apperror.ErrRepeatedPortfolioId) {
+		if errors.Is(err, apperror.ErrRepeatedPortfolioId) || errors.Is(err, apperror.ErrNoSpecialization) {
 			response.BadRequest(w, err.Error(), "")
 			return
 		}
@@ -233,6 +197,12 @@ func (h *Handler) UpdateDoctor(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrNoSpecialization) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
 	}
 	h.logger.Info("DOCTOR UPDATED")
 	response.JSON(w, http.StatusOK, "DOCTOR UPDATED")
@@ -7,23 +7,29 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"strings"
 	"time"
 )
 
 var _ Storage = &DoctorStorage{}
 
+/// Запрос докторов вместе со всеми их специализациями, основная специализация хранится в doctors.specialization_id \\\
+
+const selectDoctors = `SELECT d.*, ARRAY(SELECT ds.specialization_id FROM doctor_specialization ds
+			 WHERE ds.doctor_id = d.id ORDER BY ds.specialization_id) FROM doctors d`
+
 /// Структура DoctorStorage содержащая поля для работы с БД \\\
 
 type DoctorStorage struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 /// Структура NewStorage возвращает новый экземпляр DoctorStorage инициализируя переданные в него аргументы \\\
 
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &DoctorStorage{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -40,20 +46,36 @@ func (d *DoctorStorage) Create(doctor *Doctor) (*Doctor, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create doctor transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	/// Выполнение запроса к БД \\\
-	row := d.conn.QueryRow(ctx,
+	row := tx.QueryRow(ctx,
 		`INSERT INTO doctors (name, surname, image_id, gender, rating, age,recording_is_available, specialization_id, portfolio_id)
 			 VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9) 
 			 RETURNING id`,
 		doctor.Name, doctor.Surname, doctor.ImageID, doctor.Gender, doctor.Rating, doctor.Age, doctor.RecordingIsAvailable, doctor.SpecializationID, doctor.PortfolioID)
 
 	/// Сканирование полученных значений из БД \\\
-	err := row.Scan(&doctor.ID)
+	err = row.Scan(&doctor.ID)
 	if err != nil {
 		err = fmt.Errorf("failed to execute create doctor query: %v", err)
 		d.logger.Error(err)
 		return nil, err
 	}
+
+	/// Сохранение всех специализаций доктора \\\
+	if err = insertSpecializations(ctx, tx, doctor.ID, doctor.SpecializationIDs); err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create doctor transaction: %v", err)
+	}
 	return doctor, nil
 }
 
@@ -68,7 +90,7 @@ func (d *DoctorStorage) FindAll() ([]Doctor, error) {
 
 	/// Выполнение запроса к БД \\\
 	rows, err := d.conn.Query(ctx,
-		`SELECT * FROM doctors`)
+		selectDoctors)
 	if err != nil {
 		err = fmt.Errorf("failed to SELLECT: %v", err)
 		d.logger.Error(err)
@@ -86,6 +108,7 @@ func (d *DoctorStorage) FindAll() ([]Doctor, error) {
 		err = rows.Scan(
 			&doctor.ID, &doctor.Name, &doctor.Surname, &doctor.Patronymic, &doctor.ImageID, &doctor.Gender,
 			&doctor.Rating, &doctor.Age, &doctor.RecordingIsAvailable, &doctor.SpecializationID, &doctor.PortfolioID,
+			&doctor.SpecializationIDs,
 		)
 
 		if err != nil {
@@ -114,8 +137,9 @@ func (d *DoctorStorage) FindAllAvailable(id int64, recordingIsAvailable bool) ([
 
 	/// Выполнение запроса к БД \\\
 	rows, err := d.conn.Query(ctx,
-		`SELECT * FROM doctors
-			 WHERE specialization_id=$1 AND recording_is_available=$2`,
+		selectDoctors+`
+			 WHERE d.recording_is_available=$2 AND EXISTS (SELECT 1 FROM doctor_specialization ds
+			 WHERE ds.doctor_id = d.id AND ds.specialization_id=$1)`,
 		id, recordingIsAvailable)
 	if err != nil {
 		err = fmt.Errorf("failed to SELLECT: %v", err)
@@ -135,6 +159,7 @@ func (d *DoctorStorage) FindAllAvailable(id int64, recordingIsAvailable bool) ([
 		err = rows.Scan(
 			&doctor.ID, &doctor.Name, &doctor.Surname, &doctor.Patronymic, &doctor.ImageID, &doctor.Gender,
 			&doctor.Rating, &doctor.Age, &doctor.RecordingIsAvailable, &doctor.SpecializationID, &doctor.PortfolioID,
+			&doctor.SpecializationIDs,
 		)
 
 		if err != nil {
@@ -163,8 +188,8 @@ func (d *DoctorStorage) FindByPortfolioId(id int64) (*Doctor, error) {
 
 	/// Выполнение запроса к БД \\\
 	row := d.conn.QueryRow(ctx,
-		`SELECT * FROM doctors
-			 WHERE portfolio_id = $1`, id)
+		selectDoctors+`
+			 WHERE d.portfolio_id = $1`, id)
 
 	doctor := &Doctor{}
 
@@ -172,6 +197,7 @@ func (d *DoctorStorage) FindByPortfolioId(id int64) (*Doctor, error) {
 	err := row.Scan(
 		&doctor.ID, &doctor.Name, &doctor.Surname, &doctor.Patronymic, &doctor.ImageID, &doctor.Gender,
 		&doctor.Rating, &doctor.Age, &doctor.RecordingIsAvailable, &doctor.SpecializationID, &doctor.PortfolioID,
+		&doctor.SpecializationIDs,
 	)
 
 	if err != nil {
@@ -198,8 +224,8 @@ func (d *DoctorStorage) FindById(id int64) (*Doctor, error) {
 
 	/// Выполнение запроса к БД \\\
 	row := d.conn.QueryRow(ctx,
-		`SELECT * FROM doctors
-			 WHERE id = $1`, id)
+		selectDoctors+`
+			 WHERE d.id = $1`, id)
 
 	doctor := &Doctor{}
 
@@ -208,7 +234,7 @@ func (d *DoctorStorage) FindById(id int64) (*Doctor, error) {
 		&doctor.ID, &doctor.Name, &doctor.Surname, &doctor.Patronymic,
 		&doctor.ImageID, &doctor.Gender, &doctor.Rating, &doctor.Age,
 		&doctor.RecordingIsAvailable, &doctor.SpecializationID,
-		&doctor.PortfolioID,
+		&doctor.PortfolioID, &doctor.SpecializationIDs,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -223,6 +249,7 @@ func (d *DoctorStorage) FindById(id int64) (*Doctor, error) {
 }
 
 /// Функция Update для сущности DoctorStorage обновляет записи о докторе в БД \\\
+/// Если передан список специализаций, основная специализация и все связи заменяются в той же транзакции \\\
 
 func (d *DoctorStorage) Update(doctor *UpdateDoctorDTO) error {
 	d.logger.Info("POSTGRES: UPDATE DOCTOR")
@@ -231,12 +258,18 @@ func (d *DoctorStorage) Update(doctor *UpdateDoctorDTO) error {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin update doctor transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	/// Выполнение запроса к БД \\\
-	result, err := d.conn.Exec(ctx,
+	result, err := tx.Exec(ctx,
 		`UPDATE doctors
-			SET patronymic=$1, image_id=$2, rating=$3, age=$4, recording_is_available=$5
-			WHERE id =$6`,
-		doctor.Patronymic, doctor.ImageID, doctor.Rating, doctor.Age, doctor.RecordingIsAvailable, doctor.ID)
+			SET patronymic=$1, image_id=$2, age=$3, recording_is_available=$4
+			WHERE id =$5`,
+		doctor.Patronymic, doctor.ImageID, doctor.Age, doctor.RecordingIsAvailable, doctor.ID)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -250,6 +283,44 @@ func (d *DoctorStorage) Update(doctor *UpdateDoctorDTO) error {
 	if result.RowsAffected() == 0 {
 		return apperror.ErrEmptyString
 	}
+
+	/// Замена специализаций доктора \\\
+	if len(doctor.SpecializationIDs) > 0 {
+		_, err = tx.Exec(ctx,
+			`UPDATE doctors SET specialization_id=$1 WHERE id=$2`, doctor.SpecializationID, doctor.ID)
+		if err != nil {
+			err = fmt.Errorf("failed to update primary specialization: %v", err)
+			d.logger.Error(err)
+			return err
+		}
+		_, err = tx.Exec(ctx,
+			`DELETE FROM doctor_specialization WHERE doctor_id=$1`, doctor.ID)
+		if err != nil {
+			err = fmt.Errorf("failed to delete doctor specializations: %v", err)
+			d.logger.Error(err)
+			return err
+		}
+		if err = insertSpecializations(ctx, tx, doctor.ID, doctor.SpecializationIDs); err != nil {
+			d.logger.Error(err)
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update doctor transaction: %v", err)
+	}
+	return nil
+}
+
+/// Функция insertSpecializations сохраняет связи доктора со специализациями \\\
+
+func insertSpecializations(ctx context.Context, tx pgx.Tx, doctorId int64, specializationIds []int64) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO doctor_specialization (doctor_id, specialization_id)
+			 SELECT $1, unnest($2::bigint[])`, doctorId, specializationIds)
+	if err != nil {
+		return fmt.Errorf("failed to insert doctor specializations: %v", err)
+	}
 	return nil
 }
 
@@ -271,11 +342,6 @@ func (d *DoctorStorage) PartiallyUpdate(doctor *PartiallyUpdateDoctorDTO) error
 		args = append(args, *doctor.ImageID)
 		argId++
 	}
-	if doctor.Rating != nil {
-		values = append(values, fmt.Sprintf("rating=$%d", argId))
-		args = append(args, *doctor.Rating)
-		argId++
-	}
 	if doctor.RecordingIsAvailable != nil {
 		values = append(values, fmt.Sprintf("recording_is_available=$%d", argId))
 		args = append(args, *doctor.RecordingIsAvailable)
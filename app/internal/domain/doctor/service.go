@@ -53,16 +53,22 @@ func (s *service) Create(ctx context.Context, input *CreateDoctorDTO) (*Doctor,
 		return nil, apperror.ErrRepeatedPortfolioId
 	}
 
+	/// Основная специализация всегда входит в список специализаций доктора \\\
+	primary, specializations, err := normalizeSpecializations(input.SpecializationID, input.SpecializationIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	/// Создание структуры doc на основе полученных данных \\\
 	doc := Doctor{
 		Name:                 input.Name,
 		Surname:              input.Surname,
 		ImageID:              input.ImageID,
 		Gender:               input.Gender,
-		Rating:               input.Rating,
 		Age:                  input.Age,
 		RecordingIsAvailable: input.RecordingIsAvailable,
-		SpecializationID:     input.SpecializationID,
+		SpecializationID:     primary,
+		SpecializationIDs:    specializations,
 		PortfolioID:          input.PortfolioID,
 	}
 
@@ -149,7 +155,7 @@ func (s *service) Update(ctx context.Context, doctor *UpdateDoctorDTO) error {
 	s.logger.Info("SERVICE: UPDATE DOCTOR")
 
 	/// Вызов функции FindById в хранилище докторов \\\
-	_, err := s.storage.FindById(doctor.ID)
+	current, err := s.storage.FindById(doctor.ID)
 	if err != nil {
 		if !errors.Is(err, apperror.ErrEmptyString) {
 			s.logger.Errorf("failed to get doctor: %v", err)
@@ -157,6 +163,18 @@ func (s *service) Update(ctx context.Context, doctor *UpdateDoctorDTO) error {
 		return err
 	}
 
+	/// Новая основная специализация без списка добавляется к уже имеющимся специализациям \\\
+	if doctor.SpecializationID != 0 || len(doctor.SpecializationIDs) > 0 {
+		specializations := doctor.SpecializationIDs
+		if len(specializations) == 0 {
+			specializations = current.SpecializationIDs
+		}
+		doctor.SpecializationID, doctor.SpecializationIDs, err = normalizeSpecializations(doctor.SpecializationID, specializations)
+		if err != nil {
+			return err
+		}
+	}
+
 	/// Вызов функции Update в хранилище докторов \\\
 	err = s.storage.Update(doctor)
 	if err != nil {
@@ -204,3 +222,28 @@ func (s *service) Delete(id int64) error {
 	}
 	return nil
 }
+
+/// Функция normalizeSpecializations определяет основную специализацию и убирает повторы из списка \\\
+/// Без явной основной специализации ей становится первая из списка \\\
+
+func normalizeSpecializations(primary int64, ids []int64) (int64, []int64, error) {
+	if primary == 0 && len(ids) > 0 {
+		primary = ids[0]
+	}
+	if primary < 1 {
+		return 0, nil, apperror.ErrNoSpecialization
+	}
+
+	specializations := []int64{primary}
+	seen := map[int64]bool{primary: true}
+	for _, id := range ids {
+		if id < 1 {
+			return 0, nil, apperror.ErrNoSpecialization
+		}
+		if !seen[id] {
+			seen[id] = true
+			specializations = append(specializations, id)
+		}
+	}
+	return primary, specializations, nil
+}
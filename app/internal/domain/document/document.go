@@ -0,0 +1,16 @@
+package document
+
+import "time"
+
+/// Структура рецепта для печати: назначение препарата по заболеванию с инструкцией \\\
+
+type Prescription struct {
+	ID                 int64
+	CreatedAt          time.Time
+	Disease            string
+	Quantity           int16
+	Medication         string
+	Interchangeability *string
+	Appointed          string
+	Instruction        string
+}
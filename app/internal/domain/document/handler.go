@@ -0,0 +1,117 @@
+package document
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	appointmentCardURL = "/hospital_record/records/:id/card.pdf"
+	prescriptionURL    = "/hospital_record/prescriptions/:id/prescription.pdf"
+	pdfContentType     = "application/pdf"
+)
+
+/// Структура Handler представляющая собой обработчик объекта documentService для печатных документов \\\
+
+type Handler struct {
+	logger          logger.Logger
+	documentService Service
+	authorize       handler.Middleware
+	staff           handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, documentService Service, authorize, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:          logger,
+		documentService: documentService,
+		authorize:       authorize,
+		staff:           staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для печатных документов \\\
+/// Талон пациент получает по токену доступа только на свою запись, рецепты печатают сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, appointmentCardURL, h.authorize(h.GetAppointmentCard))
+	router.HandlerFunc(http.MethodGet, prescriptionURL, h.staff(h.GetPrescription))
+}
+
+/// Функция GetAppointmentCard отдает талон на прием авторизованного пациента в PDF \\\
+
+func (h *Handler) GetAppointmentCard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET APPOINTMENT CARD PDF")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	body, err := h.documentService.AppointmentCard(r.Context(), user.ID, id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writePDF(w, fmt.Sprintf("card-%d.pdf", id), body)
+	h.logger.Info("GOT APPOINTMENT CARD PDF")
+}
+
+/// Функция GetPrescription отдает рецепт в PDF \\\
+
+func (h *Handler) GetPrescription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PRESCRIPTION PDF")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	body, err := h.documentService.Prescription(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writePDF(w, fmt.Sprintf("prescription-%d.pdf", id), body)
+	h.logger.Info("GOT PRESCRIPTION PDF")
+}
+
+/// Функция writePDF отдает документ для просмотра в браузере с именем файла для сохранения \\\
+
+func (h *Handler) writePDF(w http.ResponseWriter, filename string, body []byte) {
+	w.Header().Set("Content-Type", pdfContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.logger.Warnf("failed to write pdf: %v", err)
+	}
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
@@ -0,0 +1,63 @@
+package document
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &DocumentStorage{}
+
+/// Структура DocumentStorage содержащая поля для работы с БД \\\
+
+type DocumentStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр DocumentStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &DocumentStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция FindPrescription для сущности DocumentStorage получает рецепт по id из представления prescription_pacient \\\
+
+func (d *DocumentStorage) FindPrescription(id int64) (*Prescription, error) {
+	d.logger.Info("POSTGRES: GET PRESCRIPTION")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := d.conn.QueryRow(ctx,
+		`SELECT prescription_id, created_at, name_disease, quanyity_medicat, name, interchangeability, appointed, instruction
+			 FROM prescription_pacient
+			 WHERE prescription_id = $1`, id)
+
+	prescription := &Prescription{}
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&prescription.ID, &prescription.CreatedAt, &prescription.Disease, &prescription.Quantity,
+		&prescription.Medication, &prescription.Interchangeability, &prescription.Appointed, &prescription.Instruction)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find prescription query: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	return prescription, nil
+}
@@ -0,0 +1,121 @@
+package document
+
+import (
+	"HospitalRecord/app/internal/domain/readmodel"
+	"HospitalRecord/app/pkg/pdf"
+	"bytes"
+	"fmt"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"strconv"
+	"time"
+)
+
+/// Разметка печатной формы в пунктах: поля страницы, колонка подписей и шаг строки \\\
+
+const (
+	fontRegular = "regular"
+	fontBold    = "bold"
+	margin      = 56.0
+	labelWidth  = 150.0
+	lineHeight  = 16.0
+	dateFormat  = "02.01.2006"
+	timeFormat  = "02.01.2006 15:04"
+)
+
+/// Структура form заполняет страницу строками "подпись: значение" сверху вниз \\\
+
+type form struct {
+	doc *pdf.Document
+	y   float64
+}
+
+/// Функция newForm начинает документ со шрифтами Go, в которых есть кириллица, и печатает заголовок с названием клиники \\\
+
+func newForm(title, clinic string) (*form, error) {
+	doc := pdf.New()
+	if err := doc.AddFont(fontRegular, goregular.TTF); err != nil {
+		return nil, err
+	}
+	if err := doc.AddFont(fontBold, gobold.TTF); err != nil {
+		return nil, err
+	}
+	doc.AddPage()
+	f := &form{doc: doc, y: margin + 18}
+
+	doc.SetFont(fontBold, 18)
+	doc.Text(margin, f.y, title)
+	f.y += lineHeight
+	doc.SetFont(fontRegular, 10)
+	doc.Text(margin, f.y, clinic)
+	f.y += lineHeight / 2
+	doc.Line(margin, f.y, pdf.PageWidth-margin, f.y)
+	f.y += lineHeight * 1.5
+	return f, nil
+}
+
+/// Функция row печатает подпись и значение, длинное значение переносится по ширине колонки \\\
+
+func (f *form) row(label, value string) {
+	f.doc.SetFont(fontBold, 11)
+	f.doc.Text(margin, f.y, label)
+	f.doc.SetFont(fontRegular, 11)
+	for _, line := range f.doc.Wrap(value, pdf.PageWidth-2*margin-labelWidth) {
+		f.doc.Text(margin+labelWidth, f.y, line)
+		f.y += lineHeight
+	}
+}
+
+/// Функция bytes печатает подвал с временем формирования и возвращает файл \\\
+
+func (f *form) bytes(now time.Time) ([]byte, error) {
+	f.y += lineHeight
+	f.doc.Line(margin, f.y, pdf.PageWidth-margin, f.y)
+	f.y += lineHeight
+	f.doc.SetFont(fontRegular, 9)
+	f.doc.Text(margin, f.y, "Сформировано "+now.Format(timeFormat))
+
+	var buf bytes.Buffer
+	if err := f.doc.Write(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render pdf: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+/// Функция renderCard печатает талон на прием \\\
+
+func (s *service) renderCard(card *readmodel.AppointmentCard) ([]byte, error) {
+	f, err := newForm("Талон на прием № "+strconv.FormatInt(card.RecordID, 10), s.clinicName)
+	if err != nil {
+		return nil, err
+	}
+	f.row("Пациент", card.PatientName)
+	f.row("Врач", card.DoctorName)
+	f.row("Специализация", card.Specialization)
+	f.row("Дата и время", card.TimeRecord.In(s.location).Format(timeFormat))
+	f.row("Адрес", card.HospitalAddress)
+	f.row("Кабинет", card.DoctorOffice)
+	if card.Tagging != "" {
+		f.row("Примечание", card.Tagging)
+	}
+	return f.bytes(time.Now().In(s.location))
+}
+
+/// Функция renderPrescription печатает рецепт \\\
+
+func (s *service) renderPrescription(prescription *Prescription) ([]byte, error) {
+	f, err := newForm("Рецепт № "+strconv.FormatInt(prescription.ID, 10), s.clinicName)
+	if err != nil {
+		return nil, err
+	}
+	f.row("Дата выдачи", prescription.CreatedAt.In(s.location).Format(dateFormat))
+	f.row("Заболевание", prescription.Disease)
+	f.row("Препарат", prescription.Medication)
+	f.row("Количество", strconv.Itoa(int(prescription.Quantity)))
+	if prescription.Interchangeability != nil && *prescription.Interchangeability != "" {
+		f.row("Замена", *prescription.Interchangeability)
+	}
+	f.row("Назначил", prescription.Appointed)
+	f.row("Применение", prescription.Instruction)
+	return f.bytes(time.Now().In(s.location))
+}
@@ -0,0 +1,71 @@
+package document
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/readmodel"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для печатных документов \\\
+
+type Service interface {
+	AppointmentCard(ctx context.Context, patientId, recordId int64) ([]byte, error)
+	Prescription(ctx context.Context, id int64) ([]byte, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service печатных документов \\\
+
+type service struct {
+	logger     logger.Logger
+	storage    Storage
+	cards      readmodel.Service
+	clinicName string
+	location   *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(cards readmodel.Service, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:     logger,
+		storage:    storage,
+		cards:      cards,
+		clinicName: cfg.Documents.ClinicName,
+		location:   time.UTC,
+	}
+	if cfg.Documents.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Documents.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown documents time zone %q, using UTC: %v", cfg.Documents.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция AppointmentCard возвращает талон на прием в PDF, чужая запись не отличается от несуществующей \\\
+
+func (s *service) AppointmentCard(ctx context.Context, patientId, recordId int64) ([]byte, error) {
+	s.logger.Info("SERVICE: GET APPOINTMENT CARD PDF")
+
+	card, err := s.cards.GetAppointmentCard(ctx, patientId, recordId)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderCard(card)
+}
+
+/// Функция Prescription возвращает рецепт в PDF \\\
+
+func (s *service) Prescription(ctx context.Context, id int64) ([]byte, error) {
+	s.logger.Info("SERVICE: GET PRESCRIPTION PDF")
+
+	prescription, err := s.storage.FindPrescription(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.renderPrescription(prescription)
+}
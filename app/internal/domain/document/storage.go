@@ -0,0 +1,5 @@
+package document
+
+type Storage interface {
+	FindPrescription(id int64) (*Prescription, error)
+}
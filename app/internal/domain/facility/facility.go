@@ -0,0 +1,149 @@
+package facility
+
+import (
+	"regexp"
+	"time"
+)
+
+/// Время работы и приема задается в виде 15:04, дни недели от 1 (понедельник) до 7 (воскресенье) \\\
+
+var clockRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+/// Структура часов работы больницы в один день недели \\\
+
+type WorkingHours struct {
+	Weekday int    `json:"weekday" example:"1"`
+	Opens   string `json:"opens" example:"08:00"`
+	Closes  string `json:"closes" example:"20:00"`
+}
+
+/// Структура больницы: адрес, контакты и часы работы \\\
+
+type Hospital struct {
+	ID           int64          `json:"id" example:"1"`
+	Name         string         `json:"name" example:"Gorodskaya poliklinika 1"`
+	Address      string         `json:"address" example:"Roterta, dom 12"`
+	Phone        *string        `json:"phone,omitempty" example:"+74950000000"`
+	Email        *string        `json:"email,omitempty" example:"info@hospital.ru"`
+	WorkingHours []WorkingHours `json:"working_hours"`
+}
+
+/// Структура отделения больницы, отделение может вести прием по одной специализации \\\
+
+type Department struct {
+	ID               int64   `json:"id" example:"1"`
+	HospitalID       int64   `json:"hospital_id" example:"1"`
+	Name             string  `json:"name" example:"Oftalmologiya"`
+	SpecializationID *int64  `json:"specialization_id,omitempty" example:"1"`
+	Phone            *string `json:"phone,omitempty" example:"+74950000001"`
+}
+
+/// Структура кабинета отделения, адрес больницы берется из справочника \\\
+
+type Office struct {
+	ID              int64   `json:"id" example:"1"`
+	DepartmentID    int64   `json:"department_id" example:"1"`
+	Number          string  `json:"number" example:"201B"`
+	Floor           *int    `json:"floor,omitempty" example:"2"`
+	Name            *string `json:"name,omitempty" example:"Kabinet proverki zreniya"`
+	HospitalID      int64   `json:"hospital_id" example:"1"`
+	HospitalAddress string  `json:"hospital_address" example:"Roterta, dom 12"`
+}
+
+/// Структура расписания доктора в кабинете: день недели, часы приема и срок действия \\\
+
+type Schedule struct {
+	ID        int64      `json:"id" example:"1"`
+	OfficeID  int64      `json:"office_id" example:"1"`
+	DoctorID  int64      `json:"doctor_id" example:"1"`
+	Weekday   int        `json:"weekday" example:"1"`
+	StartsAt  string     `json:"starts_at" example:"09:00"`
+	EndsAt    string     `json:"ends_at" example:"15:00"`
+	ValidFrom time.Time  `json:"valid_from" example:"2023-07-01T00:00:00Z"`
+	ValidTo   *time.Time `json:"valid_to,omitempty" example:"2023-12-31T00:00:00Z"`
+}
+
+type CreateHospitalDTO struct {
+	Name         string         `json:"name" example:"Gorodskaya poliklinika 1"`
+	Address      string         `json:"address" example:"Roterta, dom 12"`
+	Phone        *string        `json:"phone,omitempty" example:"+74950000000"`
+	Email        *string        `json:"email,omitempty" example:"info@hospital.ru"`
+	WorkingHours []WorkingHours `json:"working_hours"`
+}
+
+/// Смена адреса больницы переносится в записи на прием в ее кабинеты \\\
+
+type PartiallyUpdateHospitalDTO struct {
+	ID           int64           `json:"-"`
+	Name         *string         `json:"name,omitempty" example:"Gorodskaya poliklinika 1"`
+	Address      *string         `json:"address,omitempty" example:"Roterta, dom 12"`
+	Phone        *string         `json:"phone,omitempty" example:"+74950000000"`
+	Email        *string         `json:"email,omitempty" example:"info@hospital.ru"`
+	WorkingHours *[]WorkingHours `json:"working_hours,omitempty"`
+}
+
+type CreateDepartmentDTO struct {
+	HospitalID       int64   `json:"-"`
+	Name             string  `json:"name" example:"Oftalmologiya"`
+	SpecializationID *int64  `json:"specialization_id,omitempty" example:"1"`
+	Phone            *string `json:"phone,omitempty" example:"+74950000001"`
+}
+
+type CreateOfficeDTO struct {
+	DepartmentID int64   `json:"-"`
+	Number       string  `json:"number" example:"201B"`
+	Floor        *int    `json:"floor,omitempty" example:"2"`
+	Name         *string `json:"name,omitempty" example:"Kabinet proverki zreniya"`
+}
+
+/// Смена номера кабинета переносится в записи на прием в этот кабинет \\\
+
+type PartiallyUpdateOfficeDTO struct {
+	ID     int64   `json:"-"`
+	Number *string `json:"number,omitempty" example:"201B"`
+	Floor  *int    `json:"floor,omitempty" example:"2"`
+	Name   *string `json:"name,omitempty" example:"Kabinet proverki zreniya"`
+}
+
+type CreateScheduleDTO struct {
+	OfficeID  int64      `json:"-"`
+	DoctorID  int64      `json:"doctor_id" example:"1"`
+	Weekday   int        `json:"weekday" example:"1"`
+	StartsAt  string     `json:"starts_at" example:"09:00"`
+	EndsAt    string     `json:"ends_at" example:"15:00"`
+	ValidFrom *time.Time `json:"valid_from,omitempty" example:"2023-07-01T00:00:00Z"`
+	ValidTo   *time.Time `json:"valid_to,omitempty" example:"2023-12-31T00:00:00Z"`
+}
+
+/// Функция validHours проверяет часы работы: известный день недели, открытие раньше закрытия, один интервал на день \\\
+
+func validHours(hours []WorkingHours) bool {
+	seen := make(map[int]bool, len(hours))
+	for _, h := range hours {
+		if !validWeekday(h.Weekday) || seen[h.Weekday] || !validInterval(h.Opens, h.Closes) {
+			return false
+		}
+		seen[h.Weekday] = true
+	}
+	return true
+}
+
+/// Функция validInterval проверяет формат времени и что начало раньше конца \\\
+/// Строки 15:04 одной длины сравниваются как время \\\
+
+func validInterval(starts, ends string) bool {
+	return clockRegexp.MatchString(starts) && clockRegexp.MatchString(ends) && starts < ends
+}
+
+func validWeekday(weekday int) bool {
+	return weekday >= 1 && weekday <= 7
+}
+
+/// Функция isoWeekday возвращает день недели от 1 (понедельник) до 7 (воскресенье) \\\
+
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
@@ -0,0 +1,465 @@
+package facility
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	hospitalsURL       = "/hospital_record/hospitals"
+	hospitalURL        = "/hospital_record/hospitals/:id"
+	departmentsURL     = "/hospital_record/hospitals/:id/departments"
+	departmentURL      = "/hospital_record/departments/:id"
+	officesURL         = "/hospital_record/departments/:id/offices"
+	officeURL          = "/hospital_record/offices/:id"
+	officeSchedulesURL = "/hospital_record/offices/:id/schedules"
+	scheduleURL        = "/hospital_record/office_schedules/:id"
+	doctorSchedulesURL = "/hospital_record/doctors/schedules/:id"
+)
+
+/// Структура Handler представляющая собой обработчик объекта facilityService для справочника больниц \\\
+
+type Handler struct {
+	logger          logger.Logger
+	facilityService Service
+	admin           handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, facilityService Service, admin handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:          logger,
+		facilityService: facilityService,
+		admin:           admin,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для справочника больниц, отделений и кабинетов \\\
+/// Справочник открыт для чтения, изменять его может только администратор \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, hospitalsURL, h.admin(h.CreateHospital))
+	router.HandlerFunc(http.MethodGet, hospitalsURL, h.GetHospitals)
+	router.HandlerFunc(http.MethodGet, hospitalURL, h.GetHospital)
+	router.HandlerFunc(http.MethodPatch, hospitalURL, h.admin(h.PartiallyUpdateHospital))
+	router.HandlerFunc(http.MethodDelete, hospitalURL, h.admin(h.DeleteHospital))
+	router.HandlerFunc(http.MethodPost, departmentsURL, h.admin(h.CreateDepartment))
+	router.HandlerFunc(http.MethodGet, departmentsURL, h.GetDepartments)
+	router.HandlerFunc(http.MethodDelete, departmentURL, h.admin(h.DeleteDepartment))
+	router.HandlerFunc(http.MethodPost, officesURL, h.admin(h.CreateOffice))
+	router.HandlerFunc(http.MethodGet, officesURL, h.GetOffices)
+	router.HandlerFunc(http.MethodGet, officeURL, h.GetOffice)
+	router.HandlerFunc(http.MethodPatch, officeURL, h.admin(h.PartiallyUpdateOffice))
+	router.HandlerFunc(http.MethodDelete, officeURL, h.admin(h.DeleteOffice))
+	router.HandlerFunc(http.MethodPost, officeSchedulesURL, h.admin(h.CreateSchedule))
+	router.HandlerFunc(http.MethodGet, officeSchedulesURL, h.GetOfficeSchedules)
+	router.HandlerFunc(http.MethodDelete, scheduleURL, h.admin(h.DeleteSchedule))
+	router.HandlerFunc(http.MethodGet, doctorSchedulesURL, h.GetDoctorSchedules)
+}
+
+/// Функция CreateHospital добавляет больницу \\\
+
+func (h *Handler) CreateHospital(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE HOSPITAL")
+
+	/// Чтение тела запроса в структуру CreateHospitalDTO \\\
+	var input CreateHospitalDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	hospital, err := h.facilityService.CreateHospital(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("HOSPITAL CREATED")
+	response.JSON(w, http.StatusCreated, hospital)
+}
+
+/// Функция GetHospitals получает все больницы \\\
+
+func (h *Handler) GetHospitals(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET HOSPITALS")
+
+	hospitals, err := h.facilityService.GetHospitals(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT HOSPITALS")
+	response.JSON(w, http.StatusOK, hospitals)
+}
+
+/// Функция GetHospital получает больницу по id \\\
+
+func (h *Handler) GetHospital(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET HOSPITAL")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	hospital, err := h.facilityService.GetHospital(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT HOSPITAL")
+	response.JSON(w, http.StatusOK, hospital)
+}
+
+/// Функция PartiallyUpdateHospital частично обновляет больницу \\\
+
+func (h *Handler) PartiallyUpdateHospital(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: PARTIALLY UPDATE HOSPITAL")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру PartiallyUpdateHospitalDTO \\\
+	var input PartiallyUpdateHospitalDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	hospital, err := h.facilityService.PartiallyUpdateHospital(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("HOSPITAL UPDATED")
+	response.JSON(w, http.StatusOK, hospital)
+}
+
+/// Функция DeleteHospital удаляет больницу вместе с ее отделениями и кабинетами \\\
+
+func (h *Handler) DeleteHospital(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE HOSPITAL")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err = h.facilityService.DeleteHospital(r.Context(), id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("HOSPITAL DELETED")
+	response.JSON(w, http.StatusOK, "HOSPITAL DELETED")
+}
+
+/// Функция CreateDepartment добавляет отделение в больницу \\\
+
+func (h *Handler) CreateDepartment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE DEPARTMENT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID больницы из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру CreateDepartmentDTO \\\
+	var input CreateDepartmentDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.HospitalID = id
+
+	department, err := h.facilityService.CreateDepartment(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("DEPARTMENT CREATED")
+	response.JSON(w, http.StatusCreated, department)
+}
+
+/// Функция GetDepartments получает отделения больницы \\\
+
+func (h *Handler) GetDepartments(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DEPARTMENTS")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID больницы из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	departments, err := h.facilityService.GetDepartments(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT DEPARTMENTS")
+	response.JSON(w, http.StatusOK, departments)
+}
+
+/// Функция DeleteDepartment удаляет отделение вместе с его кабинетами \\\
+
+func (h *Handler) DeleteDepartment(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE DEPARTMENT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err = h.facilityService.DeleteDepartment(r.Context(), id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("DEPARTMENT DELETED")
+	response.JSON(w, http.StatusOK, "DEPARTMENT DELETED")
+}
+
+/// Функция CreateOffice добавляет кабинет в отделение \\\
+
+func (h *Handler) CreateOffice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE OFFICE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID отделения из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру CreateOfficeDTO \\\
+	var input CreateOfficeDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.DepartmentID = id
+
+	office, err := h.facilityService.CreateOffice(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("OFFICE CREATED")
+	response.JSON(w, http.StatusCreated, office)
+}
+
+/// Функция GetOffices получает кабинеты отделения \\\
+
+func (h *Handler) GetOffices(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET OFFICES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID отделения из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	offices, err := h.facilityService.GetOffices(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT OFFICES")
+	response.JSON(w, http.StatusOK, offices)
+}
+
+/// Функция GetOffice получает кабинет по id \\\
+
+func (h *Handler) GetOffice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET OFFICE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	office, err := h.facilityService.GetOffice(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT OFFICE")
+	response.JSON(w, http.StatusOK, office)
+}
+
+/// Функция PartiallyUpdateOffice частично обновляет кабинет \\\
+
+func (h *Handler) PartiallyUpdateOffice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: PARTIALLY UPDATE OFFICE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру PartiallyUpdateOfficeDTO \\\
+	var input PartiallyUpdateOfficeDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	office, err := h.facilityService.PartiallyUpdateOffice(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("OFFICE UPDATED")
+	response.JSON(w, http.StatusOK, office)
+}
+
+/// Функция DeleteOffice удаляет кабинет, записи на прием в него сохраняют адрес и номер кабинета \\\
+
+func (h *Handler) DeleteOffice(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE OFFICE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err = h.facilityService.DeleteOffice(r.Context(), id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("OFFICE DELETED")
+	response.JSON(w, http.StatusOK, "OFFICE DELETED")
+}
+
+/// Функция CreateSchedule назначает доктору часы приема в кабинете \\\
+
+func (h *Handler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE OFFICE SCHEDULE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID кабинета из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру CreateScheduleDTO \\\
+	var input CreateScheduleDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.OfficeID = id
+
+	schedule, err := h.facilityService.CreateSchedule(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("OFFICE SCHEDULE CREATED")
+	response.JSON(w, http.StatusCreated, schedule)
+}
+
+/// Функция GetOfficeSchedules получает расписание кабинета \\\
+
+func (h *Handler) GetOfficeSchedules(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET OFFICE SCHEDULES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID кабинета из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	schedules, err := h.facilityService.GetOfficeSchedules(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT OFFICE SCHEDULES")
+	response.JSON(w, http.StatusOK, schedules)
+}
+
+/// Функция DeleteSchedule удаляет часы приема \\\
+
+func (h *Handler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE OFFICE SCHEDULE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err = h.facilityService.DeleteSchedule(r.Context(), id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("OFFICE SCHEDULE DELETED")
+	response.JSON(w, http.StatusOK, "OFFICE SCHEDULE DELETED")
+}
+
+/// Функция GetDoctorSchedules получает расписание доктора по кабинетам \\\
+
+func (h *Handler) GetDoctorSchedules(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DOCTOR SCHEDULES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID доктора из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	schedules, err := h.facilityService.GetDoctorSchedules(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT DOCTOR SCHEDULES")
+	response.JSON(w, http.StatusOK, schedules)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidFacility):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrScheduleConflict):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
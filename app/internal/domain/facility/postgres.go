@@ -0,0 +1,655 @@
+package facility
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+	"time"
+)
+
+var _ Storage = &FacilityStorage{}
+
+/// Колонки кабинета вместе с адресом больницы из справочника \\\
+
+const officeColumns = `o.id, o.department_id, o.number, o.floor, o.name, h.id, h.address
+	FROM office o
+	JOIN department d ON d.id = o.department_id
+	JOIN hospital h ON h.id = d.hospital_id`
+
+/// Колонки расписания, время приема отдается в виде 15:04 \\\
+
+const scheduleColumns = `id, office_id, doctor_id, weekday, to_char(starts_at, 'HH24:MI'), to_char(ends_at, 'HH24:MI'), valid_from, valid_to`
+
+/// Структура FacilityStorage содержащая поля для работы с БД \\\
+
+type FacilityStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр FacilityStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &FacilityStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция CreateHospital для сущности FacilityStorage добавляет больницу в справочник \\\
+
+func (f *FacilityStorage) CreateHospital(hospital *Hospital) (*Hospital, error) {
+	f.logger.Info("POSTGRES: CREATE HOSPITAL")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	created, err := scanHospital(f.conn.QueryRow(ctx,
+		`INSERT INTO hospital (name, address, phone, email, working_hours)
+			 VALUES($1,$2,$3,$4,$5)
+			 RETURNING *`,
+		hospital.Name, hospital.Address, hospital.Phone, hospital.Email, hospital.WorkingHours))
+	if err != nil {
+		err = fmt.Errorf("failed to execute create hospital query: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+/// Функция FindHospitals для сущности FacilityStorage получает все больницы \\\
+
+func (f *FacilityStorage) FindHospitals() ([]Hospital, error) {
+	f.logger.Info("POSTGRES: GET HOSPITALS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx,
+		`SELECT * FROM hospital
+			 ORDER BY name, id`)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения больниц \\\
+	hospitals := make([]Hospital, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		hospital, err := scanHospital(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find hospitals query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		hospitals = append(hospitals, *hospital)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return hospitals, nil
+}
+
+/// Функция FindHospitalById для сущности FacilityStorage получает больницу по id \\\
+
+func (f *FacilityStorage) FindHospitalById(id int64) (*Hospital, error) {
+	f.logger.Info("POSTGRES: GET HOSPITAL BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	hospital, err := scanHospital(f.conn.QueryRow(ctx,
+		`SELECT * FROM hospital
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find hospital by id query: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	return hospital, nil
+}
+
+/// Функция PartiallyUpdateHospital для сущности FacilityStorage частично обновляет больницу \\\
+/// Новый адрес в той же транзакции записывается в записи на прием в кабинеты больницы \\\
+
+func (f *FacilityStorage) PartiallyUpdateHospital(input *PartiallyUpdateHospitalDTO) (*Hospital, error) {
+	f.logger.Info("POSTGRES: PARTIALLY UPDATE HOSPITAL")
+
+	/// Создание пустого слайса для хранения обновляемых строк \\\
+	values := make([]string, 0)
+
+	/// Создание пустого слайса для хранения аргументов запроса \\\
+	args := make([]interface{}, 0)
+	argId := 1
+
+	/// Проверки на наличие новых значений \\\
+	if input.Name != nil {
+		values = append(values, fmt.Sprintf("name=$%d", argId))
+		args = append(args, *input.Name)
+		argId++
+	}
+	if input.Address != nil {
+		values = append(values, fmt.Sprintf("address=$%d", argId))
+		args = append(args, *input.Address)
+		argId++
+	}
+	if input.Phone != nil {
+		values = append(values, fmt.Sprintf("phone=$%d", argId))
+		args = append(args, *input.Phone)
+		argId++
+	}
+	if input.Email != nil {
+		values = append(values, fmt.Sprintf("email=$%d", argId))
+		args = append(args, *input.Email)
+		argId++
+	}
+	if input.WorkingHours != nil {
+		values = append(values, fmt.Sprintf("working_hours=$%d", argId))
+		args = append(args, *input.WorkingHours)
+		argId++
+	}
+
+	/// Формирование строки со всеми измененными полями и их значениями \\\
+	query := fmt.Sprintf("UPDATE hospital SET %s WHERE id = $%d RETURNING *", strings.Join(values, ", "), argId)
+	args = append(args, input.ID)
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	tx, err := f.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin update hospital transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	hospital, err := scanHospital(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to update hospital partially: %v", err)
+	}
+
+	if input.Address != nil {
+		_, err = tx.Exec(ctx,
+			`UPDATE record SET hospital_address = $1
+				 WHERE office_id IN (SELECT o.id FROM office o
+				     JOIN department d ON d.id = o.department_id
+				     WHERE d.hospital_id = $2)`, hospital.Address, hospital.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update address of hospital records: %v", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit update hospital transaction: %v", err)
+	}
+	return hospital, nil
+}
+
+/// Функция DeleteHospital для сущности FacilityStorage удаляет больницу вместе с отделениями, кабинетами и расписаниями \\\
+/// Записи на прием сохраняют адрес и кабинет текстом \\\
+
+func (f *FacilityStorage) DeleteHospital(id int64) error {
+	f.logger.Info("POSTGRES: DELETE HOSPITAL")
+	return f.delete(`DELETE FROM hospital WHERE id = $1`, id)
+}
+
+/// Функция CreateDepartment для сущности FacilityStorage добавляет отделение больницы \\\
+
+func (f *FacilityStorage) CreateDepartment(department *Department) (*Department, error) {
+	f.logger.Info("POSTGRES: CREATE DEPARTMENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	err := f.conn.QueryRow(ctx,
+		`INSERT INTO department (hospital_id, name, specialization_id, phone)
+			 VALUES($1,$2,$3,$4)
+			 RETURNING id`,
+		department.HospitalID, department.Name, department.SpecializationID, department.Phone).Scan(&department.ID)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create department query: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	return department, nil
+}
+
+/// Функция FindDepartments для сущности FacilityStorage получает отделения больницы \\\
+
+func (f *FacilityStorage) FindDepartments(hospitalId int64) ([]Department, error) {
+	f.logger.Info("POSTGRES: GET DEPARTMENTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx,
+		`SELECT * FROM department
+			 WHERE hospital_id = $1
+			 ORDER BY name, id`, hospitalId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения отделений \\\
+	departments := make([]Department, 0)
+
+	for rows.Next() {
+		var department Department
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&department.ID, &department.HospitalID, &department.Name, &department.SpecializationID, &department.Phone)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find departments query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		departments = append(departments, department)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return departments, nil
+}
+
+/// Функция DeleteDepartment для сущности FacilityStorage удаляет отделение вместе с кабинетами и расписаниями \\\
+
+func (f *FacilityStorage) DeleteDepartment(id int64) error {
+	f.logger.Info("POSTGRES: DELETE DEPARTMENT")
+	return f.delete(`DELETE FROM department WHERE id = $1`, id)
+}
+
+/// Функция CreateOffice для сущности FacilityStorage добавляет кабинет отделения \\\
+
+func (f *FacilityStorage) CreateOffice(office *Office) (*Office, error) {
+	f.logger.Info("POSTGRES: CREATE OFFICE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД, адрес больницы возвращается вместе с кабинетом, без отделения кабинет не создается \\\
+	created, err := scanOffice(f.conn.QueryRow(ctx,
+		`WITH o AS (INSERT INTO office (department_id, number, floor, name)
+			 SELECT id, $2, $3, $4 FROM department WHERE id = $1
+			 RETURNING *)
+			 SELECT o.id, o.department_id, o.number, o.floor, o.name, h.id, h.address
+			 FROM o
+			 JOIN department d ON d.id = o.department_id
+			 JOIN hospital h ON h.id = d.hospital_id`,
+		office.DepartmentID, office.Number, office.Floor, office.Name))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperror.ErrEmptyString
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to execute create office query: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+/// Функция FindOffices для сущности FacilityStorage получает кабинеты отделения \\\
+
+func (f *FacilityStorage) FindOffices(departmentId int64) ([]Office, error) {
+	f.logger.Info("POSTGRES: GET OFFICES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx,
+		`SELECT `+officeColumns+`
+			 WHERE o.department_id = $1
+			 ORDER BY o.number, o.id`, departmentId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения кабинетов \\\
+	offices := make([]Office, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		office, err := scanOffice(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find offices query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		offices = append(offices, *office)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return offices, nil
+}
+
+/// Функция FindOfficeById для сущности FacilityStorage получает кабинет с адресом больницы по id \\\
+
+func (f *FacilityStorage) FindOfficeById(id int64) (*Office, error) {
+	f.logger.Info("POSTGRES: GET OFFICE BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	office, err := scanOffice(f.conn.QueryRow(ctx,
+		`SELECT `+officeColumns+`
+			 WHERE o.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find office by id query: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	return office, nil
+}
+
+/// Функция PartiallyUpdateOffice для сущности FacilityStorage частично обновляет кабинет \\\
+/// Новый номер в той же транзакции записывается в записи на прием в этот кабинет \\\
+
+func (f *FacilityStorage) PartiallyUpdateOffice(input *PartiallyUpdateOfficeDTO) (*Office, error) {
+	f.logger.Info("POSTGRES: PARTIALLY UPDATE OFFICE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	tx, err := f.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin update office transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД, незаполненные поля остаются прежними \\\
+	result, err := tx.Exec(ctx,
+		`UPDATE office SET number = coalesce($1, number), floor = coalesce($2, floor), name = coalesce($3, name)
+			 WHERE id = $4`, input.Number, input.Floor, input.Name, input.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update office partially: %v", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, apperror.ErrEmptyString
+	}
+
+	if input.Number != nil {
+		_, err = tx.Exec(ctx,
+			`UPDATE record SET doctor_office = $1 WHERE office_id = $2`, *input.Number, input.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update office of records: %v", err)
+		}
+	}
+
+	office, err := scanOffice(tx.QueryRow(ctx,
+		`SELECT `+officeColumns+`
+			 WHERE o.id = $1`, input.ID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find updated office: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit update office transaction: %v", err)
+	}
+	return office, nil
+}
+
+/// Функция DeleteOffice для сущности FacilityStorage удаляет кабинет вместе с расписаниями \\\
+
+func (f *FacilityStorage) DeleteOffice(id int64) error {
+	f.logger.Info("POSTGRES: DELETE OFFICE")
+	return f.delete(`DELETE FROM office WHERE id = $1`, id)
+}
+
+/// Функция CreateSchedule для сущности FacilityStorage добавляет часы приема доктора в кабинете \\\
+/// Часы не должны пересекаться с другими часами кабинета и с часами доктора в других кабинетах \\\
+
+func (f *FacilityStorage) CreateSchedule(schedule *Schedule) (*Schedule, error) {
+	f.logger.Info("POSTGRES: CREATE OFFICE SCHEDULE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	tx, err := f.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create office schedule transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Блокировка кабинета и доктора, чтобы параллельные расписания не пересеклись \\\
+	_, err = tx.Exec(ctx,
+		`SELECT 1 FROM office WHERE id = $1 FOR UPDATE`, schedule.OfficeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock office: %v", err)
+	}
+	_, err = tx.Exec(ctx,
+		`SELECT 1 FROM doctors WHERE id = $1 FOR UPDATE`, schedule.DoctorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock doctor: %v", err)
+	}
+
+	/// Проверка пересечения по дню недели, часам и сроку действия \\\
+	var conflict bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM office_schedule
+			 WHERE (office_id = $1 OR doctor_id = $2) AND weekday = $3
+			 AND starts_at < $5::time AND ends_at > $4::time
+			 AND valid_from <= coalesce($7::date, 'infinity'::date)
+			 AND coalesce(valid_to, 'infinity'::date) >= $6::date)`,
+		schedule.OfficeID, schedule.DoctorID, schedule.Weekday, schedule.StartsAt, schedule.EndsAt,
+		schedule.ValidFrom, schedule.ValidTo).Scan(&conflict)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check office schedule conflicts: %v", err)
+	}
+	if conflict {
+		return nil, apperror.ErrScheduleConflict
+	}
+
+	/// Выполнение запроса к БД \\\
+	created, err := scanSchedule(tx.QueryRow(ctx,
+		`INSERT INTO office_schedule (office_id, doctor_id, weekday, starts_at, ends_at, valid_from, valid_to)
+			 VALUES($1,$2,$3,$4::time,$5::time,$6,$7)
+			 RETURNING `+scheduleColumns,
+		schedule.OfficeID, schedule.DoctorID, schedule.Weekday, schedule.StartsAt, schedule.EndsAt,
+		schedule.ValidFrom, schedule.ValidTo))
+	if err != nil {
+		err = fmt.Errorf("failed to execute create office schedule query: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create office schedule transaction: %v", err)
+	}
+	return created, nil
+}
+
+/// Функция FindSchedulesByOffice для сущности FacilityStorage получает расписание кабинета \\\
+
+func (f *FacilityStorage) FindSchedulesByOffice(officeId int64) ([]Schedule, error) {
+	f.logger.Info("POSTGRES: GET OFFICE SCHEDULES")
+	return f.findSchedules(`office_id = $1`, officeId)
+}
+
+/// Функция FindSchedulesByDoctor для сущности FacilityStorage получает расписание доктора во всех кабинетах \\\
+
+func (f *FacilityStorage) FindSchedulesByDoctor(doctorId int64) ([]Schedule, error) {
+	f.logger.Info("POSTGRES: GET DOCTOR SCHEDULES")
+	return f.findSchedules(`doctor_id = $1`, doctorId)
+}
+
+/// Функция DeleteSchedule для сущности FacilityStorage удаляет часы приема \\\
+
+func (f *FacilityStorage) DeleteSchedule(id int64) error {
+	f.logger.Info("POSTGRES: DELETE OFFICE SCHEDULE")
+	return f.delete(`DELETE FROM office_schedule WHERE id = $1`, id)
+}
+
+/// Функция FindDoctorLocation для сущности FacilityStorage находит кабинет, в котором доктор принимает в день day в момент clock \\\
+
+func (f *FacilityStorage) FindDoctorLocation(doctorId int64, weekday int, clock string, day time.Time) (*record.Location, error) {
+	f.logger.Info("POSTGRES: FIND DOCTOR OFFICE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	location := &record.Location{}
+	err := f.conn.QueryRow(ctx,
+		`SELECT o.id, h.address, o.number FROM office_schedule s
+			 JOIN office o ON o.id = s.office_id
+			 JOIN department d ON d.id = o.department_id
+			 JOIN hospital h ON h.id = d.hospital_id
+			 WHERE s.doctor_id = $1 AND s.weekday = $2
+			 AND s.starts_at <= $3::time AND s.ends_at > $3::time
+			 AND s.valid_from <= $4::date AND (s.valid_to IS NULL OR s.valid_to >= $4::date)
+			 ORDER BY s.valid_from DESC
+			 LIMIT 1`,
+		doctorId, weekday, clock, day).Scan(&location.OfficeID, &location.HospitalAddress, &location.DoctorOffice)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find doctor office query: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	return location, nil
+}
+
+/// Функция findSchedules получает расписания по условию where с одним аргументом \\\
+
+func (f *FacilityStorage) findSchedules(where string, arg int64) ([]Schedule, error) {
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx,
+		`SELECT `+scheduleColumns+` FROM office_schedule
+			 WHERE `+where+`
+			 ORDER BY weekday, starts_at, id`, arg)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения расписаний \\\
+	schedules := make([]Schedule, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		schedule, err := scanSchedule(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find office schedules query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		schedules = append(schedules, *schedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+/// Функция delete удаляет строку справочника по id \\\
+
+func (f *FacilityStorage) delete(query string, id int64) error {
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := f.conn.Exec(ctx, query, id)
+	if err != nil {
+		err = fmt.Errorf("failed to execute delete query: %v", err)
+		f.logger.Error(err)
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция scanHospital сканирует строку таблицы hospital \\\
+
+func scanHospital(row pgx.Row) (*Hospital, error) {
+	hospital := &Hospital{}
+	err := row.Scan(&hospital.ID, &hospital.Name, &hospital.Address, &hospital.Phone, &hospital.Email, &hospital.WorkingHours)
+	if err != nil {
+		return nil, err
+	}
+	return hospital, nil
+}
+
+/// Функция scanOffice сканирует кабинет с колонками officeColumns \\\
+
+func scanOffice(row pgx.Row) (*Office, error) {
+	office := &Office{}
+	err := row.Scan(&office.ID, &office.DepartmentID, &office.Number, &office.Floor, &office.Name,
+		&office.HospitalID, &office.HospitalAddress)
+	if err != nil {
+		return nil, err
+	}
+	return office, nil
+}
+
+/// Функция scanSchedule сканирует расписание с колонками scheduleColumns \\\
+
+func scanSchedule(row pgx.Row) (*Schedule, error) {
+	schedule := &Schedule{}
+	err := row.Scan(&schedule.ID, &schedule.OfficeID, &schedule.DoctorID, &schedule.Weekday,
+		&schedule.StartsAt, &schedule.EndsAt, &schedule.ValidFrom, &schedule.ValidTo)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
@@ -0,0 +1,338 @@
+package facility
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы справочника больниц, отделений и кабинетов \\\
+/// Service также реализует record.Offices, через который запись на прием получает адрес и кабинет \\\
+
+type Service interface {
+	record.Offices
+	CreateHospital(ctx context.Context, input *CreateHospitalDTO) (*Hospital, error)
+	GetHospitals(ctx context.Context) (*[]Hospital, error)
+	GetHospital(ctx context.Context, id int64) (*Hospital, error)
+	PartiallyUpdateHospital(ctx context.Context, input *PartiallyUpdateHospitalDTO) (*Hospital, error)
+	DeleteHospital(ctx context.Context, id int64) error
+	CreateDepartment(ctx context.Context, input *CreateDepartmentDTO) (*Department, error)
+	GetDepartments(ctx context.Context, hospitalId int64) (*[]Department, error)
+	DeleteDepartment(ctx context.Context, id int64) error
+	CreateOffice(ctx context.Context, input *CreateOfficeDTO) (*Office, error)
+	GetOffices(ctx context.Context, departmentId int64) (*[]Office, error)
+	GetOffice(ctx context.Context, id int64) (*Office, error)
+	PartiallyUpdateOffice(ctx context.Context, input *PartiallyUpdateOfficeDTO) (*Office, error)
+	DeleteOffice(ctx context.Context, id int64) error
+	CreateSchedule(ctx context.Context, input *CreateScheduleDTO) (*Schedule, error)
+	GetOfficeSchedules(ctx context.Context, officeId int64) (*[]Schedule, error)
+	GetDoctorSchedules(ctx context.Context, doctorId int64) (*[]Schedule, error)
+	DeleteSchedule(ctx context.Context, id int64) error
+}
+
+/// Структура  service реализизирующая инфтерфейс Service справочника \\\
+
+type service struct {
+	logger   logger.Logger
+	storage  Storage
+	doc      doctor.Storage
+	location *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:   logger,
+		storage:  storage,
+		doc:      doc,
+		location: time.UTC,
+	}
+	if cfg.Facilities.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Facilities.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown facilities time zone %q, using UTC: %v", cfg.Facilities.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция CreateHospital добавляет больницу, адрес больницы уникален \\\
+
+func (s *service) CreateHospital(ctx context.Context, input *CreateHospitalDTO) (*Hospital, error) {
+	s.logger.Info("SERVICE: CREATE HOSPITAL")
+
+	hospital := Hospital{
+		Name:         strings.TrimSpace(input.Name),
+		Address:      strings.TrimSpace(input.Address),
+		Phone:        input.Phone,
+		Email:        input.Email,
+		WorkingHours: input.WorkingHours,
+	}
+	if hospital.WorkingHours == nil {
+		hospital.WorkingHours = make([]WorkingHours, 0)
+	}
+	if hospital.Name == "" || hospital.Address == "" || !validHours(hospital.WorkingHours) {
+		return nil, apperror.ErrInvalidFacility
+	}
+	return s.storage.CreateHospital(&hospital)
+}
+
+/// Функция GetHospitals возвращает все больницы \\\
+
+func (s *service) GetHospitals(ctx context.Context) (*[]Hospital, error) {
+	s.logger.Info("SERVICE: GET HOSPITALS")
+
+	hospitals, err := s.storage.FindHospitals()
+	if err != nil {
+		return nil, err
+	}
+	return &hospitals, nil
+}
+
+/// Функция GetHospital возвращает больницу по id \\\
+
+func (s *service) GetHospital(ctx context.Context, id int64) (*Hospital, error) {
+	s.logger.Info("SERVICE: GET HOSPITAL")
+
+	return s.storage.FindHospitalById(id)
+}
+
+/// Функция PartiallyUpdateHospital частично обновляет больницу \\\
+
+func (s *service) PartiallyUpdateHospital(ctx context.Context, input *PartiallyUpdateHospitalDTO) (*Hospital, error) {
+	s.logger.Info("SERVICE: PARTIALLY UPDATE HOSPITAL")
+
+	/// Проверка входных данных \\\
+	if input.Name == nil && input.Address == nil && input.Phone == nil && input.Email == nil && input.WorkingHours == nil {
+		return nil, apperror.ErrInvalidFacility
+	}
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		return nil, apperror.ErrInvalidFacility
+	}
+	if input.Address != nil && strings.TrimSpace(*input.Address) == "" {
+		return nil, apperror.ErrInvalidFacility
+	}
+	if input.WorkingHours != nil && !validHours(*input.WorkingHours) {
+		return nil, apperror.ErrInvalidFacility
+	}
+	return s.storage.PartiallyUpdateHospital(input)
+}
+
+/// Функция DeleteHospital удаляет больницу \\\
+
+func (s *service) DeleteHospital(ctx context.Context, id int64) error {
+	s.logger.Info("SERVICE: DELETE HOSPITAL")
+
+	return s.storage.DeleteHospital(id)
+}
+
+/// Функция CreateDepartment добавляет отделение в существующую больницу \\\
+
+func (s *service) CreateDepartment(ctx context.Context, input *CreateDepartmentDTO) (*Department, error) {
+	s.logger.Info("SERVICE: CREATE DEPARTMENT")
+
+	department := Department{
+		HospitalID:       input.HospitalID,
+		Name:             strings.TrimSpace(input.Name),
+		SpecializationID: input.SpecializationID,
+		Phone:            input.Phone,
+	}
+	if department.Name == "" {
+		return nil, apperror.ErrInvalidFacility
+	}
+	if _, err := s.storage.FindHospitalById(input.HospitalID); err != nil {
+		return nil, err
+	}
+	return s.storage.CreateDepartment(&department)
+}
+
+/// Функция GetDepartments возвращает отделения больницы \\\
+
+func (s *service) GetDepartments(ctx context.Context, hospitalId int64) (*[]Department, error) {
+	s.logger.Info("SERVICE: GET DEPARTMENTS")
+
+	departments, err := s.storage.FindDepartments(hospitalId)
+	if err != nil {
+		return nil, err
+	}
+	return &departments, nil
+}
+
+/// Функция DeleteDepartment удаляет отделение \\\
+
+func (s *service) DeleteDepartment(ctx context.Context, id int64) error {
+	s.logger.Info("SERVICE: DELETE DEPARTMENT")
+
+	return s.storage.DeleteDepartment(id)
+}
+
+/// Функция CreateOffice добавляет кабинет в отделение \\\
+
+func (s *service) CreateOffice(ctx context.Context, input *CreateOfficeDTO) (*Office, error) {
+	s.logger.Info("SERVICE: CREATE OFFICE")
+
+	office := Office{
+		DepartmentID: input.DepartmentID,
+		Number:       strings.TrimSpace(input.Number),
+		Floor:        input.Floor,
+		Name:         input.Name,
+	}
+	if office.Number == "" {
+		return nil, apperror.ErrInvalidFacility
+	}
+
+	created, err := s.storage.CreateOffice(&office)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+/// Функция GetOffices возвращает кабинеты отделения \\\
+
+func (s *service) GetOffices(ctx context.Context, departmentId int64) (*[]Office, error) {
+	s.logger.Info("SERVICE: GET OFFICES")
+
+	offices, err := s.storage.FindOffices(departmentId)
+	if err != nil {
+		return nil, err
+	}
+	return &offices, nil
+}
+
+/// Функция GetOffice возвращает кабинет по id \\\
+
+func (s *service) GetOffice(ctx context.Context, id int64) (*Office, error) {
+	s.logger.Info("SERVICE: GET OFFICE")
+
+	return s.storage.FindOfficeById(id)
+}
+
+/// Функция PartiallyUpdateOffice частично обновляет кабинет \\\
+
+func (s *service) PartiallyUpdateOffice(ctx context.Context, input *PartiallyUpdateOfficeDTO) (*Office, error) {
+	s.logger.Info("SERVICE: PARTIALLY UPDATE OFFICE")
+
+	if input.Number == nil && input.Floor == nil && input.Name == nil {
+		return nil, apperror.ErrInvalidFacility
+	}
+	if input.Number != nil && strings.TrimSpace(*input.Number) == "" {
+		return nil, apperror.ErrInvalidFacility
+	}
+	return s.storage.PartiallyUpdateOffice(input)
+}
+
+/// Функция DeleteOffice удаляет кабинет \\\
+
+func (s *service) DeleteOffice(ctx context.Context, id int64) error {
+	s.logger.Info("SERVICE: DELETE OFFICE")
+
+	return s.storage.DeleteOffice(id)
+}
+
+/// Функция CreateSchedule назначает доктора в кабинет на часы приема в день недели \\\
+/// Без valid_from расписание действует с сегодняшнего дня \\\
+
+func (s *service) CreateSchedule(ctx context.Context, input *CreateScheduleDTO) (*Schedule, error) {
+	s.logger.Info("SERVICE: CREATE OFFICE SCHEDULE")
+
+	/// Проверка входных данных \\\
+	if !validWeekday(input.Weekday) || !validInterval(input.StartsAt, input.EndsAt) {
+		return nil, apperror.ErrInvalidFacility
+	}
+	validFrom := s.day(time.Now())
+	if input.ValidFrom != nil {
+		validFrom = s.day(*input.ValidFrom)
+	}
+	var validTo *time.Time
+	if input.ValidTo != nil {
+		day := s.day(*input.ValidTo)
+		if day.Before(validFrom) {
+			return nil, apperror.ErrInvalidFacility
+		}
+		validTo = &day
+	}
+
+	/// Проверка что кабинет и доктор существуют \\\
+	if _, err := s.storage.FindOfficeById(input.OfficeID); err != nil {
+		return nil, err
+	}
+	if _, err := s.doc.FindById(input.DoctorID); err != nil {
+		return nil, err
+	}
+
+	schedule := Schedule{
+		OfficeID:  input.OfficeID,
+		DoctorID:  input.DoctorID,
+		Weekday:   input.Weekday,
+		StartsAt:  input.StartsAt,
+		EndsAt:    input.EndsAt,
+		ValidFrom: validFrom,
+		ValidTo:   validTo,
+	}
+	return s.storage.CreateSchedule(&schedule)
+}
+
+/// Функция GetOfficeSchedules возвращает расписание кабинета \\\
+
+func (s *service) GetOfficeSchedules(ctx context.Context, officeId int64) (*[]Schedule, error) {
+	s.logger.Info("SERVICE: GET OFFICE SCHEDULES")
+
+	schedules, err := s.storage.FindSchedulesByOffice(officeId)
+	if err != nil {
+		return nil, err
+	}
+	return &schedules, nil
+}
+
+/// Функция GetDoctorSchedules возвращает расписание доктора по кабинетам \\\
+
+func (s *service) GetDoctorSchedules(ctx context.Context, doctorId int64) (*[]Schedule, error) {
+	s.logger.Info("SERVICE: GET DOCTOR SCHEDULES")
+
+	schedules, err := s.storage.FindSchedulesByDoctor(doctorId)
+	if err != nil {
+		return nil, err
+	}
+	return &schedules, nil
+}
+
+/// Функция DeleteSchedule удаляет часы приема \\\
+
+func (s *service) DeleteSchedule(ctx context.Context, id int64) error {
+	s.logger.Info("SERVICE: DELETE OFFICE SCHEDULE")
+
+	return s.storage.DeleteSchedule(id)
+}
+
+/// Функция Locate возвращает кабинет справочника с адресом больницы \\\
+
+func (s *service) Locate(officeId int64) (*record.Location, error) {
+	office, err := s.storage.FindOfficeById(officeId)
+	if err != nil {
+		return nil, err
+	}
+	return &record.Location{OfficeID: office.ID, HospitalAddress: office.HospitalAddress, DoctorOffice: office.Number}, nil
+}
+
+/// Функция LocateDoctor возвращает кабинет, в котором доктор принимает в момент at по местному времени \\\
+
+func (s *service) LocateDoctor(doctorId int64, at time.Time) (*record.Location, error) {
+	local := at.In(s.location)
+	return s.storage.FindDoctorLocation(doctorId, isoWeekday(local), local.Format("15:04"), s.day(local))
+}
+
+/// Функция day возвращает начало дня t по местному времени \\\
+
+func (s *service) day(t time.Time) time.Time {
+	year, month, day := t.In(s.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, s.location)
+}
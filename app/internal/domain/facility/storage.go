@@ -0,0 +1,27 @@
+package facility
+
+import (
+	"HospitalRecord/app/internal/domain/record"
+	"time"
+)
+
+type Storage interface {
+	CreateHospital(hospital *Hospital) (*Hospital, error)
+	FindHospitals() ([]Hospital, error)
+	FindHospitalById(id int64) (*Hospital, error)
+	PartiallyUpdateHospital(input *PartiallyUpdateHospitalDTO) (*Hospital, error)
+	DeleteHospital(id int64) error
+	CreateDepartment(department *Department) (*Department, error)
+	FindDepartments(hospitalId int64) ([]Department, error)
+	DeleteDepartment(id int64) error
+	CreateOffice(office *Office) (*Office, error)
+	FindOffices(departmentId int64) ([]Office, error)
+	FindOfficeById(id int64) (*Office, error)
+	PartiallyUpdateOffice(input *PartiallyUpdateOfficeDTO) (*Office, error)
+	DeleteOffice(id int64) error
+	CreateSchedule(schedule *Schedule) (*Schedule, error)
+	FindSchedulesByOffice(officeId int64) ([]Schedule, error)
+	FindSchedulesByDoctor(doctorId int64) ([]Schedule, error)
+	DeleteSchedule(id int64) error
+	FindDoctorLocation(doctorId int64, weekday int, clock string, day time.Time) (*record.Location, error)
+}
@@ -0,0 +1,274 @@
+package fhir
+
+import (
+	"HospitalRecord/app/internal/domain/record"
+	"time"
+)
+
+/// Версия FHIR и тип содержимого ответов \\\
+
+const (
+	Version     = "4.0.1"
+	ContentType = "application/fhir+json; charset=utf-8"
+)
+
+/// Локальные системы идентификаторов и кодов: полис ОМС пациента и специализации докторов \\\
+
+const (
+	SystemPolicy         = "urn:hospital-record:oms-policy"
+	SystemSpecialization = "urn:hospital-record:specialization"
+	SystemConditionState = "http://terminology.hl7.org/CodeSystem/condition-clinical"
+)
+
+/// Статусы Appointment: отмененная запись, прошедший и предстоящий прием \\\
+
+const (
+	AppointmentBooked    = "booked"
+	AppointmentFulfilled = "fulfilled"
+	AppointmentCancelled = "cancelled"
+)
+
+/// Общие типы данных FHIR, используются только нужные фасаду поля \\\
+
+type Identifier struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+type HumanName struct {
+	Use    string   `json:"use,omitempty"`
+	Text   string   `json:"text,omitempty"`
+	Family string   `json:"family"`
+	Given  []string `json:"given,omitempty"`
+}
+
+type ContactPoint struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+type Address struct {
+	Text string `json:"text"`
+}
+
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+type Reference struct {
+	Reference string `json:"reference,omitempty"`
+	Display   string `json:"display,omitempty"`
+}
+
+/// Структура ресурса Patient из пациента \\\
+
+type Patient struct {
+	ResourceType string         `json:"resourceType"`
+	ID           string         `json:"id"`
+	Identifier   []Identifier   `json:"identifier,omitempty"`
+	Active       bool           `json:"active"`
+	Name         []HumanName    `json:"name"`
+	Telecom      []ContactPoint `json:"telecom,omitempty"`
+	Gender       string         `json:"gender"`
+	Address      []Address      `json:"address,omitempty"`
+}
+
+/// Структура ресурса Practitioner из доктора \\\
+
+type Practitioner struct {
+	ResourceType string      `json:"resourceType"`
+	ID           string      `json:"id"`
+	Active       bool        `json:"active"`
+	Name         []HumanName `json:"name"`
+	Gender       string      `json:"gender"`
+}
+
+/// Структура ресурса PractitionerRole: доктор в одной из своих специализаций, id - "<доктор>-<специализация>" \\\
+
+type PractitionerRole struct {
+	ResourceType string            `json:"resourceType"`
+	ID           string            `json:"id"`
+	Active       bool              `json:"active"`
+	Practitioner Reference         `json:"practitioner"`
+	Specialty    []CodeableConcept `json:"specialty"`
+}
+
+/// Структура ресурса Appointment из записи на прием \\\
+
+type Appointment struct {
+	ResourceType    string            `json:"resourceType"`
+	ID              string            `json:"id"`
+	Status          string            `json:"status"`
+	Specialty       []CodeableConcept `json:"specialty,omitempty"`
+	Start           time.Time         `json:"start"`
+	End             time.Time         `json:"end"`
+	MinutesDuration int               `json:"minutesDuration"`
+	Comment         string            `json:"comment,omitempty"`
+	Participant     []Participant     `json:"participant"`
+}
+
+type Participant struct {
+	Actor    Reference `json:"actor"`
+	Required string    `json:"required"`
+	Status   string    `json:"status"`
+}
+
+/// Структура ресурса Condition: заболевание из карты пациента, id - "<пациент>-<заболевание>" \\\
+
+type Condition struct {
+	ResourceType   string            `json:"resourceType"`
+	ID             string            `json:"id"`
+	ClinicalStatus CodeableConcept   `json:"clinicalStatus"`
+	Code           CodeableConcept   `json:"code"`
+	BodySite       []CodeableConcept `json:"bodySite,omitempty"`
+	Subject        Reference         `json:"subject"`
+}
+
+/// Структура Bundle результатов поиска, ссылка next есть, если найдено больше _count \\\
+
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Link         []BundleLink  `json:"link"`
+	Entry        []BundleEntry `json:"entry"`
+}
+
+type BundleLink struct {
+	Relation string `json:"relation"`
+	URL      string `json:"url"`
+}
+
+type BundleEntry struct {
+	FullURL  string      `json:"fullUrl"`
+	Resource interface{} `json:"resource"`
+	Search   EntrySearch `json:"search"`
+}
+
+type EntrySearch struct {
+	Mode string `json:"mode"`
+}
+
+/// Структура OperationOutcome, которой фасад отвечает на ошибки \\\
+
+type OperationOutcome struct {
+	ResourceType string  `json:"resourceType"`
+	Issue        []Issue `json:"issue"`
+}
+
+type Issue struct {
+	Severity    string `json:"severity"`
+	Code        string `json:"code"`
+	Diagnostics string `json:"diagnostics,omitempty"`
+}
+
+/// Структура CapabilityStatement: поддерживаемые ресурсы, взаимодействия и параметры поиска \\\
+
+type CapabilityStatement struct {
+	ResourceType string       `json:"resourceType"`
+	Status       string       `json:"status"`
+	Date         string       `json:"date"`
+	Kind         string       `json:"kind"`
+	FhirVersion  string       `json:"fhirVersion"`
+	Format       []string     `json:"format"`
+	Rest         []Capability `json:"rest"`
+}
+
+type Capability struct {
+	Mode     string               `json:"mode"`
+	Resource []CapabilityResource `json:"resource"`
+}
+
+type CapabilityResource struct {
+	Type        string             `json:"type"`
+	Interaction []CapabilityCode   `json:"interaction"`
+	SearchParam []CapabilitySearch `json:"searchParam"`
+}
+
+type CapabilityCode struct {
+	Code string `json:"code"`
+}
+
+type CapabilitySearch struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+/// Структуры параметров поиска, пустые поля не ограничивают поиск \\\
+
+type Page struct {
+	Count  int
+	Offset int
+}
+
+type PatientSearch struct {
+	ID         *int64
+	Identifier *string
+	Family     *string
+	Given      *string
+	Email      *string
+	Phone      *string
+	Gender     *string
+	Page
+}
+
+type PractitionerSearch struct {
+	ID     *int64
+	Name   *string
+	Family *string
+	Given  *string
+	Gender *string
+	Page
+}
+
+type RoleSearch struct {
+	PractitionerID   *int64
+	SpecializationID *int64
+	Page
+}
+
+type AppointmentSearch struct {
+	ID             *int64
+	PatientID      *int64
+	PractitionerID *int64
+	From           *time.Time
+	To             *time.Time
+	Status         *string
+	Page
+}
+
+type ConditionSearch struct {
+	PatientID *int64
+	DiseaseID *int64
+	Page
+}
+
+/// Структуры строк хранилища: запись со статусом приема, специализация доктора и заболевание пациента \\\
+
+type Visit struct {
+	record.Record
+	Status         string
+	Specialization string
+}
+
+type Role struct {
+	DoctorID         int64
+	Surname          string
+	Name             string
+	SpecializationID int64
+	Specialization   string
+	Active           bool
+}
+
+type Disease struct {
+	PatientID   int64
+	ID          int64
+	BodyPart    string
+	Description string
+}
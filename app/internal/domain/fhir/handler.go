@@ -0,0 +1,134 @@
+package fhir
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/pkg/logger"
+	"encoding/json"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+/// Фасад FHIR живет под отдельным префиксом, ресурсы только читаются \\\
+
+const (
+	metadataURL = "/fhir/metadata"
+	patientsURL = "/fhir/Patient"
+	patientURL  = "/fhir/Patient/:id"
+	doctorsURL  = "/fhir/Practitioner"
+	doctorURL   = "/fhir/Practitioner/:id"
+	rolesURL    = "/fhir/PractitionerRole"
+	roleURL     = "/fhir/PractitionerRole/:id"
+	visitsURL   = "/fhir/Appointment"
+	visitURL    = "/fhir/Appointment/:id"
+	diseasesURL = "/fhir/Condition"
+	diseaseURL  = "/fhir/Condition/:id"
+)
+
+/// Структура Handler представляющая собой обработчик объекта fhirService для фасада FHIR \\\
+
+type Handler struct {
+	logger      logger.Logger
+	fhirService Service
+	staff       handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, fhirService Service, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:      logger,
+		fhirService: fhirService,
+		staff:       staff,
+	}
+}
+
+/// Структура Register регистрирует запросы фасада FHIR, CapabilityStatement открыт, ресурсы читает персонал \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, metadataURL, h.GetMetadata)
+	router.HandlerFunc(http.MethodGet, patientsURL, h.staff(h.search("Patient")))
+	router.HandlerFunc(http.MethodGet, patientURL, h.staff(h.read("Patient")))
+	router.HandlerFunc(http.MethodGet, doctorsURL, h.staff(h.search("Practitioner")))
+	router.HandlerFunc(http.MethodGet, doctorURL, h.staff(h.read("Practitioner")))
+	router.HandlerFunc(http.MethodGet, rolesURL, h.staff(h.search("PractitionerRole")))
+	router.HandlerFunc(http.MethodGet, roleURL, h.staff(h.read("PractitionerRole")))
+	router.HandlerFunc(http.MethodGet, visitsURL, h.staff(h.search("Appointment")))
+	router.HandlerFunc(http.MethodGet, visitURL, h.staff(h.read("Appointment")))
+	router.HandlerFunc(http.MethodGet, diseasesURL, h.staff(h.search("Condition")))
+	router.HandlerFunc(http.MethodGet, diseaseURL, h.staff(h.read("Condition")))
+}
+
+/// Функция GetMetadata возвращает CapabilityStatement фасада \\\
+
+func (h *Handler) GetMetadata(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET FHIR METADATA")
+
+	h.write(w, http.StatusOK, h.fhirService.Metadata(r.Context()))
+}
+
+/// Функция read возвращает обработчик чтения ресурса resourceType по id \\\
+
+func (h *Handler) read(resourceType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.logger.Infof("HANDLER: READ FHIR %s", resourceType)
+
+		id := httprouter.ParamsFromContext(r.Context()).ByName("id")
+		resource, err := h.fhirService.Read(r.Context(), resourceType, id)
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		h.logger.Infof("GOT FHIR %s", resourceType)
+		h.write(w, http.StatusOK, resource)
+	}
+}
+
+/// Функция search возвращает обработчик поиска ресурсов resourceType, ответ - Bundle \\\
+
+func (h *Handler) search(resourceType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.logger.Infof("HANDLER: SEARCH FHIR %s", resourceType)
+
+		bundle, err := h.fhirService.Search(r.Context(), resourceType, r.URL.Query())
+		if err != nil {
+			h.writeError(w, err)
+			return
+		}
+		h.logger.Infof("FOUND FHIR %s", resourceType)
+		h.write(w, http.StatusOK, bundle)
+	}
+}
+
+/// Функция write отвечает ресурсом FHIR с типом содержимого application/fhir+json \\\
+
+func (h *Handler) write(w http.ResponseWriter, code int, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		h.logger.Errorf("failed to encode FHIR resource: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(code)
+	if _, err = w.Write(body); err != nil {
+		h.logger.Warnf("failed to write FHIR resource: %v", err)
+	}
+}
+
+/// Функция writeError отвечает OperationOutcome с кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	code, issue := http.StatusInternalServerError, "exception"
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		code, issue = http.StatusNotFound, "not-found"
+	case errors.Is(err, apperror.ErrInvalidFHIRSearch):
+		code, issue = http.StatusBadRequest, "invalid"
+	}
+	h.write(w, code, OperationOutcome{
+		ResourceType: "OperationOutcome",
+		Issue:        []Issue{{Severity: "error", Code: issue, Diagnostics: err.Error()}},
+	})
+}
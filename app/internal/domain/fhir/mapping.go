@@ -0,0 +1,156 @@
+package fhir
+
+import (
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/user"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// Функция patient переводит пациента в ресурс Patient, полис ОМС - идентификатор пациента \\\
+
+func patient(u *user.User) Patient {
+	resource := Patient{
+		ResourceType: "Patient",
+		ID:           strconv.FormatInt(u.ID, 10),
+		Identifier:   []Identifier{{System: SystemPolicy, Value: u.PolicyNumber}},
+		Active:       true,
+		Name:         []HumanName{humanName(u.Surname, u.Name, u.Patronymic)},
+		Gender:       gender(u.Gender),
+	}
+	if u.Email != "" {
+		resource.Telecom = append(resource.Telecom, ContactPoint{System: "email", Value: u.Email})
+	}
+	if u.PhoneNumber != nil && *u.PhoneNumber != "" {
+		resource.Telecom = append(resource.Telecom, ContactPoint{System: "phone", Value: *u.PhoneNumber})
+	}
+	if u.Address != nil && *u.Address != "" {
+		resource.Address = []Address{{Text: *u.Address}}
+	}
+	return resource
+}
+
+/// Функция practitioner переводит доктора в ресурс Practitioner, активен доктор, к которому открыта запись \\\
+
+func practitioner(d *doctor.Doctor) Practitioner {
+	return Practitioner{
+		ResourceType: "Practitioner",
+		ID:           strconv.FormatInt(d.ID, 10),
+		Active:       d.RecordingIsAvailable,
+		Name:         []HumanName{humanName(d.Surname, d.Name, d.Patronymic)},
+		Gender:       gender(d.Gender),
+	}
+}
+
+/// Функция practitionerRole переводит специализацию доктора в ресурс PractitionerRole \\\
+
+func practitionerRole(r *Role) PractitionerRole {
+	return PractitionerRole{
+		ResourceType: "PractitionerRole",
+		ID:           roleId(r.DoctorID, r.SpecializationID),
+		Active:       r.Active,
+		Practitioner: Reference{
+			Reference: reference("Practitioner", r.DoctorID),
+			Display:   r.Surname + " " + r.Name,
+		},
+		Specialty: []CodeableConcept{specialty(r.SpecializationID, r.Specialization)},
+	}
+}
+
+/// Функция appointment переводит запись в ресурс Appointment, прием длится один слот \\\
+/// Адрес и кабинет передаются участником-местом только с display, ресурса Location у фасада нет \\\
+
+func appointment(v *Visit, slot time.Duration) Appointment {
+	resource := Appointment{
+		ResourceType:    "Appointment",
+		ID:              strconv.FormatInt(v.ID, 10),
+		Status:          v.Status,
+		Specialty:       []CodeableConcept{specialty(v.SpecializationID, v.Specialization)},
+		Start:           v.TimeRecord.UTC(),
+		End:             v.TimeRecord.Add(slot).UTC(),
+		MinutesDuration: int(slot / time.Minute),
+		Comment:         v.Tagging,
+		Participant: []Participant{
+			{Actor: Reference{Reference: reference("Patient", v.PatientsID)}, Required: "required", Status: "accepted"},
+			{Actor: Reference{Reference: reference("Practitioner", v.DoctorID)}, Required: "required", Status: "accepted"},
+		},
+	}
+	if location := strings.Trim(v.HospitalAddress+", "+v.DoctorOffice, ", "); location != "" {
+		resource.Participant = append(resource.Participant,
+			Participant{Actor: Reference{Display: location}, Required: "information-only", Status: "accepted"})
+	}
+	return resource
+}
+
+/// Функция condition переводит заболевание из карты пациента в ресурс Condition \\\
+
+func condition(d *Disease) Condition {
+	resource := Condition{
+		ResourceType: "Condition",
+		ID:           conditionId(d.PatientID, d.ID),
+		ClinicalStatus: CodeableConcept{
+			Coding: []Coding{{System: SystemConditionState, Code: "active"}},
+		},
+		Code:    CodeableConcept{Text: d.Description},
+		Subject: Reference{Reference: reference("Patient", d.PatientID)},
+	}
+	if d.BodyPart != "" {
+		resource.BodySite = []CodeableConcept{{Text: d.BodyPart}}
+	}
+	return resource
+}
+
+/// Функция humanName собирает имя: фамилия - family, имя и отчество - given \\\
+
+func humanName(surname, name string, patronymic *string) HumanName {
+	given := []string{name}
+	if patronymic != nil && *patronymic != "" {
+		given = append(given, *patronymic)
+	}
+	return HumanName{
+		Use:    "official",
+		Text:   strings.Join(append([]string{surname}, given...), " "),
+		Family: surname,
+		Given:  given,
+	}
+}
+
+/// Функция gender переводит пол в значения FHIR male, female, other и unknown \\\
+
+func gender(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "male":
+		return "male"
+	case "female":
+		return "female"
+	case "":
+		return "unknown"
+	default:
+		return "other"
+	}
+}
+
+/// Функция specialty кодирует специализацию локальной системой кодов \\\
+
+func specialty(id int64, name string) CodeableConcept {
+	return CodeableConcept{
+		Coding: []Coding{{System: SystemSpecialization, Code: strconv.FormatInt(id, 10), Display: name}},
+		Text:   name,
+	}
+}
+
+/// Функции reference, roleId и conditionId собирают ссылки и составные id ресурсов \\\
+
+func reference(resourceType string, id int64) string {
+	return fmt.Sprintf("%s/%d", resourceType, id)
+}
+
+func roleId(doctorId, specializationId int64) string {
+	return fmt.Sprintf("%d-%d", doctorId, specializationId)
+}
+
+func conditionId(patientId, diseaseId int64) string {
+	return fmt.Sprintf("%d-%d", patientId, diseaseId)
+}
@@ -0,0 +1,330 @@
+package fhir
+
+import (
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &FHIRStorage{}
+
+/// Поиск пациентов: фамилия и имя ищутся по началу строки без учета регистра, имя - и по отчеству \\\
+
+const selectPatients = `
+SELECT id, email, name, surname, patronymic, age, gender, phone_number, address, policy_number, disease_id, created_at
+FROM patients
+WHERE ($1::bigint IS NULL OR id = $1)
+  AND ($2::text IS NULL OR policy_number = $2)
+  AND ($3::text IS NULL OR surname ILIKE $3 || '%')
+  AND ($4::text IS NULL OR name ILIKE $4 || '%' OR patronymic ILIKE $4 || '%')
+  AND ($5::text IS NULL OR lower(email) = lower($5))
+  AND ($6::text IS NULL OR phone_number = $6)
+  AND ($7::text IS NULL OR gender = $7)
+ORDER BY id
+LIMIT $8 OFFSET $9`
+
+/// Поиск докторов: параметр name ищется и в фамилии, и в имени \\\
+
+const selectPractitioners = `
+SELECT d.id, d.name, d.surname, d.patronymic, d.image_id, d.gender, d.rating, d.age,
+       coalesce(d.recording_is_available, true), d.specialization_id, d.portfolio_id,
+       ARRAY(SELECT ds.specialization_id FROM doctor_specialization ds WHERE ds.doctor_id = d.id ORDER BY ds.specialization_id)
+FROM doctors d
+WHERE ($1::bigint IS NULL OR d.id = $1)
+  AND ($2::text IS NULL OR d.surname ILIKE $2 || '%' OR d.name ILIKE $2 || '%')
+  AND ($3::text IS NULL OR d.surname ILIKE $3 || '%')
+  AND ($4::text IS NULL OR d.name ILIKE $4 || '%' OR d.patronymic ILIKE $4 || '%')
+  AND ($5::text IS NULL OR d.gender = $5)
+ORDER BY d.id
+LIMIT $6 OFFSET $7`
+
+/// Поиск специализаций докторов \\\
+
+const selectRoles = `
+SELECT d.id, d.surname, d.name, s.id, s.name_specialization, coalesce(d.recording_is_available, true)
+FROM doctor_specialization ds
+INNER JOIN doctors d ON d.id = ds.doctor_id
+INNER JOIN specialization s ON s.id = ds.specialization_id
+WHERE ($1::bigint IS NULL OR ds.doctor_id = $1)
+  AND ($2::bigint IS NULL OR ds.specialization_id = $2)
+ORDER BY d.id, s.id
+LIMIT $3 OFFSET $4`
+
+/// Поиск записей вместе с отмененными: отмененная запись - cancelled, прошедшая - fulfilled, остальные - booked \\\
+
+const selectAppointments = `
+SELECT id, hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record,
+       status, name_specialization
+FROM (SELECT v.*, s.name_specialization,
+             CASE WHEN v.cancelled THEN 'cancelled' WHEN v.time_record < now() THEN 'fulfilled' ELSE 'booked' END AS status
+      FROM (SELECT id, hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record,
+                   false AS cancelled
+            FROM record
+            UNION ALL
+            SELECT id, hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record,
+                   true AS cancelled
+            FROM record_cancellation) v
+      INNER JOIN specialization s ON s.id = v.specialization_id) a
+WHERE ($1::bigint IS NULL OR id = $1)
+  AND ($2::bigint IS NULL OR patients_id = $2)
+  AND ($3::bigint IS NULL OR doctor_id = $3)
+  AND ($4::timestamptz IS NULL OR time_record >= $4)
+  AND ($5::timestamptz IS NULL OR time_record < $5)
+  AND ($6::text IS NULL OR status = $6)
+ORDER BY time_record, id
+LIMIT $7 OFFSET $8`
+
+/// Поиск заболеваний из карт пациентов \\\
+
+const selectConditions = `
+SELECT p.id, d.id, d.body_part, d.description
+FROM patients p
+CROSS JOIN LATERAL unnest(p.disease_id) AS pd(id)
+INNER JOIN disease d ON d.id = pd.id
+WHERE ($1::bigint IS NULL OR p.id = $1)
+  AND ($2::bigint IS NULL OR d.id = $2)
+ORDER BY p.id, d.id
+LIMIT $3 OFFSET $4`
+
+/// Структура FHIRStorage содержащая поля для работы с БД \\\
+
+type FHIRStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр FHIRStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &FHIRStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция FindPatients для сущности FHIRStorage ищет пациентов, пароль не выбирается \\\
+
+func (f *FHIRStorage) FindPatients(search *PatientSearch) ([]user.User, error) {
+	f.logger.Info("POSTGRES: FHIR FIND PATIENTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx, selectPatients,
+		search.ID, search.Identifier, search.Family, search.Given, search.Email, search.Phone, search.Gender,
+		search.Count, search.Offset)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения пациентов \\\
+	patients := make([]user.User, 0)
+
+	for rows.Next() {
+		var patient user.User
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(
+			&patient.ID, &patient.Email, &patient.Name, &patient.Surname, &patient.Patronymic,
+			&patient.Age, &patient.Gender, &patient.PhoneNumber, &patient.Address,
+			&patient.PolicyNumber, &patient.DiseaseID, &patient.CreatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to execute fhir find patients query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		patients = append(patients, patient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return patients, nil
+}
+
+/// Функция FindPractitioners для сущности FHIRStorage ищет докторов со всеми их специализациями \\\
+
+func (f *FHIRStorage) FindPractitioners(search *PractitionerSearch) ([]doctor.Doctor, error) {
+	f.logger.Info("POSTGRES: FHIR FIND PRACTITIONERS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx, selectPractitioners,
+		search.ID, search.Name, search.Family, search.Given, search.Gender, search.Count, search.Offset)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения докторов \\\
+	doctors := make([]doctor.Doctor, 0)
+
+	for rows.Next() {
+		var practitioner doctor.Doctor
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(
+			&practitioner.ID, &practitioner.Name, &practitioner.Surname, &practitioner.Patronymic,
+			&practitioner.ImageID, &practitioner.Gender, &practitioner.Rating, &practitioner.Age,
+			&practitioner.RecordingIsAvailable, &practitioner.SpecializationID, &practitioner.PortfolioID,
+			&practitioner.SpecializationIDs,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to execute fhir find practitioners query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		doctors = append(doctors, practitioner)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return doctors, nil
+}
+
+/// Функция FindRoles для сущности FHIRStorage ищет пары доктор - специализация \\\
+
+func (f *FHIRStorage) FindRoles(search *RoleSearch) ([]Role, error) {
+	f.logger.Info("POSTGRES: FHIR FIND PRACTITIONER ROLES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx, selectRoles,
+		search.PractitionerID, search.SpecializationID, search.Count, search.Offset)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения специализаций докторов \\\
+	roles := make([]Role, 0)
+
+	for rows.Next() {
+		var role Role
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(
+			&role.DoctorID, &role.Surname, &role.Name, &role.SpecializationID, &role.Specialization, &role.Active,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to execute fhir find practitioner roles query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+/// Функция FindAppointments для сущности FHIRStorage ищет записи, включая отмененные \\\
+
+func (f *FHIRStorage) FindAppointments(search *AppointmentSearch) ([]Visit, error) {
+	f.logger.Info("POSTGRES: FHIR FIND APPOINTMENTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx, selectAppointments,
+		search.ID, search.PatientID, search.PractitionerID, search.From, search.To, search.Status,
+		search.Count, search.Offset)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения записей \\\
+	visits := make([]Visit, 0)
+
+	for rows.Next() {
+		var visit Visit
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(
+			&visit.ID, &visit.HospitalAddress, &visit.DoctorOffice, &visit.Tagging, &visit.PatientsID,
+			&visit.DoctorID, &visit.SpecializationID, &visit.TimeRecord, &visit.Status, &visit.Specialization,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to execute fhir find appointments query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		visits = append(visits, visit)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return visits, nil
+}
+
+/// Функция FindConditions для сущности FHIRStorage ищет заболевания из карт пациентов \\\
+
+func (f *FHIRStorage) FindConditions(search *ConditionSearch) ([]Disease, error) {
+	f.logger.Info("POSTGRES: FHIR FIND CONDITIONS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), f.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := f.conn.Query(ctx, selectConditions,
+		search.PatientID, search.DiseaseID, search.Count, search.Offset)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		f.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения заболеваний \\\
+	diseases := make([]Disease, 0)
+
+	for rows.Next() {
+		var disease Disease
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&disease.PatientID, &disease.ID, &disease.BodyPart, &disease.Description)
+		if err != nil {
+			err = fmt.Errorf("failed to execute fhir find conditions query: %v", err)
+			f.logger.Error(err)
+			return nil, err
+		}
+		diseases = append(diseases, disease)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return diseases, nil
+}
@@ -0,0 +1,214 @@
+package fhir
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// Параметры поиска ресурсов, они же публикуются в CapabilityStatement \\\
+/// Служебные параметры _count и _offset допустимы для всех ресурсов \\\
+
+var searchParams = map[string][]CapabilitySearch{
+	"Patient": {
+		{Name: "_id", Type: "token"}, {Name: "identifier", Type: "token"}, {Name: "family", Type: "string"},
+		{Name: "given", Type: "string"}, {Name: "email", Type: "token"}, {Name: "phone", Type: "token"},
+		{Name: "gender", Type: "token"},
+	},
+	"Practitioner": {
+		{Name: "_id", Type: "token"}, {Name: "name", Type: "string"}, {Name: "family", Type: "string"},
+		{Name: "given", Type: "string"}, {Name: "gender", Type: "token"},
+	},
+	"PractitionerRole": {
+		{Name: "_id", Type: "token"}, {Name: "practitioner", Type: "reference"}, {Name: "specialty", Type: "token"},
+	},
+	"Appointment": {
+		{Name: "_id", Type: "token"}, {Name: "patient", Type: "reference"}, {Name: "practitioner", Type: "reference"},
+		{Name: "date", Type: "date"}, {Name: "status", Type: "token"},
+	},
+	"Condition": {
+		{Name: "_id", Type: "token"}, {Name: "patient", Type: "reference"}, {Name: "subject", Type: "reference"},
+	},
+}
+
+/// Функция withoutAuth возвращает параметры поиска без ключа сотрудника staff_key \\\
+/// Ключ проверяет middleware, он не является параметром поиска и не должен попасть в ссылки self и next \\\
+
+func withoutAuth(query url.Values) url.Values {
+	params := url.Values{}
+	for name, values := range query {
+		if name == "staff_key" {
+			continue
+		}
+		params[name] = values
+	}
+	return params
+}
+
+/// Функция checkParams отклоняет неизвестные параметры, чтобы опечатка не превратилась в поиск без фильтра \\\
+
+func checkParams(resourceType string, query url.Values) error {
+	for name := range query {
+		switch name {
+		case "_count", "_offset", "_format":
+			continue
+		}
+		known := false
+		for _, param := range searchParams[resourceType] {
+			if param.Name == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: %s does not support %q", apperror.ErrInvalidFHIRSearch, resourceType, name)
+		}
+	}
+	return nil
+}
+
+/// Функция page читает _count и _offset, _count ограничен сверху \\\
+
+func (s *service) page(query url.Values) (Page, error) {
+	page := Page{Count: s.pageSize}
+	if raw := query.Get("_count"); raw != "" {
+		count, err := strconv.Atoi(raw)
+		if err != nil || count < 1 {
+			return page, fmt.Errorf("%w: _count must be a positive integer", apperror.ErrInvalidFHIRSearch)
+		}
+		page.Count = count
+	}
+	if page.Count > s.maxPage {
+		page.Count = s.maxPage
+	}
+	if raw := query.Get("_offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return page, fmt.Errorf("%w: _offset must be a non-negative integer", apperror.ErrInvalidFHIRSearch)
+		}
+		page.Offset = offset
+	}
+	return page, nil
+}
+
+/// Функция stringParam возвращает непустое значение параметра \\\
+
+func stringParam(query url.Values, name string) *string {
+	value := strings.TrimSpace(query.Get(name))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+/// Функция idParam читает положительный целый id, ссылка вида "Patient/1" тоже допустима \\\
+
+func idParam(query url.Values, name, resourceType string) (*int64, error) {
+	value := stringParam(query, name)
+	if value == nil {
+		return nil, nil
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(*value, resourceType+"/"), 10, 64)
+	if err != nil || id < 1 {
+		return nil, fmt.Errorf("%w: %s must be a %s id", apperror.ErrInvalidFHIRSearch, name, resourceType)
+	}
+	return &id, nil
+}
+
+/// Функция pairParam читает составной id вида "<id>-<id>" \\\
+
+func pairParam(query url.Values, name string) (*int64, *int64, error) {
+	value := stringParam(query, name)
+	if value == nil {
+		return nil, nil, nil
+	}
+	parts := strings.Split(*value, "-")
+	if len(parts) == 2 {
+		first, err1 := strconv.ParseInt(parts[0], 10, 64)
+		second, err2 := strconv.ParseInt(parts[1], 10, 64)
+		if err1 == nil && err2 == nil && first > 0 && second > 0 {
+			return &first, &second, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: %s must look like <id>-<id>", apperror.ErrInvalidFHIRSearch, name)
+}
+
+/// Функция tokenParam читает токен "system|code" или "code", other - false, если система не совпадает с ожидаемой \\\
+
+func tokenParam(query url.Values, name, system string) (value *string, other bool) {
+	raw := stringParam(query, name)
+	if raw == nil {
+		return nil, false
+	}
+	code := *raw
+	if i := strings.Index(code, "|"); i >= 0 {
+		if i > 0 && code[:i] != system {
+			return nil, true
+		}
+		code = code[i+1:]
+	}
+	return &code, false
+}
+
+/// Функция genderParam проверяет значение пола по справочнику FHIR \\\
+
+func genderParam(query url.Values) (*string, error) {
+	value := stringParam(query, "gender")
+	if value == nil {
+		return nil, nil
+	}
+	switch *value {
+	case "male", "female", "other", "unknown":
+		return value, nil
+	}
+	return nil, fmt.Errorf("%w: gender must be male, female, other or unknown", apperror.ErrInvalidFHIRSearch)
+}
+
+/// Функция dateRange переводит параметры date с префиксами eq, ge, gt, le и lt в интервал [from, to) \\\
+/// Даты задаются как YYYY-MM-DD по местному времени, несколько параметров сужают интервал \\\
+
+func (s *service) dateRange(query url.Values) (from, to *time.Time, err error) {
+	for _, raw := range query["date"] {
+		prefix := "eq"
+		if len(raw) > 2 && raw[0] >= 'a' && raw[0] <= 'z' {
+			prefix, raw = raw[:2], raw[2:]
+		}
+		day, err := time.ParseInLocation("2006-01-02", raw, s.location)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: date must look like [eq|ge|gt|le|lt]YYYY-MM-DD", apperror.ErrInvalidFHIRSearch)
+		}
+		next := day.AddDate(0, 0, 1)
+		switch prefix {
+		case "eq":
+			from, to = later(from, day), earlier(to, next)
+		case "ge":
+			from = later(from, day)
+		case "gt":
+			from = later(from, next)
+		case "le":
+			to = earlier(to, next)
+		case "lt":
+			to = earlier(to, day)
+		default:
+			return nil, nil, fmt.Errorf("%w: unsupported date prefix %q", apperror.ErrInvalidFHIRSearch, prefix)
+		}
+	}
+	return from, to, nil
+}
+
+func later(current *time.Time, t time.Time) *time.Time {
+	if current != nil && current.After(t) {
+		return current
+	}
+	return &t
+}
+
+func earlier(current *time.Time, t time.Time) *time.Time {
+	if current != nil && current.Before(t) {
+		return current
+	}
+	return &t
+}
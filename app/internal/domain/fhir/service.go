@@ -0,0 +1,337 @@
+package fhir
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы чтения ресурсов FHIR \\\
+
+type Service interface {
+	Metadata(ctx context.Context) *CapabilityStatement
+	Read(ctx context.Context, resourceType, id string) (interface{}, error)
+	Search(ctx context.Context, resourceType string, query url.Values) (*Bundle, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service фасада FHIR \\\
+
+type service struct {
+	logger   logger.Logger
+	storage  Storage
+	baseURL  string
+	slot     time.Duration
+	pageSize int
+	maxPage  int
+	location *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+/// Даты в параметрах поиска считаются в часовом поясе фасада, длительность приема - один слот записи \\\
+
+func NewService(storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:   logger,
+		storage:  storage,
+		baseURL:  strings.TrimRight(cfg.FHIR.BaseURL, "/"),
+		slot:     time.Duration(cfg.Records.SlotMinutes) * time.Minute,
+		pageSize: cfg.FHIR.PageSize,
+		maxPage:  cfg.FHIR.MaxPage,
+		location: time.UTC,
+	}
+	if s.pageSize < 1 {
+		s.pageSize = 50
+	}
+	if s.maxPage < s.pageSize {
+		s.maxPage = s.pageSize
+	}
+	if cfg.FHIR.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.FHIR.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown FHIR time zone %q, using UTC: %v", cfg.FHIR.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция Metadata возвращает CapabilityStatement: ресурсы только читаются и ищутся \\\
+
+func (s *service) Metadata(ctx context.Context) *CapabilityStatement {
+	s.logger.Info("SERVICE: GET FHIR METADATA")
+
+	statement := &CapabilityStatement{
+		ResourceType: "CapabilityStatement",
+		Status:       "active",
+		Date:         time.Now().UTC().Format("2006-01-02"),
+		Kind:         "instance",
+		FhirVersion:  Version,
+		Format:       []string{"json"},
+		Rest:         []Capability{{Mode: "server"}},
+	}
+	for _, resourceType := range []string{"Patient", "Practitioner", "PractitionerRole", "Appointment", "Condition"} {
+		statement.Rest[0].Resource = append(statement.Rest[0].Resource, CapabilityResource{
+			Type:        resourceType,
+			Interaction: []CapabilityCode{{Code: "read"}, {Code: "search-type"}},
+			SearchParam: searchParams[resourceType],
+		})
+	}
+	return statement
+}
+
+/// Функция Read возвращает ресурс по id, это поиск по _id с одним результатом \\\
+/// Id неверного вида не может принадлежать ресурсу, поэтому это тоже 404, а не 400 \\\
+
+func (s *service) Read(ctx context.Context, resourceType, id string) (interface{}, error) {
+	s.logger.Info("SERVICE: READ FHIR RESOURCE")
+
+	if _, ok := searchParams[resourceType]; !ok {
+		return nil, apperror.ErrEmptyString
+	}
+	resources, err := s.find(resourceType, url.Values{"_id": {id}}, Page{Count: 1})
+	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidFHIRSearch) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, err
+	}
+	if len(resources) == 0 {
+		return nil, apperror.ErrEmptyString
+	}
+	return resources[0].resource, nil
+}
+
+/// Функция Search ищет ресурсы и собирает Bundle searchset \\\
+/// Запрашивается на один ресурс больше страницы, чтобы понять, нужна ли ссылка next \\\
+
+func (s *service) Search(ctx context.Context, resourceType string, query url.Values) (*Bundle, error) {
+	s.logger.Info("SERVICE: SEARCH FHIR RESOURCES")
+
+	if _, ok := searchParams[resourceType]; !ok {
+		return nil, apperror.ErrEmptyString
+	}
+	query = withoutAuth(query)
+	if err := checkParams(resourceType, query); err != nil {
+		return nil, err
+	}
+	page, err := s.page(query)
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.find(resourceType, query, Page{Count: page.Count + 1, Offset: page.Offset})
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &Bundle{
+		ResourceType: "Bundle",
+		Type:         "searchset",
+		Link:         []BundleLink{{Relation: "self", URL: s.pageURL(resourceType, query, page)}},
+		Entry:        make([]BundleEntry, 0, len(resources)),
+	}
+	if len(resources) > page.Count {
+		resources = resources[:page.Count]
+		next := Page{Count: page.Count, Offset: page.Offset + page.Count}
+		bundle.Link = append(bundle.Link, BundleLink{Relation: "next", URL: s.pageURL(resourceType, query, next)})
+	}
+	for _, r := range resources {
+		bundle.Entry = append(bundle.Entry, BundleEntry{
+			FullURL:  fmt.Sprintf("%s/%s/%s", s.baseURL, resourceType, r.id),
+			Resource: r.resource,
+			Search:   EntrySearch{Mode: "match"},
+		})
+	}
+	return bundle, nil
+}
+
+/// Структура found - найденный ресурс и его id для fullUrl \\\
+
+type found struct {
+	id       string
+	resource interface{}
+}
+
+/// Функция find разбирает параметры поиска ресурса, ищет в хранилище и переводит результаты в ресурсы FHIR \\\
+
+func (s *service) find(resourceType string, query url.Values, page Page) ([]found, error) {
+	resources := make([]found, 0)
+
+	switch resourceType {
+	case "Patient":
+		search := PatientSearch{Page: page}
+		var err error
+		if search.ID, err = idParam(query, "_id", resourceType); err != nil {
+			return nil, err
+		}
+		var other bool
+		if search.Identifier, other = tokenParam(query, "identifier", SystemPolicy); other {
+			return resources, nil
+		}
+		if search.Gender, err = genderParam(query); err != nil {
+			return nil, err
+		}
+		search.Family = stringParam(query, "family")
+		search.Given = stringParam(query, "given")
+		search.Email = stringParam(query, "email")
+		search.Phone = stringParam(query, "phone")
+
+		patients, err := s.storage.FindPatients(&search)
+		if err != nil {
+			return nil, err
+		}
+		for i := range patients {
+			r := patient(&patients[i])
+			resources = append(resources, found{id: r.ID, resource: r})
+		}
+
+	case "Practitioner":
+		search := PractitionerSearch{Page: page}
+		var err error
+		if search.ID, err = idParam(query, "_id", resourceType); err != nil {
+			return nil, err
+		}
+		if search.Gender, err = genderParam(query); err != nil {
+			return nil, err
+		}
+		search.Name = stringParam(query, "name")
+		search.Family = stringParam(query, "family")
+		search.Given = stringParam(query, "given")
+
+		doctors, err := s.storage.FindPractitioners(&search)
+		if err != nil {
+			return nil, err
+		}
+		for i := range doctors {
+			r := practitioner(&doctors[i])
+			resources = append(resources, found{id: r.ID, resource: r})
+		}
+
+	case "PractitionerRole":
+		search := RoleSearch{Page: page}
+		doctorId, specializationId, err := pairParam(query, "_id")
+		if err != nil {
+			return nil, err
+		}
+		if search.PractitionerID, err = idParam(query, "practitioner", "Practitioner"); err != nil {
+			return nil, err
+		}
+		if search.PractitionerID, err = same(search.PractitionerID, doctorId); err != nil {
+			return resources, nil
+		}
+		specialization, other := tokenParam(query, "specialty", SystemSpecialization)
+		if other {
+			return resources, nil
+		}
+		if specialization != nil {
+			id, err := strconv.ParseInt(*specialization, 10, 64)
+			if err != nil || id < 1 {
+				return nil, fmt.Errorf("%w: specialty must be a specialization id", apperror.ErrInvalidFHIRSearch)
+			}
+			search.SpecializationID = &id
+		}
+		if search.SpecializationID, err = same(search.SpecializationID, specializationId); err != nil {
+			return resources, nil
+		}
+
+		roles, err := s.storage.FindRoles(&search)
+		if err != nil {
+			return nil, err
+		}
+		for i := range roles {
+			r := practitionerRole(&roles[i])
+			resources = append(resources, found{id: r.ID, resource: r})
+		}
+
+	case "Appointment":
+		search := AppointmentSearch{Page: page}
+		var err error
+		if search.ID, err = idParam(query, "_id", resourceType); err != nil {
+			return nil, err
+		}
+		if search.PatientID, err = idParam(query, "patient", "Patient"); err != nil {
+			return nil, err
+		}
+		if search.PractitionerID, err = idParam(query, "practitioner", "Practitioner"); err != nil {
+			return nil, err
+		}
+		if search.From, search.To, err = s.dateRange(query); err != nil {
+			return nil, err
+		}
+		if search.Status = stringParam(query, "status"); search.Status != nil {
+			switch *search.Status {
+			case AppointmentBooked, AppointmentFulfilled, AppointmentCancelled:
+			default:
+				return nil, fmt.Errorf("%w: status must be booked, fulfilled or cancelled", apperror.ErrInvalidFHIRSearch)
+			}
+		}
+
+		visits, err := s.storage.FindAppointments(&search)
+		if err != nil {
+			return nil, err
+		}
+		for i := range visits {
+			r := appointment(&visits[i], s.slot)
+			resources = append(resources, found{id: r.ID, resource: r})
+		}
+
+	case "Condition":
+		search := ConditionSearch{Page: page}
+		patientId, diseaseId, err := pairParam(query, "_id")
+		if err != nil {
+			return nil, err
+		}
+		search.DiseaseID = diseaseId
+		for _, name := range []string{"patient", "subject"} {
+			id, err := idParam(query, name, "Patient")
+			if err != nil {
+				return nil, err
+			}
+			if patientId, err = same(patientId, id); err != nil {
+				return resources, nil
+			}
+		}
+		search.PatientID = patientId
+
+		diseases, err := s.storage.FindConditions(&search)
+		if err != nil {
+			return nil, err
+		}
+		for i := range diseases {
+			r := condition(&diseases[i])
+			resources = append(resources, found{id: r.ID, resource: r})
+		}
+	}
+	return resources, nil
+}
+
+/// Функция same объединяет два условия на один id, разные значения - ошибка, то есть пустой результат \\\
+
+func same(a, b *int64) (*int64, error) {
+	if a == nil {
+		return b, nil
+	}
+	if b != nil && *a != *b {
+		return nil, apperror.ErrEmptyString
+	}
+	return a, nil
+}
+
+/// Функция pageURL собирает ссылку на страницу поиска с теми же параметрами \\\
+
+func (s *service) pageURL(resourceType string, query url.Values, page Page) string {
+	params := url.Values{}
+	for name, values := range query {
+		params[name] = values
+	}
+	params.Set("_count", strconv.Itoa(page.Count))
+	params.Set("_offset", strconv.Itoa(page.Offset))
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, resourceType, params.Encode())
+}
@@ -0,0 +1,14 @@
+package fhir
+
+import (
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/user"
+)
+
+type Storage interface {
+	FindPatients(search *PatientSearch) ([]user.User, error)
+	FindPractitioners(search *PractitionerSearch) ([]doctor.Doctor, error)
+	FindRoles(search *RoleSearch) ([]Role, error)
+	FindAppointments(search *AppointmentSearch) ([]Visit, error)
+	FindConditions(search *ConditionSearch) ([]Disease, error)
+}
@@ -1,7 +1,14 @@
 package handler
 
-import "github.com/julienschmidt/httprouter"
+import (
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
 
 type Hand interface {
 	Register(router *httprouter.Router)
 }
+
+/// Тип Middleware оборачивает обработчик запроса дополнительной логикой (например, проверкой токена доступа) \\\
+
+type Middleware func(next http.HandlerFunc) http.HandlerFunc
@@ -38,3 +38,32 @@ func ReadIdParam16(r *http.Request) (int16, error) {
 
 	return int16(id), nil
 }
+
+/// Значения пагинации по умолчанию и максимальный размер страницы \\\
+
+const (
+	DefaultPerPage = 20
+	MaxPerPage     = 100
+)
+
+/// Функция ReadPagination извлекает параметры page и per_page из URL, отсутствующие параметры заменяются значениями по умолчанию \\\
+
+func ReadPagination(r *http.Request) (int, int, error) {
+	page, perPage := 1, DefaultPerPage
+	query := r.URL.Query()
+	if value := query.Get("page"); value != "" {
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 1 {
+			return 0, 0, fmt.Errorf("page must be a positive integer")
+		}
+		page = p
+	}
+	if value := query.Get("per_page"); value != "" {
+		p, err := strconv.Atoi(value)
+		if err != nil || p < 1 || p > MaxPerPage {
+			return 0, 0, fmt.Errorf("per_page must be an integer from 1 to %d", MaxPerPage)
+		}
+		perPage = p
+	}
+	return page, perPage, nil
+}
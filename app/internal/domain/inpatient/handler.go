@@ -0,0 +1,335 @@
+package inpatient
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+)
+
+const (
+	wardsURL      = "/hospital_record/departments/:id/wards"
+	occupancyURL  = "/hospital_record/departments/:id/occupancy"
+	wardURL       = "/hospital_record/wards/:id"
+	bedsURL       = "/hospital_record/wards/:id/beds"
+	admissionsURL = "/hospital_record/admissions"
+	admissionURL  = "/hospital_record/admissions/:id"
+	transferURL   = "/hospital_record/admissions/:id/transfer"
+	dischargeURL  = "/hospital_record/admissions/:id/discharge"
+)
+
+/// Структура Handler представляющая собой обработчик объекта inpatientService для стационара \\\
+
+type Handler struct {
+	logger           logger.Logger
+	inpatientService Service
+	admin            handler.Middleware
+	staff            handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, inpatientService Service, admin, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:           logger,
+		inpatientService: inpatientService,
+		admin:            admin,
+		staff:            staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для стационара \\\
+/// Палаты и койки заводит администратор, госпитализации ведут сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, wardsURL, h.admin(h.CreateWard))
+	router.HandlerFunc(http.MethodGet, wardsURL, h.staff(h.GetWards))
+	router.HandlerFunc(http.MethodGet, occupancyURL, h.staff(h.GetOccupancy))
+	router.HandlerFunc(http.MethodGet, wardURL, h.staff(h.GetWard))
+	router.HandlerFunc(http.MethodPost, bedsURL, h.admin(h.CreateBed))
+	router.HandlerFunc(http.MethodPost, admissionsURL, h.staff(h.Admit))
+	router.HandlerFunc(http.MethodGet, admissionsURL, h.staff(h.GetAdmissions))
+	router.HandlerFunc(http.MethodGet, admissionURL, h.staff(h.GetAdmission))
+	router.HandlerFunc(http.MethodPost, transferURL, h.staff(h.Transfer))
+	router.HandlerFunc(http.MethodPost, dischargeURL, h.staff(h.Discharge))
+}
+
+/// Функция CreateWard добавляет палату с койками в отделение \\\
+
+func (h *Handler) CreateWard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE WARD")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID отделения из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру CreateWardDTO \\\
+	var input CreateWardDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.DepartmentID = id
+
+	ward, err := h.inpatientService.CreateWard(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("WARD CREATED")
+	response.JSON(w, http.StatusCreated, ward)
+}
+
+/// Функция GetWards получает палаты отделения с койками \\\
+
+func (h *Handler) GetWards(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WARDS")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID отделения из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	wards, err := h.inpatientService.GetWards(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT WARDS")
+	response.JSON(w, http.StatusOK, wards)
+}
+
+/// Функция GetOccupancy получает занятость коек отделения \\\
+
+func (h *Handler) GetOccupancy(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DEPARTMENT OCCUPANCY")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID отделения из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	occupancy, err := h.inpatientService.GetOccupancy(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT DEPARTMENT OCCUPANCY")
+	response.JSON(w, http.StatusOK, occupancy)
+}
+
+/// Функция GetWard получает палату с койками по id \\\
+
+func (h *Handler) GetWard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WARD")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ward, err := h.inpatientService.GetWard(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT WARD")
+	response.JSON(w, http.StatusOK, ward)
+}
+
+/// Функция CreateBed добавляет койку в палату \\\
+
+func (h *Handler) CreateBed(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE BED")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID палаты из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру CreateBedDTO \\\
+	var input CreateBedDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.WardID = id
+
+	bed, err := h.inpatientService.CreateBed(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("BED CREATED")
+	response.JSON(w, http.StatusCreated, bed)
+}
+
+/// Функция Admit госпитализирует пациента \\\
+
+func (h *Handler) Admit(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ADMIT PATIENT")
+
+	/// Чтение тела запроса в структуру AdmitDTO \\\
+	var input AdmitDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	admission, err := h.inpatientService.Admit(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("PATIENT ADMITTED")
+	response.JSON(w, http.StatusCreated, admission)
+}
+
+/// Функция GetAdmissions получает госпитализации, параметры department_id, patient_id и status фильтруют список \\\
+
+func (h *Handler) GetAdmissions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET ADMISSIONS")
+
+	query := r.URL.Query()
+	var departmentId, patientId *int64
+	if raw := query.Get("department_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 1 {
+			response.BadRequest(w, "department_id must be a positive integer", "")
+			return
+		}
+		departmentId = &id
+	}
+	if raw := query.Get("patient_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 1 {
+			response.BadRequest(w, "patient_id must be a positive integer", "")
+			return
+		}
+		patientId = &id
+	}
+	status := query.Get("status")
+	switch status {
+	case "", StatusAdmitted, StatusDischarged:
+	default:
+		response.BadRequest(w, "unknown admission status", "")
+		return
+	}
+
+	admissions, err := h.inpatientService.GetAdmissions(r.Context(), departmentId, patientId, status)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT ADMISSIONS")
+	response.JSON(w, http.StatusOK, admissions)
+}
+
+/// Функция GetAdmission получает госпитализацию с историей коек \\\
+
+func (h *Handler) GetAdmission(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET ADMISSION")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	admission, err := h.inpatientService.GetAdmission(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT ADMISSION")
+	response.JSON(w, http.StatusOK, admission)
+}
+
+/// Функция Transfer переводит пациента на другую койку, для пациента без койки назначает первую \\\
+
+func (h *Handler) Transfer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: TRANSFER PATIENT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру TransferDTO \\\
+	var input TransferDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	admission, err := h.inpatientService.Transfer(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("PATIENT TRANSFERRED")
+	response.JSON(w, http.StatusOK, admission)
+}
+
+/// Функция Discharge выписывает пациента с выписным эпикризом \\\
+
+func (h *Handler) Discharge(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DISCHARGE PATIENT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру DischargeDTO \\\
+	var input DischargeDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	admission, err := h.inpatientService.Discharge(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("PATIENT DISCHARGED")
+	response.JSON(w, http.StatusOK, admission)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidInpatient):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrBedOccupied), errors.Is(err, apperror.ErrPatientAdmitted), errors.Is(err, apperror.ErrAdmissionState):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
@@ -0,0 +1,132 @@
+package inpatient
+
+import (
+	"HospitalRecord/app/internal/domain/outbox"
+	"time"
+)
+
+/// Статусы госпитализации \\\
+
+const (
+	StatusAdmitted   = "admitted"
+	StatusDischarged = "discharged"
+)
+
+/// Структура палаты отделения с ее койками \\\
+
+type Ward struct {
+	ID           int64   `json:"id" example:"1"`
+	DepartmentID int64   `json:"department_id" example:"1"`
+	Number       string  `json:"number" example:"12"`
+	Name         *string `json:"name,omitempty" example:"Palata intensivnoy terapii"`
+	Beds         []Bed   `json:"beds"`
+}
+
+/// Структура койки палаты, у занятой койки заполнена госпитализация \\\
+
+type Bed struct {
+	ID          int64  `json:"id" example:"3"`
+	WardID      int64  `json:"ward_id" example:"1"`
+	Label       string `json:"label" example:"12-A"`
+	Occupied    bool   `json:"occupied" example:"true"`
+	AdmissionID *int64 `json:"admission_id,omitempty" example:"1"`
+}
+
+/// Структура госпитализации пациента: лечащий доктор, диагноз, отделение и текущая койка \\\
+/// Пациент может быть госпитализирован без койки, койка назначается позже переводом \\\
+
+type Admission struct {
+	ID               int64      `json:"id" example:"1"`
+	PatientID        int64      `json:"patient_id" example:"1"`
+	DoctorID         int64      `json:"doctor_id" example:"1"`
+	DiseaseID        *int64     `json:"disease_id,omitempty" example:"1567"`
+	Diagnosis        string     `json:"diagnosis" example:"Perelom luchevoy kosti"`
+	DepartmentID     int64      `json:"department_id" example:"1"`
+	BedID            *int64     `json:"bed_id,omitempty" example:"3"`
+	Status           string     `json:"status" example:"admitted"`
+	AdmittedAt       time.Time  `json:"admitted_at" example:"2023-07-27T10:00:00Z"`
+	DischargedAt     *time.Time `json:"discharged_at,omitempty" example:"2023-08-03T12:00:00Z"`
+	DischargeSummary *string    `json:"discharge_summary,omitempty" example:"Vypisan v udovletvoritelnom sostoyanii"`
+	Movements        []Movement `json:"movements,omitempty"`
+}
+
+/// Структура пребывания на койке, перевод закрывает текущее пребывание и открывает новое \\\
+
+type Movement struct {
+	BedID      int64      `json:"bed_id" example:"3"`
+	BedLabel   string     `json:"bed_label" example:"12-A"`
+	WardID     int64      `json:"ward_id" example:"1"`
+	WardNumber string     `json:"ward_number" example:"12"`
+	AssignedAt time.Time  `json:"assigned_at" example:"2023-07-27T10:00:00Z"`
+	ReleasedAt *time.Time `json:"released_at,omitempty" example:"2023-07-29T09:00:00Z"`
+}
+
+/// Структура занятости коек отделения, awaiting_bed - госпитализированные пациенты без койки \\\
+
+type Occupancy struct {
+	DepartmentID int64           `json:"department_id" example:"1"`
+	Beds         int             `json:"beds" example:"20"`
+	Occupied     int             `json:"occupied" example:"14"`
+	Free         int             `json:"free" example:"6"`
+	AwaitingBed  int             `json:"awaiting_bed" example:"1"`
+	Wards        []WardOccupancy `json:"wards"`
+}
+
+type WardOccupancy struct {
+	WardID   int64  `json:"ward_id" example:"1"`
+	Number   string `json:"number" example:"12"`
+	Beds     int    `json:"beds" example:"4"`
+	Occupied int    `json:"occupied" example:"3"`
+	Free     int    `json:"free" example:"1"`
+}
+
+/// Палата создается сразу с койками, метки коек уникальны в палате \\\
+
+type CreateWardDTO struct {
+	DepartmentID int64    `json:"-"`
+	Number       string   `json:"number" example:"12"`
+	Name         *string  `json:"name,omitempty" example:"Palata intensivnoy terapii"`
+	Beds         []string `json:"beds" example:"12-A,12-B"`
+}
+
+type CreateBedDTO struct {
+	WardID int64  `json:"-"`
+	Label  string `json:"label" example:"12-C"`
+}
+
+/// Без отделения пациент госпитализируется в отделение койки \\\
+
+type AdmitDTO struct {
+	PatientID    int64  `json:"patient_id" example:"1"`
+	DoctorID     int64  `json:"doctor_id" example:"1"`
+	DiseaseID    *int64 `json:"disease_id,omitempty" example:"1567"`
+	Diagnosis    string `json:"diagnosis" example:"Perelom luchevoy kosti"`
+	DepartmentID int64  `json:"department_id,omitempty" example:"1"`
+	BedID        *int64 `json:"bed_id,omitempty" example:"3"`
+}
+
+type TransferDTO struct {
+	ID    int64 `json:"-"`
+	BedID int64 `json:"bed_id" example:"4"`
+}
+
+type DischargeDTO struct {
+	ID      int64  `json:"-"`
+	Summary string `json:"discharge_summary" example:"Vypisan v udovletvoritelnom sostoyanii"`
+}
+
+/// Функция EventPayload возвращает данные доменного события госпитализации \\\
+
+func (a *Admission) EventPayload(previousBedId *int64) outbox.AdmissionPayload {
+	return outbox.AdmissionPayload{
+		AdmissionID:   a.ID,
+		PatientID:     a.PatientID,
+		DoctorID:      a.DoctorID,
+		DepartmentID:  a.DepartmentID,
+		BedID:         a.BedID,
+		PreviousBedID: previousBedId,
+		Status:        a.Status,
+		AdmittedAt:    a.AdmittedAt,
+		DischargedAt:  a.DischargedAt,
+	}
+}
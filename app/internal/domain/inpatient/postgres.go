@@ -0,0 +1,670 @@
+package inpatient
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &InpatientStorage{}
+
+/// Структура InpatientStorage содержащая поля для работы с БД \\\
+
+type InpatientStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр InpatientStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &InpatientStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Запрос палат с койками и текущими госпитализациями на них \\\
+
+const wardsQuery = `SELECT w.id, w.department_id, w.number, w.name, b.id, b.label, a.id
+	 FROM ward w
+	 LEFT JOIN bed b ON b.ward_id = w.id
+	 LEFT JOIN admission a ON a.bed_id = b.id AND a.status = 'admitted'`
+
+/// Функция CreateWard для сущности InpatientStorage добавляет палату с койками в отделение \\\
+
+func (i *InpatientStorage) CreateWard(ward *Ward, labels []string) (*Ward, error) {
+	i.logger.Info("POSTGRES: CREATE WARD")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	tx, err := i.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create ward transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Номер палаты уникален в отделении \\\
+	var exists bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM ward WHERE department_id = $1 AND number = $2)`,
+		ward.DepartmentID, ward.Number).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check ward number: %v", err)
+	}
+	if exists {
+		return nil, apperror.ErrInvalidInpatient
+	}
+
+	/// Выполнение запроса к БД, без отделения палата не создается \\\
+	err = tx.QueryRow(ctx,
+		`INSERT INTO ward (department_id, number, name)
+			 SELECT id, $2, $3 FROM department WHERE id = $1
+			 RETURNING id`,
+		ward.DepartmentID, ward.Number, ward.Name).Scan(&ward.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute create ward query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+
+	ward.Beds = make([]Bed, 0, len(labels))
+	for _, label := range labels {
+		bed := Bed{WardID: ward.ID, Label: label}
+		err = tx.QueryRow(ctx,
+			`INSERT INTO bed (ward_id, label)
+				 VALUES($1,$2)
+				 RETURNING id`, bed.WardID, bed.Label).Scan(&bed.ID)
+		if err != nil {
+			err = fmt.Errorf("failed to execute create bed query: %v", err)
+			i.logger.Error(err)
+			return nil, err
+		}
+		ward.Beds = append(ward.Beds, bed)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create ward transaction: %v", err)
+	}
+	return ward, nil
+}
+
+/// Функция FindWards для сущности InpatientStorage получает палаты отделения с койками \\\
+
+func (i *InpatientStorage) FindWards(departmentId int64) ([]Ward, error) {
+	i.logger.Info("POSTGRES: GET WARDS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := i.conn.Query(ctx, wardsQuery+`
+		 WHERE w.department_id = $1
+		 ORDER BY w.number, w.id, b.label, b.id`, departmentId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	wards, err := collectWards(rows)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find wards query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	return wards, nil
+}
+
+/// Функция FindWardById для сущности InpatientStorage получает палату с койками по id \\\
+
+func (i *InpatientStorage) FindWardById(id int64) (*Ward, error) {
+	i.logger.Info("POSTGRES: GET WARD BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := i.conn.Query(ctx, wardsQuery+`
+		 WHERE w.id = $1
+		 ORDER BY b.label, b.id`, id)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	wards, err := collectWards(rows)
+	if err != nil {
+		err = fmt.Errorf("failed to execute find ward by id query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	if len(wards) == 0 {
+		return nil, apperror.ErrEmptyString
+	}
+	return &wards[0], nil
+}
+
+/// Функция CreateBed для сущности InpatientStorage добавляет койку в палату \\\
+
+func (i *InpatientStorage) CreateBed(bed *Bed) (*Bed, error) {
+	i.logger.Info("POSTGRES: CREATE BED")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	/// Метка койки уникальна в палате \\\
+	var exists bool
+	err := i.conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM bed WHERE ward_id = $1 AND label = $2)`,
+		bed.WardID, bed.Label).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bed label: %v", err)
+	}
+	if exists {
+		return nil, apperror.ErrInvalidInpatient
+	}
+
+	/// Выполнение запроса к БД, без палаты койка не создается \\\
+	err = i.conn.QueryRow(ctx,
+		`INSERT INTO bed (ward_id, label)
+			 SELECT id, $2 FROM ward WHERE id = $1
+			 RETURNING id`, bed.WardID, bed.Label).Scan(&bed.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute create bed query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	return bed, nil
+}
+
+/// Функция FindOccupancy для сущности InpatientStorage считает занятые и свободные койки отделения по палатам \\\
+
+func (i *InpatientStorage) FindOccupancy(departmentId int64) (*Occupancy, error) {
+	i.logger.Info("POSTGRES: GET DEPARTMENT OCCUPANCY")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	occupancy := &Occupancy{DepartmentID: departmentId, Wards: make([]WardOccupancy, 0)}
+
+	/// Проверка существования отделения и подсчет пациентов без койки \\\
+	var exists bool
+	err := i.conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM department WHERE id = $1),
+			 (SELECT count(*) FROM admission WHERE department_id = $1 AND status = 'admitted' AND bed_id IS NULL)`,
+		departmentId).Scan(&exists, &occupancy.AwaitingBed)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	if !exists {
+		return nil, apperror.ErrEmptyString
+	}
+
+	/// Выполнение запроса к БД \\\
+	rows, err := i.conn.Query(ctx,
+		`SELECT w.id, w.number, count(b.id), count(a.id)
+			 FROM ward w
+			 LEFT JOIN bed b ON b.ward_id = w.id
+			 LEFT JOIN admission a ON a.bed_id = b.id AND a.status = 'admitted'
+			 WHERE w.department_id = $1
+			 GROUP BY w.id, w.number
+			 ORDER BY w.number, w.id`, departmentId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		var ward WardOccupancy
+		if err = rows.Scan(&ward.WardID, &ward.Number, &ward.Beds, &ward.Occupied); err != nil {
+			err = fmt.Errorf("failed to execute find occupancy query: %v", err)
+			i.logger.Error(err)
+			return nil, err
+		}
+		ward.Free = ward.Beds - ward.Occupied
+		occupancy.Beds += ward.Beds
+		occupancy.Occupied += ward.Occupied
+		occupancy.Wards = append(occupancy.Wards, ward)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	occupancy.Free = occupancy.Beds - occupancy.Occupied
+	return occupancy, nil
+}
+
+/// Функция Admit для сущности InpatientStorage госпитализирует пациента, койка при наличии занимается в той же транзакции \\\
+
+func (i *InpatientStorage) Admit(admission *Admission) (*Admission, error) {
+	i.logger.Info("POSTGRES: ADMIT PATIENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	tx, err := i.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin admit patient transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Пациент не может быть госпитализирован дважды \\\
+	var admitted bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM admission WHERE patient_id = $1 AND status = $2)`,
+		admission.PatientID, StatusAdmitted).Scan(&admitted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check admissions of patient: %v", err)
+	}
+	if admitted {
+		return nil, apperror.ErrPatientAdmitted
+	}
+
+	/// Койка должна быть свободна и находиться в отделении госпитализации \\\
+	if admission.BedID != nil {
+		departmentId, err := lockBed(ctx, tx, *admission.BedID)
+		if err != nil {
+			return nil, err
+		}
+		if admission.DepartmentID == 0 {
+			admission.DepartmentID = departmentId
+		}
+		if admission.DepartmentID != departmentId {
+			return nil, apperror.ErrInvalidInpatient
+		}
+	} else {
+		var exists bool
+		err = tx.QueryRow(ctx,
+			`SELECT EXISTS (SELECT 1 FROM department WHERE id = $1)`, admission.DepartmentID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check department: %v", err)
+		}
+		if !exists {
+			return nil, apperror.ErrEmptyString
+		}
+	}
+
+	/// Выполнение запроса к БД \\\
+	created, err := scanAdmission(tx.QueryRow(ctx,
+		`INSERT INTO admission (patient_id, doctor_id, disease_id, diagnosis, department_id, bed_id, status)
+			 VALUES($1,$2,$3,$4,$5,$6,$7)
+			 RETURNING *`,
+		admission.PatientID, admission.DoctorID, admission.DiseaseID, admission.Diagnosis,
+		admission.DepartmentID, admission.BedID, StatusAdmitted))
+	if err != nil {
+		err = fmt.Errorf("failed to execute admit patient query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	if created.BedID != nil {
+		if err = assignBed(ctx, tx, created.ID, *created.BedID); err != nil {
+			return nil, err
+		}
+	}
+	if err = outbox.Write(ctx, tx, outbox.AdmissionCreated, created.ID, created.EventPayload(nil)); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit admit patient transaction: %v", err)
+	}
+	return created, nil
+}
+
+/// Функция FindAdmissionById для сущности InpatientStorage получает госпитализацию по id \\\
+
+func (i *InpatientStorage) FindAdmissionById(id int64) (*Admission, error) {
+	i.logger.Info("POSTGRES: GET ADMISSION BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	admission, err := scanAdmission(i.conn.QueryRow(ctx,
+		`SELECT * FROM admission
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find admission by id query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	return admission, nil
+}
+
+/// Функция FindAdmissions для сущности InpatientStorage получает госпитализации, новые первыми \\\
+/// Пустые departmentId, patientId и status не ограничивают выборку \\\
+
+func (i *InpatientStorage) FindAdmissions(departmentId, patientId *int64, status string) ([]Admission, error) {
+	i.logger.Info("POSTGRES: GET ADMISSIONS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := i.conn.Query(ctx,
+		`SELECT * FROM admission
+			 WHERE ($1::bigint IS NULL OR department_id = $1)
+			 AND ($2::bigint IS NULL OR patient_id = $2)
+			 AND ($3 = '' OR status = $3)
+			 ORDER BY admitted_at DESC, id DESC`,
+		departmentId, patientId, status)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения госпитализаций \\\
+	admissions := make([]Admission, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		admission, err := scanAdmission(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find admissions query: %v", err)
+			i.logger.Error(err)
+			return nil, err
+		}
+		admissions = append(admissions, *admission)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return admissions, nil
+}
+
+/// Функция FindMovements для сущности InpatientStorage получает историю коек госпитализации по порядку \\\
+
+func (i *InpatientStorage) FindMovements(admissionId int64) ([]Movement, error) {
+	i.logger.Info("POSTGRES: GET ADMISSION MOVEMENTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := i.conn.Query(ctx,
+		`SELECT b.id, b.label, w.id, w.number, m.assigned_at, m.released_at
+			 FROM bed_assignment m
+			 JOIN bed b ON b.id = m.bed_id
+			 JOIN ward w ON w.id = b.ward_id
+			 WHERE m.admission_id = $1
+			 ORDER BY m.assigned_at, m.id`, admissionId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения истории коек \\\
+	movements := make([]Movement, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		var m Movement
+		if err = rows.Scan(&m.BedID, &m.BedLabel, &m.WardID, &m.WardNumber, &m.AssignedAt, &m.ReleasedAt); err != nil {
+			err = fmt.Errorf("failed to execute find admission movements query: %v", err)
+			i.logger.Error(err)
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+/// Функция Transfer для сущности InpatientStorage переводит пациента на свободную койку или назначает первую койку \\\
+/// Отделение госпитализации меняется на отделение новой койки \\\
+
+func (i *InpatientStorage) Transfer(input *TransferDTO) (*Admission, error) {
+	i.logger.Info("POSTGRES: TRANSFER PATIENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	tx, err := i.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transfer patient transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockAdmission(ctx, tx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.BedID != nil && *current.BedID == input.BedID {
+		return nil, apperror.ErrInvalidInpatient
+	}
+	departmentId, err := lockBed(ctx, tx, input.BedID)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Выполнение запроса к БД \\\
+	admission, err := scanAdmission(tx.QueryRow(ctx,
+		`UPDATE admission SET bed_id = $1, department_id = $2
+			 WHERE id = $3
+			 RETURNING *`, input.BedID, departmentId, input.ID))
+	if err != nil {
+		err = fmt.Errorf("failed to execute transfer patient query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	if err = releaseBed(ctx, tx, admission.ID); err != nil {
+		return nil, err
+	}
+	if err = assignBed(ctx, tx, admission.ID, input.BedID); err != nil {
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, outbox.AdmissionTransferred, admission.ID, admission.EventPayload(current.BedID)); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer patient transaction: %v", err)
+	}
+	return admission, nil
+}
+
+/// Функция Discharge для сущности InpatientStorage выписывает пациента и освобождает его койку \\\
+
+func (i *InpatientStorage) Discharge(input *DischargeDTO) (*Admission, error) {
+	i.logger.Info("POSTGRES: DISCHARGE PATIENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), i.requestTimeout)
+	defer cancel()
+
+	tx, err := i.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin discharge patient transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = lockAdmission(ctx, tx, input.ID); err != nil {
+		return nil, err
+	}
+
+	/// Выполнение запроса к БД, койка остается в госпитализации как последняя, но со сменой статуса считается свободной \\\
+	admission, err := scanAdmission(tx.QueryRow(ctx,
+		`UPDATE admission SET status = $1, discharged_at = now(), discharge_summary = $2
+			 WHERE id = $3
+			 RETURNING *`, StatusDischarged, input.Summary, input.ID))
+	if err != nil {
+		err = fmt.Errorf("failed to execute discharge patient query: %v", err)
+		i.logger.Error(err)
+		return nil, err
+	}
+	if err = releaseBed(ctx, tx, admission.ID); err != nil {
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, outbox.AdmissionDischarged, admission.ID, admission.EventPayload(nil)); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit discharge patient transaction: %v", err)
+	}
+	return admission, nil
+}
+
+/// Функция lockAdmission блокирует госпитализацию и проверяет, что пациент еще не выписан \\\
+
+func lockAdmission(ctx context.Context, tx pgx.Tx, id int64) (*Admission, error) {
+	admission, err := scanAdmission(tx.QueryRow(ctx,
+		`SELECT * FROM admission WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to lock admission: %v", err)
+	}
+	if admission.Status != StatusAdmitted {
+		return nil, apperror.ErrAdmissionState
+	}
+	return admission, nil
+}
+
+/// Функция lockBed блокирует койку, проверяет что она свободна и возвращает отделение койки \\\
+/// Блокировка строки койки не дает двум госпитализациям занять ее одновременно \\\
+
+func lockBed(ctx context.Context, tx pgx.Tx, bedId int64) (int64, error) {
+	var departmentId int64
+	err := tx.QueryRow(ctx,
+		`SELECT w.department_id FROM bed b
+			 JOIN ward w ON w.id = b.ward_id
+			 WHERE b.id = $1
+			 FOR UPDATE OF b`, bedId).Scan(&departmentId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperror.ErrEmptyString
+		}
+		return 0, fmt.Errorf("failed to lock bed: %v", err)
+	}
+
+	var occupied bool
+	err = tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM admission WHERE bed_id = $1 AND status = $2)`,
+		bedId, StatusAdmitted).Scan(&occupied)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check bed occupancy: %v", err)
+	}
+	if occupied {
+		return 0, apperror.ErrBedOccupied
+	}
+	return departmentId, nil
+}
+
+/// Функция assignBed открывает пребывание госпитализации на койке \\\
+
+func assignBed(ctx context.Context, tx pgx.Tx, admissionId, bedId int64) error {
+	_, err := tx.Exec(ctx,
+		`INSERT INTO bed_assignment (admission_id, bed_id)
+			 VALUES($1,$2)`, admissionId, bedId)
+	if err != nil {
+		return fmt.Errorf("failed to assign bed: %v", err)
+	}
+	return nil
+}
+
+/// Функция releaseBed закрывает текущее пребывание госпитализации на койке \\\
+
+func releaseBed(ctx context.Context, tx pgx.Tx, admissionId int64) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE bed_assignment SET released_at = now()
+			 WHERE admission_id = $1 AND released_at IS NULL`, admissionId)
+	if err != nil {
+		return fmt.Errorf("failed to release bed: %v", err)
+	}
+	return nil
+}
+
+/// Функция collectWards собирает палаты из строк палата-койка, строки палаты идут подряд \\\
+
+func collectWards(rows pgx.Rows) ([]Ward, error) {
+	wards := make([]Ward, 0)
+	for rows.Next() {
+		var (
+			ward        Ward
+			bedId       *int64
+			label       *string
+			admissionId *int64
+		)
+		if err := rows.Scan(&ward.ID, &ward.DepartmentID, &ward.Number, &ward.Name, &bedId, &label, &admissionId); err != nil {
+			return nil, err
+		}
+		if len(wards) == 0 || wards[len(wards)-1].ID != ward.ID {
+			ward.Beds = make([]Bed, 0)
+			wards = append(wards, ward)
+		}
+		if bedId != nil {
+			last := &wards[len(wards)-1]
+			last.Beds = append(last.Beds, Bed{
+				ID:          *bedId,
+				WardID:      ward.ID,
+				Label:       *label,
+				Occupied:    admissionId != nil,
+				AdmissionID: admissionId,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return wards, nil
+}
+
+/// Функция scanAdmission сканирует строку таблицы admission \\\
+
+func scanAdmission(row pgx.Row) (*Admission, error) {
+	admission := &Admission{}
+	err := row.Scan(&admission.ID, &admission.PatientID, &admission.DoctorID, &admission.DiseaseID,
+		&admission.Diagnosis, &admission.DepartmentID, &admission.BedID, &admission.Status,
+		&admission.AdmittedAt, &admission.DischargedAt, &admission.DischargeSummary)
+	if err != nil {
+		return nil, err
+	}
+	return admission, nil
+}
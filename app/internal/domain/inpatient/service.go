@@ -0,0 +1,198 @@
+package inpatient
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/disease"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"strings"
+)
+
+/// Интерфейс Service реализизирующий service и методы стационара: палаты, койки и госпитализации \\\
+
+type Service interface {
+	CreateWard(ctx context.Context, input *CreateWardDTO) (*Ward, error)
+	GetWards(ctx context.Context, departmentId int64) (*[]Ward, error)
+	GetWard(ctx context.Context, id int64) (*Ward, error)
+	CreateBed(ctx context.Context, input *CreateBedDTO) (*Bed, error)
+	GetOccupancy(ctx context.Context, departmentId int64) (*Occupancy, error)
+	Admit(ctx context.Context, input *AdmitDTO) (*Admission, error)
+	GetAdmission(ctx context.Context, id int64) (*Admission, error)
+	GetAdmissions(ctx context.Context, departmentId, patientId *int64, status string) (*[]Admission, error)
+	Transfer(ctx context.Context, input *TransferDTO) (*Admission, error)
+	Discharge(ctx context.Context, input *DischargeDTO) (*Admission, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service стационара \\\
+
+type service struct {
+	logger   logger.Logger
+	storage  Storage
+	patients user.Storage
+	doc      doctor.Storage
+	diseases disease.Storage
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, patients user.Storage, diseases disease.Storage, storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:   logger,
+		storage:  storage,
+		patients: patients,
+		doc:      doc,
+		diseases: diseases,
+	}
+}
+
+/// Функция CreateWard добавляет палату с койками, метки коек не повторяются \\\
+
+func (s *service) CreateWard(ctx context.Context, input *CreateWardDTO) (*Ward, error) {
+	s.logger.Info("SERVICE: CREATE WARD")
+
+	ward := Ward{
+		DepartmentID: input.DepartmentID,
+		Number:       strings.TrimSpace(input.Number),
+		Name:         input.Name,
+	}
+	if ward.Number == "" {
+		return nil, apperror.ErrInvalidInpatient
+	}
+	labels := make([]string, 0, len(input.Beds))
+	seen := make(map[string]bool, len(input.Beds))
+	for _, label := range input.Beds {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[label] {
+			return nil, apperror.ErrInvalidInpatient
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	return s.storage.CreateWard(&ward, labels)
+}
+
+/// Функция GetWards возвращает палаты отделения с койками \\\
+
+func (s *service) GetWards(ctx context.Context, departmentId int64) (*[]Ward, error) {
+	s.logger.Info("SERVICE: GET WARDS")
+
+	wards, err := s.storage.FindWards(departmentId)
+	if err != nil {
+		return nil, err
+	}
+	return &wards, nil
+}
+
+/// Функция GetWard возвращает палату с койками по id \\\
+
+func (s *service) GetWard(ctx context.Context, id int64) (*Ward, error) {
+	s.logger.Info("SERVICE: GET WARD")
+
+	return s.storage.FindWardById(id)
+}
+
+/// Функция CreateBed добавляет койку в палату \\\
+
+func (s *service) CreateBed(ctx context.Context, input *CreateBedDTO) (*Bed, error) {
+	s.logger.Info("SERVICE: CREATE BED")
+
+	bed := Bed{WardID: input.WardID, Label: strings.TrimSpace(input.Label)}
+	if bed.Label == "" {
+		return nil, apperror.ErrInvalidInpatient
+	}
+	return s.storage.CreateBed(&bed)
+}
+
+/// Функция GetOccupancy возвращает занятость коек отделения \\\
+
+func (s *service) GetOccupancy(ctx context.Context, departmentId int64) (*Occupancy, error) {
+	s.logger.Info("SERVICE: GET DEPARTMENT OCCUPANCY")
+
+	return s.storage.FindOccupancy(departmentId)
+}
+
+/// Функция Admit госпитализирует пациента с лечащим доктором и диагнозом \\\
+
+func (s *service) Admit(ctx context.Context, input *AdmitDTO) (*Admission, error) {
+	s.logger.Info("SERVICE: ADMIT PATIENT")
+
+	/// Проверка входных данных \\\
+	admission := Admission{
+		PatientID:    input.PatientID,
+		DoctorID:     input.DoctorID,
+		DiseaseID:    input.DiseaseID,
+		Diagnosis:    strings.TrimSpace(input.Diagnosis),
+		DepartmentID: input.DepartmentID,
+		BedID:        input.BedID,
+	}
+	if admission.Diagnosis == "" || admission.DepartmentID < 0 || (admission.DepartmentID == 0 && admission.BedID == nil) {
+		return nil, apperror.ErrInvalidInpatient
+	}
+
+	/// Проверка что пациент, доктор и заболевание существуют \\\
+	if _, err := s.patients.FindById(input.PatientID); err != nil {
+		return nil, err
+	}
+	if _, err := s.doc.FindById(input.DoctorID); err != nil {
+		return nil, err
+	}
+	if input.DiseaseID != nil {
+		if _, err := s.diseases.FindById(*input.DiseaseID); err != nil {
+			return nil, err
+		}
+	}
+	return s.storage.Admit(&admission)
+}
+
+/// Функция GetAdmission возвращает госпитализацию с историей коек \\\
+
+func (s *service) GetAdmission(ctx context.Context, id int64) (*Admission, error) {
+	s.logger.Info("SERVICE: GET ADMISSION")
+
+	admission, err := s.storage.FindAdmissionById(id)
+	if err != nil {
+		return nil, err
+	}
+	admission.Movements, err = s.storage.FindMovements(id)
+	if err != nil {
+		return nil, err
+	}
+	return admission, nil
+}
+
+/// Функция GetAdmissions возвращает госпитализации по отделению, пациенту и статусу \\\
+
+func (s *service) GetAdmissions(ctx context.Context, departmentId, patientId *int64, status string) (*[]Admission, error) {
+	s.logger.Info("SERVICE: GET ADMISSIONS")
+
+	admissions, err := s.storage.FindAdmissions(departmentId, patientId, status)
+	if err != nil {
+		return nil, err
+	}
+	return &admissions, nil
+}
+
+/// Функция Transfer переводит госпитализированного пациента на другую койку \\\
+
+func (s *service) Transfer(ctx context.Context, input *TransferDTO) (*Admission, error) {
+	s.logger.Info("SERVICE: TRANSFER PATIENT")
+
+	if input.BedID < 1 {
+		return nil, apperror.ErrInvalidInpatient
+	}
+	return s.storage.Transfer(input)
+}
+
+/// Функция Discharge выписывает пациента, выписной эпикриз обязателен \\\
+
+func (s *service) Discharge(ctx context.Context, input *DischargeDTO) (*Admission, error) {
+	s.logger.Info("SERVICE: DISCHARGE PATIENT")
+
+	input.Summary = strings.TrimSpace(input.Summary)
+	if input.Summary == "" {
+		return nil, apperror.ErrInvalidInpatient
+	}
+	return s.storage.Discharge(input)
+}
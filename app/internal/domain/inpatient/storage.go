@@ -0,0 +1,15 @@
+package inpatient
+
+type Storage interface {
+	CreateWard(ward *Ward, labels []string) (*Ward, error)
+	FindWards(departmentId int64) ([]Ward, error)
+	FindWardById(id int64) (*Ward, error)
+	CreateBed(bed *Bed) (*Bed, error)
+	FindOccupancy(departmentId int64) (*Occupancy, error)
+	Admit(admission *Admission) (*Admission, error)
+	FindAdmissionById(id int64) (*Admission, error)
+	FindAdmissions(departmentId, patientId *int64, status string) ([]Admission, error)
+	FindMovements(admissionId int64) ([]Movement, error)
+	Transfer(input *TransferDTO) (*Admission, error)
+	Discharge(input *DischargeDTO) (*Admission, error)
+}
@@ -0,0 +1,182 @@
+package insurance
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	insurersURL      = "/hospital_record/insurers"
+	insurerURL       = "/hospital_record/insurers/:id"
+	policiesURL      = "/hospital_record/insurance_policies"
+	policyURL        = "/hospital_record/insurance_policies/:number"
+	patientPolicyURL = "/hospital_record/patient_policy"
+)
+
+/// Структура Handler представляющая собой обработчик объекта insuranceService для реестра страховщиков и полисов \\\
+
+type Handler struct {
+	logger           logger.Logger
+	insuranceService Service
+	authorize        handler.Middleware
+	admin            handler.Middleware
+	staff            handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, insuranceService Service, authorize, admin, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:           logger,
+		insuranceService: insuranceService,
+		authorize:        authorize,
+		admin:            admin,
+		staff:            staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для реестра страховщиков и полисов \\\
+/// Реестр ведет администратор, сотрудники проверяют полисы, пациент по токену доступа видит свой полис \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, insurersURL, h.admin(h.CreateInsurer))
+	router.HandlerFunc(http.MethodGet, insurersURL, h.GetInsurers)
+	router.HandlerFunc(http.MethodGet, insurerURL, h.GetInsurer)
+	router.HandlerFunc(http.MethodPost, policiesURL, h.admin(h.RegisterPolicy))
+	router.HandlerFunc(http.MethodGet, policyURL, h.staff(h.GetPolicy))
+	router.HandlerFunc(http.MethodGet, patientPolicyURL, h.authorize(h.GetPatientPolicy))
+}
+
+/// Функция CreateInsurer добавляет страховщика в реестр \\\
+
+func (h *Handler) CreateInsurer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE INSURER")
+
+	/// Чтение тела запроса в структуру CreateInsurerDTO \\\
+	var input CreateInsurerDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	insurer, err := h.insuranceService.CreateInsurer(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("INSURER CREATED")
+	response.JSON(w, http.StatusCreated, insurer)
+}
+
+/// Функция GetInsurers получает реестр страховщиков \\\
+
+func (h *Handler) GetInsurers(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET INSURERS")
+
+	insurers, err := h.insuranceService.GetInsurers(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT INSURERS")
+	response.JSON(w, http.StatusOK, insurers)
+}
+
+/// Функция GetInsurer получает страховщика по id \\\
+
+func (h *Handler) GetInsurer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET INSURER")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	insurer, err := h.insuranceService.GetInsurer(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT INSURER")
+	response.JSON(w, http.StatusOK, insurer)
+}
+
+/// Функция RegisterPolicy регистрирует полис в реестре \\\
+
+func (h *Handler) RegisterPolicy(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: REGISTER INSURANCE POLICY")
+
+	/// Чтение тела запроса в структуру RegisterPolicyDTO \\\
+	var input RegisterPolicyDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	policy, err := h.insuranceService.RegisterPolicy(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("INSURANCE POLICY REGISTERED")
+	response.JSON(w, http.StatusOK, policy)
+}
+
+/// Функция GetPolicy получает полис из реестра по номеру \\\
+
+func (h *Handler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET INSURANCE POLICY")
+
+	number := httprouter.ParamsFromContext(r.Context()).ByName("number")
+
+	policy, err := h.insuranceService.GetPolicy(r.Context(), number)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT INSURANCE POLICY")
+	response.JSON(w, http.StatusOK, policy)
+}
+
+/// Функция GetPatientPolicy получает полис авторизованного пациента \\\
+
+func (h *Handler) GetPatientPolicy(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PATIENT INSURANCE POLICY")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	policy, err := h.insuranceService.GetPatientPolicy(r.Context(), user.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT PATIENT INSURANCE POLICY")
+	response.JSON(w, http.StatusOK, policy)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidInsurance), errors.Is(err, apperror.ErrInvalidPolicyNumber):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrPolicyNotValid):
+		response.Error(w, http.StatusForbidden, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
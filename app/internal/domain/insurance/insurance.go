@@ -0,0 +1,65 @@
+package insurance
+
+import "time"
+
+/// Виды полиса ОМС: электронный и бумажный полис единого образца, временное свидетельство на время оформления полиса \\\
+
+const (
+	PolicyTypeElectronic = "electronic"
+	PolicyTypePaper      = "paper"
+	PolicyTypeTemporary  = "temporary"
+)
+
+/// Структура страховой медицинской организации из реестра страховщиков \\\
+
+type Insurer struct {
+	ID        int64     `json:"id" example:"1"`
+	Name      string    `json:"name" example:"AO \"SOGAZ-Med\""`
+	Region    string    `json:"region" example:"Moskva"`
+	CreatedAt time.Time `json:"created_at" example:"2023-07-27T10:00:00Z"`
+}
+
+/// Структура полиса из реестра, с пациентом полис связан номером полиса \\\
+/// Полис без даты окончания действует бессрочно \\\
+
+type Policy struct {
+	Number     string     `json:"number" example:"2194589700000056"`
+	InsurerID  int64      `json:"insurer_id" example:"1"`
+	Insurer    string     `json:"insurer" example:"AO \"SOGAZ-Med\""`
+	Region     string     `json:"region" example:"Moskva"`
+	PolicyType string     `json:"policy_type" example:"electronic"`
+	ValidFrom  time.Time  `json:"valid_from" example:"2021-01-01T00:00:00Z"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" example:"2023-12-31T00:00:00Z"`
+	PatientID  *int64     `json:"patient_id,omitempty" example:"1"`
+}
+
+type CreateInsurerDTO struct {
+	Name   string `json:"name" example:"AO \"SOGAZ-Med\""`
+	Region string `json:"region" example:"Moskva"`
+}
+
+/// Повторная регистрация номера заменяет страховщика, вид и срок действия полиса \\\
+
+type RegisterPolicyDTO struct {
+	Number     string     `json:"number" example:"2194589700000056"`
+	InsurerID  int64      `json:"insurer_id" example:"1"`
+	PolicyType string     `json:"policy_type" example:"electronic"`
+	ValidFrom  time.Time  `json:"valid_from" example:"2021-01-01T00:00:00Z"`
+	ValidUntil *time.Time `json:"valid_until,omitempty" example:"2023-12-31T00:00:00Z"`
+}
+
+/// Функция validPolicyType проверяет, что policyType - известный вид полиса \\\
+
+func validPolicyType(policyType string) bool {
+	switch policyType {
+	case PolicyTypeElectronic, PolicyTypePaper, PolicyTypeTemporary:
+		return true
+	}
+	return false
+}
+
+/// Функция covers проверяет, что полис действует в день day \\\
+
+func (p *Policy) covers(day time.Time) bool {
+	return !day.Before(p.ValidFrom) && (p.ValidUntil == nil || !day.After(*p.ValidUntil))
+}
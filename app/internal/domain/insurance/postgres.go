@@ -0,0 +1,217 @@
+package insurance
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &InsuranceStorage{}
+
+/// Запрос полиса с названием и регионом страховщика и пациентом с этим номером полиса \\\
+
+const selectPolicy = `
+SELECT ip.number, ip.insurer_id, i.name, i.region, ip.policy_type, ip.valid_from, ip.valid_until, p.id
+FROM insurance_policy ip
+INNER JOIN insurer i ON i.id = ip.insurer_id
+LEFT JOIN patients p ON p.policy_number = ip.number
+WHERE ip.number = $1`
+
+/// Структура InsuranceStorage содержащая поля для работы с БД \\\
+
+type InsuranceStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр InsuranceStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &InsuranceStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция CreateInsurer для сущности InsuranceStorage добавляет страховщика в реестр \\\
+
+func (s *InsuranceStorage) CreateInsurer(insurer *Insurer) (*Insurer, error) {
+	s.logger.Info("POSTGRES: CREATE INSURER")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	created, err := scanInsurer(s.conn.QueryRow(ctx,
+		`INSERT INTO insurer (name, region)
+			 VALUES($1,$2)
+			 RETURNING *`, insurer.Name, insurer.Region))
+	if err != nil {
+		err = fmt.Errorf("failed to execute create insurer query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+/// Функция FindInsurers для сущности InsuranceStorage получает всех страховщиков по региону и названию \\\
+
+func (s *InsuranceStorage) FindInsurers() ([]Insurer, error) {
+	s.logger.Info("POSTGRES: GET INSURERS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := s.conn.Query(ctx,
+		`SELECT * FROM insurer
+			 ORDER BY region, name`)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения страховщиков \\\
+	insurers := make([]Insurer, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		insurer, err := scanInsurer(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find insurers query: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		insurers = append(insurers, *insurer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return insurers, nil
+}
+
+/// Функция FindInsurerById для сущности InsuranceStorage получает страховщика по id \\\
+
+func (s *InsuranceStorage) FindInsurerById(id int64) (*Insurer, error) {
+	s.logger.Info("POSTGRES: GET INSURER BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	insurer, err := scanInsurer(s.conn.QueryRow(ctx,
+		`SELECT * FROM insurer
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find insurer by id query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	return insurer, nil
+}
+
+/// Функция SavePolicy для сущности InsuranceStorage регистрирует полис у существующего страховщика \\\
+/// Если страховщика нет, возвращается apperror.ErrEmptyString \\\
+
+func (s *InsuranceStorage) SavePolicy(input *RegisterPolicyDTO) (*Policy, error) {
+	s.logger.Info("POSTGRES: SAVE INSURANCE POLICY")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin save insurance policy transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	var number string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO insurance_policy (number, insurer_id, policy_type, valid_from, valid_until)
+			 SELECT $1, id, $3, $4, $5 FROM insurer WHERE id = $2
+			 ON CONFLICT (number)
+			 DO UPDATE SET insurer_id = EXCLUDED.insurer_id, policy_type = EXCLUDED.policy_type,
+			               valid_from = EXCLUDED.valid_from, valid_until = EXCLUDED.valid_until
+			 RETURNING number`,
+		input.Number, input.InsurerID, input.PolicyType, input.ValidFrom, input.ValidUntil).Scan(&number)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute save insurance policy query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	policy, err := scanPolicy(tx.QueryRow(ctx, selectPolicy, number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved insurance policy: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit save insurance policy transaction: %v", err)
+	}
+	return policy, nil
+}
+
+/// Функция FindPolicy для сущности InsuranceStorage получает полис по номеру \\\
+
+func (s *InsuranceStorage) FindPolicy(number string) (*Policy, error) {
+	s.logger.Info("POSTGRES: GET INSURANCE POLICY")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	policy, err := scanPolicy(s.conn.QueryRow(ctx, selectPolicy, number))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find insurance policy query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	return policy, nil
+}
+
+/// Функция scanInsurer сканирует строку таблицы insurer \\\
+
+func scanInsurer(row pgx.Row) (*Insurer, error) {
+	insurer := &Insurer{}
+	err := row.Scan(&insurer.ID, &insurer.Name, &insurer.Region, &insurer.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return insurer, nil
+}
+
+/// Функция scanPolicy сканирует строку запроса selectPolicy \\\
+
+func scanPolicy(row pgx.Row) (*Policy, error) {
+	policy := &Policy{}
+	err := row.Scan(&policy.Number, &policy.InsurerID, &policy.Insurer, &policy.Region, &policy.PolicyType,
+		&policy.ValidFrom, &policy.ValidUntil, &policy.PatientID)
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
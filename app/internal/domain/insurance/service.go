@@ -0,0 +1,179 @@
+package insurance
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"HospitalRecord/app/pkg/oms"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для реестра страховщиков и полисов ОМС \\\
+/// Service также реализует user.Policies и record.Policies, через которые проверяется полис при регистрации и записи на прием \\\
+
+type Service interface {
+	CreateInsurer(ctx context.Context, input *CreateInsurerDTO) (*Insurer, error)
+	GetInsurers(ctx context.Context) (*[]Insurer, error)
+	GetInsurer(ctx context.Context, id int64) (*Insurer, error)
+	RegisterPolicy(ctx context.Context, input *RegisterPolicyDTO) (*Policy, error)
+	GetPolicy(ctx context.Context, number string) (*Policy, error)
+	GetPatientPolicy(ctx context.Context, patientId int64) (*Policy, error)
+	Check(number string, at time.Time) error
+	CheckPatient(patientId int64, at time.Time) error
+}
+
+/// Структура  service реализизирующая инфтерфейс Service страхования \\\
+
+type service struct {
+	logger            logger.Logger
+	storage           Storage
+	patients          user.Storage
+	requireRegistered bool
+	location          *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(patients user.Storage, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:            logger,
+		storage:           storage,
+		patients:          patients,
+		requireRegistered: cfg.Insurance.RequireRegistered,
+		location:          time.UTC,
+	}
+	if cfg.Insurance.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Insurance.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown insurance time zone %q, using UTC: %v", cfg.Insurance.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция CreateInsurer добавляет страховщика в реестр \\\
+
+func (s *service) CreateInsurer(ctx context.Context, input *CreateInsurerDTO) (*Insurer, error) {
+	s.logger.Info("SERVICE: CREATE INSURER")
+
+	/// Проверка входных данных \\\
+	insurer := Insurer{
+		Name:   strings.TrimSpace(input.Name),
+		Region: strings.TrimSpace(input.Region),
+	}
+	if insurer.Name == "" || insurer.Region == "" {
+		return nil, apperror.ErrInvalidInsurance
+	}
+	return s.storage.CreateInsurer(&insurer)
+}
+
+/// Функция GetInsurers возвращает реестр страховщиков \\\
+
+func (s *service) GetInsurers(ctx context.Context) (*[]Insurer, error) {
+	s.logger.Info("SERVICE: GET INSURERS")
+
+	insurers, err := s.storage.FindInsurers()
+	if err != nil {
+		return nil, err
+	}
+	return &insurers, nil
+}
+
+/// Функция GetInsurer возвращает страховщика по id \\\
+
+func (s *service) GetInsurer(ctx context.Context, id int64) (*Insurer, error) {
+	s.logger.Info("SERVICE: GET INSURER")
+
+	return s.storage.FindInsurerById(id)
+}
+
+/// Функция RegisterPolicy регистрирует полис в реестре, временное свидетельство всегда имеет дату окончания \\\
+
+func (s *service) RegisterPolicy(ctx context.Context, input *RegisterPolicyDTO) (*Policy, error) {
+	s.logger.Info("SERVICE: REGISTER INSURANCE POLICY")
+
+	/// Проверка входных данных \\\
+	if !oms.Valid(input.Number) {
+		return nil, apperror.ErrInvalidPolicyNumber
+	}
+	input.ValidFrom = s.date(input.ValidFrom)
+	if input.ValidUntil != nil {
+		validUntil := s.date(*input.ValidUntil)
+		input.ValidUntil = &validUntil
+	}
+	if !validPolicyType(input.PolicyType) ||
+		(input.PolicyType == PolicyTypeTemporary && input.ValidUntil == nil) ||
+		(input.ValidUntil != nil && input.ValidUntil.Before(input.ValidFrom)) {
+		return nil, apperror.ErrInvalidInsurance
+	}
+	return s.storage.SavePolicy(input)
+}
+
+/// Функция GetPolicy возвращает полис из реестра по номеру \\\
+
+func (s *service) GetPolicy(ctx context.Context, number string) (*Policy, error) {
+	s.logger.Info("SERVICE: GET INSURANCE POLICY")
+
+	if !oms.Valid(number) {
+		return nil, apperror.ErrInvalidPolicyNumber
+	}
+	return s.storage.FindPolicy(number)
+}
+
+/// Функция GetPatientPolicy возвращает полис пациента из реестра \\\
+
+func (s *service) GetPatientPolicy(ctx context.Context, patientId int64) (*Policy, error) {
+	s.logger.Info("SERVICE: GET PATIENT INSURANCE POLICY")
+
+	patient, err := s.patients.FindById(patientId)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.FindPolicy(patient.PolicyNumber)
+}
+
+/// Функция Check проверяет номер полиса и его действие в день at \\\
+/// Номер без записи в реестре принимается, если реестр не обязателен \\\
+
+func (s *service) Check(number string, at time.Time) error {
+	if !oms.Valid(number) {
+		return apperror.ErrInvalidPolicyNumber
+	}
+	policy, err := s.storage.FindPolicy(number)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			if s.requireRegistered {
+				return apperror.ErrPolicyNotValid
+			}
+			return nil
+		}
+		return err
+	}
+	if !policy.covers(s.date(at)) {
+		return apperror.ErrPolicyNotValid
+	}
+	return nil
+}
+
+/// Функция CheckPatient проверяет полис пациента в день приема at \\\
+
+func (s *service) CheckPatient(patientId int64, at time.Time) error {
+	patient, err := s.patients.FindById(patientId)
+	if err != nil {
+		return err
+	}
+	return s.Check(patient.PolicyNumber, at)
+}
+
+/// Функция date возвращает дату t по местному времени, даты полисов хранятся без часового пояса \\\
+
+func (s *service) date(t time.Time) time.Time {
+	year, month, day := t.In(s.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
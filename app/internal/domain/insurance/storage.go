@@ -0,0 +1,9 @@
+package insurance
+
+type Storage interface {
+	CreateInsurer(insurer *Insurer) (*Insurer, error)
+	FindInsurers() ([]Insurer, error)
+	FindInsurerById(id int64) (*Insurer, error)
+	SavePolicy(input *RegisterPolicyDTO) (*Policy, error)
+	FindPolicy(number string) (*Policy, error)
+}
@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/// Типы доменных событий, тип агрегата - часть имени до точки \\\
+
+const (
+	RecordCreated        = "record.created"
+	RecordUpdated        = "record.updated"
+	RecordRescheduled    = "record.rescheduled"
+	RecordCancelled      = "record.cancelled"
+	PatientRegistered    = "patient.registered"
+	PatientUpdated       = "patient.updated"
+	PatientDeleted       = "patient.deleted"
+	TicketIssued         = "ticket.issued"
+	TicketAssigned       = "ticket.assigned"
+	TicketCalled         = "ticket.called"
+	TicketServed         = "ticket.served"
+	TicketSkipped        = "ticket.skipped"
+	AdmissionCreated     = "admission.created"
+	AdmissionTransferred = "admission.transferred"
+	AdmissionDischarged  = "admission.discharged"
+)
+
+/// Список всех типов событий для проверки подписок \\\
+
+var EventTypes = []string{
+	RecordCreated, RecordUpdated, RecordRescheduled, RecordCancelled,
+	PatientRegistered, PatientUpdated, PatientDeleted,
+	TicketIssued, TicketAssigned, TicketCalled, TicketServed, TicketSkipped,
+	AdmissionCreated, AdmissionTransferred, AdmissionDischarged,
+}
+
+/// Функция IsEventType проверяет, что eventType - известный тип события \\\
+
+func IsEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+/// Структура доменного события из таблицы outbox_event \\\
+
+type Event struct {
+	ID            int64           `json:"id" example:"1"`
+	Type          string          `json:"type" example:"record.created"`
+	AggregateType string          `json:"aggregate_type" example:"record"`
+	AggregateID   int64           `json:"aggregate_id" example:"1567"`
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Attempts      int             `json:"-"`
+	Delivered     []string        `json:"-"`
+}
+
+/// Структура данных событий записи на прием, для переноса заполняются прежние доктор и время \\\
+
+type RecordPayload struct {
+	RecordID         int64      `json:"record_id" example:"1567"`
+	PatientID        int64      `json:"patient_id" example:"1"`
+	DoctorID         int64      `json:"doctor_id" example:"1"`
+	SpecializationID int64      `json:"specialization_id" example:"1"`
+	TimeRecord       time.Time  `json:"time_record" example:"2023-07-27T15:30:00Z"`
+	HospitalAddress  string     `json:"hospital_address" example:"Roterta, dom 12"`
+	DoctorOffice     string     `json:"doctor_office" example:"201B"`
+	OfficeID         *int64     `json:"office_id,omitempty" example:"1"`
+	PreviousDoctorID *int64     `json:"previous_doctor_id,omitempty" example:"1"`
+	PreviousTime     *time.Time `json:"previous_time,omitempty" example:"2023-07-26T15:30:00Z"`
+	Reason           *string    `json:"reason,omitempty" example:"Ne uspevayu posle raboty"`
+}
+
+/// Структура данных событий пациента, в событии изменения перечисляются измененные поля без их значений \\\
+
+type PatientPayload struct {
+	PatientID int64    `json:"patient_id" example:"1"`
+	Email     string   `json:"email,omitempty" example:"petrovmaksim1992@mail.ru"`
+	Name      string   `json:"name,omitempty" example:"Maksim"`
+	Surname   string   `json:"surname,omitempty" example:"Petrov"`
+	Fields    []string `json:"fields,omitempty" example:"email,phone_number"`
+}
+
+/// Структура данных событий талона электронной очереди, данные пациента в событие не попадают \\\
+
+type TicketPayload struct {
+	TicketID        int64     `json:"ticket_id" example:"1"`
+	Number          string    `json:"number" example:"B-012"`
+	Category        string    `json:"category" example:"booked"`
+	Status          string    `json:"status" example:"called"`
+	HospitalAddress string    `json:"hospital_address" example:"Roterta, dom 12"`
+	DoctorID        *int64    `json:"doctor_id,omitempty" example:"1"`
+	DoctorOffice    *string   `json:"doctor_office,omitempty" example:"201B"`
+	RecordID        *int64    `json:"record_id,omitempty" example:"1567"`
+	IssuedAt        time.Time `json:"issued_at" example:"2023-07-27T08:05:00Z"`
+}
+
+/// Структура данных событий госпитализации, при переводе заполняется прежняя койка \\\
+
+type AdmissionPayload struct {
+	AdmissionID   int64      `json:"admission_id" example:"1"`
+	PatientID     int64      `json:"patient_id" example:"1"`
+	DoctorID      int64      `json:"doctor_id" example:"1"`
+	DepartmentID  int64      `json:"department_id" example:"1"`
+	BedID         *int64     `json:"bed_id,omitempty" example:"3"`
+	PreviousBedID *int64     `json:"previous_bed_id,omitempty" example:"2"`
+	Status        string     `json:"status" example:"admitted"`
+	AdmittedAt    time.Time  `json:"admitted_at" example:"2023-07-27T10:00:00Z"`
+	DischargedAt  *time.Time `json:"discharged_at,omitempty" example:"2023-08-03T12:00:00Z"`
+}
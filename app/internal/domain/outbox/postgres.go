@@ -0,0 +1,194 @@
+package outbox
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+	"time"
+)
+
+var _ Storage = &OutboxStorage{}
+
+/// Функция Write сохраняет доменное событие в outbox_event внутри транзакции изменения \\\
+/// Событие становится видно диспетчеру только вместе с фиксацией транзакции tx \\\
+
+func Write(ctx context.Context, tx pgx.Tx, eventType string, aggregateId int64, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %v", eventType, err)
+	}
+	aggregateType := strings.SplitN(eventType, ".", 2)[0]
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO outbox_event (event_type, aggregate_type, aggregate_id, payload)
+			 VALUES($1,$2,$3,$4)`,
+		eventType, aggregateType, aggregateId, data)
+	if err != nil {
+		return fmt.Errorf("failed to write %s event: %v", eventType, err)
+	}
+	return nil
+}
+
+/// Структура OutboxStorage содержащая поля для работы с БД \\\
+
+type OutboxStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр OutboxStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &OutboxStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция ClaimDue для сущности OutboxStorage захватывает до limit неразосланных событий на время lease \\\
+/// Если диспетчер остановится не завершив рассылку, после lease события будут захвачены снова \\\
+
+func (o *OutboxStorage) ClaimDue(now time.Time, lease time.Duration, limit int) ([]Event, error) {
+	/// Опрос выполняется часто, поэтому пишется в журнал на уровне debug \\\
+	o.logger.Debug("POSTGRES: CLAIM OUTBOX EVENTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), o.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := o.conn.Query(ctx,
+		`WITH claimed AS (
+			 UPDATE outbox_event SET attempts = attempts + 1, next_attempt_at = $1::timestamptz + $2::interval
+			 WHERE id IN (SELECT id FROM outbox_event
+			              WHERE dispatched_at IS NULL AND next_attempt_at <= $1
+			              ORDER BY id
+			              LIMIT $3
+			              FOR UPDATE SKIP LOCKED)
+			 RETURNING id, event_type, aggregate_type, aggregate_id, payload, created_at, attempts)
+		 SELECT c.id, c.event_type, c.aggregate_type, c.aggregate_id, c.payload, c.created_at, c.attempts,
+		        coalesce((SELECT array_agg(d.subscriber) FROM outbox_delivery d WHERE d.event_id = c.id), '{}')
+		 FROM claimed c
+		 ORDER BY c.id`, now, lease, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		o.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения событий \\\
+	events := make([]Event, 0)
+
+	for rows.Next() {
+		var e Event
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &e.Payload, &e.CreatedAt, &e.Attempts, &e.Delivered)
+		if err != nil {
+			err = fmt.Errorf("failed to execute claim outbox events query: %v", err)
+			o.logger.Error(err)
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+/// Функция MarkDelivered для сущности OutboxStorage отмечает доставку события подписчику \\\
+/// При повторной рассылке события этот подписчик пропускается \\\
+
+func (o *OutboxStorage) MarkDelivered(eventId int64, subscriber string) error {
+	o.logger.Info("POSTGRES: MARK OUTBOX EVENT DELIVERED")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), o.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	_, err := o.conn.Exec(ctx,
+		`INSERT INTO outbox_delivery (event_id, subscriber)
+			 VALUES($1,$2)
+			 ON CONFLICT (event_id, subscriber) DO NOTHING`, eventId, subscriber)
+	if err != nil {
+		err = fmt.Errorf("failed to execute mark outbox event delivered query: %v", err)
+		o.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+/// Функция MarkDispatched для сущности OutboxStorage отмечает, что событие доставлено всем подписчикам \\\
+
+func (o *OutboxStorage) MarkDispatched(eventId int64) error {
+	o.logger.Info("POSTGRES: MARK OUTBOX EVENT DISPATCHED")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), o.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := o.conn.Exec(ctx,
+		`UPDATE outbox_event SET dispatched_at = now(), last_error = NULL
+			 WHERE id = $1 AND dispatched_at IS NULL`, eventId)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event dispatched: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция MarkFailed для сущности OutboxStorage сохраняет ошибку рассылки и время следующей попытки \\\
+
+func (o *OutboxStorage) MarkFailed(eventId int64, reason string, retryAt time.Time) error {
+	o.logger.Info("POSTGRES: MARK OUTBOX EVENT FAILED")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), o.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := o.conn.Exec(ctx,
+		`UPDATE outbox_event SET last_error = $2, next_attempt_at = $3
+			 WHERE id = $1 AND dispatched_at IS NULL`, eventId, reason, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция DeleteDispatched для сущности OutboxStorage удаляет разосланные события старше before \\\
+
+func (o *OutboxStorage) DeleteDispatched(before time.Time) (int64, error) {
+	o.logger.Info("POSTGRES: DELETE DISPATCHED OUTBOX EVENTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), o.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := o.conn.Exec(ctx,
+		`DELETE FROM outbox_event WHERE dispatched_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete dispatched outbox events: %v", err)
+	}
+	return result.RowsAffected(), nil
+}
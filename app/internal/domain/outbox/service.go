@@ -0,0 +1,225 @@
+package outbox
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/// Время, на которое захватывается пачка событий, время на обработку события одним подписчиком и период очистки \\\
+
+const (
+	claimLease     = 10 * time.Minute
+	handleTimeout  = 30 * time.Second
+	cleanupPeriod  = time.Hour
+	maxBackoffStep = 20
+)
+
+/// Интерфейс Subscriber получателя доменных событий: подписчика внутри приложения или внешнего приемника \\\
+/// Доставка выполняется как минимум один раз, поэтому Handle должен быть идемпотентным по Event.ID \\\
+/// Name сохраняется в отметках о доставке и должно быть постоянным между перезапусками \\\
+
+type Subscriber interface {
+	Name() string
+	Handle(ctx context.Context, event Event) error
+}
+
+/// Структура funcSubscriber подписчик на основе функции \\\
+
+type funcSubscriber struct {
+	name   string
+	handle func(ctx context.Context, event Event) error
+}
+
+func (f *funcSubscriber) Name() string {
+	return f.name
+}
+
+func (f *funcSubscriber) Handle(ctx context.Context, event Event) error {
+	return f.handle(ctx, event)
+}
+
+/// Функция NewSubscriber возвращает подписчика с именем name, вызывающего handle \\\
+
+func NewSubscriber(name string, handle func(ctx context.Context, event Event) error) Subscriber {
+	return &funcSubscriber{name: name, handle: handle}
+}
+
+/// Интерфейс Service реализизирующий service и методы диспетчера доменных событий \\\
+
+type Service interface {
+	Subscribe(subscriber Subscriber, eventTypes ...string)
+	Run(ctx context.Context)
+}
+
+/// Структура подписки: подписчик и типы событий, пустой набор означает все события \\\
+
+type subscription struct {
+	subscriber Subscriber
+	eventTypes map[string]bool
+}
+
+/// Структура  service реализизирующая инфтерфейс Service диспетчера \\\
+
+type service struct {
+	logger        logger.Logger
+	storage       Storage
+	poll          time.Duration
+	batchSize     int
+	retry         time.Duration
+	maxRetry      time.Duration
+	retention     time.Duration
+	mu            sync.RWMutex
+	subscriptions []subscription
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	return &service{
+		logger:    logger,
+		storage:   storage,
+		poll:      time.Duration(cfg.Outbox.PollSeconds) * time.Second,
+		batchSize: cfg.Outbox.BatchSize,
+		retry:     time.Duration(cfg.Outbox.RetrySeconds) * time.Second,
+		maxRetry:  time.Duration(cfg.Outbox.MaxRetryMinutes) * time.Minute,
+		retention: time.Duration(cfg.Outbox.RetentionDays) * 24 * time.Hour,
+	}
+}
+
+/// Функция Subscribe подписывает subscriber на события типов eventTypes, без типов - на все события \\\
+
+func (s *service) Subscribe(subscriber Subscriber, eventTypes ...string) {
+	types := make(map[string]bool, len(eventTypes))
+	for _, t := range eventTypes {
+		types[t] = true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscriptions = append(s.subscriptions, subscription{subscriber: subscriber, eventTypes: types})
+	s.logger.Infof("outbox subscriber %s registered", subscriber.Name())
+}
+
+/// Функция Run периодически рассылает новые события подписчикам и удаляет старые разосланные события \\\
+/// Работает до отмены ctx, при poll_seconds <= 0 события не рассылаются \\\
+
+func (s *service) Run(ctx context.Context) {
+	if s.poll <= 0 {
+		s.logger.Info("outbox dispatcher is disabled")
+		return
+	}
+	ticker := time.NewTicker(s.poll)
+	defer ticker.Stop()
+
+	var cleaned time.Time
+	for {
+		s.dispatch(ctx)
+		if s.retention > 0 && time.Since(cleaned) >= cleanupPeriod {
+			if _, err := s.storage.DeleteDispatched(time.Now().Add(-s.retention)); err != nil {
+				s.logger.Warnf("failed to delete dispatched outbox events: %v", err)
+			}
+			cleaned = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/// Функция dispatch захватывает наступившие события пачками и рассылает их \\\
+
+func (s *service) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		events, err := s.storage.ClaimDue(time.Now(), claimLease, s.batchSize)
+		if err != nil {
+			s.logger.Warnf("failed to claim outbox events: %v", err)
+			return
+		}
+		for i := range events {
+			s.deliver(ctx, &events[i])
+		}
+		if len(events) < s.batchSize {
+			return
+		}
+	}
+}
+
+/// Функция deliver доставляет событие подписчикам, которые его еще не получили \\\
+/// Если хотя бы один подписчик вернул ошибку, событие будет повторено с экспоненциальной задержкой \\\
+
+func (s *service) deliver(ctx context.Context, event *Event) {
+	delivered := make(map[string]bool, len(event.Delivered))
+	for _, name := range event.Delivered {
+		delivered[name] = true
+	}
+
+	s.mu.RLock()
+	subscriptions := s.subscriptions
+	s.mu.RUnlock()
+
+	var failure error
+	for _, sub := range subscriptions {
+		name := sub.subscriber.Name()
+		if delivered[name] || (len(sub.eventTypes) > 0 && !sub.eventTypes[event.Type]) {
+			continue
+		}
+		if err := s.handle(ctx, sub.subscriber, event); err != nil {
+			s.logger.Warnf("outbox subscriber %s failed on event %d: %v", name, event.ID, err)
+			if failure == nil {
+				failure = fmt.Errorf("%s: %v", name, err)
+			}
+			continue
+		}
+		if err := s.storage.MarkDelivered(event.ID, name); err != nil {
+			s.logger.Warnf("failed to mark outbox event %d delivered to %s: %v", event.ID, name, err)
+		}
+	}
+
+	if failure != nil {
+		if err := s.storage.MarkFailed(event.ID, failure.Error(), time.Now().Add(s.backoff(event.Attempts))); err != nil {
+			s.logger.Warnf("failed to mark outbox event %d failed: %v", event.ID, err)
+		}
+		return
+	}
+	if err := s.storage.MarkDispatched(event.ID); err != nil {
+		s.logger.Warnf("failed to mark outbox event %d dispatched: %v", event.ID, err)
+	}
+}
+
+/// Функция handle вызывает подписчика с ограничением времени, паника подписчика считается ошибкой доставки \\\
+
+func (s *service) handle(ctx context.Context, subscriber Subscriber, event *Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	handleCtx, cancel := context.WithTimeout(ctx, handleTimeout)
+	defer cancel()
+	return subscriber.Handle(handleCtx, *event)
+}
+
+/// Функция backoff возвращает задержку перед попыткой attempts+1: retry, 2*retry, 4*retry... но не больше maxRetry \\\
+
+func (s *service) backoff(attempts int) time.Duration {
+	step := attempts - 1
+	if step < 0 {
+		step = 0
+	}
+	if step > maxBackoffStep {
+		step = maxBackoffStep
+	}
+	delay := s.retry << uint(step)
+	if s.maxRetry > 0 && delay > s.maxRetry {
+		delay = s.maxRetry
+	}
+	return delay
+}
@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+/// Структура HTTPSink внешний приемник событий: каждое событие отправляется POST запросом с JSON телом Event \\\
+/// Заголовок Idempotency-Key содержит id события, по нему приемник отбрасывает повторные доставки \\\
+
+type HTTPSink struct {
+	url    string
+	client *http.Client
+}
+
+/// Структура NewHTTPSink возвращает новый экземпляр HTTPSink инициализируя переданные в него аргументы \\\
+
+func NewHTTPSink(url string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+/// Функция Name возвращает имя приемника для отметок о доставке \\\
+
+func (h *HTTPSink) Name() string {
+	return "http:" + h.url
+}
+
+/// Функция Handle отправляет событие, ответ вне диапазона 2xx считается ошибкой \\\
+
+func (h *HTTPSink) Handle(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sink request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", strconv.FormatInt(event.ID, 10))
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call sink: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("sink responded %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package outbox
+
+import "time"
+
+type Storage interface {
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]Event, error)
+	MarkDelivered(eventId int64, subscriber string) error
+	MarkDispatched(eventId int64) error
+	MarkFailed(eventId int64, reason string, retryAt time.Time) error
+	DeleteDispatched(before time.Time) (int64, error)
+}
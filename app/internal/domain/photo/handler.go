@@ -0,0 +1,193 @@
+package photo
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+const (
+	doctorPhotoURL = "/hospital_record/doctors/profile/:id/photo"
+	doctorImageURL = "/hospital_record/doctor/image"
+)
+
+/// Запас на служебные части multipart запроса сверх максимального размера файла \\\
+
+const multipartOverhead = 1 << 20
+
+/// Время, в течение которого клиент может не перепроверять фотографию \\\
+
+const cacheControl = "public, max-age=3600"
+
+/// Структура Handler представляющая собой обработчик объекта photoService для фотографий докторов \\\
+
+type Handler struct {
+	logger       logger.Logger
+	photoService Service
+	staff        handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, photoService Service, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:       logger,
+		photoService: photoService,
+		staff:        staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для фотографий докторов, загружают их только сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, doctorPhotoURL, h.GetDoctorPhoto)
+	router.HandlerFunc(http.MethodPost, doctorPhotoURL, h.staff(h.UploadDoctorPhoto))
+	router.HandlerFunc(http.MethodPost, doctorImageURL, h.staff(h.UploadDoctorImage))
+}
+
+/// Функция UploadDoctorPhoto загружает фотографию доктора из поля image form-data \\\
+
+func (h *Handler) UploadDoctorPhoto(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: UPLOAD DOCTOR PHOTO")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	if !h.parseForm(w, r) {
+		return
+	}
+	h.upload(w, r, id)
+}
+
+/// Функция UploadDoctorImage загружает изображение доктора из form-data с полями image и doctor_id \\\
+
+func (h *Handler) UploadDoctorImage(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE DOCTOR IMAGE")
+
+	if !h.parseForm(w, r) {
+		return
+	}
+	doctorId, err := strconv.ParseInt(r.FormValue("doctor_id"), 10, 64)
+	if err != nil || doctorId < 1 {
+		response.BadRequest(w, "doctor_id must have type int64", "")
+		return
+	}
+	h.upload(w, r, doctorId)
+}
+
+/// Функция parseForm разбирает form-data запроса, ограничивая размер его тела \\\
+
+func (h *Handler) parseForm(w http.ResponseWriter, r *http.Request) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, h.photoService.MaxSize()+multipartOverhead)
+	if err := r.ParseMultipartForm(multipartOverhead); err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			response.Error(w, http.StatusRequestEntityTooLarge, apperror.ErrAttachmentTooLarge.Error(), "")
+			return false
+		}
+		response.BadRequest(w, err.Error(), "")
+		return false
+	}
+	return true
+}
+
+/// Функция upload читает файл из поля image запроса и сохраняет его как фотографию доктора \\\
+
+func (h *Handler) upload(w http.ResponseWriter, r *http.Request, doctorId int64) {
+	/// Принимает объект r типа form-data \\\
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	defer file.Close()
+
+	input := UploadPhotoDTO{
+		DoctorID: doctorId,
+		FileName: header.Filename,
+	}
+	if user, ok := auth.FromContext(r.Context()); ok {
+		input.UploadedBy = &user.ID
+	}
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Upload передавая ей полученные значения и содержимое файла \\\
+	photo, err := h.photoService.Upload(r.Context(), &input, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrAttachmentTooLarge):
+			response.Error(w, http.StatusRequestEntityTooLarge, err.Error(), "")
+		case errors.Is(err, apperror.ErrPhotoFormat), errors.Is(err, apperror.ErrAttachmentType):
+			response.Error(w, http.StatusUnsupportedMediaType, err.Error(), "")
+		case errors.Is(err, apperror.ErrPhotoDimensions):
+			response.Error(w, http.StatusUnprocessableEntity, err.Error(), "")
+		default:
+			response.InternalError(w, fmt.Sprintf("cannot upload doctor photo: %v", err), "")
+		}
+		return
+	}
+	h.logger.Info("DOCTOR PHOTO UPLOADED")
+	response.JSON(w, http.StatusCreated, photo)
+}
+
+/// Функция GetDoctorPhoto отдает фотографию доктора, размер задается параметром size \\\
+
+func (h *Handler) GetDoctorPhoto(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DOCTOR PHOTO")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции Open передавая ей id доктора и размер фотографии \\\
+	variant, content, err := h.photoService.Open(r.Context(), id, r.URL.Query().Get("size"))
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidPhotoSize):
+			response.BadRequest(w, err.Error(), "")
+		default:
+			h.logger.Error(err)
+			response.InternalError(w, err.Error(), "")
+		}
+		return
+	}
+	defer content.Close()
+
+	/// Содержимое адресуется хешем, поэтому он же служит ETag для повторной проверки кеша \\\
+	etag := `"` + variant.BlobKey + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", variant.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(variant.ByteSize, 10))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Errorf("failed to send doctor photo: %v", err)
+		return
+	}
+	h.logger.Info("GOT DOCTOR PHOTO")
+}
@@ -0,0 +1,50 @@
+package photo
+
+/// Стандартные размеры фотографии доктора, значение - максимальная сторона миниатюры в пикселях \\\
+
+const (
+	SizeOriginal = "original"
+	SizeSmall    = "small"
+	SizeMedium   = "medium"
+	SizeLarge    = "large"
+)
+
+var sides = map[string]int{
+	SizeSmall:  64,
+	SizeMedium: 256,
+	SizeLarge:  512,
+}
+
+/// Порядок создания миниатюр при загрузке фотографии \\\
+
+var variantSizes = []string{SizeSmall, SizeMedium, SizeLarge}
+
+/// Структура миниатюры фотографии, содержимое хранится в BlobStore по ключу BlobKey \\\
+
+type Variant struct {
+	ID           int64  `json:"id" example:"1567"`
+	AttachmentID int64  `json:"attachment_id" example:"1567"`
+	Size         string `json:"size" example:"medium"`
+	BlobKey      string `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	ContentType  string `json:"content_type" example:"image/jpeg"`
+	Width        int    `json:"width" example:"256"`
+	Height       int    `json:"height" example:"192"`
+	ByteSize     int64  `json:"byte_size" example:"20480"`
+}
+
+/// Структура фотографии доктора: оригинал хранится как вложение, миниатюры в Variants \\\
+
+type Photo struct {
+	DoctorID     int64     `json:"doctor_id" example:"1567"`
+	AttachmentID int64     `json:"attachment_id" example:"1567"`
+	ContentType  string    `json:"content_type" example:"image/jpeg"`
+	Width        int       `json:"width" example:"1024"`
+	Height       int       `json:"height" example:"768"`
+	Variants     []Variant `json:"variants"`
+}
+
+type UploadPhotoDTO struct {
+	DoctorID   int64  `json:"doctor_id" example:"1567"`
+	FileName   string `json:"file_name" example:"doctor.jpg"`
+	UploadedBy *int64 `json:"uploaded_by,omitempty" example:"1"`
+}
@@ -0,0 +1,93 @@
+package photo
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &PhotoStorage{}
+
+/// Структура PhotoStorage содержащая поля для работы с БД \\\
+
+type PhotoStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр PhotoStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &PhotoStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция CreateVariant для сущности PhotoStorage сохраняет миниатюру фотографии в БД \\\
+
+func (p *PhotoStorage) CreateVariant(variant *Variant) (*Variant, error) {
+	p.logger.Info("POSTGRES: CREATE PHOTO VARIANT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), p.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД, повторное создание миниатюры того же размера заменяет прежнюю \\\
+	row := p.conn.QueryRow(ctx,
+		`INSERT INTO doctor_photo_variant (attachment_id, size, blob_key, content_type, width, height, byte_size)
+			 VALUES($1,$2,$3,$4,$5,$6,$7)
+			 ON CONFLICT (attachment_id, size) DO UPDATE
+			 SET blob_key = EXCLUDED.blob_key, content_type = EXCLUDED.content_type,
+			     width = EXCLUDED.width, height = EXCLUDED.height, byte_size = EXCLUDED.byte_size
+			 RETURNING id`,
+		variant.AttachmentID, variant.Size, variant.BlobKey, variant.ContentType,
+		variant.Width, variant.Height, variant.ByteSize)
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&variant.ID)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create photo variant query: %v", err)
+		p.logger.Error(err)
+		return nil, err
+	}
+	return variant, nil
+}
+
+/// Функция FindVariant для сущности PhotoStorage получает миниатюру фотографии из БД по id вложения и размеру \\\
+
+func (p *PhotoStorage) FindVariant(attachmentId int64, size string) (*Variant, error) {
+	p.logger.Info("POSTGRES: GET PHOTO VARIANT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), p.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := p.conn.QueryRow(ctx,
+		`SELECT * FROM doctor_photo_variant
+			 WHERE attachment_id = $1 AND size = $2`, attachmentId, size)
+
+	variant := &Variant{}
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&variant.ID, &variant.AttachmentID, &variant.Size, &variant.BlobKey,
+		&variant.ContentType, &variant.Width, &variant.Height, &variant.ByteSize,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find photo variant query: %v", err)
+		p.logger.Error(err)
+		return nil, err
+	}
+	return variant, nil
+}
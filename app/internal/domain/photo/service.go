@@ -0,0 +1,267 @@
+package photo
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/attachment"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/pkg/blobstore"
+	"HospitalRecord/app/pkg/imaging"
+	"HospitalRecord/app/pkg/logger"
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+)
+
+/// Качество JPEG при кодировании миниатюр \\\
+
+const jpegQuality = 85
+
+/// Интерфейс Service реализизирующий service и методы для обработки фотографий докторов \\\
+
+type Service interface {
+	Upload(ctx context.Context, input *UploadPhotoDTO, content io.Reader) (*Photo, error)
+	Open(ctx context.Context, doctorId int64, size string) (*Variant, io.ReadCloser, error)
+	MaxSize() int64
+}
+
+/// Структура  service реализизирующая инфтерфейс Service фотографий докторов \\\
+
+type service struct {
+	logger      logger.Logger
+	storage     Storage
+	blobs       blobstore.BlobStore
+	doc         doctor.Storage
+	attachments attachment.Service
+	minSide     int
+	maxWidth    int
+	maxHeight   int
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, attachments attachment.Service, storage Storage, blobs blobstore.BlobStore, cfg *config.Config, logger logger.Logger) Service {
+	return &service{
+		logger:      logger,
+		storage:     storage,
+		blobs:       blobs,
+		doc:         doc,
+		attachments: attachments,
+		minSide:     cfg.Photos.MinSide,
+		maxWidth:    cfg.Photos.MaxWidth,
+		maxHeight:   cfg.Photos.MaxHeight,
+	}
+}
+
+/// Функция MaxSize возвращает максимальный размер файла фотографии в байтах \\\
+
+func (s *service) MaxSize() int64 {
+	return s.attachments.MaxSize()
+}
+
+/// Функция Upload проверяет фотографию, сохраняет ее с миниатюрами и привязывает к доктору \\\
+
+func (s *service) Upload(ctx context.Context, input *UploadPhotoDTO, content io.Reader) (*Photo, error) {
+	s.logger.Info("SERVICE: UPLOAD DOCTOR PHOTO")
+
+	/// Проверка что доктор существует \\\
+	doc, err := s.doc.FindById(input.DoctorID)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Чтение файла с ограничением размера \\\
+	data, err := io.ReadAll(io.LimitReader(content, s.MaxSize()+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.MaxSize() {
+		return nil, apperror.ErrAttachmentTooLarge
+	}
+
+	/// Проверка формата и размеров по заголовку, до декодирования всего изображения \\\
+	imgConfig, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "png" && format != "jpeg") {
+		return nil, apperror.ErrPhotoFormat
+	}
+	if imgConfig.Width < s.minSide || imgConfig.Height < s.minSide ||
+		imgConfig.Width > s.maxWidth || imgConfig.Height > s.maxHeight {
+		return nil, apperror.ErrPhotoDimensions
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperror.ErrPhotoFormat
+	}
+
+	/// Сохранение оригинала как вложения доктора \\\
+	original, err := s.attachments.Upload(ctx, &attachment.UploadAttachmentDTO{
+		FileName:   input.FileName,
+		OwnerType:  attachment.OwnerDoctor,
+		OwnerID:    input.DoctorID,
+		UploadedBy: input.UploadedBy,
+	}, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	photo := &Photo{
+		DoctorID:     input.DoctorID,
+		AttachmentID: original.ID,
+		ContentType:  original.ContentType,
+		Width:        imgConfig.Width,
+		Height:       imgConfig.Height,
+		Variants:     make([]Variant, 0, len(variantSizes)),
+	}
+
+	/// Создание миниатюр стандартных размеров \\\
+	for _, size := range variantSizes {
+		variant, err := s.createVariant(img, format, original.ID, size)
+		if err != nil {
+			s.discard(original.ID)
+			return nil, err
+		}
+		photo.Variants = append(photo.Variants, *variant)
+	}
+
+	/// Привязка фотографии к доктору \\\
+	imageId := strconv.FormatInt(original.ID, 10)
+	err = s.doc.PartiallyUpdate(&doctor.PartiallyUpdateDoctorDTO{
+		ID:      input.DoctorID,
+		ImageID: &imageId,
+	})
+	if err != nil {
+		s.discard(original.ID)
+		return nil, err
+	}
+
+	/// Прежняя фотография доктора больше не нужна \\\
+	s.replace(ctx, doc.ID, doc.ImageID, original.ID)
+	return photo, nil
+}
+
+/// Функция Open возвращает метаданные и содержимое фотографии доктора нужного размера \\\
+
+func (s *service) Open(ctx context.Context, doctorId int64, size string) (*Variant, io.ReadCloser, error) {
+	s.logger.Info("SERVICE: OPEN DOCTOR PHOTO")
+
+	if size == "" {
+		size = SizeOriginal
+	}
+	if _, ok := sides[size]; !ok && size != SizeOriginal {
+		return nil, nil, apperror.ErrInvalidPhotoSize
+	}
+
+	/// Поиск вложения, на которое ссылается image_id доктора \\\
+	doc, err := s.doc.FindById(doctorId)
+	if err != nil {
+		return nil, nil, err
+	}
+	attachmentId, err := strconv.ParseInt(doc.ImageID, 10, 64)
+	if err != nil {
+		return nil, nil, apperror.ErrEmptyString
+	}
+	original, err := s.attachments.GetById(ctx, attachmentId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if original.OwnerType != attachment.OwnerDoctor || original.OwnerID != doctorId {
+		return nil, nil, apperror.ErrEmptyString
+	}
+
+	/// Для фотографий без миниатюр отдается оригинал \\\
+	variant := &Variant{
+		AttachmentID: original.ID,
+		Size:         SizeOriginal,
+		BlobKey:      original.BlobKey,
+		ContentType:  original.ContentType,
+		ByteSize:     original.Size,
+	}
+	if size != SizeOriginal {
+		found, err := s.storage.FindVariant(original.ID, size)
+		if err == nil {
+			variant = found
+		} else if !errors.Is(err, apperror.ErrEmptyString) {
+			return nil, nil, err
+		}
+	}
+
+	/// Открытие файла в хранилище файлов \\\
+	content, err := s.blobs.Open(variant.BlobKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrBlobNotFound) {
+			s.logger.Errorf("photo of doctor %d references missing blob %s", doctorId, variant.BlobKey)
+			return nil, nil, apperror.ErrEmptyString
+		}
+		return nil, nil, err
+	}
+	return variant, content, nil
+}
+
+/// Функция createVariant уменьшает изображение до размера size и сохраняет миниатюру \\\
+
+func (s *service) createVariant(img image.Image, format string, attachmentId int64, size string) (*Variant, error) {
+	thumb := imaging.Fit(img, sides[size])
+
+	/// Миниатюра кодируется в формате оригинала, чтобы сохранить прозрачность PNG \\\
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	var err error
+	if format == "png" {
+		contentType = "image/png"
+		err = png.Encode(&buf, thumb)
+	} else {
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: jpegQuality})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, byteSize, err := s.blobs.Put(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := thumb.Bounds()
+	variant, err := s.storage.CreateVariant(&Variant{
+		AttachmentID: attachmentId,
+		Size:         size,
+		BlobKey:      key,
+		ContentType:  contentType,
+		Width:        bounds.Dx(),
+		Height:       bounds.Dy(),
+		ByteSize:     byteSize,
+	})
+	if err != nil {
+		s.attachments.Release(key)
+		return nil, err
+	}
+	return variant, nil
+}
+
+/// Функция discard удаляет оригинал фотографии, файлы миниатюр освобождаются вместе с ним \\\
+
+func (s *service) discard(attachmentId int64) {
+	if err := s.attachments.Delete(attachmentId); err != nil {
+		s.logger.Warnf("failed to discard photo attachment %d: %v", attachmentId, err)
+	}
+}
+
+/// Функция replace удаляет прежнюю фотографию доктора после загрузки новой \\\
+/// image_id может ссылаться на файл из старой схемы хранения, такие значения пропускаются \\\
+
+func (s *service) replace(ctx context.Context, doctorId int64, previousImageId string, currentId int64) {
+	previousId, err := strconv.ParseInt(previousImageId, 10, 64)
+	if err != nil || previousId == currentId {
+		return
+	}
+	previous, err := s.attachments.GetById(ctx, previousId)
+	if err != nil || previous.OwnerType != attachment.OwnerDoctor || previous.OwnerID != doctorId {
+		return
+	}
+	s.discard(previousId)
+}
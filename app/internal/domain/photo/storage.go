@@ -0,0 +1,6 @@
+package photo
+
+type Storage interface {
+	CreateVariant(variant *Variant) (*Variant, error)
+	FindVariant(attachmentId int64, size string) (*Variant, error)
+}
@@ -9,11 +9,20 @@ import (
 	"fmt"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
+	"strconv"
 )
 
 const (
 	portfoliosURL = "/hospital_record/portfolios"
 	portfolioURL  = "/hospital_record/portfolios/:id"
+	expiringURL   = "/hospital_record/expiring_certificates"
+)
+
+/// Период по умолчанию и максимальный период поиска истекающих сертификатов в днях \\\
+
+const (
+	defaultExpiringDays = 30
+	maxExpiringDays     = 365
 )
 
 /// Структура Handler представляющая собой обработчик объекта portfolioService для портфолио докторов \\\
@@ -36,6 +45,7 @@ func NewHandler(logger logger.Logger, portfolioService Service) handler.Hand {
 
 func (h *Handler) Register(router *httprouter.Router) {
 	router.HandlerFunc(http.MethodGet, portfolioURL, h.GetPortfolioById)
+	router.HandlerFunc(http.MethodGet, expiringURL, h.GetExpiringCertificates)
 	router.HandlerFunc(http.MethodPost, portfoliosURL, h.CreatePortfolio)
 	router.HandlerFunc(http.MethodPut, portfolioURL, h.UpdatePortfolio)
 	router.HandlerFunc(http.MethodDelete, portfolioURL, h.DeletePortfolio)
@@ -69,6 +79,32 @@ func (h *Handler) GetPortfolioById(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, portfolio)
 }
 
+/// Функция GetExpiringCertificates получает сертификаты докторов, истекающие в ближайшие days дней \\\
+
+func (h *Handler) GetExpiringCertificates(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET EXPIRING CERTIFICATES")
+
+	/// Извлечение параметра days из URL \\\
+	days := defaultExpiringDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		d, err := strconv.Atoi(value)
+		if err != nil || d < 1 || d > maxExpiringDays {
+			response.BadRequest(w, fmt.Sprintf("days must be an integer from 1 to %d", maxExpiringDays), "")
+			return
+		}
+		days = d
+	}
+
+	/// Вызов функции GetExpiringCertificates передавая ей количество дней \\\
+	certificates, err := h.portfolioService.GetExpiringCertificates(r.Context(), days)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT EXPIRING CERTIFICATES")
+	response.JSON(w, http.StatusOK, certificates)
+}
+
 /// Функция CreatePortfolio создает портфолио по полученным данным из input \\\
 
 func (h *Handler) CreatePortfolio(w http.ResponseWriter, r *http.Request) {
@@ -86,6 +122,10 @@ func (h *Handler) CreatePortfolio(w http.ResponseWriter, r *http.Request) {
 	/// Вызов функции Create передавая ей полученные значения и ссылку на структуру input \\\
 	portfolio, err := h.portfolioService.Create(r.Context(), &input)
 	if err != nil {
+		if errors.Is(err, apperror.ErrInvalidPortfolio) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
 		response.InternalError(w, fmt.Sprintf("cannot create portfolio: %v", err), "")
 		return
 	}
@@ -122,6 +162,12 @@ func (h *Handler) UpdatePortfolio(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrInvalidPortfolio) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
 	}
 	h.logger.Info("PORTFOLIO UPDATED")
 	response.JSON(w, http.StatusOK, "PORTFOLIO UPDATED")
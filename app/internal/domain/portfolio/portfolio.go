@@ -1,23 +1,93 @@
 package portfolio
 
-/// Структура для создания и обновления портфолио докторов \\\
+import "time"
+
+/// Структура портфолио доктора как история карьеры, стаж работы вычисляется по местам работы \\\
 
 type Portfolio struct {
-	ID             int64  `json:"id" example:"1567"`
-	Education      string `json:"education" example:"residency Institute of N. I. Pirogov"`
-	Awards         string `json:"awards,omitempty" example:"Advanced training course of 4 categories"`
-	WorkExperience uint8  `json:"work_experience" example:"25"`
+	ID             int64         `json:"id" example:"1567"`
+	Education      []Education   `json:"education"`
+	Certificates   []Certificate `json:"certificates"`
+	Awards         []Award       `json:"awards"`
+	Publications   []Publication `json:"publications"`
+	Workplaces     []Workplace   `json:"workplaces"`
+	WorkExperience uint8         `json:"work_experience" example:"25"`
+}
+
+/// Структура записи об образовании \\\
+
+type Education struct {
+	ID          int64  `json:"id" example:"1"`
+	Institution string `json:"institution" example:"Institute of N. I. Pirogov"`
+	Degree      string `json:"degree" example:"residency"`
+	StartYear   int16  `json:"start_year" example:"1998"`
+	EndYear     *int16 `json:"end_year,omitempty" example:"2004"`
+}
+
+/// Структура сертификата специалиста, ExpiresAt пустой у бессрочных сертификатов \\\
+
+type Certificate struct {
+	ID        int64      `json:"id" example:"1"`
+	Title     string     `json:"title" example:"Ophthalmology"`
+	Issuer    string     `json:"issuer" example:"Ministry of Health"`
+	IssuedAt  time.Time  `json:"issued_at" example:"2021-03-01T00:00:00Z"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-03-01T00:00:00Z"`
+}
+
+/// Структура награды \\\
+
+type Award struct {
+	ID    int64  `json:"id" example:"1"`
+	Title string `json:"title" example:"The best doctor of the hospital number 56"`
+	Year  int16  `json:"year" example:"2019"`
+}
+
+/// Структура публикации \\\
+
+type Publication struct {
+	ID    int64   `json:"id" example:"1"`
+	Title string  `json:"title" example:"Modern methods of cataract surgery"`
+	Venue string  `json:"venue" example:"Vestnik oftalmologii"`
+	Year  int16   `json:"year" example:"2020"`
+	URL   *string `json:"url,omitempty" example:"https://example.org/article"`
+}
+
+/// Структура места работы, EndDate пустой у текущего места работы \\\
+
+type Workplace struct {
+	ID           int64      `json:"id" example:"1"`
+	Organization string     `json:"organization" example:"City hospital number 56"`
+	Position     string     `json:"position" example:"ophthalmologist"`
+	StartDate    time.Time  `json:"start_date" example:"2004-09-01T00:00:00Z"`
+	EndDate      *time.Time `json:"end_date,omitempty" example:"2015-08-31T00:00:00Z"`
+}
+
+/// Структура сертификата доктора, срок действия которого скоро истекает \\\
+
+type ExpiringCertificate struct {
+	DoctorID      int64     `json:"doctor_id" example:"1"`
+	Name          string    `json:"name" example:"Vitaliy"`
+	Surname       string    `json:"surname" example:"Ivanov"`
+	Patronymic    *string   `json:"patronymic,omitempty" example:"Semenovich"`
+	PortfolioID   int64     `json:"portfolio_id" example:"1"`
+	CertificateID int64     `json:"certificate_id" example:"1"`
+	Title         string    `json:"title" example:"Ophthalmology"`
+	ExpiresAt     time.Time `json:"expires_at" example:"2026-03-01T00:00:00Z"`
 }
 
 type CreatePortfolioDTO struct {
-	Education      string `json:"education" example:"residency Institute of N. I. Pirogov"`
-	Awards         string `json:"awards,omitempty" example:"Advanced training course of 4 categories"`
-	WorkExperience uint8  `json:"work_experience" example:"25"`
+	Education    []Education   `json:"education"`
+	Certificates []Certificate `json:"certificates"`
+	Awards       []Award       `json:"awards"`
+	Publications []Publication `json:"publications"`
+	Workplaces   []Workplace   `json:"workplaces"`
 }
 
 type UpdatePortfolioDTO struct {
-	ID             int64  `json:"id" example:"1567"`
-	Education      string `json:"education" example:"residency Institute of N. I. Pirogov"`
-	Awards         string `json:"awards,omitempty" example:"Advanced training course of 4 categories"`
-	WorkExperience uint8  `json:"work_experience" example:"25"`
+	ID           int64         `json:"id" example:"1567"`
+	Education    []Education   `json:"education"`
+	Certificates []Certificate `json:"certificates"`
+	Awards       []Award       `json:"awards"`
+	Publications []Publication `json:"publications"`
+	Workplaces   []Workplace   `json:"workplaces"`
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"time"
 )
 
@@ -16,13 +17,13 @@ var _ Storage = &PortfolioStorage{}
 
 type PortfolioStorage struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 /// Структура NewStorage возвращает новый экземпляр PortfolioStorage инициализируя переданные в него аргументы \\\
 
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &PortfolioStorage{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -30,7 +31,7 @@ func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
 	}
 }
 
-/// Функция Create для сущности PortfolioStorage создает записи портфолио в БД \\\
+/// Функция Create для сущности PortfolioStorage создает портфолио со всеми разделами в одной транзакции \\\
 
 func (d *PortfolioStorage) Create(portfolio *Portfolio) (*Portfolio, error) {
 	d.logger.Info("POSTGRES: CREATE PORTFOLIO")
@@ -39,24 +40,40 @@ func (d *PortfolioStorage) Create(portfolio *Portfolio) (*Portfolio, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create portfolio transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	/// Выполнение запроса к БД \\\
-	row := d.conn.QueryRow(ctx,
-		`INSERT INTO portfolio (education, awards, work_experience)
-			 VALUES($1,$2,$3) 
-			 RETURNING id`,
-		portfolio.Education, portfolio.Awards, portfolio.WorkExperience)
+	row := tx.QueryRow(ctx,
+		`INSERT INTO portfolio DEFAULT VALUES
+			 RETURNING id`)
 
 	/// Сканирование полученных значений из БД \\\
-	err := row.Scan(&portfolio.ID)
+	err = row.Scan(&portfolio.ID)
 	if err != nil {
 		err = fmt.Errorf("failed to execute create portfolio query: %v", err)
 		d.logger.Error(err)
 		return nil, err
 	}
+
+	/// Сохранение разделов портфолио \\\
+	err = insertSections(ctx, tx, portfolio.ID, portfolio.Education, portfolio.Certificates,
+		portfolio.Awards, portfolio.Publications, portfolio.Workplaces)
+	if err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create portfolio transaction: %v", err)
+	}
 	return portfolio, nil
 }
 
-/// Функция FindById для сущности PortfolioStorage получает записи портфолио из БД по id \\\
+/// Функция FindById для сущности PortfolioStorage получает портфолио со всеми разделами из БД по id \\\
 
 func (d *PortfolioStorage) FindById(id int64) (*Portfolio, error) {
 	d.logger.Info("POSTGRES: GET PORTFOLIO BY ID")
@@ -66,16 +83,10 @@ func (d *PortfolioStorage) FindById(id int64) (*Portfolio, error) {
 	defer cancel()
 
 	/// Выполнение запроса к БД \\\
-	row := d.conn.QueryRow(ctx,
-		`SELECT * FROM portfolio
-			 WHERE id = $1`, id)
-
 	portfolio := &Portfolio{}
-
-	/// Сканирование полученных значений из БД \\\
-	err := row.Scan(
-		&portfolio.ID, &portfolio.Education, &portfolio.Awards, &portfolio.WorkExperience,
-	)
+	err := d.conn.QueryRow(ctx,
+		`SELECT id FROM portfolio
+			 WHERE id = $1`, id).Scan(&portfolio.ID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, apperror.ErrEmptyString
@@ -84,10 +95,62 @@ func (d *PortfolioStorage) FindById(id int64) (*Portfolio, error) {
 		d.logger.Error(err)
 		return nil, err
 	}
+
+	/// Загрузка разделов портфолио \\\
+	if err = d.loadSections(ctx, portfolio); err != nil {
+		d.logger.Error(err)
+		return nil, err
+	}
 	return portfolio, nil
 }
 
-/// Функция Update для сущности PortfolioStorage обновляет записи о портфолио в БД \\\
+/// Функция FindExpiringCertificates для сущности PortfolioStorage получает сертификаты докторов, истекающие до until \\\
+
+func (d *PortfolioStorage) FindExpiringCertificates(until time.Time) ([]ExpiringCertificate, error) {
+	d.logger.Info("POSTGRES: GET EXPIRING CERTIFICATES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД, уже истекшие сертификаты не учитываются \\\
+	rows, err := d.conn.Query(ctx,
+		`SELECT d.id, d.name, d.surname, d.patronymic, d.portfolio_id, c.id, c.title, c.expires_at
+			 FROM portfolio_certificate c
+			 INNER JOIN doctors d ON d.portfolio_id = c.portfolio_id
+			 WHERE c.expires_at >= current_date AND c.expires_at <= $1
+			 ORDER BY c.expires_at, d.surname, d.name`, until)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		d.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения сертификатов \\\
+	certificates := make([]ExpiringCertificate, 0)
+
+	for rows.Next() {
+		var c ExpiringCertificate
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&c.DoctorID, &c.Name, &c.Surname, &c.Patronymic, &c.PortfolioID,
+			&c.CertificateID, &c.Title, &c.ExpiresAt)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find expiring certificates query: %v", err)
+			d.logger.Error(err)
+			return nil, err
+		}
+		certificates = append(certificates, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return certificates, nil
+}
+
+/// Функция Update для сущности PortfolioStorage заменяет все разделы портфолио в одной транзакции \\\
 
 func (d *PortfolioStorage) Update(portfolio *UpdatePortfolioDTO) error {
 	d.logger.Info("POSTGRES: UPDATE PORTFOLIO")
@@ -96,12 +159,16 @@ func (d *PortfolioStorage) Update(portfolio *UpdatePortfolioDTO) error {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
-	/// Выполнение запроса к БД \\\
-	result, err := d.conn.Exec(ctx,
-		`UPDATE portfolio
-			SET education=$1, awards=$2, work_experience=$3
-			WHERE id =$4`,
-		portfolio.Education, portfolio.Awards, portfolio.WorkExperience, portfolio.ID)
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin update portfolio transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Блокировка портфолио на время замены разделов \\\
+	var id int64
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM portfolio WHERE id = $1 FOR UPDATE`, portfolio.ID).Scan(&id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return apperror.ErrEmptyString
@@ -110,8 +177,27 @@ func (d *PortfolioStorage) Update(portfolio *UpdatePortfolioDTO) error {
 		d.logger.Error(err)
 		return err
 	}
-	if result.RowsAffected() == 0 {
-		return apperror.ErrEmptyString
+
+	/// Удаление прежних разделов \\\
+	for _, table := range sectionTables {
+		_, err = tx.Exec(ctx, `DELETE FROM `+table+` WHERE portfolio_id = $1`, portfolio.ID)
+		if err != nil {
+			err = fmt.Errorf("failed to clear %s: %v", table, err)
+			d.logger.Error(err)
+			return err
+		}
+	}
+
+	/// Сохранение новых разделов \\\
+	err = insertSections(ctx, tx, portfolio.ID, portfolio.Education, portfolio.Certificates,
+		portfolio.Awards, portfolio.Publications, portfolio.Workplaces)
+	if err != nil {
+		d.logger.Error(err)
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update portfolio transaction: %v", err)
 	}
 	return nil
 }
@@ -125,7 +211,7 @@ func (d *PortfolioStorage) Delete(id int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
-	/// Выполнение запроса к БД \\\
+	/// Выполнение запроса к БД, разделы удаляются каскадно \\\
 	result, err := d.conn.Exec(ctx,
 		`DELETE FROM portfolio WHERE id = $1`, id)
 	if err != nil {
@@ -137,3 +223,160 @@ func (d *PortfolioStorage) Delete(id int64) error {
 	}
 	return nil
 }
+
+/// Таблицы разделов портфолио \\\
+
+var sectionTables = []string{
+	"portfolio_education",
+	"portfolio_certificate",
+	"portfolio_award",
+	"portfolio_publication",
+	"portfolio_workplace",
+}
+
+/// Функция insertSections сохраняет разделы портфолио с id равным portfolioId \\\
+
+func insertSections(ctx context.Context, tx pgx.Tx, portfolioId int64, education []Education, certificates []Certificate,
+	awards []Award, publications []Publication, workplaces []Workplace) error {
+	batch := &pgx.Batch{}
+	for _, e := range education {
+		batch.Queue(`INSERT INTO portfolio_education (portfolio_id, institution, degree, start_year, end_year)
+			 VALUES($1,$2,$3,$4,$5)`, portfolioId, e.Institution, e.Degree, e.StartYear, e.EndYear)
+	}
+	for _, c := range certificates {
+		batch.Queue(`INSERT INTO portfolio_certificate (portfolio_id, title, issuer, issued_at, expires_at)
+			 VALUES($1,$2,$3,$4,$5)`, portfolioId, c.Title, c.Issuer, c.IssuedAt, c.ExpiresAt)
+	}
+	for _, a := range awards {
+		batch.Queue(`INSERT INTO portfolio_award (portfolio_id, title, year)
+			 VALUES($1,$2,$3)`, portfolioId, a.Title, a.Year)
+	}
+	for _, p := range publications {
+		batch.Queue(`INSERT INTO portfolio_publication (portfolio_id, title, venue, year, url)
+			 VALUES($1,$2,$3,$4,$5)`, portfolioId, p.Title, p.Venue, p.Year, p.URL)
+	}
+	for _, w := range workplaces {
+		batch.Queue(`INSERT INTO portfolio_workplace (portfolio_id, organization, position, start_date, end_date)
+			 VALUES($1,$2,$3,$4,$5)`, portfolioId, w.Organization, w.Position, w.StartDate, w.EndDate)
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return fmt.Errorf("failed to insert portfolio section: %v", err)
+		}
+	}
+	return results.Close()
+}
+
+/// Функция loadSections загружает все разделы портфолио \\\
+
+func (d *PortfolioStorage) loadSections(ctx context.Context, portfolio *Portfolio) error {
+	portfolio.Education = make([]Education, 0)
+	portfolio.Certificates = make([]Certificate, 0)
+	portfolio.Awards = make([]Award, 0)
+	portfolio.Publications = make([]Publication, 0)
+	portfolio.Workplaces = make([]Workplace, 0)
+
+	/// Образование \\\
+	rows, err := d.conn.Query(ctx,
+		`SELECT id, institution, degree, start_year, end_year FROM portfolio_education
+			 WHERE portfolio_id = $1 ORDER BY start_year, id`, portfolio.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find portfolio education: %v", err)
+	}
+	for rows.Next() {
+		var e Education
+		if err = rows.Scan(&e.ID, &e.Institution, &e.Degree, &e.StartYear, &e.EndYear); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan portfolio education: %v", err)
+		}
+		portfolio.Education = append(portfolio.Education, e)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	/// Сертификаты \\\
+	rows, err = d.conn.Query(ctx,
+		`SELECT id, title, issuer, issued_at, expires_at FROM portfolio_certificate
+			 WHERE portfolio_id = $1 ORDER BY issued_at, id`, portfolio.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find portfolio certificates: %v", err)
+	}
+	for rows.Next() {
+		var c Certificate
+		if err = rows.Scan(&c.ID, &c.Title, &c.Issuer, &c.IssuedAt, &c.ExpiresAt); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan portfolio certificate: %v", err)
+		}
+		portfolio.Certificates = append(portfolio.Certificates, c)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	/// Награды \\\
+	rows, err = d.conn.Query(ctx,
+		`SELECT id, title, year FROM portfolio_award
+			 WHERE portfolio_id = $1 ORDER BY year, id`, portfolio.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find portfolio awards: %v", err)
+	}
+	for rows.Next() {
+		var a Award
+		if err = rows.Scan(&a.ID, &a.Title, &a.Year); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan portfolio award: %v", err)
+		}
+		portfolio.Awards = append(portfolio.Awards, a)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	/// Публикации \\\
+	rows, err = d.conn.Query(ctx,
+		`SELECT id, title, venue, year, url FROM portfolio_publication
+			 WHERE portfolio_id = $1 ORDER BY year, id`, portfolio.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find portfolio publications: %v", err)
+	}
+	for rows.Next() {
+		var p Publication
+		if err = rows.Scan(&p.ID, &p.Title, &p.Venue, &p.Year, &p.URL); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan portfolio publication: %v", err)
+		}
+		portfolio.Publications = append(portfolio.Publications, p)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	/// Места работы \\\
+	rows, err = d.conn.Query(ctx,
+		`SELECT id, organization, position, start_date, end_date FROM portfolio_workplace
+			 WHERE portfolio_id = $1 ORDER BY start_date, id`, portfolio.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find portfolio workplaces: %v", err)
+	}
+	for rows.Next() {
+		var w Workplace
+		if err = rows.Scan(&w.ID, &w.Organization, &w.Position, &w.StartDate, &w.EndDate); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan portfolio workplace: %v", err)
+		}
+		portfolio.Workplaces = append(portfolio.Workplaces, w)
+	}
+	rows.Close()
+	return rows.Err()
+}
@@ -5,6 +5,11 @@ import (
 	"HospitalRecord/app/pkg/logger"
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
 )
 
 /// Интерфейс Service реализизирующий service и методы для обработки CRUD системы портфолио докторов \\\
@@ -12,6 +17,7 @@ import (
 type Service interface {
 	Create(ctx context.Context, input *CreatePortfolioDTO) (*Portfolio, error)
 	GetById(ctx context.Context, id int64) (*Portfolio, error)
+	GetExpiringCertificates(ctx context.Context, days int) (*[]ExpiringCertificate, error)
 	Update(ctx context.Context, portfolio *UpdatePortfolioDTO) error
 	Delete(id int64) error
 }
@@ -37,20 +43,27 @@ func NewService(storage Storage, logger logger.Logger) Service {
 func (s *service) Create(ctx context.Context, input *CreatePortfolioDTO) (*Portfolio, error) {
 	s.logger.Info("SERVICE: CREATE PORTFOLIO")
 
+	/// Проверка разделов портфолио \\\
+	err := validateSections(input.Education, input.Certificates, input.Awards, input.Publications, input.Workplaces)
+	if err != nil {
+		return nil, err
+	}
+
 	/// Создание структуры portf на основе полученных данных \\\
 	portf := Portfolio{
-		Education:      input.Education,
-		Awards:         input.Awards,
-		WorkExperience: input.WorkExperience,
+		Education:    input.Education,
+		Certificates: input.Certificates,
+		Awards:       input.Awards,
+		Publications: input.Publications,
+		Workplaces:   input.Workplaces,
 	}
 
-	/// Вызов функции Create в хранилище докторов \\\
-
+	/// Вызов функции Create в хранилище портфолио \\\
 	portfolio, err := s.storage.Create(&portf)
 	if err != nil {
 		return nil, err
 	}
-
+	portfolio.WorkExperience = workExperience(portfolio.Workplaces, time.Now())
 	return portfolio, nil
 }
 
@@ -69,27 +82,41 @@ func (s *service) GetById(ctx context.Context, id int64) (*Portfolio, error) {
 		s.logger.Warnf("cannot find portfolio by id: %v", err)
 		return nil, err
 	}
+	portfolio.WorkExperience = workExperience(portfolio.Workplaces, time.Now())
 	return portfolio, nil
 }
 
+/// Функция GetExpiringCertificates возвращает сертификаты докторов, срок действия которых истекает в ближайшие days дней \\\
+
+func (s *service) GetExpiringCertificates(ctx context.Context, days int) (*[]ExpiringCertificate, error) {
+	s.logger.Info("SERVICE: GET EXPIRING CERTIFICATES")
+
+	/// Вызов функции FindExpiringCertificates в хранилище портфолио \\\
+	certificates, err := s.storage.FindExpiringCertificates(time.Now().AddDate(0, 0, days))
+	if err != nil {
+		s.logger.Warnf("cannot find expiring certificates: %v", err)
+		return nil, err
+	}
+	return &certificates, nil
+}
+
 /// Функция Update обновляет портфолио через интерфейс Service принимая входные данные portfolio \\\
 
 func (s *service) Update(ctx context.Context, portfolio *UpdatePortfolioDTO) error {
 	s.logger.Info("SERVICE: UPDATE PORTFOLIO")
 
-	/// Вызов функции FindById в хранилище портфолио \\\
-	_, err := s.storage.FindById(portfolio.ID)
+	/// Проверка разделов портфолио \\\
+	err := validateSections(portfolio.Education, portfolio.Certificates, portfolio.Awards, portfolio.Publications, portfolio.Workplaces)
 	if err != nil {
-		if !errors.Is(err, apperror.ErrEmptyString) {
-			s.logger.Errorf("failed to get portfolio: %v", err)
-		}
 		return err
 	}
 
 	/// Вызов функции Update в хранилище портфолио \\\
 	err = s.storage.Update(portfolio)
 	if err != nil {
-		s.logger.Errorf("failed to update portfolio: %v", err)
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Errorf("failed to update portfolio: %v", err)
+		}
 		return err
 	}
 	return nil
@@ -110,3 +137,81 @@ func (s *service) Delete(id int64) error {
 	}
 	return nil
 }
+
+/// Функция validateSections проверяет обязательные поля и согласованность дат в разделах портфолио \\\
+
+func validateSections(education []Education, certificates []Certificate, awards []Award, publications []Publication, workplaces []Workplace) error {
+	for _, e := range education {
+		if strings.TrimSpace(e.Institution) == "" || e.StartYear < 1 {
+			return fmt.Errorf("%w: education requires institution and start year", apperror.ErrInvalidPortfolio)
+		}
+		if e.EndYear != nil && *e.EndYear < e.StartYear {
+			return fmt.Errorf("%w: education ends before it starts", apperror.ErrInvalidPortfolio)
+		}
+	}
+	for _, c := range certificates {
+		if strings.TrimSpace(c.Title) == "" || c.IssuedAt.IsZero() {
+			return fmt.Errorf("%w: certificate requires title and issue date", apperror.ErrInvalidPortfolio)
+		}
+		if c.ExpiresAt != nil && c.ExpiresAt.Before(c.IssuedAt) {
+			return fmt.Errorf("%w: certificate expires before it is issued", apperror.ErrInvalidPortfolio)
+		}
+	}
+	for _, a := range awards {
+		if strings.TrimSpace(a.Title) == "" || a.Year < 1 {
+			return fmt.Errorf("%w: award requires title and year", apperror.ErrInvalidPortfolio)
+		}
+	}
+	for _, p := range publications {
+		if strings.TrimSpace(p.Title) == "" || p.Year < 1 {
+			return fmt.Errorf("%w: publication requires title and year", apperror.ErrInvalidPortfolio)
+		}
+	}
+	for _, w := range workplaces {
+		if strings.TrimSpace(w.Organization) == "" || w.StartDate.IsZero() {
+			return fmt.Errorf("%w: workplace requires organization and start date", apperror.ErrInvalidPortfolio)
+		}
+		if w.EndDate != nil && w.EndDate.Before(w.StartDate) {
+			return fmt.Errorf("%w: workplace ends before it starts", apperror.ErrInvalidPortfolio)
+		}
+	}
+	return nil
+}
+
+/// Функция workExperience вычисляет стаж в полных годах по местам работы \\\
+/// Пересекающиеся периоды учитываются один раз, текущее место работы считается до now \\\
+
+func workExperience(workplaces []Workplace, now time.Time) uint8 {
+	type period struct{ start, end time.Time }
+	periods := make([]period, 0, len(workplaces))
+	for _, w := range workplaces {
+		end := now
+		if w.EndDate != nil && w.EndDate.Before(now) {
+			end = *w.EndDate
+		}
+		if end.After(w.StartDate) {
+			periods = append(periods, period{w.StartDate, end})
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+
+	/// Объединение пересекающихся периодов \\\
+	var total time.Duration
+	for i := 0; i < len(periods); {
+		start, end := periods[i].start, periods[i].end
+		j := i + 1
+		for ; j < len(periods) && !periods[j].start.After(end); j++ {
+			if periods[j].end.After(end) {
+				end = periods[j].end
+			}
+		}
+		total += end.Sub(start)
+		i = j
+	}
+
+	years := math.Floor(total.Hours() / (24 * 365.25))
+	if years > math.MaxUint8 {
+		return math.MaxUint8
+	}
+	return uint8(years)
+}
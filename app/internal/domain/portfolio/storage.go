@@ -1,8 +1,11 @@
 package portfolio
 
+import "time"
+
 type Storage interface {
 	Create(portfolio *Portfolio) (*Portfolio, error)
 	FindById(id int64) (*Portfolio, error)
+	FindExpiringCertificates(until time.Time) ([]ExpiringCertificate, error)
 	Update(portfolio *UpdatePortfolioDTO) error
 	Delete(id int64) error
 }
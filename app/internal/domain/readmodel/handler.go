@@ -0,0 +1,165 @@
+package readmodel
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	doctorCardsURL      = "/hospital_record/doctor_cards"
+	doctorCardURL       = "/hospital_record/doctor_cards/:id"
+	appointmentCardsURL = "/hospital_record/appointment_cards"
+	appointmentCardURL  = "/hospital_record/appointment_cards/:id"
+	patientCardURL      = "/hospital_record/patient_cards/:id"
+)
+
+/// Структура Handler представляющая собой обработчик объекта readModelService для карточек \\\
+
+type Handler struct {
+	logger           logger.Logger
+	readModelService Service
+	authorize        handler.Middleware
+	staff            handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, readModelService Service, authorize, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:           logger,
+		readModelService: readModelService,
+		authorize:        authorize,
+		staff:            staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для карточек \\\
+/// Карточки докторов открыты всем, талоны пациент видит по токену доступа, карточку пациента - только сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, doctorCardsURL, h.GetDoctorCards)
+	router.HandlerFunc(http.MethodGet, doctorCardURL, h.GetDoctorCard)
+	router.HandlerFunc(http.MethodGet, appointmentCardsURL, h.authorize(h.GetAppointmentCards))
+	router.HandlerFunc(http.MethodGet, appointmentCardURL, h.authorize(h.GetAppointmentCard))
+	router.HandlerFunc(http.MethodGet, patientCardURL, h.staff(h.GetPatientCard))
+}
+
+/// Функция GetDoctorCards получает карточки всех докторов \\\
+
+func (h *Handler) GetDoctorCards(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DOCTOR CARDS")
+
+	cards, err := h.readModelService.GetDoctorCards(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT DOCTOR CARDS")
+	response.JSON(w, http.StatusOK, cards)
+}
+
+/// Функция GetDoctorCard получает карточку доктора по id \\\
+
+func (h *Handler) GetDoctorCard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DOCTOR CARD")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	card, err := h.readModelService.GetDoctorCard(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT DOCTOR CARD")
+	response.JSON(w, http.StatusOK, card)
+}
+
+/// Функция GetAppointmentCards получает талоны на прием авторизованного пациента \\\
+
+func (h *Handler) GetAppointmentCards(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET APPOINTMENT CARDS")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	cards, err := h.readModelService.GetAppointmentCards(r.Context(), user.ID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT APPOINTMENT CARDS")
+	response.JSON(w, http.StatusOK, cards)
+}
+
+/// Функция GetAppointmentCard получает талон на прием авторизованного пациента по id записи \\\
+
+func (h *Handler) GetAppointmentCard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET APPOINTMENT CARD")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	card, err := h.readModelService.GetAppointmentCard(r.Context(), user.ID, id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT APPOINTMENT CARD")
+	response.JSON(w, http.StatusOK, card)
+}
+
+/// Функция GetPatientCard получает карточку пациента с заболеваниями \\\
+
+func (h *Handler) GetPatientCard(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PATIENT CARD")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	card, err := h.readModelService.GetPatientCard(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT PATIENT CARD")
+	response.JSON(w, http.StatusOK, card)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
@@ -0,0 +1,252 @@
+package readmodel
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &ReadModelStorage{}
+
+/// Запросы к представлениям, представления читают таблицы при каждом запросе и не устаревают \\\
+
+const (
+	selectDoctorCards = `
+SELECT doctor_id, concat_ws(' ', surname, name, patronymic), name, surname, patronymic, image_id, gender, rating, age,
+       recording_is_available, specialization_id, name_specialization, specializations, portfolio_id
+FROM doctor_specialization_portfolio`
+	selectAppointmentCards = `
+SELECT record_id, patients_id, patient_name, doctor_id, doctor_name, specialization_id, name_specialization,
+       hospital_address, doctor_office, tagging, time_record
+FROM appointment_card`
+)
+
+/// Структура ReadModelStorage содержащая поля для работы с БД \\\
+
+type ReadModelStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр ReadModelStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &ReadModelStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция FindDoctorCards для сущности ReadModelStorage получает карточки всех докторов по алфавиту \\\
+
+func (s *ReadModelStorage) FindDoctorCards() ([]DoctorCard, error) {
+	s.logger.Info("POSTGRES: GET DOCTOR CARDS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := s.conn.Query(ctx, selectDoctorCards+`
+ORDER BY surname, name, patronymic`)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения карточек \\\
+	cards := make([]DoctorCard, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		card, err := scanDoctorCard(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find doctor cards query: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		cards = append(cards, *card)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+/// Функция FindDoctorCardById для сущности ReadModelStorage получает карточку доктора по id \\\
+
+func (s *ReadModelStorage) FindDoctorCardById(id int64) (*DoctorCard, error) {
+	s.logger.Info("POSTGRES: GET DOCTOR CARD BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	card, err := scanDoctorCard(s.conn.QueryRow(ctx, selectDoctorCards+`
+WHERE doctor_id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find doctor card by id query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	return card, nil
+}
+
+/// Функция FindAppointmentCard для сущности ReadModelStorage получает талон на прием по id записи \\\
+
+func (s *ReadModelStorage) FindAppointmentCard(recordId int64) (*AppointmentCard, error) {
+	s.logger.Info("POSTGRES: GET APPOINTMENT CARD")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	card, err := scanAppointmentCard(s.conn.QueryRow(ctx, selectAppointmentCards+`
+WHERE record_id = $1`, recordId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find appointment card query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	return card, nil
+}
+
+/// Функция FindAppointmentCards для сущности ReadModelStorage получает талоны пациента по времени приема \\\
+
+func (s *ReadModelStorage) FindAppointmentCards(patientId int64) ([]AppointmentCard, error) {
+	s.logger.Info("POSTGRES: GET APPOINTMENT CARDS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := s.conn.Query(ctx, selectAppointmentCards+`
+WHERE patients_id = $1
+ORDER BY time_record`, patientId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения талонов \\\
+	cards := make([]AppointmentCard, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		card, err := scanAppointmentCard(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find appointment cards query: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		cards = append(cards, *card)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+/// Функция FindPatientCard для сущности ReadModelStorage получает карточку пациента с заболеваниями \\\
+/// Представление возвращает строку на каждое заболевание, у пациента без заболеваний поля заболевания пустые \\\
+
+func (s *ReadModelStorage) FindPatientCard(id int64) (*PatientCard, error) {
+	s.logger.Info("POSTGRES: GET PATIENT CARD")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := s.conn.Query(ctx,
+		`SELECT patient_id, email, name, surname, patronymic, age, gender, phone_number, address, policy_number, created_at,
+			        disease_id, body_part, description
+			 FROM patients_disease
+			 WHERE patient_id = $1
+			 ORDER BY disease_id`, id)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var card *PatientCard
+	for rows.Next() {
+		row := PatientCard{}
+		var diseaseId *int64
+		var bodyPart, description *string
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&row.ID, &row.Email, &row.Name, &row.Surname, &row.Patronymic, &row.Age, &row.Gender,
+			&row.PhoneNumber, &row.Address, &row.PolicyNumber, &row.CreatedAt, &diseaseId, &bodyPart, &description)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find patient card query: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		if card == nil {
+			card = &row
+			card.Diseases = make([]Disease, 0)
+		}
+		if diseaseId != nil {
+			card.Diseases = append(card.Diseases, Disease{ID: *diseaseId, BodyPart: *bodyPart, Description: *description})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, apperror.ErrEmptyString
+	}
+	return card, nil
+}
+
+/// Функция scanDoctorCard сканирует строку карточки доктора \\\
+
+func scanDoctorCard(row pgx.Row) (*DoctorCard, error) {
+	card := &DoctorCard{}
+	err := row.Scan(&card.ID, &card.FullName, &card.Name, &card.Surname, &card.Patronymic, &card.ImageID, &card.Gender,
+		&card.Rating, &card.Age, &card.RecordingIsAvailable, &card.SpecializationID, &card.Specialization,
+		&card.Specializations, &card.PortfolioID)
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+/// Функция scanAppointmentCard сканирует строку талона на прием \\\
+
+func scanAppointmentCard(row pgx.Row) (*AppointmentCard, error) {
+	card := &AppointmentCard{}
+	err := row.Scan(&card.RecordID, &card.PatientID, &card.PatientName, &card.DoctorID, &card.DoctorName,
+		&card.SpecializationID, &card.Specialization, &card.HospitalAddress, &card.DoctorOffice, &card.Tagging,
+		&card.TimeRecord)
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
@@ -0,0 +1,63 @@
+package readmodel
+
+import "time"
+
+/// Структура карточки доктора из представления doctor_specialization_portfolio: основная специализация и все специализации доктора \\\
+
+type DoctorCard struct {
+	ID                   int64    `json:"id" example:"1"`
+	FullName             string   `json:"full_name" example:"Semenov Boris Ivanovich"`
+	Name                 string   `json:"name" example:"Boris"`
+	Surname              string   `json:"surname" example:"Semenov"`
+	Patronymic           *string  `json:"patronymic,omitempty" example:"Ivanovich"`
+	ImageID              string   `json:"image_id" example:"1"`
+	Gender               string   `json:"gender" example:"male"`
+	Rating               float32  `json:"rating" example:"4.3"`
+	Age                  int32    `json:"age" example:"41"`
+	RecordingIsAvailable bool     `json:"recording_is_available" example:"true"`
+	SpecializationID     int64    `json:"specialization_id" example:"1"`
+	Specialization       string   `json:"specialization" example:"ophthalmologist"`
+	Specializations      []string `json:"specializations" example:"ophthalmologist,surgeon"`
+	PortfolioID          int64    `json:"portfolio_id" example:"1"`
+}
+
+/// Структура талона на прием из представления appointment_card \\\
+
+type AppointmentCard struct {
+	RecordID         int64     `json:"record_id" example:"1"`
+	PatientID        int64     `json:"patient_id" example:"1"`
+	PatientName      string    `json:"patient_name" example:"Vasilieva Julia Evgenievna"`
+	DoctorID         int64     `json:"doctor_id" example:"1"`
+	DoctorName       string    `json:"doctor_name" example:"Semenov Boris Ivanovich"`
+	SpecializationID int64     `json:"specialization_id" example:"1"`
+	Specialization   string    `json:"specialization" example:"ophthalmologist"`
+	HospitalAddress  string    `json:"hospital_address" example:"Roterta, dom 12"`
+	DoctorOffice     string    `json:"doctor_office" example:"201B"`
+	Tagging          string    `json:"tagging" example:"Ne opazdovat"`
+	TimeRecord       time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+}
+
+/// Структура карточки пациента из представления patients_disease, строки представления собираются в список заболеваний \\\
+
+type PatientCard struct {
+	ID           int64     `json:"id" example:"1"`
+	Email        string    `json:"email" example:"secondpatient@mail.ru"`
+	Name         string    `json:"name" example:"Julia"`
+	Surname      string    `json:"surname" example:"Vasilieva"`
+	Patronymic   *string   `json:"patronymic,omitempty" example:"Evgenievna"`
+	Age          int16     `json:"age" example:"21"`
+	Gender       string    `json:"gender" example:"female"`
+	PhoneNumber  *string   `json:"phone_number,omitempty" example:"89998887765"`
+	Address      *string   `json:"address,omitempty" example:"Moscow, Prospect Mira d. 5, kv. 201"`
+	PolicyNumber string    `json:"policy_number" example:"2194589700000056"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-07-27T10:00:00Z"`
+	Diseases     []Disease `json:"diseases"`
+}
+
+/// Структура заболевания в карточке пациента \\\
+
+type Disease struct {
+	ID          int64  `json:"id" example:"2"`
+	BodyPart    string `json:"body_part" example:"hand"`
+	Description string `json:"description" example:"broken finger"`
+}
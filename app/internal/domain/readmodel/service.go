@@ -0,0 +1,88 @@
+package readmodel
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+)
+
+/// Интерфейс Service реализизирующий service и методы для карточек докторов, пациентов и талонов на прием \\\
+
+type Service interface {
+	GetDoctorCards(ctx context.Context) (*[]DoctorCard, error)
+	GetDoctorCard(ctx context.Context, id int64) (*DoctorCard, error)
+	GetAppointmentCard(ctx context.Context, patientId, recordId int64) (*AppointmentCard, error)
+	GetAppointmentCards(ctx context.Context, patientId int64) (*[]AppointmentCard, error)
+	GetPatientCard(ctx context.Context, id int64) (*PatientCard, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service карточек \\\
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+	}
+}
+
+/// Функция GetDoctorCards возвращает карточки всех докторов \\\
+
+func (s *service) GetDoctorCards(ctx context.Context) (*[]DoctorCard, error) {
+	s.logger.Info("SERVICE: GET DOCTOR CARDS")
+
+	cards, err := s.storage.FindDoctorCards()
+	if err != nil {
+		return nil, err
+	}
+	return &cards, nil
+}
+
+/// Функция GetDoctorCard возвращает карточку доктора по id \\\
+
+func (s *service) GetDoctorCard(ctx context.Context, id int64) (*DoctorCard, error) {
+	s.logger.Info("SERVICE: GET DOCTOR CARD")
+
+	return s.storage.FindDoctorCardById(id)
+}
+
+/// Функция GetAppointmentCard возвращает талон на прием пациента, чужая запись не отличается от несуществующей \\\
+
+func (s *service) GetAppointmentCard(ctx context.Context, patientId, recordId int64) (*AppointmentCard, error) {
+	s.logger.Info("SERVICE: GET APPOINTMENT CARD")
+
+	card, err := s.storage.FindAppointmentCard(recordId)
+	if err != nil {
+		return nil, err
+	}
+	if card.PatientID != patientId {
+		return nil, apperror.ErrEmptyString
+	}
+	return card, nil
+}
+
+/// Функция GetAppointmentCards возвращает талоны на прием пациента \\\
+
+func (s *service) GetAppointmentCards(ctx context.Context, patientId int64) (*[]AppointmentCard, error) {
+	s.logger.Info("SERVICE: GET APPOINTMENT CARDS")
+
+	cards, err := s.storage.FindAppointmentCards(patientId)
+	if err != nil {
+		return nil, err
+	}
+	return &cards, nil
+}
+
+/// Функция GetPatientCard возвращает карточку пациента с заболеваниями \\\
+
+func (s *service) GetPatientCard(ctx context.Context, id int64) (*PatientCard, error) {
+	s.logger.Info("SERVICE: GET PATIENT CARD")
+
+	return s.storage.FindPatientCard(id)
+}
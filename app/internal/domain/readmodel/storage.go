@@ -0,0 +1,9 @@
+package readmodel
+
+type Storage interface {
+	FindDoctorCards() ([]DoctorCard, error)
+	FindDoctorCardById(id int64) (*DoctorCard, error)
+	FindAppointmentCard(recordId int64) (*AppointmentCard, error)
+	FindAppointmentCards(patientId int64) ([]AppointmentCard, error)
+	FindPatientCard(id int64) (*PatientCard, error)
+}
@@ -0,0 +1,192 @@
+package realtime
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"encoding/json"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	streamURL = "/hospital_record/stream"
+	retryMs   = 3000
+
+	/// Сколько id последних отправленных событий помнит поток для отсева повторов \\\
+	sentWindow = 4096
+)
+
+/// Структура Handler представляющая собой обработчик объекта realtimeService для потоков событий \\\
+
+type Handler struct {
+	logger          logger.Logger
+	realtimeService Service
+	staff           handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, realtimeService Service, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:          logger,
+		realtimeService: realtimeService,
+		staff:           staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для потоков событий, поток доступен только сотрудникам \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, streamURL, h.staff(h.Stream))
+}
+
+/// Функция Stream отдает поток событий в формате Server-Sent Events \\\
+/// После переподключения с заголовком Last-Event-ID (или параметром last_event_id) пропущенные события повторяются \\\
+
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: STREAM EVENTS")
+
+	filter, err := h.realtimeService.NewFilter(r.URL.Query())
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	lastEventId, err := readLastEventId(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Поток живет дольше write_timeout сервера, поэтому срок записи снимается \\\
+	controller := http.NewResponseController(w)
+	if err = controller.SetWriteDeadline(time.Time{}); err != nil {
+		response.InternalError(w, err.Error(), "streaming is not supported")
+		return
+	}
+
+	/// Подписка выполняется до повтора, чтобы не потерять события между повтором и подпиской \\\
+	client, unsubscribe := h.realtimeService.Subscribe(filter)
+	defer unsubscribe()
+
+	var replay []outbox.Event
+	if lastEventId > 0 {
+		replay, err = h.realtimeService.Replay(r.Context(), filter, lastEventId)
+		if err != nil {
+			response.InternalError(w, err.Error(), "")
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", retryMs)
+
+	/// id событий outbox выдаются до фиксации транзакции, поэтому событие с меньшим id может прийти позже \\\
+	/// Повторы отсеиваются по множеству уже отправленных id, а не по последнему id \\\
+	sent := newSentEvents(sentWindow)
+	for i := range replay {
+		if !sent.add(replay[i].ID) {
+			continue
+		}
+		if err = writeEvent(w, &replay[i]); err != nil {
+			return
+		}
+	}
+	if err = controller.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.realtimeService.Heartbeat())
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err = fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-client.Events():
+			if !ok {
+				return
+			}
+			/// Событие уже отправлено при повторе или доставлено outbox повторно \\\
+			if !sent.add(event.ID) {
+				continue
+			}
+			if err = writeEvent(w, &event); err != nil {
+				return
+			}
+		}
+		if err = controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+/// Функция writeEvent записывает событие в формате text/event-stream \\\
+
+func writeEvent(w http.ResponseWriter, event *outbox.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+/// Структура sentEvents хранит id последних limit отправленных событий, самые старые вытесняются \\\
+
+type sentEvents struct {
+	ids   map[int64]struct{}
+	order []int64
+	limit int
+}
+
+/// Функция newSentEvents возвращает пустое множество отправленных событий \\\
+
+func newSentEvents(limit int) *sentEvents {
+	return &sentEvents{ids: make(map[int64]struct{}, limit), limit: limit}
+}
+
+/// Функция add запоминает id события и возвращает false, если событие уже отправлялось \\\
+
+func (s *sentEvents) add(id int64) bool {
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+	if len(s.order) == s.limit {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.ids[id] = struct{}{}
+	s.order = append(s.order, id)
+	return true
+}
+
+/// Функция readLastEventId читает id последнего полученного клиентом события \\\
+
+func readLastEventId(r *http.Request) (int64, error) {
+	raw := r.Header.Get("Last-Event-ID")
+	if raw == "" {
+		raw = r.URL.Query().Get("last_event_id")
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id < 0 {
+		return 0, fmt.Errorf("%w: last event id must be a non-negative integer", apperror.ErrInvalidStreamFilter)
+	}
+	return id, nil
+}
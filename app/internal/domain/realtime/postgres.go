@@ -0,0 +1,75 @@
+package realtime
+
+import (
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &RealtimeStorage{}
+
+/// Структура RealtimeStorage содержащая поля для работы с БД \\\
+
+type RealtimeStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр RealtimeStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &RealtimeStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция FindEventsAfter для сущности RealtimeStorage получает из outbox_event события после lastEventId \\\
+/// Журнал outbox хранит события retention_days дней, более старые события повторить нельзя \\\
+
+func (r *RealtimeStorage) FindEventsAfter(lastEventId int64, eventTypes []string, limit int) ([]outbox.Event, error) {
+	r.logger.Info("POSTGRES: GET EVENTS AFTER LAST EVENT ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := r.conn.Query(ctx,
+		`SELECT id, event_type, aggregate_type, aggregate_id, payload, created_at FROM outbox_event
+			 WHERE id > $1 AND event_type = ANY($2)
+			 ORDER BY id
+			 LIMIT $3`, lastEventId, eventTypes, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения событий \\\
+	events := make([]outbox.Event, 0)
+
+	for rows.Next() {
+		var e outbox.Event
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&e.ID, &e.Type, &e.AggregateType, &e.AggregateID, &e.Payload, &e.CreatedAt)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find events after query: %v", err)
+			r.logger.Error(err)
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,84 @@
+package realtime
+
+import (
+	"HospitalRecord/app/internal/domain/outbox"
+	"encoding/json"
+	"time"
+)
+
+/// Типы событий потока по умолчанию \\\
+
+var DefaultEventTypes = []string{
+	outbox.RecordCreated, outbox.RecordUpdated, outbox.RecordRescheduled, outbox.RecordCancelled,
+	outbox.TicketIssued, outbox.TicketAssigned, outbox.TicketCalled, outbox.TicketServed, outbox.TicketSkipped,
+}
+
+/// Структура фильтра потока: очередь доктора за день, все события по адресу больницы или их сочетание \\\
+/// Пустое поле фильтра не ограничивает поток \\\
+
+type Filter struct {
+	DoctorID        *int64
+	HospitalAddress *string
+	DayStart        *time.Time
+	EventTypes      map[string]bool
+}
+
+/// Структура полей данных события, по которым работает фильтр \\\
+
+type eventFields struct {
+	DoctorID         *int64     `json:"doctor_id"`
+	PreviousDoctorID *int64     `json:"previous_doctor_id"`
+	HospitalAddress  *string    `json:"hospital_address"`
+	TimeRecord       *time.Time `json:"time_record"`
+	PreviousTime     *time.Time `json:"previous_time"`
+	IssuedAt         *time.Time `json:"issued_at"`
+}
+
+/// Функция Match проверяет, попадает ли событие в поток \\\
+/// Перенос попадает в очередь и прежнего, и нового доктора и дня, чтобы запись исчезла из прежней очереди \\\
+
+func (f *Filter) Match(event *outbox.Event) bool {
+	if !f.EventTypes[event.Type] {
+		return false
+	}
+	if f.DoctorID == nil && f.HospitalAddress == nil && f.DayStart == nil {
+		return true
+	}
+
+	var fields eventFields
+	if err := json.Unmarshal(event.Payload, &fields); err != nil {
+		return false
+	}
+	if f.HospitalAddress != nil && (fields.HospitalAddress == nil || *fields.HospitalAddress != *f.HospitalAddress) {
+		return false
+	}
+	/// У талона очереди нет времени приема, день определяется временем выдачи талона \\\
+	at := fields.TimeRecord
+	if at == nil {
+		at = fields.IssuedAt
+	}
+	current := f.matchSlot(fields.DoctorID, at)
+	previous := fields.PreviousTime != nil && f.matchSlot(orDefault(fields.PreviousDoctorID, fields.DoctorID), fields.PreviousTime)
+	return current || previous
+}
+
+/// Функция matchSlot проверяет доктора и день приема \\\
+
+func (f *Filter) matchSlot(doctorId *int64, at *time.Time) bool {
+	if f.DoctorID != nil && (doctorId == nil || *doctorId != *f.DoctorID) {
+		return false
+	}
+	if f.DayStart != nil {
+		if at == nil || at.Before(*f.DayStart) || !at.Before(f.DayStart.AddDate(0, 0, 1)) {
+			return false
+		}
+	}
+	return true
+}
+
+func orDefault(value, fallback *int64) *int64 {
+	if value != nil {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,199 @@
+package realtime
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/// Имя подписчика outbox \\\
+
+const subscriberName = "realtime"
+
+/// Структура Client подключенного клиента потока \\\
+/// Канал Events закрывается, если клиент не успевает читать события, клиент переподключается с Last-Event-ID \\\
+
+type Client struct {
+	filter *Filter
+	events chan outbox.Event
+}
+
+/// Функция Events возвращает канал событий клиента \\\
+
+func (c *Client) Events() <-chan outbox.Event {
+	return c.events
+}
+
+/// Интерфейс Service реализизирующий service и методы для потоков событий сотрудников \\\
+
+type Service interface {
+	outbox.Subscriber
+	NewFilter(query url.Values) (*Filter, error)
+	Subscribe(filter *Filter) (*Client, func())
+	Replay(ctx context.Context, filter *Filter, lastEventId int64) ([]outbox.Event, error)
+	Heartbeat() time.Duration
+}
+
+/// Структура  service реализизирующая инфтерфейс Service потоков \\\
+
+type service struct {
+	logger      logger.Logger
+	storage     Storage
+	heartbeat   time.Duration
+	replayLimit int
+	buffer      int
+	location    *time.Location
+	mu          sync.Mutex
+	clients     map[*Client]struct{}
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:      logger,
+		storage:     storage,
+		heartbeat:   time.Duration(cfg.Realtime.HeartbeatSeconds) * time.Second,
+		replayLimit: cfg.Realtime.ReplayLimit,
+		buffer:      cfg.Realtime.ClientBuffer,
+		location:    time.UTC,
+		clients:     make(map[*Client]struct{}),
+	}
+	if s.buffer < 1 {
+		s.buffer = 1
+	}
+	if s.heartbeat <= 0 {
+		s.heartbeat = 15 * time.Second
+	}
+	if cfg.Realtime.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Realtime.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown realtime time zone %q, using UTC: %v", cfg.Realtime.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция NewFilter создает фильтр потока из параметров запроса doctor_id, date, hospital_address и types \\\
+/// date задается в виде 2006-01-02, без date очередь доктора включает все дни \\\
+
+func (s *service) NewFilter(query url.Values) (*Filter, error) {
+	filter := &Filter{EventTypes: make(map[string]bool)}
+
+	if raw := query.Get("doctor_id"); raw != "" {
+		doctorId, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || doctorId < 1 {
+			return nil, fmt.Errorf("%w: doctor_id must be a positive integer", apperror.ErrInvalidStreamFilter)
+		}
+		filter.DoctorID = &doctorId
+	}
+	if raw := query.Get("date"); raw != "" {
+		day, err := time.ParseInLocation("2006-01-02", raw, s.location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date must have format 2006-01-02", apperror.ErrInvalidStreamFilter)
+		}
+		filter.DayStart = &day
+	}
+	if raw := query.Get("hospital_address"); raw != "" {
+		filter.HospitalAddress = &raw
+	}
+
+	types := DefaultEventTypes
+	if raw := query.Get("types"); raw != "" {
+		types = strings.Split(raw, ",")
+	}
+	for _, t := range types {
+		t = strings.TrimSpace(t)
+		if !outbox.IsEventType(t) {
+			return nil, fmt.Errorf("%w: unknown event type %q", apperror.ErrInvalidStreamFilter, t)
+		}
+		filter.EventTypes[t] = true
+	}
+	return filter, nil
+}
+
+/// Функция Subscribe подключает клиента к потоку, возвращаемая функция отключает его \\\
+
+func (s *service) Subscribe(filter *Filter) (*Client, func()) {
+	client := &Client{filter: filter, events: make(chan outbox.Event, s.buffer)}
+
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+
+	return client, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.clients[client]; ok {
+			delete(s.clients, client)
+			close(client.events)
+		}
+	}
+}
+
+/// Функция Replay возвращает события после lastEventId, подходящие под фильтр \\\
+
+func (s *service) Replay(ctx context.Context, filter *Filter, lastEventId int64) ([]outbox.Event, error) {
+	s.logger.Info("SERVICE: REPLAY STREAM EVENTS")
+
+	types := make([]string, 0, len(filter.EventTypes))
+	for t := range filter.EventTypes {
+		types = append(types, t)
+	}
+	events, err := s.storage.FindEventsAfter(lastEventId, types, s.replayLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]outbox.Event, 0, len(events))
+	for i := range events {
+		if filter.Match(&events[i]) {
+			matched = append(matched, events[i])
+		}
+	}
+	return matched, nil
+}
+
+/// Функция Heartbeat возвращает интервал служебных комментариев, поддерживающих соединение \\\
+
+func (s *service) Heartbeat() time.Duration {
+	return s.heartbeat
+}
+
+/// Функция Name возвращает имя подписчика outbox \\\
+
+func (s *service) Name() string {
+	return subscriberName
+}
+
+/// Функция Handle рассылает событие из outbox подключенным клиентам, чей фильтр ему соответствует \\\
+/// Клиент с заполненным буфером отключается, чтобы медленный клиент не задерживал остальных \\\
+
+func (s *service) Handle(ctx context.Context, event outbox.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for client := range s.clients {
+		if !client.filter.Match(&event) {
+			continue
+		}
+		select {
+		case client.events <- event:
+		default:
+			s.logger.Warnf("stream client is too slow, disconnecting it at event %d", event.ID)
+			delete(s.clients, client)
+			close(client.events)
+		}
+	}
+	return nil
+}
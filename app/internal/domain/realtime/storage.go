@@ -0,0 +1,7 @@
+package realtime
+
+import "HospitalRecord/app/internal/domain/outbox"
+
+type Storage interface {
+	FindEventsAfter(lastEventId int64, eventTypes []string, limit int) ([]outbox.Event, error)
+}
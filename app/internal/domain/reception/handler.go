@@ -0,0 +1,248 @@
+package reception
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+)
+
+const (
+	ticketsURL  = "/hospital_record/reception/tickets"
+	ticketURL   = "/hospital_record/reception/tickets/:id"
+	assignURL   = "/hospital_record/reception/tickets/:id/assign"
+	serveURL    = "/hospital_record/reception/tickets/:id/serve"
+	skipURL     = "/hospital_record/reception/tickets/:id/skip"
+	callNextURL = "/hospital_record/reception/call_next"
+	displayURL  = "/hospital_record/reception/display"
+)
+
+/// Структура Handler представляющая собой обработчик объекта receptionService для электронной очереди \\\
+
+type Handler struct {
+	logger           logger.Logger
+	receptionService Service
+	staff            handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, receptionService Service, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:           logger,
+		receptionService: receptionService,
+		staff:            staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для электронной очереди \\\
+/// Табло в холле открыто без ключа и не содержит данных пациентов, остальные запросы доступны сотрудникам \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, ticketsURL, h.staff(h.IssueTicket))
+	router.HandlerFunc(http.MethodGet, ticketsURL, h.staff(h.GetTickets))
+	router.HandlerFunc(http.MethodGet, ticketURL, h.staff(h.GetTicket))
+	router.HandlerFunc(http.MethodPost, assignURL, h.staff(h.AssignTicket))
+	router.HandlerFunc(http.MethodPost, serveURL, h.staff(h.ServeTicket))
+	router.HandlerFunc(http.MethodPost, skipURL, h.staff(h.SkipTicket))
+	router.HandlerFunc(http.MethodPost, callNextURL, h.staff(h.CallNext))
+	router.HandlerFunc(http.MethodGet, displayURL, h.GetDisplay)
+}
+
+/// Функция IssueTicket выдает талон электронной очереди \\\
+
+func (h *Handler) IssueTicket(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ISSUE RECEPTION TICKET")
+
+	/// Чтение тела запроса в структуру IssueTicketDTO \\\
+	var input IssueTicketDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	ticket, err := h.receptionService.Issue(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECEPTION TICKET ISSUED")
+	response.JSON(w, http.StatusCreated, ticket)
+}
+
+/// Функция GetTickets получает сегодняшние талоны, параметры hospital_address, doctor_id и status фильтруют очередь \\\
+
+func (h *Handler) GetTickets(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET RECEPTION TICKETS")
+
+	query := r.URL.Query()
+	var doctorId *int64
+	if raw := query.Get("doctor_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 1 {
+			response.BadRequest(w, "doctor_id must be a positive integer", "")
+			return
+		}
+		doctorId = &id
+	}
+	status := query.Get("status")
+	switch status {
+	case "", TicketWaiting, TicketCalled, TicketServed, TicketSkipped:
+	default:
+		response.BadRequest(w, "unknown ticket status", "")
+		return
+	}
+
+	tickets, err := h.receptionService.GetTickets(r.Context(), query.Get("hospital_address"), doctorId, status)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT RECEPTION TICKETS")
+	response.JSON(w, http.StatusOK, tickets)
+}
+
+/// Функция GetTicket получает талон по id \\\
+
+func (h *Handler) GetTicket(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET RECEPTION TICKET")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ticket, err := h.receptionService.GetTicket(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT RECEPTION TICKET")
+	response.JSON(w, http.StatusOK, ticket)
+}
+
+/// Функция AssignTicket направляет ожидающий талон в кабинет доктора \\\
+
+func (h *Handler) AssignTicket(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ASSIGN RECEPTION TICKET")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру AssignTicketDTO \\\
+	var input AssignTicketDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	ticket, err := h.receptionService.Assign(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECEPTION TICKET ASSIGNED")
+	response.JSON(w, http.StatusOK, ticket)
+}
+
+/// Функция ServeTicket отмечает вызванный талон обслуженным \\\
+
+func (h *Handler) ServeTicket(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: SERVE RECEPTION TICKET")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ticket, err := h.receptionService.Serve(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECEPTION TICKET SERVED")
+	response.JSON(w, http.StatusOK, ticket)
+}
+
+/// Функция SkipTicket снимает талон с очереди \\\
+
+func (h *Handler) SkipTicket(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: SKIP RECEPTION TICKET")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	ticket, err := h.receptionService.Skip(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECEPTION TICKET SKIPPED")
+	response.JSON(w, http.StatusOK, ticket)
+}
+
+/// Функция CallNext вызывает следующий талон очереди доктора, пустая очередь отвечает 404 \\\
+
+func (h *Handler) CallNext(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CALL NEXT RECEPTION TICKET")
+
+	/// Чтение тела запроса в структуру CallNextDTO \\\
+	var input CallNextDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	ticket, err := h.receptionService.CallNext(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECEPTION TICKET CALLED")
+	response.JSON(w, http.StatusOK, ticket)
+}
+
+/// Функция GetDisplay получает состояние очереди по адресу больницы для табло в холле \\\
+
+func (h *Handler) GetDisplay(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET RECEPTION DISPLAY")
+
+	display, err := h.receptionService.GetDisplay(r.Context(), r.URL.Query().Get("hospital_address"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	response.JSON(w, http.StatusOK, display)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidTicket):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrTicketState), errors.Is(err, apperror.ErrTicketIssued):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
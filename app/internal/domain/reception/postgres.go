@@ -0,0 +1,325 @@
+package reception
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &ReceptionStorage{}
+
+/// Колонки талона в порядке полей scanTicket \\\
+
+const ticketColumns = "id, number, category, priority, hospital_address, queue_date, patient_id, record_id, doctor_id, doctor_office, status, issued_at, called_at, finished_at"
+
+/// Структура ReceptionStorage содержащая поля для работы с БД \\\
+
+type ReceptionStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр ReceptionStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &ReceptionStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция Issue для сущности ReceptionStorage выдает талон со следующим номером дня в категории \\\
+
+func (r *ReceptionStorage) Issue(ticket *Ticket, prefix string) (*Ticket, error) {
+	r.logger.Info("POSTGRES: ISSUE RECEPTION TICKET")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin issue reception ticket transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Счетчик номеров блокируется строкой, поэтому номера не повторяются при одновременной выдаче \\\
+	var number int
+	err = tx.QueryRow(ctx,
+		`INSERT INTO reception_counter (hospital_address, queue_date, category, last_number)
+			 VALUES($1,$2,$3,1)
+			 ON CONFLICT (hospital_address, queue_date, category)
+			 DO UPDATE SET last_number = reception_counter.last_number + 1
+			 RETURNING last_number`,
+		ticket.HospitalAddress, ticket.QueueDate, ticket.Category).Scan(&number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to increment reception counter: %v", err)
+	}
+	ticket.Number = fmt.Sprintf("%s-%03d", prefix, number)
+
+	/// Выполнение запроса к БД, по записи на прием не выдается второй действующий талон (индекс reception_ticket_record_idx) \\\
+	created, err := scanTicket(tx.QueryRow(ctx,
+		`INSERT INTO reception_ticket (number, category, priority, hospital_address, queue_date,
+			 patient_id, record_id, doctor_id, doctor_office, status)
+			 VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			 ON CONFLICT (record_id) WHERE status IN ('waiting', 'called') DO NOTHING
+			 RETURNING `+ticketColumns,
+		ticket.Number, ticket.Category, ticket.Priority, ticket.HospitalAddress, ticket.QueueDate,
+		ticket.PatientID, ticket.RecordID, ticket.DoctorID, ticket.DoctorOffice, TicketWaiting))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrTicketIssued
+		}
+		err = fmt.Errorf("failed to execute issue reception ticket query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, outbox.TicketIssued, created.ID, created.EventPayload()); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit issue reception ticket transaction: %v", err)
+	}
+	return created, nil
+}
+
+/// Функция FindTicketById для сущности ReceptionStorage получает талон по id \\\
+
+func (r *ReceptionStorage) FindTicketById(id int64) (*Ticket, error) {
+	r.logger.Info("POSTGRES: GET RECEPTION TICKET BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	ticket, err := scanTicket(r.conn.QueryRow(ctx,
+		`SELECT `+ticketColumns+` FROM reception_ticket
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find reception ticket by id query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	return ticket, nil
+}
+
+/// Функция FindTickets для сущности ReceptionStorage получает талоны дня по адресу в порядке очереди \\\
+/// Пустой hospitalAddress и doctorId не ограничивают выборку \\\
+
+func (r *ReceptionStorage) FindTickets(hospitalAddress string, doctorId *int64, queueDate time.Time, statuses []string) ([]Ticket, error) {
+	r.logger.Info("POSTGRES: GET RECEPTION TICKETS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := r.conn.Query(ctx,
+		`SELECT `+ticketColumns+` FROM reception_ticket
+			 WHERE queue_date = $1 AND status = ANY($2)
+			 AND ($3 = '' OR hospital_address = $3)
+			 AND ($4::bigint IS NULL OR doctor_id = $4)
+			 ORDER BY priority, issued_at, id`,
+		queueDate, statuses, hospitalAddress, doctorId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения талонов \\\
+	tickets := make([]Ticket, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find reception tickets query: %v", err)
+			r.logger.Error(err)
+			return nil, err
+		}
+		tickets = append(tickets, *ticket)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+/// Функция Assign для сущности ReceptionStorage направляет ожидающий талон к доктору \\\
+
+func (r *ReceptionStorage) Assign(input *AssignTicketDTO) (*Ticket, error) {
+	r.logger.Info("POSTGRES: ASSIGN RECEPTION TICKET")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin assign reception ticket transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = lockTicket(ctx, tx, input.ID, TicketWaiting); err != nil {
+		return nil, err
+	}
+
+	/// Выполнение запроса к БД \\\
+	ticket, err := scanTicket(tx.QueryRow(ctx,
+		`UPDATE reception_ticket SET doctor_id = $1, doctor_office = coalesce($2, doctor_office)
+			 WHERE id = $3
+			 RETURNING `+ticketColumns, input.DoctorID, input.DoctorOffice, input.ID))
+	if err != nil {
+		err = fmt.Errorf("failed to execute assign reception ticket query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, outbox.TicketAssigned, ticket.ID, ticket.EventPayload()); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit assign reception ticket transaction: %v", err)
+	}
+	return ticket, nil
+}
+
+/// Функция CallNext для сущности ReceptionStorage вызывает к доктору первый по приоритету ожидающий талон дня \\\
+/// Заблокированные другим вызовом талоны пропускаются, поэтому два кабинета не вызовут один талон \\\
+
+func (r *ReceptionStorage) CallNext(input *CallNextDTO, queueDate time.Time) (*Ticket, error) {
+	r.logger.Info("POSTGRES: CALL NEXT RECEPTION TICKET")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin call next reception ticket transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	ticket, err := scanTicket(tx.QueryRow(ctx,
+		`UPDATE reception_ticket SET status = $1, called_at = now(), doctor_office = coalesce($2, doctor_office)
+			 WHERE id = (SELECT id FROM reception_ticket
+			     WHERE doctor_id = $3 AND queue_date = $4 AND status = $5
+			     AND ($6::text IS NULL OR hospital_address = $6)
+			     ORDER BY priority, issued_at, id
+			     LIMIT 1
+			     FOR UPDATE SKIP LOCKED)
+			 RETURNING `+ticketColumns,
+		TicketCalled, input.DoctorOffice, input.DoctorID, queueDate, TicketWaiting, input.HospitalAddress))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute call next reception ticket query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, outbox.TicketCalled, ticket.ID, ticket.EventPayload()); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit call next reception ticket transaction: %v", err)
+	}
+	return ticket, nil
+}
+
+/// Функция Finish для сущности ReceptionStorage завершает талон статусом served или skipped \\\
+/// Обслужить можно только вызванный талон, пропустить - вызванный или ожидающий \\\
+
+func (r *ReceptionStorage) Finish(id int64, status string) (*Ticket, error) {
+	r.logger.Info("POSTGRES: FINISH RECEPTION TICKET")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin finish reception ticket transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	from := []string{TicketCalled}
+	eventType := outbox.TicketServed
+	if status == TicketSkipped {
+		from = append(from, TicketWaiting)
+		eventType = outbox.TicketSkipped
+	}
+	if err = lockTicket(ctx, tx, id, from...); err != nil {
+		return nil, err
+	}
+
+	/// Выполнение запроса к БД \\\
+	ticket, err := scanTicket(tx.QueryRow(ctx,
+		`UPDATE reception_ticket SET status = $1, finished_at = now()
+			 WHERE id = $2
+			 RETURNING `+ticketColumns, status, id))
+	if err != nil {
+		err = fmt.Errorf("failed to execute finish reception ticket query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	if err = outbox.Write(ctx, tx, eventType, ticket.ID, ticket.EventPayload()); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit finish reception ticket transaction: %v", err)
+	}
+	return ticket, nil
+}
+
+/// Функция lockTicket блокирует талон и проверяет, что его статус один из допустимых \\\
+
+func lockTicket(ctx context.Context, tx pgx.Tx, id int64, statuses ...string) error {
+	var status string
+	err := tx.QueryRow(ctx,
+		`SELECT status FROM reception_ticket WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrEmptyString
+		}
+		return fmt.Errorf("failed to lock reception ticket: %v", err)
+	}
+	for _, s := range statuses {
+		if s == status {
+			return nil
+		}
+	}
+	return apperror.ErrTicketState
+}
+
+/// Функция scanTicket сканирует строку таблицы reception_ticket \\\
+
+func scanTicket(row pgx.Row) (*Ticket, error) {
+	ticket := &Ticket{}
+	err := row.Scan(&ticket.ID, &ticket.Number, &ticket.Category, &ticket.Priority, &ticket.HospitalAddress,
+		&ticket.QueueDate, &ticket.PatientID, &ticket.RecordID, &ticket.DoctorID, &ticket.DoctorOffice,
+		&ticket.Status, &ticket.IssuedAt, &ticket.CalledAt, &ticket.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
@@ -0,0 +1,120 @@
+package reception
+
+import (
+	"HospitalRecord/app/internal/domain/outbox"
+	"time"
+)
+
+/// Категории приоритета талонов: экстренные вызываются первыми, затем пожилые пациенты, записанные и остальные \\\
+
+const (
+	CategoryEmergency = "emergency"
+	CategoryElderly   = "elderly"
+	CategoryBooked    = "booked"
+	CategoryGeneral   = "general"
+)
+
+/// Статусы талона \\\
+
+const (
+	TicketWaiting = "waiting"
+	TicketCalled  = "called"
+	TicketServed  = "served"
+	TicketSkipped = "skipped"
+)
+
+/// Структура категории: приоритет (меньше - раньше) и буква номера талона \\\
+
+type category struct {
+	priority int
+	prefix   string
+}
+
+var categories = map[string]category{
+	CategoryEmergency: {priority: 0, prefix: "E"},
+	CategoryElderly:   {priority: 1, prefix: "P"},
+	CategoryBooked:    {priority: 2, prefix: "B"},
+	CategoryGeneral:   {priority: 3, prefix: "G"},
+}
+
+/// Структура талона электронной очереди, номер сбрасывается каждый день по адресу больницы и категории \\\
+
+type Ticket struct {
+	ID              int64      `json:"id" example:"1"`
+	Number          string     `json:"number" example:"B-012"`
+	Category        string     `json:"category" example:"booked"`
+	Priority        int        `json:"-"`
+	HospitalAddress string     `json:"hospital_address" example:"Roterta, dom 12"`
+	QueueDate       time.Time  `json:"queue_date" example:"2023-07-27T00:00:00Z"`
+	PatientID       *int64     `json:"patient_id,omitempty" example:"1"`
+	RecordID        *int64     `json:"record_id,omitempty" example:"1567"`
+	DoctorID        *int64     `json:"doctor_id,omitempty" example:"1"`
+	DoctorOffice    *string    `json:"doctor_office,omitempty" example:"201B"`
+	Status          string     `json:"status" example:"waiting"`
+	IssuedAt        time.Time  `json:"issued_at" example:"2023-07-27T08:05:00Z"`
+	CalledAt        *time.Time `json:"called_at,omitempty" example:"2023-07-27T08:20:00Z"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty" example:"2023-07-27T08:35:00Z"`
+}
+
+/// Талон выдается по записи на прием, по пациенту или без пациента для живой очереди \\\
+/// Без категории: пожилой пациент получает elderly, пациент с записью booked, остальные general \\\
+
+type IssueTicketDTO struct {
+	HospitalAddress string  `json:"hospital_address" example:"Roterta, dom 12"`
+	Category        string  `json:"category,omitempty" example:"emergency"`
+	PatientID       *int64  `json:"patient_id,omitempty" example:"1"`
+	RecordID        *int64  `json:"record_id,omitempty" example:"1567"`
+	DoctorID        *int64  `json:"doctor_id,omitempty" example:"1"`
+	DoctorOffice    *string `json:"doctor_office,omitempty" example:"201B"`
+}
+
+type AssignTicketDTO struct {
+	ID           int64   `json:"-"`
+	DoctorID     int64   `json:"doctor_id" example:"1"`
+	DoctorOffice *string `json:"doctor_office,omitempty" example:"201B"`
+}
+
+/// Структура вызова следующего талона в кабинет доктора \\\
+
+type CallNextDTO struct {
+	DoctorID        int64   `json:"doctor_id" example:"1"`
+	DoctorOffice    *string `json:"doctor_office,omitempty" example:"201B"`
+	HospitalAddress *string `json:"hospital_address,omitempty" example:"Roterta, dom 12"`
+}
+
+/// Структура табло в холле: вызванные талоны и ближайшие ожидающие, без данных пациентов \\\
+
+type Display struct {
+	HospitalAddress string          `json:"hospital_address" example:"Roterta, dom 12"`
+	QueueDate       time.Time       `json:"queue_date" example:"2023-07-27T00:00:00Z"`
+	Called          []DisplayTicket `json:"called"`
+	Waiting         []DisplayTicket `json:"waiting"`
+}
+
+type DisplayTicket struct {
+	Number       string     `json:"number" example:"B-012"`
+	DoctorOffice *string    `json:"doctor_office,omitempty" example:"201B"`
+	CalledAt     *time.Time `json:"called_at,omitempty" example:"2023-07-27T08:20:00Z"`
+}
+
+/// Функция EventPayload возвращает данные доменного события талона \\\
+
+func (t *Ticket) EventPayload() outbox.TicketPayload {
+	return outbox.TicketPayload{
+		TicketID:        t.ID,
+		Number:          t.Number,
+		Category:        t.Category,
+		Status:          t.Status,
+		HospitalAddress: t.HospitalAddress,
+		DoctorID:        t.DoctorID,
+		DoctorOffice:    t.DoctorOffice,
+		RecordID:        t.RecordID,
+		IssuedAt:        t.IssuedAt,
+	}
+}
+
+/// Функция display возвращает талон для табло \\\
+
+func (t *Ticket) display() DisplayTicket {
+	return DisplayTicket{Number: t.Number, DoctorOffice: t.DoctorOffice, CalledAt: t.CalledAt}
+}
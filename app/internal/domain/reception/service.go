@@ -0,0 +1,247 @@
+package reception
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для электронной очереди регистратуры \\\
+
+type Service interface {
+	Issue(ctx context.Context, input *IssueTicketDTO) (*Ticket, error)
+	GetTicket(ctx context.Context, id int64) (*Ticket, error)
+	GetTickets(ctx context.Context, hospitalAddress string, doctorId *int64, status string) (*[]Ticket, error)
+	Assign(ctx context.Context, input *AssignTicketDTO) (*Ticket, error)
+	CallNext(ctx context.Context, input *CallNextDTO) (*Ticket, error)
+	Serve(ctx context.Context, id int64) (*Ticket, error)
+	Skip(ctx context.Context, id int64) (*Ticket, error)
+	GetDisplay(ctx context.Context, hospitalAddress string) (*Display, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service электронной очереди \\\
+
+type service struct {
+	logger       logger.Logger
+	storage      Storage
+	records      record.Storage
+	patients     user.Storage
+	doc          doctor.Storage
+	elderlyAge   uint8
+	displayLimit int
+	location     *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, patients user.Storage, records record.Storage, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:       logger,
+		storage:      storage,
+		records:      records,
+		patients:     patients,
+		doc:          doc,
+		elderlyAge:   cfg.Reception.ElderlyAge,
+		displayLimit: cfg.Reception.DisplayLimit,
+		location:     time.UTC,
+	}
+	if cfg.Reception.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Reception.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown reception time zone %q, using UTC: %v", cfg.Reception.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция Issue выдает талон пациенту с записью на сегодня, пациенту без записи или анонимному посетителю \\\
+
+func (s *service) Issue(ctx context.Context, input *IssueTicketDTO) (*Ticket, error) {
+	s.logger.Info("SERVICE: ISSUE RECEPTION TICKET")
+
+	today := s.today()
+	ticket := Ticket{
+		HospitalAddress: strings.TrimSpace(input.HospitalAddress),
+		QueueDate:       today,
+		PatientID:       input.PatientID,
+		RecordID:        input.RecordID,
+		DoctorID:        input.DoctorID,
+		DoctorOffice:    input.DoctorOffice,
+	}
+
+	/// Талон по записи направляется к доктору записи, запись должна быть на сегодня \\\
+	if input.RecordID != nil {
+		rec, err := s.records.FindRecordById(*input.RecordID)
+		if err != nil {
+			return nil, err
+		}
+		year, month, day := rec.TimeRecord.In(s.location).Date()
+		if !time.Date(year, month, day, 0, 0, 0, 0, s.location).Equal(today) {
+			return nil, apperror.ErrInvalidTicket
+		}
+		if input.PatientID != nil && *input.PatientID != rec.PatientsID {
+			return nil, apperror.ErrInvalidTicket
+		}
+		ticket.PatientID = &rec.PatientsID
+		ticket.DoctorID = &rec.DoctorID
+		if ticket.HospitalAddress == "" {
+			ticket.HospitalAddress = rec.HospitalAddress
+		}
+		if ticket.DoctorOffice == nil && rec.DoctorOffice != "" {
+			ticket.DoctorOffice = &rec.DoctorOffice
+		}
+	} else if input.DoctorID != nil {
+		if _, err := s.doc.FindById(*input.DoctorID); err != nil {
+			return nil, err
+		}
+	}
+	if ticket.HospitalAddress == "" {
+		return nil, apperror.ErrInvalidTicket
+	}
+
+	/// Определение категории талона \\\
+	ticket.Category = input.Category
+	if ticket.Category == "" {
+		ticket.Category = CategoryGeneral
+		if ticket.RecordID != nil {
+			ticket.Category = CategoryBooked
+		}
+		if ticket.PatientID != nil {
+			patient, err := s.patients.FindById(*ticket.PatientID)
+			if err != nil {
+				return nil, err
+			}
+			if s.elderlyAge > 0 && patient.Age >= s.elderlyAge {
+				ticket.Category = CategoryElderly
+			}
+		}
+	}
+	cat, ok := categories[ticket.Category]
+	if !ok {
+		return nil, apperror.ErrInvalidTicket
+	}
+	ticket.Priority = cat.priority
+
+	return s.storage.Issue(&ticket, cat.prefix)
+}
+
+/// Функция GetTicket возвращает талон по id \\\
+
+func (s *service) GetTicket(ctx context.Context, id int64) (*Ticket, error) {
+	s.logger.Info("SERVICE: GET RECEPTION TICKET")
+
+	return s.storage.FindTicketById(id)
+}
+
+/// Функция GetTickets возвращает сегодняшнюю очередь, по умолчанию ожидающие и вызванные талоны \\\
+
+func (s *service) GetTickets(ctx context.Context, hospitalAddress string, doctorId *int64, status string) (*[]Ticket, error) {
+	s.logger.Info("SERVICE: GET RECEPTION TICKETS")
+
+	statuses := []string{TicketWaiting, TicketCalled}
+	if status != "" {
+		statuses = []string{status}
+	}
+
+	tickets, err := s.storage.FindTickets(hospitalAddress, doctorId, s.today(), statuses)
+	if err != nil {
+		return nil, err
+	}
+	return &tickets, nil
+}
+
+/// Функция Assign направляет ожидающий талон в кабинет доктора \\\
+
+func (s *service) Assign(ctx context.Context, input *AssignTicketDTO) (*Ticket, error) {
+	s.logger.Info("SERVICE: ASSIGN RECEPTION TICKET")
+
+	if _, err := s.doc.FindById(input.DoctorID); err != nil {
+		return nil, err
+	}
+	return s.storage.Assign(input)
+}
+
+/// Функция CallNext вызывает в кабинет доктора следующий талон его очереди \\\
+
+func (s *service) CallNext(ctx context.Context, input *CallNextDTO) (*Ticket, error) {
+	s.logger.Info("SERVICE: CALL NEXT RECEPTION TICKET")
+
+	if input.DoctorID < 1 {
+		return nil, apperror.ErrInvalidTicket
+	}
+	return s.storage.CallNext(input, s.today())
+}
+
+/// Функция Serve отмечает, что пациент по вызванному талону принят \\\
+
+func (s *service) Serve(ctx context.Context, id int64) (*Ticket, error) {
+	s.logger.Info("SERVICE: SERVE RECEPTION TICKET")
+
+	return s.storage.Finish(id, TicketServed)
+}
+
+/// Функция Skip снимает талон с очереди, если пациент не подошел \\\
+
+func (s *service) Skip(ctx context.Context, id int64) (*Ticket, error) {
+	s.logger.Info("SERVICE: SKIP RECEPTION TICKET")
+
+	return s.storage.Finish(id, TicketSkipped)
+}
+
+/// Функция GetDisplay возвращает состояние очереди для табло: последние вызванные и ближайшие ожидающие талоны \\\
+
+func (s *service) GetDisplay(ctx context.Context, hospitalAddress string) (*Display, error) {
+	s.logger.Info("SERVICE: GET RECEPTION DISPLAY")
+
+	hospitalAddress = strings.TrimSpace(hospitalAddress)
+	if hospitalAddress == "" {
+		return nil, apperror.ErrInvalidTicket
+	}
+
+	display := &Display{
+		HospitalAddress: hospitalAddress,
+		QueueDate:       s.today(),
+		Called:          make([]DisplayTicket, 0),
+		Waiting:         make([]DisplayTicket, 0),
+	}
+	tickets, err := s.storage.FindTickets(hospitalAddress, nil, display.QueueDate, []string{TicketWaiting, TicketCalled})
+	if err != nil {
+		return nil, err
+	}
+
+	/// Очередь уже упорядочена по приоритету, вызванные талоны показываются от последнего вызова \\\
+	called := make([]Ticket, 0)
+	for i := range tickets {
+		if tickets[i].Status == TicketCalled {
+			called = append(called, tickets[i])
+		} else if len(display.Waiting) < s.displayLimit {
+			display.Waiting = append(display.Waiting, tickets[i].display())
+		}
+	}
+	sort.SliceStable(called, func(i, j int) bool {
+		return called[i].CalledAt != nil && called[j].CalledAt != nil && called[i].CalledAt.After(*called[j].CalledAt)
+	})
+	for i := range called {
+		if len(display.Called) == s.displayLimit {
+			break
+		}
+		display.Called = append(display.Called, called[i].display())
+	}
+	return display, nil
+}
+
+/// Функция today возвращает начало сегодняшнего дня очереди \\\
+
+func (s *service) today() time.Time {
+	year, month, day := time.Now().In(s.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, s.location)
+}
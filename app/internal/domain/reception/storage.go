@@ -0,0 +1,12 @@
+package reception
+
+import "time"
+
+type Storage interface {
+	Issue(ticket *Ticket, prefix string) (*Ticket, error)
+	FindTicketById(id int64) (*Ticket, error)
+	FindTickets(hospitalAddress string, doctorId *int64, queueDate time.Time, statuses []string) ([]Ticket, error)
+	Assign(input *AssignTicketDTO) (*Ticket, error)
+	CallNext(input *CallNextDTO, queueDate time.Time) (*Ticket, error)
+	Finish(id int64, status string) (*Ticket, error)
+}
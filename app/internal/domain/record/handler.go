@@ -15,6 +15,8 @@ const (
 	recordsURL         = "/hospital_record/records"
 	recordByPatientsId = "/hospital_record/record/patients_record/:id"
 	recordURL          = "/hospital_record/records/:id"
+	rescheduleURL      = "/hospital_record/records/:id/reschedule"
+	reschedulesURL     = "/hospital_record/records/:id/reschedules"
 )
 
 /// Структура Handler представляющая собой обработчик объекта recordService для записей на прием \\\
@@ -42,6 +44,8 @@ func (h *Handler) Register(router *httprouter.Router) {
 	router.HandlerFunc(http.MethodPatch, recordURL, h.PartiallyUpdateRecord)
 	router.HandlerFunc(http.MethodDelete, recordURL, h.DeleteRecord)
 	router.HandlerFunc(http.MethodGet, recordURL, h.GetRecordById)
+	router.HandlerFunc(http.MethodPost, rescheduleURL, h.RescheduleRecord)
+	router.HandlerFunc(http.MethodGet, reschedulesURL, h.GetRecordReschedules)
 }
 
 /// Функция GetRecordById получает запись по ее id \\\
@@ -115,6 +119,32 @@ func (h *Handler) CreateRecord(w http.ResponseWriter, r *http.Request) {
 	/// Вызов функции Create передавая ей полученные значения и ссылку на структуру input \\\
 	record, err := h.recordService.Create(r.Context(), &input)
 	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		if errors.Is(err, apperror.ErrWrongSpecialization) || errors.Is(err, apperror.ErrUnknownOffice) || errors.Is(err, apperror.ErrReferralNotValid) ||
+			errors.Is(err, apperror.ErrInvalidPolicyNumber) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		if errors.Is(err, apperror.ErrPolicyNotValid) {
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+			return
+		}
+		if errors.Is(err, apperror.ErrReferralRequired) {
+			response.Error(w, http.StatusForbidden, err.Error(), "ask the doctor for a referral")
+			return
+		}
+		/// Занятого доктора можно дождаться через лист ожидания \\\
+		if errors.Is(err, apperror.ErrDoctorNotAvailable) || errors.Is(err, apperror.ErrSlotTaken) {
+			response.Error(w, http.StatusConflict, err.Error(), "join the waitlist: POST /hospital_record/waitlist")
+			return
+		}
+		if errors.Is(err, apperror.ErrNoOfficeHours) {
+			response.Error(w, http.StatusConflict, err.Error(), "")
+			return
+		}
 		response.InternalError(w, fmt.Sprintf("cannot create record: %v", err), "")
 		return
 	}
@@ -150,6 +180,16 @@ func (h *Handler) UpdateRecord(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrWrongSpecialization) || errors.Is(err, apperror.ErrUnknownOffice) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		if errors.Is(err, apperror.ErrRescheduleRequired) {
+			response.BadRequest(w, err.Error(), "reschedule the record: POST /hospital_record/records/:id/reschedule")
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
 	}
 	h.logger.Info("RECORD UPDATED")
 	response.JSON(w, http.StatusOK, "RECORD UPDATED")
@@ -182,11 +222,90 @@ func (h *Handler) PartiallyUpdateRecord(w http.ResponseWriter, r *http.Request)
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrUnknownOffice) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
 	}
 	h.logger.Info("RECORD PARTIALLY UPDATED")
 	response.JSON(w, http.StatusOK, "RECORD PARTIALLY UPDATED")
 }
 
+/// Функция RescheduleRecord переносит запись на прием по ее id на новое время из input \\\
+
+func (h *Handler) RescheduleRecord(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: RESCHEDULE RECORD")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	var input RescheduleRecordDTO
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Reschedule передавая ей полученные значения \\\
+	record, err := h.recordService.Reschedule(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidReschedule), errors.Is(err, apperror.ErrWrongSpecialization),
+			errors.Is(err, apperror.ErrInvalidPolicyNumber):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrPolicyNotValid):
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+		case errors.Is(err, apperror.ErrRescheduleTooLate), errors.Is(err, apperror.ErrRescheduleLimit):
+			response.Error(w, http.StatusUnprocessableEntity, err.Error(), "")
+		case errors.Is(err, apperror.ErrDoctorNotAvailable), errors.Is(err, apperror.ErrSlotTaken):
+			response.Error(w, http.StatusConflict, err.Error(), "join the waitlist: POST /hospital_record/waitlist")
+		case errors.Is(err, apperror.ErrNoOfficeHours):
+			response.Error(w, http.StatusConflict, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "wrong on the server")
+		}
+		return
+	}
+	h.logger.Info("RECORD RESCHEDULED")
+	response.JSON(w, http.StatusOK, record)
+}
+
+/// Функция GetRecordReschedules получает историю переносов записи на прием по ее id \\\
+
+func (h *Handler) GetRecordReschedules(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET RECORD RESCHEDULES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetReschedules передавая ей id записи \\\
+	reschedules, err := h.recordService.GetReschedules(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT RECORD RESCHEDULES")
+	response.JSON(w, http.StatusOK, reschedules)
+}
+
 /// Функция DeleteRecord удаляет запись на прием по ее id \\\
 
 func (h *Handler) DeleteRecord(w http.ResponseWriter, r *http.Request) {
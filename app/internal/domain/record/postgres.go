@@ -2,28 +2,34 @@ package record
 
 import (
 	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
 	"HospitalRecord/app/pkg/logger"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"strings"
 	"time"
 )
 
 var _ Storage = &RecordStorage{}
 
+/// Колонки записи в порядке полей ScanRecord: office_id и referral_id добавлялись миграциями, поэтому запросы не полагаются на порядок колонок таблицы \\\
+
+const Columns = "id, hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record, office_id, referral_id"
+
 /// Структура RecordStorage содержащая поля для работы с БД \\\
 
 type RecordStorage struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 /// Структура NewStorage возвращает новый экземпляр RecordStorage инициализируя переданные в него аргументы \\\
 
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &RecordStorage{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -31,29 +37,67 @@ func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
 	}
 }
 
-/// Функция CreateRecord для сущности RecordStorage создает записи на прием в БД \\\
+/// Функция CreateRecord для сущности RecordStorage создает записи на прием в БД вместе с событием record.created \\\
 
-func (r *RecordStorage) CreateRecord(record *Record) (*Record, error) {
+func (r *RecordStorage) CreateRecord(record *Record, slot time.Duration) (*Record, error) {
 	r.logger.Info("POSTGRES: CREATE RECORD")
 
 	/// Ограничение времени выполнения запроса \\\
 	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
 	defer cancel()
 
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create record transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Повторная проверка занятости под блокировкой доктора, параллельная запись на то же время ждет окончания транзакции \\\
+	if err = LockDoctors(ctx, tx, record.DoctorID); err != nil {
+		return nil, err
+	}
+	taken, err := SlotTaken(ctx, tx, record.DoctorID, record.TimeRecord, slot)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, apperror.ErrSlotTaken
+	}
+
+	/// Направление погашается в той же транзакции, поэтому по одному направлению создается одна запись \\\
+	if record.ReferralID != nil {
+		tag, err := tx.Exec(ctx,
+			`UPDATE referral SET status = 'used', used_at = now()
+				 WHERE id = $1 AND status = 'issued'`, *record.ReferralID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to use referral: %v", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return nil, apperror.ErrReferralNotValid
+		}
+	}
+
 	/// Выполнение запроса к БД \\\
-	row := r.conn.QueryRow(ctx,
-		`INSERT INTO record (hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record)
-			 VALUES($1,$2,$3,$4,$5,$6,$7) 
+	row := tx.QueryRow(ctx,
+		`INSERT INTO record (hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record, office_id, referral_id)
+			 VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9) 
 			 RETURNING id`,
-		record.HospitalAddress, record.DoctorOffice, record.Tagging, record.PatientsID, record.DoctorID, record.SpecializationID, record.TimeRecord)
+		record.HospitalAddress, record.DoctorOffice, record.Tagging, record.PatientsID, record.DoctorID, record.SpecializationID, record.TimeRecord, record.OfficeID, record.ReferralID)
 
 	/// Сканирование полученных значений из БД \\\
-	err := row.Scan(&record.ID)
+	err = row.Scan(&record.ID)
 	if err != nil {
 		err = fmt.Errorf("failed to execute create record query: %v", err)
 		r.logger.Error(err)
 		return nil, err
 	}
+
+	if err = outbox.Write(ctx, tx, outbox.RecordCreated, record.ID, record.EventPayload()); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create record transaction: %v", err)
+	}
 	return record, nil
 }
 
@@ -68,7 +112,7 @@ func (r *RecordStorage) FindRecordByPatientsId(id int64) (*Record, error) {
 
 	/// Выполнение запроса к БД \\\
 	row := r.conn.QueryRow(ctx,
-		`SELECT * FROM record
+		`SELECT `+Columns+` FROM record
 			 WHERE patients_id = $1`, id)
 
 	record := &Record{}
@@ -76,7 +120,7 @@ func (r *RecordStorage) FindRecordByPatientsId(id int64) (*Record, error) {
 	/// Сканирование полученных значений из БД \\\
 	err := row.Scan(&record.ID, &record.HospitalAddress, &record.DoctorOffice, &record.Tagging,
 		&record.PatientsID, &record.DoctorID, &record.SpecializationID,
-		&record.TimeRecord,
+		&record.TimeRecord, &record.OfficeID, &record.ReferralID,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -100,7 +144,7 @@ func (r *RecordStorage) FindRecordById(id int64) (*Record, error) {
 
 	/// Выполнение запроса к БД \\\
 	row := r.conn.QueryRow(ctx,
-		`SELECT * FROM record
+		`SELECT `+Columns+` FROM record
 			 WHERE id = $1`, id)
 
 	record := &Record{}
@@ -108,7 +152,7 @@ func (r *RecordStorage) FindRecordById(id int64) (*Record, error) {
 	/// Сканирование полученных значений из БД \\\
 	err := row.Scan(&record.ID, &record.HospitalAddress, &record.DoctorOffice, &record.Tagging,
 		&record.PatientsID, &record.DoctorID, &record.SpecializationID,
-		&record.TimeRecord,
+		&record.TimeRecord, &record.OfficeID, &record.ReferralID,
 	)
 
 	if err != nil {
@@ -123,7 +167,7 @@ func (r *RecordStorage) FindRecordById(id int64) (*Record, error) {
 	return record, nil
 }
 
-/// Функция UpdateRecord для сущности RecordStorage обновляет записи на прием в БД \\\
+/// Функция UpdateRecord для сущности RecordStorage обновляет записи на прием в БД вместе с событием record.updated \\\
 
 func (r *RecordStorage) UpdateRecord(record *UpdateRecordDTO) error {
 	r.logger.Info("POSTGRES: UPDATE RECORD")
@@ -132,12 +176,19 @@ func (r *RecordStorage) UpdateRecord(record *UpdateRecordDTO) error {
 	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
 	defer cancel()
 
-	/// Выполнение запроса к БД \\\
-	result, err := r.conn.Exec(ctx,
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin update record transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД, направление записи при обновлении не меняется \\\
+	updated, err := ScanRecord(tx.QueryRow(ctx,
 		`UPDATE record
-			 SET hospital_address=$1, doctor_office=$2, tagging=$3, patients_id=$4, doctor_id=$5, specialization_id=$6, time_record=$7
-			 WHERE id =$8`,
-		record.HospitalAddress, record.DoctorOffice, record.Tagging, record.PatientsID, record.DoctorID, record.SpecializationID, record.TimeRecord, &record.ID)
+			 SET hospital_address=$1, doctor_office=$2, tagging=$3, patients_id=$4, doctor_id=$5, specialization_id=$6, time_record=$7, office_id=$8
+			 WHERE id =$9
+			 RETURNING `+Columns,
+		record.HospitalAddress, record.DoctorOffice, record.Tagging, record.PatientsID, record.DoctorID, record.SpecializationID, record.TimeRecord, record.OfficeID, &record.ID))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -147,8 +198,12 @@ func (r *RecordStorage) UpdateRecord(record *UpdateRecordDTO) error {
 		r.logger.Error(err)
 		return err
 	}
-	if result.RowsAffected() == 0 {
-		return apperror.ErrEmptyString
+
+	if err = outbox.Write(ctx, tx, outbox.RecordUpdated, record.ID, updated.EventPayload()); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update record transaction: %v", err)
 	}
 	return nil
 }
@@ -176,39 +231,47 @@ func (r *RecordStorage) PartiallyUpdateRecord(record *PartiallyUpdateRecordDTO)
 		args = append(args, *record.DoctorOffice)
 		argId++
 	}
-	if record.DoctorID != nil {
-		values = append(values, fmt.Sprintf("doctor_id=$%d", argId))
-		args = append(args, *record.DoctorID)
-		argId++
-	}
-	if record.TimeRecord != nil {
-		values = append(values, fmt.Sprintf("time_record=$%d", argId))
-		args = append(args, *record.TimeRecord)
+	if record.OfficeID != nil {
+		values = append(values, fmt.Sprintf("office_id=$%d", argId))
+		args = append(args, *record.OfficeID)
 		argId++
 	}
 
 	/// Формирование строки со всеми измененными полями и их значениями \\\
 	valuesQuery := strings.Join(values, ", ")
-	query := fmt.Sprintf("UPDATE record  SET %s WHERE id = $%d", valuesQuery, argId)
+	query := fmt.Sprintf("UPDATE record  SET %s WHERE id = $%d RETURNING %s", valuesQuery, argId, Columns)
 	args = append(args, record.ID)
 
 	/// Ограничение времени выполнения запроса \\\
 	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
 	defer cancel()
 
-	/// Выполнение запроса к БД \\\
-	result, err := r.conn.Exec(ctx, query, args...)
+	tx, err := r.conn.Begin(ctx)
 	if err != nil {
+		return fmt.Errorf("failed to begin update record transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД, обновленная запись попадает в событие record.updated \\\
+	updated, err := ScanRecord(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrEmptyString
+		}
 		return fmt.Errorf("failed to update record partially: %v", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return apperror.ErrEmptyString
+	if err = outbox.Write(ctx, tx, outbox.RecordUpdated, updated.ID, updated.EventPayload()); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update record transaction: %v", err)
 	}
 	return nil
 }
 
 /// Функция DeleteRecord для сущности RecordStorage удаляет записи на прием из БД \\\
+/// Отмененная запись сохраняется в record_cancellation, чтобы календари пациента и доктора получили ее отмену \\\
 
 func (r *RecordStorage) DeleteRecord(id int64) error {
 	r.logger.Info("POSTGRES: DELETE RECORD")
@@ -217,15 +280,240 @@ func (r *RecordStorage) DeleteRecord(id int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
 	defer cancel()
 
-	/// Выполнение запроса к БД \\\
-	result, err := r.conn.Exec(ctx,
-		`DELETE FROM record WHERE id = $1`, id)
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin delete record transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Сохранение отмененной записи вместе с числом ее переносов \\\
+	_, err = tx.Exec(ctx,
+		`INSERT INTO record_cancellation (id, hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record, sequence)
+			 SELECT r.id, r.hospital_address, r.doctor_office, r.tagging, r.patients_id, r.doctor_id, r.specialization_id, r.time_record,
+			        (SELECT count(*) FROM record_reschedule rr WHERE rr.record_id = r.id)
+			 FROM record r WHERE r.id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to save record cancellation: %v", err)
+	}
+
+	/// Выполнение запроса к БД, удаленная запись попадает в событие record.cancelled \\\
+	deleted, err := ScanRecord(tx.QueryRow(ctx,
+		`DELETE FROM record WHERE id = $1 RETURNING `+Columns, id))
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrEmptyString
+		}
 		return fmt.Errorf("failed to delete record: %v", err)
 	}
 
-	if result.RowsAffected() == 0 {
-		return apperror.ErrEmptyString
+	/// Направление отмененной записи снова можно использовать \\\
+	if deleted.ReferralID != nil {
+		_, err = tx.Exec(ctx,
+			`UPDATE referral SET status = 'issued', used_at = NULL
+				 WHERE id = $1 AND status = 'used'`, *deleted.ReferralID)
+		if err != nil {
+			return fmt.Errorf("failed to release referral: %v", err)
+		}
+	}
+
+	if err = outbox.Write(ctx, tx, outbox.RecordCancelled, deleted.ID, deleted.EventPayload()); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit delete record transaction: %v", err)
+	}
+	return nil
+}
+
+/// Функция IsSlotTaken для сущности RecordStorage проверяет, пересекается ли прием у доктора в момент at с другими записями \\\
+/// Каждая запись занимает интервал длительностью slot, записи с id из excludeIds не учитываются \\\
+
+func (r *RecordStorage) IsSlotTaken(doctorId int64, at time.Time, slot time.Duration, excludeIds ...int64) (bool, error) {
+	r.logger.Info("POSTGRES: CHECK RECORD SLOT")
+	if excludeIds == nil {
+		excludeIds = []int64{}
+	}
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	var taken bool
+	err := r.conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM record
+			 WHERE doctor_id = $1 AND id <> ALL($2)
+			 AND time_record > $3::timestamptz - $4::interval AND time_record < $3::timestamptz + $4::interval)`,
+		doctorId, excludeIds, at, slot).Scan(&taken)
+	if err != nil {
+		err = fmt.Errorf("failed to execute check record slot query: %v", err)
+		r.logger.Error(err)
+		return false, err
+	}
+	return taken, nil
+}
+
+/// Функция LockDoctors блокирует строки докторов до конца транзакции tx \\\
+/// Все операции, занимающие время приема, берут эту блокировку перед проверкой слота, поэтому проверка и вставка записи не разделяются параллельной записью \\\
+
+func LockDoctors(ctx context.Context, tx pgx.Tx, doctorIds ...int64) error {
+	_, err := tx.Exec(ctx,
+		`SELECT id FROM doctors WHERE id = ANY($1) ORDER BY id FOR NO KEY UPDATE`, doctorIds)
+	if err != nil {
+		return fmt.Errorf("failed to lock doctors: %v", err)
 	}
 	return nil
 }
+
+/// Функция SlotTaken проверяет внутри транзакции tx, пересекается ли прием у доктора с записями, кроме excludeIds \\\
+
+func SlotTaken(ctx context.Context, tx pgx.Tx, doctorId int64, at time.Time, slot time.Duration, excludeIds ...int64) (bool, error) {
+	if excludeIds == nil {
+		excludeIds = []int64{}
+	}
+	var taken bool
+	err := tx.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM record
+			 WHERE doctor_id = $1 AND id <> ALL($2)
+			 AND time_record > $3::timestamptz - $4::interval AND time_record < $3::timestamptz + $4::interval)`,
+		doctorId, excludeIds, at, slot).Scan(&taken)
+	if err != nil {
+		return false, fmt.Errorf("failed to check record slot: %v", err)
+	}
+	return taken, nil
+}
+
+/// Функция RescheduleRecord для сущности RecordStorage переносит запись на прием и сохраняет прежние доктора и время в истории \\\
+/// Перенос не выполняется, если запись изменилась после того как была прочитана current \\\
+
+func (r *RecordStorage) RescheduleRecord(current *Record, input *RescheduleRecordDTO, location *Location, slot time.Duration) (*Reschedule, error) {
+	r.logger.Info("POSTGRES: RESCHEDULE RECORD")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin reschedule record transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Повторная проверка занятости нового времени под блокировкой доктора \\\
+	if err = LockDoctors(ctx, tx, *input.DoctorID); err != nil {
+		return nil, err
+	}
+	taken, err := SlotTaken(ctx, tx, *input.DoctorID, input.TimeRecord, slot, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, apperror.ErrSlotTaken
+	}
+
+	/// Обновление доктора и времени записи, кабинет берется из расписания доктора на новое время \\\
+	moved := *current
+	moved.DoctorID, moved.TimeRecord = *input.DoctorID, input.TimeRecord
+	moved.OfficeID, moved.HospitalAddress, moved.DoctorOffice = &location.OfficeID, location.HospitalAddress, location.DoctorOffice
+	result, err := tx.Exec(ctx,
+		`UPDATE record SET doctor_id = $1, time_record = $2, office_id = $3, hospital_address = $4, doctor_office = $5
+			 WHERE id = $6 AND doctor_id = $7 AND time_record = $8`,
+		moved.DoctorID, moved.TimeRecord, moved.OfficeID, moved.HospitalAddress, moved.DoctorOffice,
+		current.ID, current.DoctorID, current.TimeRecord)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reschedule record: %v", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, apperror.ErrEmptyString
+	}
+
+	/// Сохранение переноса в истории \\\
+	reschedule := &Reschedule{
+		RecordID:         current.ID,
+		PreviousDoctorID: current.DoctorID,
+		PreviousTime:     current.TimeRecord,
+		DoctorID:         *input.DoctorID,
+		TimeRecord:       input.TimeRecord,
+		Reason:           input.Reason,
+	}
+	err = tx.QueryRow(ctx,
+		`INSERT INTO record_reschedule (record_id, previous_doctor_id, previous_time, doctor_id, time_record, reason)
+			 VALUES($1,$2,$3,$4,$5,$6)
+			 RETURNING id, created_at`,
+		reschedule.RecordID, reschedule.PreviousDoctorID, reschedule.PreviousTime,
+		reschedule.DoctorID, reschedule.TimeRecord, reschedule.Reason).Scan(&reschedule.ID, &reschedule.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create record reschedule query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	payload := moved.EventPayload()
+	payload.PreviousDoctorID, payload.PreviousTime, payload.Reason = &current.DoctorID, &current.TimeRecord, input.Reason
+	if err = outbox.Write(ctx, tx, outbox.RecordRescheduled, current.ID, payload); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit reschedule record transaction: %v", err)
+	}
+	return reschedule, nil
+}
+
+/// Функция FindReschedules для сущности RecordStorage получает историю переносов записи на прием \\\
+
+func (r *RecordStorage) FindReschedules(recordId int64) ([]Reschedule, error) {
+	r.logger.Info("POSTGRES: GET RECORD RESCHEDULES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := r.conn.Query(ctx,
+		`SELECT * FROM record_reschedule
+			 WHERE record_id = $1
+			 ORDER BY created_at, id`, recordId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения истории переносов \\\
+	reschedules := make([]Reschedule, 0)
+
+	for rows.Next() {
+		var reschedule Reschedule
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&reschedule.ID, &reschedule.RecordID, &reschedule.PreviousDoctorID, &reschedule.PreviousTime,
+			&reschedule.DoctorID, &reschedule.TimeRecord, &reschedule.Reason, &reschedule.CreatedAt)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find record reschedules query: %v", err)
+			r.logger.Error(err)
+			return nil, err
+		}
+		reschedules = append(reschedules, reschedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return reschedules, nil
+}
+
+/// Функция ScanRecord сканирует строку record, выбранную в порядке Columns \\\
+
+func ScanRecord(row pgx.Row) (*Record, error) {
+	record := &Record{}
+	err := row.Scan(&record.ID, &record.HospitalAddress, &record.DoctorOffice, &record.Tagging,
+		&record.PatientsID, &record.DoctorID, &record.SpecializationID,
+		&record.TimeRecord, &record.OfficeID, &record.ReferralID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
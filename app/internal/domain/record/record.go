@@ -1,6 +1,9 @@
 package record
 
-import "time"
+import (
+	"HospitalRecord/app/internal/domain/outbox"
+	"time"
+)
 
 /// Структура для создания и обновления записей \\\
 
@@ -13,33 +16,96 @@ type Record struct {
 	DoctorID         int64     `json:"doctor_id" example:"1"`
 	SpecializationID int64     `json:"specialization_id" example:"1"`
 	TimeRecord       time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+	OfficeID         *int64    `json:"office_id,omitempty" example:"1"`
+	ReferralID       *int64    `json:"referral_id,omitempty" example:"1"`
 }
 
+/// Адрес и кабинет записи не задаются текстом, они берутся из справочника по office_id или по расписанию доктора \\\
+
 type CreateRecordDTO struct {
 	ID               int64     `json:"id" example:"1567"`
-	HospitalAddress  string    `json:"hospital_address" example:"Roterta, dom 12"`
-	DoctorOffice     string    `json:"doctor_office" example:"201B"`
 	Tagging          string    `json:"tagging" example:"Nichego ne est za 3 chasa pered priemom"`
 	PatientsID       int64     `json:"patients_id" example:"1"`
 	DoctorID         int64     `json:"doctor_id" example:"1"`
 	SpecializationID int64     `json:"specialization_id" example:"1"`
 	TimeRecord       time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+	OfficeID         *int64    `json:"office_id,omitempty" example:"1"`
+	ReferralID       *int64    `json:"referral_id,omitempty" example:"1"`
 }
+
+/// Адрес и кабинет заполняет сервис из справочника по office_id \\\
+
 type UpdateRecordDTO struct {
 	ID               int64     `json:"id" example:"1567"`
-	HospitalAddress  string    `json:"hospital_address" example:"Roterta, dom 12"`
-	DoctorOffice     string    `json:"doctor_office" example:"201B"`
+	HospitalAddress  string    `json:"-"`
+	DoctorOffice     string    `json:"-"`
 	Tagging          string    `json:"tagging" example:"Nichego ne est za 3 chasa pered priemom"`
 	PatientsID       int64     `json:"patients_id" example:"1"`
 	DoctorID         int64     `json:"doctor_id" example:"1"`
 	SpecializationID int64     `json:"specialization_id" example:"1"`
 	TimeRecord       time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+	OfficeID         *int64    `json:"office_id,omitempty" example:"1"`
 }
 
+/// Время и доктора записи частично обновить нельзя, для этого есть перенос записи RescheduleRecordDTO \\\
+/// Кабинет меняется только через office_id, адрес и кабинет заполняет сервис из справочника \\\
+
 type PartiallyUpdateRecordDTO struct {
-	ID              int64      `json:"id" example:"1567"`
-	HospitalAddress *string    `json:"hospital_address" example:"Roterta, dom 12"`
-	DoctorOffice    *string    `json:"doctor_office" example:"201B"`
-	DoctorID        *int64     `json:"doctor_id" example:"1"`
-	TimeRecord      *time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+	ID              int64   `json:"id" example:"1567"`
+	HospitalAddress *string `json:"-"`
+	DoctorOffice    *string `json:"-"`
+	OfficeID        *int64  `json:"office_id,omitempty" example:"1"`
+}
+
+/// Структура переноса записи на другое время и, при необходимости, к другому доктору той же специализации \\\
+
+type RescheduleRecordDTO struct {
+	ID         int64     `json:"-"`
+	DoctorID   *int64    `json:"doctor_id,omitempty" example:"1"`
+	TimeRecord time.Time `json:"time_record" example:"2023-07-28T10:00:00Z"`
+	Reason     *string   `json:"reason,omitempty" example:"Ne uspevayu posle raboty"`
+}
+
+/// Структура истории переносов записи: прежние и новые доктор и время приема \\\
+
+type Reschedule struct {
+	ID               int64     `json:"id" example:"1"`
+	RecordID         int64     `json:"record_id" example:"1567"`
+	PreviousDoctorID int64     `json:"previous_doctor_id" example:"1"`
+	PreviousTime     time.Time `json:"previous_time" example:"2023-07-27T15:30:00Z"`
+	DoctorID         int64     `json:"doctor_id" example:"1"`
+	TimeRecord       time.Time `json:"time_record" example:"2023-07-28T10:00:00Z"`
+	Reason           *string   `json:"reason,omitempty" example:"Ne uspevayu posle raboty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+/// Структура слота записи: время приема у доктора по одной из его специализаций \\\
+
+type Slot struct {
+	DoctorID         int64     `json:"doctor_id" example:"1"`
+	SpecializationID int64     `json:"specialization_id" example:"1"`
+	TimeRecord       time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+}
+
+/// Структура места приема из справочника больниц: кабинет и адрес больницы \\\
+
+type Location struct {
+	OfficeID        int64
+	HospitalAddress string
+	DoctorOffice    string
+}
+
+/// Функция EventPayload возвращает данные доменного события записи на прием \\\
+
+func (r *Record) EventPayload() outbox.RecordPayload {
+	return outbox.RecordPayload{
+		RecordID:         r.ID,
+		PatientID:        r.PatientsID,
+		DoctorID:         r.DoctorID,
+		SpecializationID: r.SpecializationID,
+		TimeRecord:       r.TimeRecord,
+		HospitalAddress:  r.HospitalAddress,
+		DoctorOffice:     r.DoctorOffice,
+		OfficeID:         r.OfficeID,
+	}
 }
@@ -1,11 +1,13 @@
 package record
 
 import (
+	"HospitalRecord/app/internal/config"
 	"HospitalRecord/app/internal/domain/apperror"
 	"HospitalRecord/app/internal/domain/doctor"
 	"HospitalRecord/app/pkg/logger"
 	"context"
 	"errors"
+	"time"
 )
 
 /// Интерфейс Service реализизирующий service и методы для обработки CRUD системы записей на прием \\\
@@ -16,24 +18,80 @@ type Service interface {
 	GetById(ctx context.Context, id int64) (*Record, error)
 	Update(ctx context.Context, record *UpdateRecordDTO) error
 	PartiallyUpdate(ctx context.Context, record *PartiallyUpdateRecordDTO) error
+	Reschedule(ctx context.Context, input *RescheduleRecordDTO) (*Record, error)
+	CanReschedule(ctx context.Context, record *Record) error
+	CheckSlot(ctx context.Context, doctorId int64, at time.Time, excludeIds ...int64) error
+	GetReschedules(ctx context.Context, id int64) (*[]Reschedule, error)
+	Prepare(ctx context.Context, record *Record) error
+	ResolveOffice(ctx context.Context, record *Record) error
+	RequiresReferral(specializationId int64) bool
+	CheckPolicy(patientId int64, at time.Time) error
 	Delete(id int64) error
 }
 
+/// Интерфейс Waitlist листа ожидания: запись сообщает ему об освободившихся слотах \\\
+/// и проверяет, не удерживается ли слот для пациента из листа ожидания \\\
+
+type Waitlist interface {
+	SlotReleased(slot Slot)
+	IsSlotHeld(doctorId int64, at time.Time) (bool, error)
+}
+
+/// Интерфейс Offices справочника больниц и кабинетов: кабинет по id и кабинет, в котором доктор принимает в момент at \\\
+/// Если кабинет не найден, возвращается apperror.ErrEmptyString \\\
+
+type Offices interface {
+	Locate(officeId int64) (*Location, error)
+	LocateDoctor(doctorId int64, at time.Time) (*Location, error)
+}
+
+/// Интерфейс Referrals направлений: нужна ли запись по направлению и подходит ли направление для записи \\\
+/// Check возвращает apperror.ErrReferralNotValid, если направление выдано другому пациенту, на другую специализацию или не действует на день приема \\\
+
+type Referrals interface {
+	Required(specializationId int64) bool
+	Check(referralId, patientId, specializationId int64, at time.Time) error
+}
+
+/// Интерфейс Policies реестра полисов ОМС: действует ли полис пациента на день приема \\\
+/// CheckPatient возвращает apperror.ErrPolicyNotValid, если полис истек, еще не начал действовать или отсутствует в реестре \\\
+
+type Policies interface {
+	CheckPatient(patientId int64, at time.Time) error
+}
+
 /// Структура  service реализизирующая инфтерфейс Service записей на прием \\\
 
 type service struct {
-	logger  logger.Logger
-	storage Storage
-	doc     doctor.Storage
+	logger    logger.Logger
+	storage   Storage
+	doc       doctor.Storage
+	waitlist  Waitlist
+	offices   Offices
+	referrals Referrals
+	policies  Policies
+	slot      time.Duration
+
+	/// Правила переноса записи \\\
+	rescheduleNotice time.Duration
+	maxReschedules   int
 }
 
 /// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
 
-func NewService(doc doctor.Storage, storage Storage, logger logger.Logger) Service {
+func NewService(doc doctor.Storage, storage Storage, waitlist Waitlist, offices Offices, referrals Referrals, policies Policies, cfg *config.Config, logger logger.Logger) Service {
 	return &service{
-		logger:  logger,
-		storage: storage,
-		doc:     doc,
+		logger:    logger,
+		storage:   storage,
+		doc:       doc,
+		waitlist:  waitlist,
+		offices:   offices,
+		referrals: referrals,
+		policies:  policies,
+		slot:      time.Duration(cfg.Records.SlotMinutes) * time.Minute,
+
+		rescheduleNotice: time.Duration(cfg.Records.RescheduleMinHours) * time.Hour,
+		maxReschedules:   cfg.Records.MaxReschedules,
 	}
 }
 
@@ -42,31 +100,54 @@ func NewService(doc doctor.Storage, storage Storage, logger logger.Logger) Servi
 func (s *service) Create(ctx context.Context, input *CreateRecordDTO) (*Record, error) {
 	s.logger.Info("SERVICE: CREATE RECORD")
 
-	/// Проверка что доктор свободен \\\
-	checkDoctor, err := s.doc.FindById(input.DoctorID)
-	if err != nil {
-		if !errors.Is(err, apperror.ErrNotFound) {
-			return nil, err
-		}
-	}
-	if checkDoctor.RecordingIsAvailable != true {
-		return nil, apperror.ErrDoctorNotAvailable
-	}
-
 	/// Создание структуры r на основе полученных данных \\\
 	r := Record{
 		ID:               input.ID,
-		HospitalAddress:  input.HospitalAddress,
-		DoctorOffice:     input.DoctorOffice,
 		Tagging:          input.Tagging,
 		PatientsID:       input.PatientsID,
 		DoctorID:         input.DoctorID,
 		SpecializationID: input.SpecializationID,
 		TimeRecord:       input.TimeRecord,
+		OfficeID:         input.OfficeID,
+		ReferralID:       input.ReferralID,
+	}
+
+	/// Проверка доктора и специализации записи \\\
+	err := s.Prepare(ctx, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Проверка направления, для некоторых специализаций запись возможна только по направлению \\\
+	if r.ReferralID == nil && s.RequiresReferral(r.SpecializationID) {
+		return nil, apperror.ErrReferralRequired
+	}
+	if r.ReferralID != nil {
+		if s.referrals == nil {
+			return nil, apperror.ErrReferralNotValid
+		}
+		if err = s.referrals.Check(*r.ReferralID, r.PatientsID, r.SpecializationID, r.TimeRecord); err != nil {
+			return nil, err
+		}
+	}
+
+	/// Проверка что полис пациента действует на день приема \\\
+	if err = s.CheckPolicy(r.PatientsID, r.TimeRecord); err != nil {
+		return nil, err
+	}
+
+	/// Проверка что время приема у доктора свободно \\\
+	if err = s.CheckSlot(ctx, r.DoctorID, r.TimeRecord); err != nil {
+		return nil, err
+	}
+
+	/// Адрес и кабинет берутся из справочника \\\
+	if err = s.ResolveOffice(ctx, &r); err != nil {
+		return nil, err
 	}
 
 	/// Вызов функции Create в хранилище записей \\\
-	record, err := s.storage.CreateRecord(&r)
+	record, err := s.storage.CreateRecord(&r, s.slot)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +190,7 @@ func (s *service) Update(ctx context.Context, record *UpdateRecordDTO) error {
 	s.logger.Info("SERVICE: UPDATE USER")
 
 	/// Вызов функции FindRecordById в хранилище записей \\\
-	_, err := s.storage.FindRecordById(record.ID)
+	current, err := s.storage.FindRecordById(record.ID)
 	if err != nil {
 		if !errors.Is(err, apperror.ErrEmptyString) {
 			s.logger.Errorf("failed to get record: %v", err)
@@ -117,6 +198,28 @@ func (s *service) Update(ctx context.Context, record *UpdateRecordDTO) error {
 		return err
 	}
 
+	/// Время и доктор записи меняются только через перенос \\\
+	if record.DoctorID != current.DoctorID || !record.TimeRecord.Equal(current.TimeRecord) {
+		return apperror.ErrRescheduleRequired
+	}
+
+	/// Проверка что специализация записи есть у доктора \\\
+	if err = s.checkSpecialization(record.DoctorID, record.SpecializationID); err != nil {
+		return err
+	}
+
+	/// Без office_id запись остается в прежнем кабинете \\\
+	record.HospitalAddress, record.DoctorOffice = current.HospitalAddress, current.DoctorOffice
+	if record.OfficeID == nil {
+		record.OfficeID = current.OfficeID
+	} else {
+		location, err := s.locate(*record.OfficeID)
+		if err != nil {
+			return err
+		}
+		record.HospitalAddress, record.DoctorOffice = location.HospitalAddress, location.DoctorOffice
+	}
+
 	/// Вызов функции UpdateRecord в хранилище записей \\\
 	err = s.storage.UpdateRecord(record)
 	if err != nil {
@@ -131,14 +234,18 @@ func (s *service) Update(ctx context.Context, record *UpdateRecordDTO) error {
 func (s *service) PartiallyUpdate(ctx context.Context, record *PartiallyUpdateRecordDTO) error {
 	s.logger.Info("SERVICE: PARTIALLY UPDATE RECORD")
 
-	/// Вызов функции FindRecordById в хранилище записей \\\
-	_, err := s.storage.FindRecordById(record.ID)
+	/// Без office_id менять нечего, проверяется только что запись существует \\\
+	if record.OfficeID == nil {
+		_, err := s.storage.FindRecordById(record.ID)
+		return err
+	}
+
+	/// Кабинет из справочника задает и адрес, и номер кабинета \\\
+	location, err := s.locate(*record.OfficeID)
 	if err != nil {
-		if !errors.Is(err, apperror.ErrEmptyString) {
-			s.logger.Errorf("failed to get record: %v", err)
-		}
 		return err
 	}
+	record.HospitalAddress, record.DoctorOffice = &location.HospitalAddress, &location.DoctorOffice
 
 	/// Вызов функции PartiallyUpdateRecord в хранилище записей \\\
 	err = s.storage.PartiallyUpdateRecord(record)
@@ -149,18 +256,284 @@ func (s *service) PartiallyUpdate(ctx context.Context, record *PartiallyUpdateRe
 	return nil
 }
 
+/// Функция Reschedule переносит запись на прием на другое время и, при необходимости, к другому доктору \\\
+/// Перенос закрывается за rescheduleNotice до приема и ограничен maxReschedules переносами на запись \\\
+
+func (s *service) Reschedule(ctx context.Context, input *RescheduleRecordDTO) (*Record, error) {
+	s.logger.Info("SERVICE: RESCHEDULE RECORD")
+
+	/// Вызов функции FindRecordById в хранилище записей \\\
+	current, err := s.storage.FindRecordById(input.ID)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Errorf("failed to get record: %v", err)
+		}
+		return nil, err
+	}
+
+	/// Проверка правил переноса \\\
+	if err = s.CanReschedule(ctx, current); err != nil {
+		return nil, err
+	}
+	if !input.TimeRecord.After(time.Now()) {
+		return nil, apperror.ErrInvalidReschedule
+	}
+
+	/// Без указанного доктора запись переносится к тому же доктору \\\
+	if input.DoctorID == nil {
+		input.DoctorID = &current.DoctorID
+	}
+	if *input.DoctorID == current.DoctorID && input.TimeRecord.Equal(current.TimeRecord) {
+		return nil, apperror.ErrInvalidReschedule
+	}
+
+	/// Новый доктор должен вести запись и прием по специализации записи \\\
+	if *input.DoctorID != current.DoctorID {
+		checkDoctor, err := s.doc.FindById(*input.DoctorID)
+		if err != nil {
+			return nil, err
+		}
+		if !checkDoctor.RecordingIsAvailable {
+			return nil, apperror.ErrDoctorNotAvailable
+		}
+		if !checkDoctor.HasSpecialization(current.SpecializationID) {
+			return nil, apperror.ErrWrongSpecialization
+		}
+	}
+
+	/// Проверка что полис пациента действует на новый день приема \\\
+	if err = s.CheckPolicy(current.PatientsID, input.TimeRecord); err != nil {
+		return nil, err
+	}
+
+	/// Проверка что новое время приема у доктора свободно \\\
+	if err = s.CheckSlot(ctx, *input.DoctorID, input.TimeRecord, current.ID); err != nil {
+		return nil, err
+	}
+
+	/// Кабинет, в котором доктор принимает в новое время, без приема по расписанию перенос невозможен \\\
+	location, err := s.locateDoctor(*input.DoctorID, input.TimeRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Вызов функции RescheduleRecord в хранилище записей \\\
+	_, err = s.storage.RescheduleRecord(current, input, location, s.slot)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Errorf("failed to reschedule record: %v", err)
+		}
+		return nil, err
+	}
+
+	/// Прежнее время приема предлагается пациентам из листа ожидания, если новый прием его не занимает \\\
+	overlaps := *input.DoctorID == current.DoctorID && absDuration(input.TimeRecord.Sub(current.TimeRecord)) < s.slot
+	if s.waitlist != nil && !overlaps {
+		s.waitlist.SlotReleased(Slot{
+			DoctorID:         current.DoctorID,
+			SpecializationID: current.SpecializationID,
+			TimeRecord:       current.TimeRecord,
+		})
+	}
+
+	rescheduled := *current
+	rescheduled.DoctorID = *input.DoctorID
+	rescheduled.TimeRecord = input.TimeRecord
+	rescheduled.OfficeID, rescheduled.HospitalAddress, rescheduled.DoctorOffice = &location.OfficeID, location.HospitalAddress, location.DoctorOffice
+	return &rescheduled, nil
+}
+
+/// Функция CanReschedule проверяет правила переноса записи: срок до приема и число уже выполненных переносов \\\
+
+func (s *service) CanReschedule(ctx context.Context, record *Record) error {
+	if record.TimeRecord.Sub(time.Now()) < s.rescheduleNotice {
+		return apperror.ErrRescheduleTooLate
+	}
+	if s.maxReschedules > 0 {
+		history, err := s.storage.FindReschedules(record.ID)
+		if err != nil {
+			return err
+		}
+		if len(history) >= s.maxReschedules {
+			return apperror.ErrRescheduleLimit
+		}
+	}
+	return nil
+}
+
+/// Функция GetReschedules возвращает историю переносов записи на прием \\\
+
+func (s *service) GetReschedules(ctx context.Context, id int64) (*[]Reschedule, error) {
+	s.logger.Info("SERVICE: GET RECORD RESCHEDULES")
+
+	/// Проверка что запись существует \\\
+	if _, err := s.storage.FindRecordById(id); err != nil {
+		return nil, err
+	}
+
+	/// Вызов функции FindReschedules в хранилище записей \\\
+	reschedules, err := s.storage.FindReschedules(id)
+	if err != nil {
+		s.logger.Warnf("cannot find record reschedules: %v", err)
+		return nil, err
+	}
+	return &reschedules, nil
+}
+
 /// Функция Delete удаляет запись на прием через интерфейс Service принимая входные данные id \\\
 
 func (s *service) Delete(id int64) error {
 	s.logger.Info("SERVICE: DELETE RECORD")
 
+	/// Вызов функции FindRecordById в хранилище записей \\\
+	record, err := s.storage.FindRecordById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("failed to get record: %v", err)
+		}
+		return err
+	}
+
 	/// Вызов функции Delete в хранилище записей \\\
-	err := s.storage.DeleteRecord(id)
+	err = s.storage.DeleteRecord(id)
 	if err != nil {
 		if !errors.Is(err, apperror.ErrEmptyString) {
 			s.logger.Warnf("failed to delete record: %v", err)
 		}
 		return err
 	}
+
+	/// Отмененный будущий прием предлагается пациентам из листа ожидания \\\
+	if s.waitlist != nil && record.TimeRecord.After(time.Now()) {
+		s.waitlist.SlotReleased(Slot{
+			DoctorID:         record.DoctorID,
+			SpecializationID: record.SpecializationID,
+			TimeRecord:       record.TimeRecord,
+		})
+	}
 	return nil
 }
+
+/// Функция Prepare проверяет правила записи на прием, общие для всех способов записи \\\
+/// Доктор должен вести запись и принимать по специализации записи, без специализации берется основная \\\
+/// Занятость слота не проверяется: ее проверяет тот, кто создает запись, в своей транзакции \\\
+
+func (s *service) Prepare(ctx context.Context, record *Record) error {
+	doc, err := s.doc.FindById(record.DoctorID)
+	if err != nil {
+		return err
+	}
+	if !doc.RecordingIsAvailable {
+		return apperror.ErrDoctorNotAvailable
+	}
+	if record.SpecializationID == 0 {
+		record.SpecializationID = doc.SpecializationID
+	}
+	if !doc.HasSpecialization(record.SpecializationID) {
+		return apperror.ErrWrongSpecialization
+	}
+	return nil
+}
+
+/// Функция RequiresReferral проверяет, ведется ли запись по специализации только по направлению \\\
+
+func (s *service) RequiresReferral(specializationId int64) bool {
+	return s.referrals != nil && s.referrals.Required(specializationId)
+}
+
+/// Функция CheckPolicy проверяет, что полис пациента действует на день приема at \\\
+
+func (s *service) CheckPolicy(patientId int64, at time.Time) error {
+	if s.policies == nil {
+		return nil
+	}
+	return s.policies.CheckPatient(patientId, at)
+}
+
+/// Функция ResolveOffice заполняет адрес и кабинет записи из справочника \\\
+/// Указанный office_id должен существовать, без него берется кабинет по расписанию доктора на время приема \\\
+/// Если доктор в это время ни в каком кабинете не принимает, возвращается ErrNoOfficeHours \\\
+
+func (s *service) ResolveOffice(ctx context.Context, record *Record) error {
+	var location *Location
+	var err error
+	if record.OfficeID != nil {
+		location, err = s.locate(*record.OfficeID)
+	} else {
+		location, err = s.locateDoctor(record.DoctorID, record.TimeRecord)
+	}
+	if err != nil {
+		return err
+	}
+	record.OfficeID = &location.OfficeID
+	record.HospitalAddress, record.DoctorOffice = location.HospitalAddress, location.DoctorOffice
+	return nil
+}
+
+/// Функция locate находит кабинет справочника, неизвестный кабинет - ошибка клиента \\\
+
+func (s *service) locate(officeId int64) (*Location, error) {
+	if s.offices == nil {
+		return nil, apperror.ErrUnknownOffice
+	}
+	location, err := s.offices.Locate(officeId)
+	if errors.Is(err, apperror.ErrEmptyString) {
+		return nil, apperror.ErrUnknownOffice
+	}
+	return location, err
+}
+
+/// Функция locateDoctor находит кабинет доктора по расписанию, возвращает ErrNoOfficeHours если доктор в это время не принимает \\\
+
+func (s *service) locateDoctor(doctorId int64, at time.Time) (*Location, error) {
+	if s.offices == nil {
+		return nil, apperror.ErrNoOfficeHours
+	}
+	location, err := s.offices.LocateDoctor(doctorId, at)
+	if errors.Is(err, apperror.ErrEmptyString) {
+		return nil, apperror.ErrNoOfficeHours
+	}
+	return location, err
+}
+
+/// Функция checkSpecialization проверяет, что у доктора есть специализация записи \\\
+
+func (s *service) checkSpecialization(doctorId, specializationId int64) error {
+	doc, err := s.doc.FindById(doctorId)
+	if err != nil {
+		return err
+	}
+	if !doc.HasSpecialization(specializationId) {
+		return apperror.ErrWrongSpecialization
+	}
+	return nil
+}
+
+/// Функция CheckSlot проверяет, что время приема у доктора не занято другой записью и не удерживается листом ожидания \\\
+/// Записи с id из excludeIds не учитываются, например переносимая запись \\\
+
+func (s *service) CheckSlot(ctx context.Context, doctorId int64, at time.Time, excludeIds ...int64) error {
+	taken, err := s.storage.IsSlotTaken(doctorId, at, s.slot, excludeIds...)
+	if err != nil {
+		return err
+	}
+	if !taken && s.waitlist != nil {
+		taken, err = s.waitlist.IsSlotHeld(doctorId, at)
+		if err != nil {
+			return err
+		}
+	}
+	if taken {
+		return apperror.ErrSlotTaken
+	}
+	return nil
+}
+
+/// Функция absDuration возвращает модуль длительности \\\
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
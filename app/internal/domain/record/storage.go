@@ -1,10 +1,15 @@
 package record
 
+import "time"
+
 type Storage interface {
-	CreateRecord(record *Record) (*Record, error)
+	CreateRecord(record *Record, slot time.Duration) (*Record, error)
 	FindRecordByPatientsId(id int64) (*Record, error)
 	FindRecordById(id int64) (*Record, error)
 	UpdateRecord(record *UpdateRecordDTO) error
 	PartiallyUpdateRecord(record *PartiallyUpdateRecordDTO) error
 	DeleteRecord(id int64) error
+	IsSlotTaken(doctorId int64, at time.Time, slot time.Duration, excludeIds ...int64) (bool, error)
+	RescheduleRecord(current *Record, input *RescheduleRecordDTO, location *Location, slot time.Duration) (*Reschedule, error)
+	FindReschedules(recordId int64) ([]Reschedule, error)
 }
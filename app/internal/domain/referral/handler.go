@@ -0,0 +1,181 @@
+package referral
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+)
+
+const (
+	referralsURL        = "/hospital_record/referrals"
+	referralURL         = "/hospital_record/referrals/:id"
+	cancelReferralURL   = "/hospital_record/referrals/:id/cancel"
+	patientReferralsURL = "/hospital_record/patient_referrals"
+)
+
+/// Структура Handler представляющая собой обработчик объекта referralService для направлений \\\
+
+type Handler struct {
+	logger          logger.Logger
+	referralService Service
+	authorize       handler.Middleware
+	staff           handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, referralService Service, authorize, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:          logger,
+		referralService: referralService,
+		authorize:       authorize,
+		staff:           staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для направлений \\\
+/// Направления выдают и отменяют сотрудники, пациент по токену доступа видит свои направления \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, referralsURL, h.staff(h.CreateReferral))
+	router.HandlerFunc(http.MethodGet, referralsURL, h.staff(h.GetReferrals))
+	router.HandlerFunc(http.MethodGet, referralURL, h.staff(h.GetReferral))
+	router.HandlerFunc(http.MethodPost, cancelReferralURL, h.staff(h.CancelReferral))
+	router.HandlerFunc(http.MethodGet, patientReferralsURL, h.authorize(h.GetPatientReferrals))
+}
+
+/// Функция CreateReferral выдает направление пациенту к специалисту \\\
+
+func (h *Handler) CreateReferral(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE REFERRAL")
+
+	/// Чтение тела запроса в структуру CreateReferralDTO \\\
+	var input CreateReferralDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	referral, err := h.referralService.Create(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("REFERRAL CREATED")
+	response.JSON(w, http.StatusCreated, referral)
+}
+
+/// Функция GetReferrals получает направления, параметры patient_id и status фильтруют список \\\
+
+func (h *Handler) GetReferrals(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET REFERRALS")
+
+	query := r.URL.Query()
+	var patientId *int64
+	if raw := query.Get("patient_id"); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id < 1 {
+			response.BadRequest(w, "patient_id must be a positive integer", "")
+			return
+		}
+		patientId = &id
+	}
+	status := query.Get("status")
+	switch status {
+	case "", StatusIssued, StatusUsed, StatusCancelled, StatusExpired:
+	default:
+		response.BadRequest(w, "unknown referral status", "")
+		return
+	}
+
+	referrals, err := h.referralService.GetReferrals(r.Context(), patientId, status)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT REFERRALS")
+	response.JSON(w, http.StatusOK, referrals)
+}
+
+/// Функция GetReferral получает направление по id \\\
+
+func (h *Handler) GetReferral(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET REFERRAL")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	referral, err := h.referralService.GetReferral(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT REFERRAL")
+	response.JSON(w, http.StatusOK, referral)
+}
+
+/// Функция CancelReferral отменяет выданное направление \\\
+
+func (h *Handler) CancelReferral(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CANCEL REFERRAL")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	referral, err := h.referralService.Cancel(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("REFERRAL CANCELLED")
+	response.JSON(w, http.StatusOK, referral)
+}
+
+/// Функция GetPatientReferrals получает направления авторизованного пациента \\\
+
+func (h *Handler) GetPatientReferrals(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PATIENT REFERRALS")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	referrals, err := h.referralService.GetReferrals(r.Context(), &user.ID, "")
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT PATIENT REFERRALS")
+	response.JSON(w, http.StatusOK, referrals)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidReferral):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrReferralState):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
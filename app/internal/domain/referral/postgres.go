@@ -0,0 +1,190 @@
+package referral
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &ReferralStorage{}
+
+/// Структура ReferralStorage содержащая поля для работы с БД \\\
+
+type ReferralStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр ReferralStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &ReferralStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Столбцы направления, запись на прием по направлению находится по record.referral_id \\\
+
+const referralColumns = `f.id, f.patient_id, f.from_doctor_id, f.specialization_id, f.reason, f.urgency,
+	 f.valid_from, f.valid_until, f.status, (SELECT r.id FROM record r WHERE r.referral_id = f.id LIMIT 1),
+	 f.created_at, f.used_at`
+
+/// Функция Create для сущности ReferralStorage выдает направление \\\
+
+func (r *ReferralStorage) Create(referral *Referral) (*Referral, error) {
+	r.logger.Info("POSTGRES: CREATE REFERRAL")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	err := r.conn.QueryRow(ctx,
+		`INSERT INTO referral (patient_id, from_doctor_id, specialization_id, reason, urgency, valid_from, valid_until, status)
+			 VALUES($1,$2,$3,$4,$5,$6,$7,$8)
+			 RETURNING id, created_at`,
+		referral.PatientID, referral.FromDoctorID, referral.SpecializationID, referral.Reason, referral.Urgency,
+		referral.ValidFrom, referral.ValidUntil, StatusIssued).Scan(&referral.ID, &referral.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create referral query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	referral.Status = StatusIssued
+	return referral, nil
+}
+
+/// Функция FindById для сущности ReferralStorage получает направление по id \\\
+
+func (r *ReferralStorage) FindById(id int64) (*Referral, error) {
+	r.logger.Info("POSTGRES: GET REFERRAL BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	referral, err := scanReferral(r.conn.QueryRow(ctx,
+		`SELECT `+referralColumns+` FROM referral f
+			 WHERE f.id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find referral by id query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	return referral, nil
+}
+
+/// Функция FindReferrals для сущности ReferralStorage получает направления, новые первыми \\\
+/// Пустые patientId и status не ограничивают выборку, статус expired определяется по дню today \\\
+
+func (r *ReferralStorage) FindReferrals(patientId *int64, status string, today time.Time) ([]Referral, error) {
+	r.logger.Info("POSTGRES: GET REFERRALS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := r.conn.Query(ctx,
+		`SELECT `+referralColumns+` FROM referral f
+			 WHERE ($1::bigint IS NULL OR f.patient_id = $1)
+			 AND ($2 = '' OR (CASE WHEN f.status = 'issued' AND f.valid_until < $3 THEN 'expired' ELSE f.status END) = $2)
+			 ORDER BY f.created_at DESC, f.id DESC`,
+		patientId, status, today)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения направлений \\\
+	referrals := make([]Referral, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		referral, err := scanReferral(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find referrals query: %v", err)
+			r.logger.Error(err)
+			return nil, err
+		}
+		referrals = append(referrals, *referral)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return referrals, nil
+}
+
+/// Функция Cancel для сущности ReferralStorage отменяет выданное направление \\\
+
+func (r *ReferralStorage) Cancel(id int64) (*Referral, error) {
+	r.logger.Info("POSTGRES: CANCEL REFERRAL")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin cancel referral transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Использованное направление отменить нельзя, сначала отменяется запись на прием \\\
+	var status string
+	err = tx.QueryRow(ctx,
+		`SELECT status FROM referral WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to lock referral: %v", err)
+	}
+	if status != StatusIssued {
+		return nil, apperror.ErrReferralState
+	}
+
+	/// Выполнение запроса к БД \\\
+	referral, err := scanReferral(tx.QueryRow(ctx,
+		`UPDATE referral f SET status = $1
+			 WHERE f.id = $2
+			 RETURNING `+referralColumns, StatusCancelled, id))
+	if err != nil {
+		err = fmt.Errorf("failed to execute cancel referral query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit cancel referral transaction: %v", err)
+	}
+	return referral, nil
+}
+
+/// Функция scanReferral сканирует строку направления \\\
+
+func scanReferral(row pgx.Row) (*Referral, error) {
+	referral := &Referral{}
+	err := row.Scan(&referral.ID, &referral.PatientID, &referral.FromDoctorID, &referral.SpecializationID,
+		&referral.Reason, &referral.Urgency, &referral.ValidFrom, &referral.ValidUntil, &referral.Status,
+		&referral.RecordID, &referral.CreatedAt, &referral.UsedAt)
+	if err != nil {
+		return nil, err
+	}
+	return referral, nil
+}
@@ -0,0 +1,67 @@
+package referral
+
+import "time"
+
+/// Срочность направления \\\
+
+const (
+	UrgencyRoutine   = "routine"
+	UrgencyUrgent    = "urgent"
+	UrgencyEmergency = "emergency"
+)
+
+/// Статусы направления, expired не хранится и вычисляется для выданного направления с истекшим сроком \\\
+
+const (
+	StatusIssued    = "issued"
+	StatusUsed      = "used"
+	StatusCancelled = "cancelled"
+	StatusExpired   = "expired"
+)
+
+/// Структура направления пациента доктором к специалисту, по направлению создается одна запись на прием \\\
+
+type Referral struct {
+	ID               int64      `json:"id" example:"1"`
+	PatientID        int64      `json:"patient_id" example:"1"`
+	FromDoctorID     int64      `json:"from_doctor_id" example:"1"`
+	SpecializationID int64      `json:"specialization_id" example:"2"`
+	Reason           string     `json:"reason" example:"Podozrenie na glaukomu"`
+	Urgency          string     `json:"urgency" example:"routine"`
+	ValidFrom        time.Time  `json:"valid_from" example:"2023-07-27T00:00:00Z"`
+	ValidUntil       time.Time  `json:"valid_until" example:"2023-08-26T00:00:00Z"`
+	Status           string     `json:"status" example:"issued"`
+	RecordID         *int64     `json:"record_id,omitempty" example:"1567"`
+	CreatedAt        time.Time  `json:"created_at" example:"2023-07-27T10:00:00Z"`
+	UsedAt           *time.Time `json:"used_at,omitempty" example:"2023-07-28T09:00:00Z"`
+}
+
+/// Без срока действия направление действует с сегодняшнего дня validity_days дней \\\
+
+type CreateReferralDTO struct {
+	PatientID        int64      `json:"patient_id" example:"1"`
+	FromDoctorID     int64      `json:"from_doctor_id" example:"1"`
+	SpecializationID int64      `json:"specialization_id" example:"2"`
+	Reason           string     `json:"reason" example:"Podozrenie na glaukomu"`
+	Urgency          string     `json:"urgency,omitempty" example:"routine"`
+	ValidFrom        *time.Time `json:"valid_from,omitempty" example:"2023-07-27T00:00:00Z"`
+	ValidUntil       *time.Time `json:"valid_until,omitempty" example:"2023-08-26T00:00:00Z"`
+}
+
+/// Функция validUrgency проверяет, что urgency - известная срочность \\\
+
+func validUrgency(urgency string) bool {
+	switch urgency {
+	case UrgencyRoutine, UrgencyUrgent, UrgencyEmergency:
+		return true
+	}
+	return false
+}
+
+/// Функция expire отмечает выданное направление с истекшим до today сроком как expired \\\
+
+func (r *Referral) expire(today time.Time) {
+	if r.Status == StatusIssued && r.ValidUntil.Before(today) {
+		r.Status = StatusExpired
+	}
+}
@@ -0,0 +1,178 @@
+package referral
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/internal/domain/specialization"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для направлений к специалистам \\\
+/// Service также реализует record.Referrals, через который запись на прием проверяет направление \\\
+
+type Service interface {
+	record.Referrals
+	Create(ctx context.Context, input *CreateReferralDTO) (*Referral, error)
+	GetReferral(ctx context.Context, id int64) (*Referral, error)
+	GetReferrals(ctx context.Context, patientId *int64, status string) (*[]Referral, error)
+	Cancel(ctx context.Context, id int64) (*Referral, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service направлений \\\
+
+type service struct {
+	logger          logger.Logger
+	storage         Storage
+	patients        user.Storage
+	doc             doctor.Storage
+	specializations specialization.Storage
+	required        map[int64]bool
+	validity        int
+	location        *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, patients user.Storage, specializations specialization.Storage, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:          logger,
+		storage:         storage,
+		patients:        patients,
+		doc:             doc,
+		specializations: specializations,
+		required:        make(map[int64]bool, len(cfg.Referrals.RequiredSpecializations)),
+		validity:        cfg.Referrals.ValidityDays,
+		location:        time.UTC,
+	}
+	for _, id := range cfg.Referrals.RequiredSpecializations {
+		s.required[id] = true
+	}
+	if s.validity < 1 {
+		s.validity = 30
+	}
+	if cfg.Referrals.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Referrals.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown referrals time zone %q, using UTC: %v", cfg.Referrals.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция Create выдает направление пациенту к специалисту \\\
+
+func (s *service) Create(ctx context.Context, input *CreateReferralDTO) (*Referral, error) {
+	s.logger.Info("SERVICE: CREATE REFERRAL")
+
+	/// Проверка входных данных \\\
+	referral := Referral{
+		PatientID:        input.PatientID,
+		FromDoctorID:     input.FromDoctorID,
+		SpecializationID: input.SpecializationID,
+		Reason:           strings.TrimSpace(input.Reason),
+		Urgency:          input.Urgency,
+		ValidFrom:        s.date(time.Now()),
+	}
+	if referral.Urgency == "" {
+		referral.Urgency = UrgencyRoutine
+	}
+	if input.ValidFrom != nil {
+		referral.ValidFrom = s.date(*input.ValidFrom)
+	}
+	referral.ValidUntil = referral.ValidFrom.AddDate(0, 0, s.validity)
+	if input.ValidUntil != nil {
+		referral.ValidUntil = s.date(*input.ValidUntil)
+	}
+	if referral.Reason == "" || !validUrgency(referral.Urgency) || referral.ValidUntil.Before(referral.ValidFrom) {
+		return nil, apperror.ErrInvalidReferral
+	}
+
+	/// Проверка что пациент, доктор и специализация существуют \\\
+	if _, err := s.patients.FindById(input.PatientID); err != nil {
+		return nil, err
+	}
+	if _, err := s.doc.FindById(input.FromDoctorID); err != nil {
+		return nil, err
+	}
+	if _, err := s.specializations.FindById(input.SpecializationID); err != nil {
+		return nil, err
+	}
+	return s.storage.Create(&referral)
+}
+
+/// Функция GetReferral возвращает направление по id \\\
+
+func (s *service) GetReferral(ctx context.Context, id int64) (*Referral, error) {
+	s.logger.Info("SERVICE: GET REFERRAL")
+
+	referral, err := s.storage.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	referral.expire(s.date(time.Now()))
+	return referral, nil
+}
+
+/// Функция GetReferrals возвращает направления пациента или все направления со статусом status \\\
+
+func (s *service) GetReferrals(ctx context.Context, patientId *int64, status string) (*[]Referral, error) {
+	s.logger.Info("SERVICE: GET REFERRALS")
+
+	today := s.date(time.Now())
+	referrals, err := s.storage.FindReferrals(patientId, status, today)
+	if err != nil {
+		return nil, err
+	}
+	for i := range referrals {
+		referrals[i].expire(today)
+	}
+	return &referrals, nil
+}
+
+/// Функция Cancel отменяет выданное направление \\\
+
+func (s *service) Cancel(ctx context.Context, id int64) (*Referral, error) {
+	s.logger.Info("SERVICE: CANCEL REFERRAL")
+
+	return s.storage.Cancel(id)
+}
+
+/// Функция Required проверяет, ведется ли запись по специализации только по направлению \\\
+
+func (s *service) Required(specializationId int64) bool {
+	return s.required[specializationId]
+}
+
+/// Функция Check проверяет, что направление выдано пациенту к специализации записи и действует в день приема at \\\
+
+func (s *service) Check(referralId, patientId, specializationId int64, at time.Time) error {
+	referral, err := s.storage.FindById(referralId)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			return apperror.ErrReferralNotValid
+		}
+		return err
+	}
+	day := s.date(at)
+	if referral.Status != StatusIssued || referral.PatientID != patientId || referral.SpecializationID != specializationId ||
+		day.Before(referral.ValidFrom) || day.After(referral.ValidUntil) {
+		return apperror.ErrReferralNotValid
+	}
+	return nil
+}
+
+/// Функция date возвращает дату t по местному времени, даты направлений хранятся без часового пояса \\\
+
+func (s *service) date(t time.Time) time.Time {
+	year, month, day := t.In(s.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,10 @@
+package referral
+
+import "time"
+
+type Storage interface {
+	Create(referral *Referral) (*Referral, error)
+	FindById(id int64) (*Referral, error)
+	FindReferrals(patientId *int64, status string, today time.Time) ([]Referral, error)
+	Cancel(id int64) (*Referral, error)
+}
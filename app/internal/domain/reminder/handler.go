@@ -0,0 +1,65 @@
+package reminder
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	recordRemindersURL = "/hospital_record/records/:id/reminders"
+)
+
+/// Структура Handler представляющая собой обработчик объекта reminderService для напоминаний о приемах \\\
+
+type Handler struct {
+	logger          logger.Logger
+	reminderService Service
+	staff           handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, reminderService Service, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:          logger,
+		reminderService: reminderService,
+		staff:           staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для напоминаний, напоминания содержат контакты пациента и доступны только сотрудникам \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodGet, recordRemindersURL, h.staff(h.GetRecordReminders))
+}
+
+/// Функция GetRecordReminders получает напоминания записи на прием и статус их доставки \\\
+
+func (h *Handler) GetRecordReminders(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET RECORD REMINDERS")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetByRecord передавая ей id записи \\\
+	reminders, err := h.reminderService.GetByRecord(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT RECORD REMINDERS")
+	response.JSON(w, http.StatusOK, reminders)
+}
@@ -0,0 +1,269 @@
+package reminder
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &ReminderStorage{}
+
+/// Колонки напоминания в порядке сканирования scanReminder \\\
+
+const reminderColumns = `id, record_id, time_record, offset_minutes, channel, recipient, send_at,
+       status, attempts, retry_at, last_error, sent_at, created_at`
+
+/// Структура ReminderStorage содержащая поля для работы с БД \\\
+
+type ReminderStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр ReminderStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &ReminderStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция Plan для сущности ReminderStorage создает напоминания для предстоящих записей по каждому смещению и каналу \\\
+/// Уже созданные напоминания не меняются, поэтому повторное планирование после перезапуска не дублирует отправку \\\
+
+func (r *ReminderStorage) Plan(offsets []int, channels []string, now time.Time) (int64, error) {
+	r.logger.Info("POSTGRES: PLAN REMINDERS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД, канал без адреса у пациента пропускается \\\
+	result, err := r.conn.Exec(ctx,
+		`INSERT INTO reminder (record_id, time_record, offset_minutes, channel, recipient, send_at)
+			 SELECT a.id, a.time_record, o.offset_minutes, ch.channel, t.recipient,
+			        a.time_record - make_interval(mins => o.offset_minutes)
+			 FROM record a
+			 INNER JOIN patients p ON p.id = a.patients_id
+			 CROSS JOIN unnest($1::int[]) AS o(offset_minutes)
+			 CROSS JOIN unnest($2::text[]) AS ch(channel)
+			 CROSS JOIN LATERAL (SELECT CASE ch.channel WHEN 'email' THEN p.email ELSE p.phone_number END AS recipient) t
+			 WHERE a.time_record - make_interval(mins => o.offset_minutes) > $3
+			   AND coalesce(t.recipient, '') <> ''
+			 ON CONFLICT (record_id, time_record, offset_minutes, channel) DO NOTHING`,
+		offsets, channels, now)
+	if err != nil {
+		err = fmt.Errorf("failed to execute plan reminders query: %v", err)
+		r.logger.Error(err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+/// Функция ReleaseStale для сущности ReminderStorage возвращает в очередь напоминания, зависшие в отправке \\\
+/// Например после аварийной остановки сервера между захватом и отметкой об отправке \\\
+
+func (r *ReminderStorage) ReleaseStale(claimedBefore time.Time) (int64, error) {
+	r.logger.Info("POSTGRES: RELEASE STALE REMINDERS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := r.conn.Exec(ctx,
+		`UPDATE reminder SET status = 'pending', claimed_at = NULL
+			 WHERE status = 'sending' AND claimed_at < $1`, claimedBefore)
+	if err != nil {
+		err = fmt.Errorf("failed to execute release stale reminders query: %v", err)
+		r.logger.Error(err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+/// Функция SkipObsolete для сущности ReminderStorage помечает skipped напоминания, которые больше не нужно отправлять: \\\
+/// запись перенесена или прием уже начался, либо уже наступило время более позднего напоминания того же канала \\\
+
+func (r *ReminderStorage) SkipObsolete(now time.Time) (int64, error) {
+	r.logger.Info("POSTGRES: SKIP OBSOLETE REMINDERS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := r.conn.Exec(ctx,
+		`UPDATE reminder m SET status = 'skipped', retry_at = NULL
+			 WHERE m.status = 'pending'
+			   AND (NOT EXISTS (SELECT 1 FROM record a
+			                    WHERE a.id = m.record_id AND a.time_record = m.time_record AND a.time_record > $1)
+			        OR EXISTS (SELECT 1 FROM reminder o
+			                   WHERE o.record_id = m.record_id AND o.time_record = m.time_record AND o.channel = m.channel
+			                     AND o.offset_minutes < m.offset_minutes AND o.send_at <= $1))`, now)
+	if err != nil {
+		err = fmt.Errorf("failed to execute skip obsolete reminders query: %v", err)
+		r.logger.Error(err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+/// Функция ClaimDue для сущности ReminderStorage захватывает до limit напоминаний, время отправки которых наступило \\\
+/// Захваченное напоминание переходит в статус sending, поэтому повторно не выбирается \\\
+
+func (r *ReminderStorage) ClaimDue(now time.Time, limit int) ([]Delivery, error) {
+	r.logger.Info("POSTGRES: CLAIM DUE REMINDERS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := r.conn.Query(ctx,
+		`WITH claimed AS (
+			 UPDATE reminder SET status = 'sending', attempts = attempts + 1, claimed_at = $1
+			 WHERE id IN (SELECT id FROM reminder
+			              WHERE status = 'pending' AND coalesce(retry_at, send_at) <= $1
+			              ORDER BY coalesce(retry_at, send_at)
+			              LIMIT $2
+			              FOR UPDATE SKIP LOCKED)
+			 RETURNING `+reminderColumns+`)
+		 SELECT c.id, c.record_id, c.time_record, c.offset_minutes, c.channel, c.recipient, c.send_at,
+		        c.status, c.attempts, c.retry_at, c.last_error, c.sent_at, c.created_at,
+		        p.surname || ' ' || p.name, d.surname || ' ' || d.name, s.name_specialization,
+		        a.hospital_address, a.doctor_office
+		 FROM claimed c
+		 INNER JOIN record a ON a.id = c.record_id
+		 INNER JOIN patients p ON p.id = a.patients_id
+		 INNER JOIN doctors d ON d.id = a.doctor_id
+		 INNER JOIN specialization s ON s.id = a.specialization_id
+		 ORDER BY c.send_at`, now, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения напоминаний \\\
+	deliveries := make([]Delivery, 0)
+
+	for rows.Next() {
+		var d Delivery
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&d.ID, &d.RecordID, &d.TimeRecord, &d.OffsetMinutes, &d.Channel, &d.Recipient, &d.SendAt,
+			&d.Status, &d.Attempts, &d.RetryAt, &d.LastError, &d.SentAt, &d.CreatedAt,
+			&d.PatientName, &d.DoctorName, &d.Specialization, &d.HospitalAddress, &d.DoctorOffice)
+		if err != nil {
+			err = fmt.Errorf("failed to execute claim due reminders query: %v", err)
+			r.logger.Error(err)
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+/// Функция MarkSent для сущности ReminderStorage отмечает напоминание отправленным \\\
+
+func (r *ReminderStorage) MarkSent(id int64) error {
+	r.logger.Info("POSTGRES: MARK REMINDER SENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := r.conn.Exec(ctx,
+		`UPDATE reminder SET status = 'sent', sent_at = now(), claimed_at = NULL, retry_at = NULL, last_error = NULL
+			 WHERE id = $1 AND status = 'sending'`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder sent: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция MarkFailed для сущности ReminderStorage сохраняет ошибку отправки \\\
+/// При retryAt != nil напоминание возвращается в очередь, иначе получает статус failed \\\
+
+func (r *ReminderStorage) MarkFailed(id int64, reason string, retryAt *time.Time) error {
+	r.logger.Info("POSTGRES: MARK REMINDER FAILED")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := r.conn.Exec(ctx,
+		`UPDATE reminder SET status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+			                     retry_at = $3, last_error = $2, claimed_at = NULL
+			 WHERE id = $1 AND status = 'sending'`, id, reason, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to mark reminder failed: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция FindByRecord для сущности ReminderStorage получает напоминания записи на прием \\\
+
+func (r *ReminderStorage) FindByRecord(recordId int64) ([]Reminder, error) {
+	r.logger.Info("POSTGRES: GET RECORD REMINDERS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := r.conn.Query(ctx,
+		`SELECT `+reminderColumns+` FROM reminder
+			 WHERE record_id = $1
+			 ORDER BY send_at, channel`, recordId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения напоминаний \\\
+	reminders := make([]Reminder, 0)
+
+	for rows.Next() {
+		var m Reminder
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&m.ID, &m.RecordID, &m.TimeRecord, &m.OffsetMinutes, &m.Channel, &m.Recipient, &m.SendAt,
+			&m.Status, &m.Attempts, &m.RetryAt, &m.LastError, &m.SentAt, &m.CreatedAt)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find reminders query: %v", err)
+			r.logger.Error(err)
+			return nil, err
+		}
+		reminders = append(reminders, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return reminders, nil
+}
@@ -0,0 +1,43 @@
+package reminder
+
+import "time"
+
+/// Статусы напоминания \\\
+
+const (
+	StatusPending = "pending"
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+/// Структура напоминания о приеме: одно напоминание на запись, время приема, смещение и канал \\\
+/// После переноса записи создаются новые напоминания, прежние помечаются skipped \\\
+
+type Reminder struct {
+	ID            int64      `json:"id" example:"1"`
+	RecordID      int64      `json:"record_id" example:"1"`
+	TimeRecord    time.Time  `json:"time_record" example:"2023-07-27T15:30:00Z"`
+	OffsetMinutes int        `json:"offset_minutes" example:"1440"`
+	Channel       string     `json:"channel" example:"email"`
+	Recipient     string     `json:"recipient" example:"secondpatient@mail.ru"`
+	SendAt        time.Time  `json:"send_at" example:"2023-07-26T15:30:00Z"`
+	Status        string     `json:"status" example:"sent"`
+	Attempts      int        `json:"attempts" example:"1"`
+	RetryAt       *time.Time `json:"retry_at,omitempty"`
+	LastError     *string    `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+/// Структура напоминания, готового к отправке, с данными приема для текста сообщения \\\
+
+type Delivery struct {
+	Reminder
+	PatientName     string
+	DoctorName      string
+	Specialization  string
+	HospitalAddress string
+	DoctorOffice    string
+}
@@ -0,0 +1,215 @@
+package reminder
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"HospitalRecord/app/pkg/notify"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/// Время, после которого захваченное, но не отмеченное напоминание считается зависшим, и время на одну отправку \\\
+
+const (
+	staleClaim  = 10 * time.Minute
+	sendTimeout = 30 * time.Second
+)
+
+/// Интерфейс Service реализизирующий service и методы для напоминаний о приемах \\\
+
+type Service interface {
+	GetByRecord(ctx context.Context, recordId int64) (*[]Reminder, error)
+	Run(ctx context.Context)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service напоминаний \\\
+
+type service struct {
+	logger      logger.Logger
+	storage     Storage
+	records     record.Storage
+	notifiers   map[string]notify.Notifier
+	channels    []string
+	offsets     []int
+	sweep       time.Duration
+	retry       time.Duration
+	maxAttempts int
+	batchSize   int
+	location    *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+/// Напоминания планируются только по каналам из notifiers \\\
+
+func NewService(records record.Storage, storage Storage, notifiers []notify.Notifier, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:      logger,
+		storage:     storage,
+		records:     records,
+		notifiers:   make(map[string]notify.Notifier, len(notifiers)),
+		sweep:       time.Duration(cfg.Reminders.SweepSeconds) * time.Second,
+		retry:       time.Duration(cfg.Reminders.RetryMinutes) * time.Minute,
+		maxAttempts: cfg.Reminders.MaxAttempts,
+		batchSize:   cfg.Reminders.BatchSize,
+		location:    time.UTC,
+	}
+	for _, n := range notifiers {
+		s.notifiers[n.Channel()] = n
+		s.channels = append(s.channels, n.Channel())
+	}
+	for _, offset := range cfg.Reminders.OffsetsMinutes {
+		if offset > 0 {
+			s.offsets = append(s.offsets, offset)
+		}
+	}
+	if cfg.Reminders.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.Reminders.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown reminders time zone %q, using UTC: %v", cfg.Reminders.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция GetByRecord возвращает напоминания записи на прием и статус их доставки \\\
+
+func (s *service) GetByRecord(ctx context.Context, recordId int64) (*[]Reminder, error) {
+	s.logger.Info("SERVICE: GET RECORD REMINDERS")
+
+	/// Проверка что запись существует \\\
+	if _, err := s.records.FindRecordById(recordId); err != nil {
+		return nil, err
+	}
+
+	/// Вызов функции FindByRecord в хранилище напоминаний \\\
+	reminders, err := s.storage.FindByRecord(recordId)
+	if err != nil {
+		return nil, err
+	}
+	return &reminders, nil
+}
+
+/// Функция Run периодически планирует напоминания о предстоящих приемах и отправляет наступившие \\\
+/// Работает до отмены ctx, без каналов, смещений или при sweep_seconds <= 0 напоминания не отправляются \\\
+
+func (s *service) Run(ctx context.Context) {
+	if s.sweep <= 0 || len(s.channels) == 0 || len(s.offsets) == 0 {
+		s.logger.Info("appointment reminders are disabled")
+		return
+	}
+	ticker := time.NewTicker(s.sweep)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/// Функция tick выполняет один проход планировщика \\\
+
+func (s *service) tick(ctx context.Context) {
+	now := time.Now()
+
+	/// Подготовка очереди: новые напоминания, возврат зависших, отбрасывание ненужных \\\
+	if _, err := s.storage.Plan(s.offsets, s.channels, now); err != nil {
+		s.logger.Warnf("failed to plan reminders: %v", err)
+		return
+	}
+	if released, err := s.storage.ReleaseStale(now.Add(-staleClaim)); err != nil {
+		s.logger.Warnf("failed to release stale reminders: %v", err)
+	} else if released > 0 {
+		s.logger.Warnf("%d stale reminders returned to the queue", released)
+	}
+	if _, err := s.storage.SkipObsolete(now); err != nil {
+		s.logger.Warnf("failed to skip obsolete reminders: %v", err)
+	}
+
+	/// Отправка наступивших напоминаний пачками \\\
+	for ctx.Err() == nil {
+		deliveries, err := s.storage.ClaimDue(now, s.batchSize)
+		if err != nil {
+			s.logger.Warnf("failed to claim due reminders: %v", err)
+			return
+		}
+		for i := range deliveries {
+			s.deliver(ctx, &deliveries[i])
+		}
+		if len(deliveries) < s.batchSize {
+			return
+		}
+	}
+}
+
+/// Функция deliver отправляет одно напоминание и сохраняет результат доставки \\\
+/// Ошибка адреса получателя не исправится повтором, поэтому такое напоминание сразу получает статус failed \\\
+
+func (s *service) deliver(ctx context.Context, d *Delivery) {
+	notifier, ok := s.notifiers[d.Channel]
+	if !ok {
+		s.fail(d, fmt.Errorf("channel %q is not configured", d.Channel), false)
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	err := notifier.Send(sendCtx, s.message(d))
+	if err != nil {
+		s.fail(d, err, !errors.Is(err, notify.ErrInvalidRecipient))
+		return
+	}
+	if err = s.storage.MarkSent(d.ID); err != nil {
+		s.logger.Warnf("failed to mark reminder %d sent: %v", d.ID, err)
+	}
+}
+
+/// Функция fail сохраняет ошибку отправки и назначает повтор, пока не исчерпаны попытки \\\
+
+func (s *service) fail(d *Delivery, sendErr error, retryable bool) {
+	s.logger.Warnf("failed to send %s reminder %d: %v", d.Channel, d.ID, sendErr)
+
+	var retryAt *time.Time
+	if retryable && d.Attempts < s.maxAttempts {
+		next := time.Now().Add(s.retry)
+		retryAt = &next
+	}
+	if err := s.storage.MarkFailed(d.ID, sendErr.Error(), retryAt); err != nil {
+		s.logger.Warnf("failed to mark reminder %d failed: %v", d.ID, err)
+	}
+}
+
+/// Функция message формирует текст напоминания, время приема указывается в часовом поясе из конфигурации \\\
+
+func (s *service) message(d *Delivery) notify.Message {
+	at := d.TimeRecord.In(s.location).Format("02.01.2006 15:04 MST")
+	location := d.HospitalAddress
+	if d.DoctorOffice != "" {
+		location = fmt.Sprintf("%s, office %s", d.HospitalAddress, d.DoctorOffice)
+	}
+
+	var body strings.Builder
+	if d.Channel == notify.ChannelSMS {
+		fmt.Fprintf(&body, "Reminder: %s, Dr. %s, %s, %s", d.Specialization, d.DoctorName, at, location)
+	} else {
+		fmt.Fprintf(&body, "Dear %s,\n\n", d.PatientName)
+		fmt.Fprintf(&body, "this is a reminder of your appointment.\n\n")
+		fmt.Fprintf(&body, "Doctor: %s (%s)\n", d.DoctorName, d.Specialization)
+		fmt.Fprintf(&body, "Time: %s\n", at)
+		fmt.Fprintf(&body, "Place: %s\n", location)
+	}
+	return notify.Message{
+		To:      d.Recipient,
+		Subject: "Appointment reminder: " + at,
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,13 @@
+package reminder
+
+import "time"
+
+type Storage interface {
+	Plan(offsets []int, channels []string, now time.Time) (int64, error)
+	ReleaseStale(claimedBefore time.Time) (int64, error)
+	SkipObsolete(now time.Time) (int64, error)
+	ClaimDue(now time.Time, limit int) ([]Delivery, error)
+	MarkSent(id int64) error
+	MarkFailed(id int64, reason string, retryAt *time.Time) error
+	FindByRecord(recordId int64) ([]Reminder, error)
+}
@@ -13,6 +13,10 @@ func BadRequest(w http.ResponseWriter, message, developerMessage string) {
 	Error(w, http.StatusBadRequest, message, developerMessage)
 }
 
+func Unauthorized(w http.ResponseWriter, message, developerMessage string) {
+	Error(w, http.StatusUnauthorized, message, developerMessage)
+}
+
 func NotFound(w http.ResponseWriter) {
 	JSON(w, http.StatusNotFound, apperror.ErrNotFound)
 }
@@ -0,0 +1,177 @@
+package review
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	reviewsURL           = "/hospital_record/reviews"
+	doctorReviewsURL     = "/hospital_record/reviews/doctors/:id"
+	reviewModerationURL  = "/hospital_record/review_moderation"
+	reviewModerateOneURL = "/hospital_record/review_moderation/:id"
+)
+
+/// Структура Handler представляющая собой обработчик объекта reviewService для отзывов \\\
+
+type Handler struct {
+	logger        logger.Logger
+	reviewService Service
+	authorize     handler.Middleware
+	staff         handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, reviewService Service, authorize, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:        logger,
+		reviewService: reviewService,
+		authorize:     authorize,
+		staff:         staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для отзывов, создание отзыва требует токен доступа пациента \\\
+/// Очередь модерации видят и разбирают только сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, reviewsURL, h.authorize(h.CreateReview))
+	router.HandlerFunc(http.MethodGet, doctorReviewsURL, h.GetDoctorReviews)
+	router.HandlerFunc(http.MethodGet, reviewModerationURL, h.staff(h.GetPendingReviews))
+	router.HandlerFunc(http.MethodPatch, reviewModerateOneURL, h.staff(h.ModerateReview))
+}
+
+/// Функция CreateReview создает отзыв авторизованного пациента о прошедшем приеме \\\
+
+func (h *Handler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE REVIEW")
+
+	/// Пациент определяется по токену доступа, а не по телу запроса \\\
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и декодирует его в структуру input \\\
+	var input CreateReviewDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.PatientID = user.ID
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Create передавая ей полученные значения \\\
+	review, err := h.reviewService.Create(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidReviewRating):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrVisitNotCompleted):
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+		case errors.Is(err, apperror.ErrRepeatedReview):
+			response.Error(w, http.StatusConflict, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "wrong on the server")
+		}
+		return
+	}
+	h.logger.Info("REVIEW CREATED")
+	response.JSON(w, http.StatusCreated, review)
+}
+
+/// Функция GetDoctorReviews получает страницу отзывов о докторе по его id, параметры page и per_page \\\
+
+func (h *Handler) GetDoctorReviews(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DOCTOR REVIEWS")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	page, perPage, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetByDoctor передавая ей id доктора и параметры страницы \\\
+	reviews, err := h.reviewService.GetByDoctor(r.Context(), id, page, perPage)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT DOCTOR REVIEWS")
+	response.JSON(w, http.StatusOK, reviews)
+}
+
+/// Функция GetPendingReviews получает страницу отзывов, ожидающих модерации \\\
+
+func (h *Handler) GetPendingReviews(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET PENDING REVIEWS")
+
+	page, perPage, err := handler.ReadPagination(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetPending передавая ей параметры страницы \\\
+	reviews, err := h.reviewService.GetPending(r.Context(), page, perPage)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT PENDING REVIEWS")
+	response.JSON(w, http.StatusOK, reviews)
+}
+
+/// Функция ModerateReview одобряет или отклоняет комментарий отзыва по его id \\\
+
+func (h *Handler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: MODERATE REVIEW")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Декодирование тела запроса в структуру input \\\
+	var input ModerateReviewDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Moderate передавая ей полученные значения \\\
+	err = h.reviewService.Moderate(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidReviewStatus):
+			response.BadRequest(w, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "wrong on the server")
+		}
+		return
+	}
+	h.logger.Info("REVIEW MODERATED")
+	response.JSON(w, http.StatusOK, "REVIEW MODERATED")
+}
@@ -0,0 +1,199 @@
+package review
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &ReviewStorage{}
+
+/// Структура ReviewStorage содержащая поля для работы с БД \\\
+
+type ReviewStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр ReviewStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &ReviewStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция Create для сущности ReviewStorage сохраняет отзыв и пересчитывает рейтинг доктора в одной транзакции \\\
+
+func (r *ReviewStorage) Create(review *Review) (*Review, error) {
+	r.logger.Info("POSTGRES: CREATE REVIEW")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create review transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	row := tx.QueryRow(ctx,
+		`INSERT INTO review (record_id, doctor_id, patient_id, rating, comment, status)
+			 VALUES($1,$2,$3,$4,$5,$6)
+			 RETURNING id, created_at`,
+		review.RecordID, review.DoctorID, review.PatientID, review.Rating, review.Comment, review.Status)
+
+	/// Сканирование полученных значений из БД \\\
+	err = row.Scan(&review.ID, &review.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create review query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	/// Пересчет рейтинга доктора по всем его отзывам \\\
+	_, err = tx.Exec(ctx,
+		`UPDATE doctors
+			SET rating = coalesce((SELECT round(avg(rating), 1) FROM review WHERE doctor_id = $1), 0)
+			WHERE id = $1`, review.DoctorID)
+	if err != nil {
+		err = fmt.Errorf("failed to recompute doctor rating: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create review transaction: %v", err)
+	}
+	return review, nil
+}
+
+/// Функция FindByRecordId для сущности ReviewStorage получает отзыв из БД по id записи на прием \\\
+
+func (r *ReviewStorage) FindByRecordId(recordId int64) (*Review, error) {
+	r.logger.Info("POSTGRES: GET REVIEW BY RECORD ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := r.conn.QueryRow(ctx,
+		`SELECT * FROM review
+			 WHERE record_id = $1`, recordId)
+
+	review := &Review{}
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&review.ID, &review.RecordID, &review.DoctorID, &review.PatientID,
+		&review.Rating, &review.Comment, &review.Status, &review.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find review by record id query: %v", err)
+		r.logger.Error(err)
+		return nil, err
+	}
+	return review, nil
+}
+
+/// Функция FindByDoctor для сущности ReviewStorage получает страницу отзывов о докторе и их общее количество \\\
+
+func (r *ReviewStorage) FindByDoctor(doctorId int64, limit, offset int) ([]Review, int64, error) {
+	r.logger.Info("POSTGRES: GET REVIEWS BY DOCTOR")
+	return r.findPage(`doctor_id = $1`, doctorId, limit, offset)
+}
+
+/// Функция FindByStatus для сущности ReviewStorage получает страницу отзывов с заданным статусом модерации \\\
+
+func (r *ReviewStorage) FindByStatus(status string, limit, offset int) ([]Review, int64, error) {
+	r.logger.Info("POSTGRES: GET REVIEWS BY STATUS")
+	return r.findPage(`status = $1`, status, limit, offset)
+}
+
+/// Функция findPage получает страницу отзывов по условию where с одним аргументом arg \\\
+
+func (r *ReviewStorage) findPage(where string, arg interface{}, limit, offset int) ([]Review, int64, error) {
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Подсчет общего количества отзывов \\\
+	var total int64
+	err := r.conn.QueryRow(ctx, `SELECT count(*) FROM review WHERE `+where, arg).Scan(&total)
+	if err != nil {
+		err = fmt.Errorf("failed to execute count reviews query: %v", err)
+		r.logger.Error(err)
+		return nil, 0, err
+	}
+
+	/// Выполнение запроса к БД \\\
+	rows, err := r.conn.Query(ctx,
+		`SELECT * FROM review
+			 WHERE `+where+`
+			 ORDER BY created_at DESC, id DESC
+			 LIMIT $2 OFFSET $3`, arg, limit, offset)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		r.logger.Error(err)
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения отзывов \\\
+	reviews := make([]Review, 0)
+
+	for rows.Next() {
+		var review Review
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&review.ID, &review.RecordID, &review.DoctorID, &review.PatientID,
+			&review.Rating, &review.Comment, &review.Status, &review.CreatedAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find reviews query: %v", err)
+			r.logger.Error(err)
+			return nil, 0, err
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+/// Функция UpdateStatus для сущности ReviewStorage изменяет статус модерации отзыва в БД \\\
+
+func (r *ReviewStorage) UpdateStatus(id int64, status string) error {
+	r.logger.Info("POSTGRES: UPDATE REVIEW STATUS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), r.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := r.conn.Exec(ctx,
+		`UPDATE review SET status = $1 WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update review status: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
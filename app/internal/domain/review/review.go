@@ -0,0 +1,45 @@
+package review
+
+import "time"
+
+/// Статусы модерации комментария отзыва \\\
+
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+)
+
+/// Структура отзыва пациента о докторе по прошедшему приему, один отзыв на одну запись \\\
+
+type Review struct {
+	ID        int64     `json:"id" example:"1567"`
+	RecordID  int64     `json:"record_id" example:"1"`
+	DoctorID  int64     `json:"doctor_id" example:"1"`
+	PatientID int64     `json:"patient_id" example:"1"`
+	Rating    int8      `json:"rating" example:"5"`
+	Comment   *string   `json:"comment,omitempty" example:"Vnimatelniy vrach"`
+	Status    string    `json:"status" example:"approved"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateReviewDTO struct {
+	RecordID  int64   `json:"record_id" example:"1"`
+	PatientID int64   `json:"-"`
+	Rating    int8    `json:"rating" example:"5"`
+	Comment   *string `json:"comment,omitempty" example:"Vnimatelniy vrach"`
+}
+
+type ModerateReviewDTO struct {
+	ID     int64  `json:"-"`
+	Status string `json:"status" example:"approved"`
+}
+
+/// Структура страницы отзывов \\\
+
+type ReviewPage struct {
+	Reviews []Review `json:"reviews"`
+	Page    int      `json:"page" example:"1"`
+	PerPage int      `json:"per_page" example:"20"`
+	Total   int64    `json:"total" example:"42"`
+}
@@ -0,0 +1,147 @@
+package review
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для обработки отзывов \\\
+
+type Service interface {
+	Create(ctx context.Context, input *CreateReviewDTO) (*Review, error)
+	GetByDoctor(ctx context.Context, doctorId int64, page, perPage int) (*ReviewPage, error)
+	GetPending(ctx context.Context, page, perPage int) (*ReviewPage, error)
+	Moderate(ctx context.Context, input *ModerateReviewDTO) error
+}
+
+/// Структура  service реализизирующая инфтерфейс Service отзывов \\\
+
+type service struct {
+	logger     logger.Logger
+	storage    Storage
+	records    record.Storage
+	moderation bool
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(records record.Storage, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	return &service{
+		logger:     logger,
+		storage:    storage,
+		records:    records,
+		moderation: cfg.Reviews.Moderation,
+	}
+}
+
+/// Функция Create создает отзыв пациента о прошедшем приеме через интерфейс Service принимая входные данные input \\\
+
+func (s *service) Create(ctx context.Context, input *CreateReviewDTO) (*Review, error) {
+	s.logger.Info("SERVICE: CREATE REVIEW")
+
+	if input.Rating < 1 || input.Rating > 5 {
+		return nil, apperror.ErrInvalidReviewRating
+	}
+
+	/// Отзыв можно оставить только о своем приеме, который уже состоялся \\\
+	rec, err := s.records.FindRecordById(input.RecordID)
+	if err != nil {
+		return nil, err
+	}
+	if rec.PatientsID != input.PatientID || !rec.TimeRecord.Before(time.Now()) {
+		return nil, apperror.ErrVisitNotCompleted
+	}
+
+	/// Проверка что о приеме еще нет отзыва \\\
+	_, err = s.storage.FindByRecordId(input.RecordID)
+	if err == nil {
+		return nil, apperror.ErrRepeatedReview
+	}
+	if !errors.Is(err, apperror.ErrEmptyString) {
+		return nil, err
+	}
+
+	/// Создание структуры review на основе полученных данных \\\
+	review := Review{
+		RecordID:  rec.ID,
+		DoctorID:  rec.DoctorID,
+		PatientID: rec.PatientsID,
+		Rating:    input.Rating,
+		Status:    StatusApproved,
+	}
+	if input.Comment != nil {
+		if comment := strings.TrimSpace(*input.Comment); comment != "" {
+			review.Comment = &comment
+			/// При включенной модерации комментарий скрыт до одобрения, на рейтинг это не влияет \\\
+			if s.moderation {
+				review.Status = StatusPending
+			}
+		}
+	}
+
+	/// Вызов функции Create в хранилище отзывов \\\
+	created, err := s.storage.Create(&review)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+/// Функция GetByDoctor возвращает страницу отзывов о докторе, неодобренные комментарии скрываются \\\
+
+func (s *service) GetByDoctor(ctx context.Context, doctorId int64, page, perPage int) (*ReviewPage, error) {
+	s.logger.Info("SERVICE: GET REVIEWS BY DOCTOR")
+
+	/// Вызов функции FindByDoctor в хранилище отзывов \\\
+	reviews, total, err := s.storage.FindByDoctor(doctorId, perPage, (page-1)*perPage)
+	if err != nil {
+		s.logger.Warnf("cannot find reviews by doctor: %v", err)
+		return nil, err
+	}
+	for i := range reviews {
+		if reviews[i].Status != StatusApproved {
+			reviews[i].Comment = nil
+		}
+	}
+	return &ReviewPage{Reviews: reviews, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+/// Функция GetPending возвращает страницу отзывов, ожидающих модерации \\\
+
+func (s *service) GetPending(ctx context.Context, page, perPage int) (*ReviewPage, error) {
+	s.logger.Info("SERVICE: GET PENDING REVIEWS")
+
+	/// Вызов функции FindByStatus в хранилище отзывов \\\
+	reviews, total, err := s.storage.FindByStatus(StatusPending, perPage, (page-1)*perPage)
+	if err != nil {
+		s.logger.Warnf("cannot find pending reviews: %v", err)
+		return nil, err
+	}
+	return &ReviewPage{Reviews: reviews, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+/// Функция Moderate одобряет или отклоняет комментарий отзыва через интерфейс Service \\\
+
+func (s *service) Moderate(ctx context.Context, input *ModerateReviewDTO) error {
+	s.logger.Info("SERVICE: MODERATE REVIEW")
+
+	if input.Status != StatusApproved && input.Status != StatusRejected {
+		return apperror.ErrInvalidReviewStatus
+	}
+
+	/// Вызов функции UpdateStatus в хранилище отзывов \\\
+	err := s.storage.UpdateStatus(input.ID, input.Status)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("failed to moderate review: %v", err)
+		}
+		return err
+	}
+	return nil
+}
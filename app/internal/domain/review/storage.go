@@ -0,0 +1,9 @@
+package review
+
+type Storage interface {
+	Create(review *Review) (*Review, error)
+	FindByRecordId(recordId int64) (*Review, error)
+	FindByDoctor(doctorId int64, limit, offset int) ([]Review, int64, error)
+	FindByStatus(status string, limit, offset int) ([]Review, int64, error)
+	UpdateStatus(id int64, status string) error
+}
@@ -0,0 +1,186 @@
+package series
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+	"strconv"
+)
+
+const (
+	seriesURL           = "/hospital_record/series"
+	seriesByIdURL       = "/hospital_record/series/:id"
+	seriesRescheduleURL = "/hospital_record/series/:id/reschedule"
+)
+
+/// Структура Handler представляющая собой обработчик объекта seriesService для серий записей на прием \\\
+
+type Handler struct {
+	logger        logger.Logger
+	seriesService Service
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, seriesService Service) handler.Hand {
+	return &Handler{
+		logger:        logger,
+		seriesService: seriesService,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для серий записей на прием \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, seriesURL, h.CreateSeries)
+	router.HandlerFunc(http.MethodGet, seriesByIdURL, h.GetSeriesById)
+	router.HandlerFunc(http.MethodDelete, seriesByIdURL, h.CancelSeries)
+	router.HandlerFunc(http.MethodPost, seriesRescheduleURL, h.RescheduleSeries)
+}
+
+/// Функция CreateSeries создает серию записей на прием по правилу повторения \\\
+
+func (h *Handler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE RECORD SERIES")
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	var input CreateSeriesDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Create передавая ей полученные значения \\\
+	series, err := h.seriesService.Create(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECORD SERIES CREATED")
+	response.JSON(w, http.StatusCreated, series)
+}
+
+/// Функция GetSeriesById получает серию записей с занятиями по ее id \\\
+
+func (h *Handler) GetSeriesById(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET RECORD SERIES BY ID")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetById передавая ей id серии \\\
+	series, err := h.seriesService.GetById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT RECORD SERIES BY ID")
+	response.JSON(w, http.StatusOK, series)
+}
+
+/// Функция CancelSeries отменяет занятия серии, параметры scope (one или remaining) и occurrence \\\
+/// Без параметров отменяются все оставшиеся будущие занятия \\\
+
+func (h *Handler) CancelSeries(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CANCEL RECORD SERIES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	input := CancelSeriesDTO{ID: id, Scope: ScopeRemaining}
+	query := r.URL.Query()
+	if value := query.Get("scope"); value != "" {
+		input.Scope = value
+	}
+	if value := query.Get("occurrence"); value != "" {
+		input.Occurrence, err = strconv.Atoi(value)
+		if err != nil || input.Occurrence < 1 {
+			response.BadRequest(w, "occurrence must be a positive integer", "")
+			return
+		}
+	}
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Cancel передавая ей полученные значения \\\
+	err = h.seriesService.Cancel(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECORD SERIES CANCELLED")
+	response.JSON(w, http.StatusOK, "RECORD SERIES CANCELLED")
+}
+
+/// Функция RescheduleSeries переносит одно занятие серии или все оставшиеся занятия \\\
+
+func (h *Handler) RescheduleSeries(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: RESCHEDULE RECORD SERIES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	var input RescheduleSeriesDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Reschedule передавая ей полученные значения \\\
+	series, err := h.seriesService.Reschedule(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("RECORD SERIES RESCHEDULED")
+	response.JSON(w, http.StatusOK, series)
+}
+
+/// Функция writeError отвечает на ошибку сервиса серий, при конфликте возвращает список занятых дат \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	var conflict *ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		response.JSON(w, http.StatusConflict, ConflictResponse{
+			Message:   apperror.ErrSlotTaken.Error(),
+			Conflicts: conflict.Dates,
+		})
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidRecurrence), errors.Is(err, apperror.ErrInvalidSeriesScope),
+		errors.Is(err, apperror.ErrInvalidReschedule), errors.Is(err, apperror.ErrWrongSpecialization),
+		errors.Is(err, apperror.ErrUnknownOffice), errors.Is(err, apperror.ErrInvalidPolicyNumber):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrRescheduleTooLate), errors.Is(err, apperror.ErrRescheduleLimit):
+		response.Error(w, http.StatusUnprocessableEntity, err.Error(), "")
+	case errors.Is(err, apperror.ErrReferralRequired), errors.Is(err, apperror.ErrPolicyNotValid):
+		response.Error(w, http.StatusForbidden, err.Error(), "")
+	case errors.Is(err, apperror.ErrDoctorNotAvailable), errors.Is(err, apperror.ErrSlotTaken), errors.Is(err, apperror.ErrNoOfficeHours):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
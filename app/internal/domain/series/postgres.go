@@ -0,0 +1,272 @@
+package series
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &SeriesStorage{}
+
+/// Структура SeriesStorage содержащая поля для работы с БД \\\
+
+type SeriesStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр SeriesStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &SeriesStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция Create для сущности SeriesStorage создает серию и все ее записи на прием в одной транзакции \\\
+/// Если время хотя бы одного занятия занято, ничего не создается и возвращается ConflictError \\\
+
+func (s *SeriesStorage) Create(series *Series, records []record.Record, slot time.Duration) (*Series, error) {
+	s.logger.Info("POSTGRES: CREATE RECORD SERIES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create record series transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Повторная проверка занятости внутри транзакции под блокировкой докторов \\\
+	doctorIds := make([]int64, 0, len(records))
+	for _, r := range records {
+		doctorIds = append(doctorIds, r.DoctorID)
+	}
+	if err = record.LockDoctors(ctx, tx, doctorIds...); err != nil {
+		return nil, err
+	}
+	conflicts := make([]time.Time, 0)
+	for _, r := range records {
+		taken, err := record.SlotTaken(ctx, tx, r.DoctorID, r.TimeRecord, slot)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			conflicts = append(conflicts, r.TimeRecord)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Dates: conflicts}
+	}
+
+	/// Создание серии \\\
+	rule := series.Recurrence
+	err = tx.QueryRow(ctx,
+		`INSERT INTO record_series (patients_id, doctor_id, specialization_id, frequency, interval, weekdays, start_date, count, until)
+			 VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9)
+			 RETURNING id, created_at`,
+		series.PatientsID, series.DoctorID, series.SpecializationID,
+		rule.Frequency, rule.Interval, rule.Weekdays, rule.StartDate, rule.Count, rule.Until,
+	).Scan(&series.ID, &series.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create record series query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+
+	/// Создание записей на прием и занятий серии \\\
+	series.Occurrences = make([]Occurrence, 0, len(records))
+	for i, r := range records {
+		var recordId int64
+		err = tx.QueryRow(ctx,
+			`INSERT INTO record (hospital_address, doctor_office, tagging, patients_id, doctor_id, specialization_id, time_record, office_id)
+				 VALUES($1,$2,$3,$4,$5,$6,$7,$8)
+				 RETURNING id`,
+			r.HospitalAddress, r.DoctorOffice, r.Tagging, r.PatientsID, r.DoctorID, r.SpecializationID, r.TimeRecord, r.OfficeID,
+		).Scan(&recordId)
+		if err != nil {
+			err = fmt.Errorf("failed to create record of the series: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO record_series_occurrence (series_id, number, record_id, time_record)
+				 VALUES($1,$2,$3,$4)`,
+			series.ID, i+1, recordId, r.TimeRecord)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create occurrence of the series: %v", err)
+		}
+		r.ID = recordId
+		if err = outbox.Write(ctx, tx, outbox.RecordCreated, recordId, r.EventPayload()); err != nil {
+			return nil, err
+		}
+		doctorId := r.DoctorID
+		series.Occurrences = append(series.Occurrences, Occurrence{
+			Number:     i + 1,
+			RecordID:   &recordId,
+			DoctorID:   &doctorId,
+			TimeRecord: r.TimeRecord,
+			Status:     OccurrenceBooked,
+		})
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create record series transaction: %v", err)
+	}
+	return series, nil
+}
+
+/// Функция FindById для сущности SeriesStorage получает серию вместе с занятиями \\\
+/// Время занятия берется из записи на прием, у отмененного занятия остается запланированное время \\\
+
+func (s *SeriesStorage) FindById(id int64) (*Series, error) {
+	s.logger.Info("POSTGRES: GET RECORD SERIES BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	series := &Series{}
+	rule := &series.Recurrence
+	err := s.conn.QueryRow(ctx,
+		`SELECT id, patients_id, doctor_id, specialization_id, frequency, interval, weekdays, start_date, count, until, created_at
+			 FROM record_series WHERE id = $1`, id,
+	).Scan(&series.ID, &series.PatientsID, &series.DoctorID, &series.SpecializationID,
+		&rule.Frequency, &rule.Interval, &rule.Weekdays, &rule.StartDate, &rule.Count, &rule.Until, &series.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find record series by id query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+
+	rows, err := s.conn.Query(ctx,
+		`SELECT o.number, r.id, r.doctor_id, coalesce(r.time_record, o.time_record)
+			 FROM record_series_occurrence o
+			 LEFT JOIN record r ON r.id = o.record_id
+			 WHERE o.series_id = $1
+			 ORDER BY o.number`, id)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения занятий серии \\\
+	series.Occurrences = make([]Occurrence, 0)
+	for rows.Next() {
+		var occurrence Occurrence
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&occurrence.Number, &occurrence.RecordID, &occurrence.DoctorID, &occurrence.TimeRecord)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find series occurrences query: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		occurrence.Status = OccurrenceBooked
+		if occurrence.RecordID == nil {
+			occurrence.Status = OccurrenceCancelled
+		}
+		series.Occurrences = append(series.Occurrences, occurrence)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+/// Функция Reschedule для сущности SeriesStorage переносит несколько записей серии в одной транзакции \\\
+/// Каждый перенос сохраняется в истории переносов записи, при пересечении с другими записями возвращается ConflictError \\\
+
+func (s *SeriesStorage) Reschedule(moves []Move, slot time.Duration, reason *string) error {
+	s.logger.Info("POSTGRES: RESCHEDULE RECORD SERIES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin reschedule record series transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Блокировка докторов, к которым переносятся записи, до проверки занятости \\\
+	doctorIds := make([]int64, 0, len(moves))
+	for _, m := range moves {
+		doctorIds = append(doctorIds, m.DoctorID)
+	}
+	if err = record.LockDoctors(ctx, tx, doctorIds...); err != nil {
+		return err
+	}
+
+	/// Перенос записей и сохранение истории \\\
+	moved := make([]int64, 0, len(moves))
+	for _, m := range moves {
+		var updated record.Record
+		err := tx.QueryRow(ctx,
+			`UPDATE record SET doctor_id = $1, time_record = $2, office_id = $3, hospital_address = $4, doctor_office = $5
+				 WHERE id = $6 AND doctor_id = $7 AND time_record = $8
+				 RETURNING `+record.Columns,
+			m.DoctorID, m.TimeRecord, m.OfficeID, m.HospitalAddress, m.DoctorOffice, m.RecordID, m.PreviousDoctorID, m.PreviousTime,
+		).Scan(&updated.ID, &updated.HospitalAddress, &updated.DoctorOffice, &updated.Tagging,
+			&updated.PatientsID, &updated.DoctorID, &updated.SpecializationID, &updated.TimeRecord, &updated.OfficeID, &updated.ReferralID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return apperror.ErrEmptyString
+			}
+			return fmt.Errorf("failed to reschedule record of the series: %v", err)
+		}
+		_, err = tx.Exec(ctx,
+			`INSERT INTO record_reschedule (record_id, previous_doctor_id, previous_time, doctor_id, time_record, reason)
+				 VALUES($1,$2,$3,$4,$5,$6)`,
+			m.RecordID, m.PreviousDoctorID, m.PreviousTime, m.DoctorID, m.TimeRecord, reason)
+		if err != nil {
+			return fmt.Errorf("failed to save reschedule of the series record: %v", err)
+		}
+		payload := updated.EventPayload()
+		payload.PreviousDoctorID, payload.PreviousTime, payload.Reason = &m.PreviousDoctorID, &m.PreviousTime, reason
+		if err = outbox.Write(ctx, tx, outbox.RecordRescheduled, m.RecordID, payload); err != nil {
+			return err
+		}
+		moved = append(moved, m.RecordID)
+	}
+
+	/// Проверка что перенесенные записи не пересекаются с другими записями докторов \\\
+	conflicts := make([]time.Time, 0)
+	for _, m := range moves {
+		taken, err := record.SlotTaken(ctx, tx, m.DoctorID, m.TimeRecord, slot, moved...)
+		if err != nil {
+			return err
+		}
+		if taken {
+			conflicts = append(conflicts, m.TimeRecord)
+		}
+	}
+	if len(conflicts) > 0 {
+		return &ConflictError{Dates: conflicts}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit reschedule record series transaction: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package series
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"fmt"
+	"strings"
+	"time"
+)
+
+/// Частота повторения серии записей \\\
+
+const (
+	FrequencyDaily  = "daily"
+	FrequencyWeekly = "weekly"
+)
+
+/// Область отмены и переноса: одно занятие или все оставшиеся начиная с указанного \\\
+
+const (
+	ScopeOne       = "one"
+	ScopeRemaining = "remaining"
+)
+
+/// Статусы занятия серии \\\
+
+const (
+	OccurrenceBooked    = "booked"
+	OccurrenceCancelled = "cancelled"
+)
+
+/// Ограничение перебора дней при развертывании правила повторения \\\
+
+const maxRecurrenceDays = 366 * 5
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+/// Структура правила повторения: время первого занятия задает и время приема всех остальных \\\
+/// Например weekly, interval 1, weekdays [tuesday], count 8 - каждый вторник, 8 занятий \\\
+
+type Recurrence struct {
+	Frequency string     `json:"frequency" example:"weekly"`
+	Interval  int        `json:"interval,omitempty" example:"1"`
+	Weekdays  []string   `json:"weekdays,omitempty" example:"tuesday"`
+	StartDate time.Time  `json:"start_date" example:"2023-08-01T10:00:00+03:00"`
+	Count     int        `json:"count,omitempty" example:"8"`
+	Until     *time.Time `json:"until,omitempty" example:"2023-09-30T00:00:00+03:00"`
+}
+
+/// Структура серии записей на прием одного пациента к одному доктору \\\
+
+type Series struct {
+	ID               int64        `json:"id" example:"1"`
+	PatientsID       int64        `json:"patients_id" example:"1"`
+	DoctorID         int64        `json:"doctor_id" example:"1"`
+	SpecializationID int64        `json:"specialization_id" example:"1"`
+	Recurrence       Recurrence   `json:"recurrence"`
+	Occurrences      []Occurrence `json:"occurrences"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
+
+/// Структура занятия серии: номер, запись на прием и время, отмененное занятие не имеет записи \\\
+
+type Occurrence struct {
+	Number     int       `json:"number" example:"1"`
+	RecordID   *int64    `json:"record_id,omitempty" example:"1567"`
+	DoctorID   *int64    `json:"doctor_id,omitempty" example:"1"`
+	TimeRecord time.Time `json:"time_record" example:"2023-08-01T10:00:00+03:00"`
+	Status     string    `json:"status" example:"booked"`
+}
+
+/// Структура переноса одной записи серии \\\
+
+type Move struct {
+	RecordID         int64
+	PreviousDoctorID int64
+	PreviousTime     time.Time
+	DoctorID         int64
+	TimeRecord       time.Time
+	OfficeID         int64
+	HospitalAddress  string
+	DoctorOffice     string
+}
+
+type CreateSeriesDTO struct {
+	Tagging          string     `json:"tagging" example:"Vzyat s soboy polotence"`
+	PatientsID       int64      `json:"patients_id" example:"1"`
+	DoctorID         int64      `json:"doctor_id" example:"1"`
+	SpecializationID int64      `json:"specialization_id" example:"1"`
+	OfficeID         *int64     `json:"office_id,omitempty" example:"1"`
+	Recurrence       Recurrence `json:"recurrence"`
+}
+
+type CancelSeriesDTO struct {
+	ID         int64
+	Scope      string
+	Occurrence int
+}
+
+/// Структура переноса занятия или оставшихся занятий серии: все они сдвигаются на столько же, на сколько занятие Occurrence \\\
+
+type RescheduleSeriesDTO struct {
+	ID         int64     `json:"-"`
+	Scope      string    `json:"scope" example:"remaining"`
+	Occurrence int       `json:"occurrence" example:"3"`
+	DoctorID   *int64    `json:"doctor_id,omitempty" example:"1"`
+	TimeRecord time.Time `json:"time_record" example:"2023-08-16T11:00:00+03:00"`
+	Reason     *string   `json:"reason,omitempty" example:"Smena grafika raboty"`
+}
+
+/// Структура ответа на конфликт: время занятий, которые нельзя записать \\\
+
+type ConflictResponse struct {
+	Message   string      `json:"message" example:"this time slot is already booked"`
+	Conflicts []time.Time `json:"conflicts"`
+}
+
+/// Ошибка ConflictError перечисляет занятия серии, время которых занято \\\
+
+type ConflictError struct {
+	Dates []time.Time
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: %d occurrences conflict", apperror.ErrSlotTaken, len(e.Dates))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return apperror.ErrSlotTaken
+}
+
+/// Функция Expand разворачивает правило повторения во время занятий, занятий не может быть больше max \\\
+
+func (r *Recurrence) Expand(max int) ([]time.Time, error) {
+	if r.Interval == 0 {
+		r.Interval = 1
+	}
+	if r.StartDate.IsZero() || r.Interval < 1 {
+		return nil, fmt.Errorf("%w: start date and a positive interval are required", apperror.ErrInvalidRecurrence)
+	}
+	if (r.Count > 0) == (r.Until != nil) || r.Count < 0 {
+		return nil, fmt.Errorf("%w: exactly one of count and until is required", apperror.ErrInvalidRecurrence)
+	}
+	if r.Count > max {
+		return nil, fmt.Errorf("%w: at most %d occurrences are allowed", apperror.ErrInvalidRecurrence, max)
+	}
+
+	/// Дни недели еженедельной серии, по умолчанию день недели первого занятия \\\
+	days := make(map[time.Weekday]bool)
+	switch r.Frequency {
+	case FrequencyDaily:
+		if len(r.Weekdays) > 0 {
+			return nil, fmt.Errorf("%w: weekdays are allowed only for weekly series", apperror.ErrInvalidRecurrence)
+		}
+	case FrequencyWeekly:
+		for _, name := range r.Weekdays {
+			day, ok := weekdays[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown weekday %q", apperror.ErrInvalidRecurrence, name)
+			}
+			days[day] = true
+		}
+		if len(days) == 0 {
+			days[r.StartDate.Weekday()] = true
+		}
+	default:
+		return nil, fmt.Errorf("%w: frequency must be daily or weekly", apperror.ErrInvalidRecurrence)
+	}
+
+	/// Смещение первого занятия от понедельника, недели интервала отсчитываются от недели первого занятия \\\
+	offset := (int(r.StartDate.Weekday()) + 6) % 7
+
+	occurrences := make([]time.Time, 0)
+	for i := 0; i < maxRecurrenceDays; i++ {
+		day := r.StartDate.AddDate(0, 0, i)
+		if r.Until != nil && day.After(*r.Until) {
+			break
+		}
+		if r.Count > 0 && len(occurrences) == r.Count {
+			break
+		}
+
+		switch r.Frequency {
+		case FrequencyDaily:
+			if i%r.Interval != 0 {
+				continue
+			}
+		case FrequencyWeekly:
+			week := (i + offset) / 7
+			if week%r.Interval != 0 || !days[day.Weekday()] {
+				continue
+			}
+		}
+		if len(occurrences) == max {
+			return nil, fmt.Errorf("%w: at most %d occurrences are allowed", apperror.ErrInvalidRecurrence, max)
+		}
+		occurrences = append(occurrences, day)
+	}
+	if len(occurrences) == 0 || (r.Count > 0 && len(occurrences) < r.Count) {
+		return nil, fmt.Errorf("%w: the rule produces no occurrences", apperror.ErrInvalidRecurrence)
+	}
+	return occurrences, nil
+}
@@ -0,0 +1,340 @@
+package series
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для работы с сериями записей на прием \\\
+
+type Service interface {
+	Create(ctx context.Context, input *CreateSeriesDTO) (*Series, error)
+	GetById(ctx context.Context, id int64) (*Series, error)
+	Cancel(ctx context.Context, input *CancelSeriesDTO) error
+	Reschedule(ctx context.Context, input *RescheduleSeriesDTO) (*Series, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service серий записей \\\
+
+type service struct {
+	logger         logger.Logger
+	storage        Storage
+	doc            doctor.Storage
+	records        record.Service
+	waitlist       record.Waitlist
+	slot           time.Duration
+	maxOccurrences int
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, records record.Service, waitlist record.Waitlist, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	return &service{
+		logger:         logger,
+		storage:        storage,
+		doc:            doc,
+		records:        records,
+		waitlist:       waitlist,
+		slot:           time.Duration(cfg.Records.SlotMinutes) * time.Minute,
+		maxOccurrences: cfg.Records.MaxSeriesOccurrences,
+	}
+}
+
+/// Функция Create записывает пациента на все занятия серии сразу \\\
+/// Если время хотя бы одного занятия занято, серия не создается и возвращается ConflictError со списком занятых дат \\\
+
+func (s *service) Create(ctx context.Context, input *CreateSeriesDTO) (*Series, error) {
+	s.logger.Info("SERVICE: CREATE RECORD SERIES")
+
+	/// Развертывание правила повторения \\\
+	occurrences, err := input.Recurrence.Expand(s.maxOccurrences)
+	if err != nil {
+		return nil, err
+	}
+	if !occurrences[0].After(time.Now()) {
+		return nil, apperror.ErrInvalidRecurrence
+	}
+
+	/// Проверка что доктор ведет запись и прием по специализации серии \\\
+	checkDoctor, err := s.doc.FindById(input.DoctorID)
+	if err != nil {
+		return nil, err
+	}
+	if !checkDoctor.RecordingIsAvailable {
+		return nil, apperror.ErrDoctorNotAvailable
+	}
+	if input.SpecializationID == 0 {
+		input.SpecializationID = checkDoctor.SpecializationID
+	}
+	if !checkDoctor.HasSpecialization(input.SpecializationID) {
+		return nil, apperror.ErrWrongSpecialization
+	}
+
+	/// Одно направление погашается одной записью, поэтому серия по такой специализации не создается \\\
+	if s.records.RequiresReferral(input.SpecializationID) {
+		return nil, apperror.ErrReferralRequired
+	}
+
+	/// Полис пациента должен действовать на весь срок серии, от первого до последнего занятия \\\
+	if err = s.records.CheckPolicy(input.PatientsID, occurrences[0]); err != nil {
+		return nil, err
+	}
+	if err = s.records.CheckPolicy(input.PatientsID, occurrences[len(occurrences)-1]); err != nil {
+		return nil, err
+	}
+
+	/// Проверка всех занятий, чтобы сообщить обо всех занятых датах сразу \\\
+	conflicts := make([]time.Time, 0)
+	records := make([]record.Record, 0, len(occurrences))
+	for _, at := range occurrences {
+		err = s.records.CheckSlot(ctx, input.DoctorID, at)
+		if errors.Is(err, apperror.ErrSlotTaken) {
+			conflicts = append(conflicts, at)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		r := record.Record{
+			Tagging:          input.Tagging,
+			PatientsID:       input.PatientsID,
+			DoctorID:         input.DoctorID,
+			SpecializationID: input.SpecializationID,
+			TimeRecord:       at,
+			OfficeID:         input.OfficeID,
+		}
+
+		/// Кабинет каждого занятия берется из расписания доктора на его день \\\
+		if err = s.records.ResolveOffice(ctx, &r); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Dates: conflicts}
+	}
+
+	/// Вызов функции Create в хранилище серий \\\
+	series := Series{
+		PatientsID:       input.PatientsID,
+		DoctorID:         input.DoctorID,
+		SpecializationID: input.SpecializationID,
+		Recurrence:       input.Recurrence,
+	}
+	return s.storage.Create(&series, records, s.slot)
+}
+
+/// Функция GetById возвращает серию записей с занятиями по id \\\
+
+func (s *service) GetById(ctx context.Context, id int64) (*Series, error) {
+	s.logger.Info("SERVICE: GET RECORD SERIES BY ID")
+
+	series, err := s.storage.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("cannot find record series by id: %v", err)
+		}
+		return nil, err
+	}
+	return series, nil
+}
+
+/// Функция Cancel отменяет одно занятие серии или все оставшиеся будущие занятия начиная с указанного \\\
+/// Записи удаляются через сервис записей, поэтому освободившееся время предлагается листу ожидания \\\
+
+func (s *service) Cancel(ctx context.Context, input *CancelSeriesDTO) error {
+	s.logger.Info("SERVICE: CANCEL RECORD SERIES")
+
+	series, err := s.storage.FindById(input.ID)
+	if err != nil {
+		return err
+	}
+	targets, err := s.targets(series, input.Scope, input.Occurrence)
+	if err != nil {
+		return err
+	}
+
+	/// Вызов функции Delete в сервисе записей для каждого занятия \\\
+	for _, occurrence := range targets {
+		err = s.records.Delete(*occurrence.RecordID)
+		if err != nil && !errors.Is(err, apperror.ErrEmptyString) {
+			return err
+		}
+	}
+	return nil
+}
+
+/// Функция Reschedule переносит одно занятие серии или все оставшиеся занятия начиная с указанного \\\
+/// Оставшиеся занятия сдвигаются на столько же, на сколько занятие Occurrence, и переносятся вместе или не переносятся вовсе \\\
+
+func (s *service) Reschedule(ctx context.Context, input *RescheduleSeriesDTO) (*Series, error) {
+	s.logger.Info("SERVICE: RESCHEDULE RECORD SERIES")
+
+	series, err := s.storage.FindById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Scope == ScopeRemaining && input.Occurrence < 1 {
+		return nil, apperror.ErrInvalidSeriesScope
+	}
+	targets, err := s.targets(series, input.Scope, input.Occurrence)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 || targets[0].Number != input.Occurrence {
+		return nil, apperror.ErrEmptyString
+	}
+
+	/// Одно занятие переносится как обычная запись на прием \\\
+	if input.Scope == ScopeOne {
+		_, err = s.records.Reschedule(ctx, &record.RescheduleRecordDTO{
+			ID:         *targets[0].RecordID,
+			DoctorID:   input.DoctorID,
+			TimeRecord: input.TimeRecord,
+			Reason:     input.Reason,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return s.storage.FindById(input.ID)
+	}
+
+	/// Сдвиг оставшихся занятий относительно занятия Occurrence \\\
+	shift := input.TimeRecord.Sub(targets[0].TimeRecord)
+	if shift == 0 && (input.DoctorID == nil || *input.DoctorID == *targets[0].DoctorID) {
+		return nil, apperror.ErrInvalidReschedule
+	}
+	if input.DoctorID != nil {
+		checkDoctor, err := s.doc.FindById(*input.DoctorID)
+		if err != nil {
+			return nil, err
+		}
+		if !checkDoctor.RecordingIsAvailable {
+			return nil, apperror.ErrDoctorNotAvailable
+		}
+		if !checkDoctor.HasSpecialization(series.SpecializationID) {
+			return nil, apperror.ErrWrongSpecialization
+		}
+	}
+
+	/// Проверка правил переноса для каждой записи \\\
+	moves := make([]Move, 0, len(targets))
+	moved := make([]int64, 0, len(targets))
+	for _, occurrence := range targets {
+		current, err := s.records.GetById(ctx, *occurrence.RecordID)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.records.CanReschedule(ctx, current); err != nil {
+			return nil, err
+		}
+		move := Move{
+			RecordID:         current.ID,
+			PreviousDoctorID: current.DoctorID,
+			PreviousTime:     current.TimeRecord,
+			DoctorID:         current.DoctorID,
+			TimeRecord:       current.TimeRecord.Add(shift),
+		}
+		if input.DoctorID != nil {
+			move.DoctorID = *input.DoctorID
+		}
+		if !move.TimeRecord.After(time.Now()) {
+			return nil, apperror.ErrInvalidReschedule
+		}
+		if err = s.records.CheckPolicy(current.PatientsID, move.TimeRecord); err != nil {
+			return nil, err
+		}
+
+		/// Кабинет берется из расписания доктора на новое время, как при переносе одной записи \\\
+		located := record.Record{DoctorID: move.DoctorID, TimeRecord: move.TimeRecord}
+		if err = s.records.ResolveOffice(ctx, &located); err != nil {
+			return nil, err
+		}
+		move.OfficeID, move.HospitalAddress, move.DoctorOffice = *located.OfficeID, located.HospitalAddress, located.DoctorOffice
+		moves = append(moves, move)
+		moved = append(moved, current.ID)
+	}
+
+	/// Проверка всех новых времен, чтобы сообщить обо всех занятых датах сразу \\\
+	conflicts := make([]time.Time, 0)
+	for _, move := range moves {
+		err = s.records.CheckSlot(ctx, move.DoctorID, move.TimeRecord, moved...)
+		if errors.Is(err, apperror.ErrSlotTaken) {
+			conflicts = append(conflicts, move.TimeRecord)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &ConflictError{Dates: conflicts}
+	}
+
+	/// Вызов функции Reschedule в хранилище серий \\\
+	if err = s.storage.Reschedule(moves, s.slot, input.Reason); err != nil {
+		var conflict *ConflictError
+		if !errors.Is(err, apperror.ErrEmptyString) && !errors.As(err, &conflict) {
+			s.logger.Errorf("failed to reschedule record series: %v", err)
+		}
+		return nil, err
+	}
+
+	/// Прежнее время занятий, которое не заняли перенесенные занятия, предлагается пациентам из листа ожидания \\\
+	if s.waitlist != nil {
+		for _, move := range moves {
+			if s.occupied(moves, move.PreviousDoctorID, move.PreviousTime) {
+				continue
+			}
+			s.waitlist.SlotReleased(record.Slot{
+				DoctorID:         move.PreviousDoctorID,
+				SpecializationID: series.SpecializationID,
+				TimeRecord:       move.PreviousTime,
+			})
+		}
+	}
+	return s.storage.FindById(input.ID)
+}
+
+/// Функция targets выбирает занятия серии для отмены или переноса \\\
+/// ScopeOne - только занятие number, ScopeRemaining - все будущие занятия начиная с number \\\
+
+func (s *service) targets(series *Series, scope string, number int) ([]Occurrence, error) {
+	targets := make([]Occurrence, 0)
+	now := time.Now()
+	switch scope {
+	case ScopeOne:
+		for _, occurrence := range series.Occurrences {
+			if occurrence.Number == number && occurrence.Status == OccurrenceBooked {
+				return append(targets, occurrence), nil
+			}
+		}
+		return nil, apperror.ErrEmptyString
+	case ScopeRemaining:
+		for _, occurrence := range series.Occurrences {
+			if occurrence.Number >= number && occurrence.Status == OccurrenceBooked && occurrence.TimeRecord.After(now) {
+				targets = append(targets, occurrence)
+			}
+		}
+		return targets, nil
+	default:
+		return nil, apperror.ErrInvalidSeriesScope
+	}
+}
+
+/// Функция occupied проверяет, занимает ли одно из перенесенных занятий время at у доктора \\\
+
+func (s *service) occupied(moves []Move, doctorId int64, at time.Time) bool {
+	for _, move := range moves {
+		if move.DoctorID == doctorId && move.TimeRecord.Sub(at) < s.slot && at.Sub(move.TimeRecord) < s.slot {
+			return true
+		}
+	}
+	return false
+}
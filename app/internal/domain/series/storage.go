@@ -0,0 +1,12 @@
+package series
+
+import (
+	"HospitalRecord/app/internal/domain/record"
+	"time"
+)
+
+type Storage interface {
+	Create(series *Series, records []record.Record, slot time.Duration) (*Series, error)
+	FindById(id int64) (*Series, error)
+	Reschedule(moves []Move, slot time.Duration, reason *string) error
+}
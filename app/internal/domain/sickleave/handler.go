@@ -0,0 +1,217 @@
+package sickleave
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	sickLeavesURL       = "/hospital_record/sick_leaves"
+	sickLeaveURL        = "/hospital_record/sick_leaves/:id"
+	extendSickLeaveURL  = "/hospital_record/sick_leaves/:id/extend"
+	closeSickLeaveURL   = "/hospital_record/sick_leaves/:id/close"
+	printSickLeaveURL   = "/hospital_record/sick_leaves/:id/print"
+	doctorSickLeavesURL = "/hospital_record/doctors/sick_leaves/:id"
+	printContentType    = "text/html; charset=utf-8"
+)
+
+/// Структура Handler представляющая собой обработчик объекта sickLeaveService для листков нетрудоспособности \\\
+
+type Handler struct {
+	logger           logger.Logger
+	sickLeaveService Service
+	staff            handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, sickLeaveService Service, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:           logger,
+		sickLeaveService: sickLeaveService,
+		staff:            staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для листков нетрудоспособности \\\
+/// Листки выдают, продлевают, закрывают и печатают только сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, sickLeavesURL, h.staff(h.IssueSickLeave))
+	router.HandlerFunc(http.MethodGet, sickLeaveURL, h.staff(h.GetSickLeave))
+	router.HandlerFunc(http.MethodPost, extendSickLeaveURL, h.staff(h.ExtendSickLeave))
+	router.HandlerFunc(http.MethodPost, closeSickLeaveURL, h.staff(h.CloseSickLeave))
+	router.HandlerFunc(http.MethodGet, printSickLeaveURL, h.staff(h.PrintSickLeave))
+	router.HandlerFunc(http.MethodGet, doctorSickLeavesURL, h.staff(h.GetDoctorSickLeaves))
+}
+
+/// Функция IssueSickLeave выдает листок нетрудоспособности по приему \\\
+
+func (h *Handler) IssueSickLeave(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ISSUE SICK LEAVE")
+
+	/// Чтение тела запроса в структуру IssueDTO \\\
+	var input IssueDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	certificate, err := h.sickLeaveService.Issue(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("SICK LEAVE CREATED")
+	response.JSON(w, http.StatusCreated, certificate)
+}
+
+/// Функция GetSickLeave получает листок по id вместе с периодами \\\
+
+func (h *Handler) GetSickLeave(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET SICK LEAVE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	certificate, err := h.sickLeaveService.GetCertificate(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT SICK LEAVE")
+	response.JSON(w, http.StatusOK, certificate)
+}
+
+/// Функция ExtendSickLeave продлевает открытый листок \\\
+
+func (h *Handler) ExtendSickLeave(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: EXTEND SICK LEAVE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру ExtendDTO \\\
+	var input ExtendDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	certificate, err := h.sickLeaveService.Extend(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("SICK LEAVE EXTENDED")
+	response.JSON(w, http.StatusOK, certificate)
+}
+
+/// Функция CloseSickLeave закрывает открытый листок \\\
+
+func (h *Handler) CloseSickLeave(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CLOSE SICK LEAVE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру CloseDTO \\\
+	var input CloseDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	certificate, err := h.sickLeaveService.Close(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("SICK LEAVE CLOSED")
+	response.JSON(w, http.StatusOK, certificate)
+}
+
+/// Функция PrintSickLeave отдает печатную форму листка \\\
+
+func (h *Handler) PrintSickLeave(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: PRINT SICK LEAVE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	body, err := h.sickLeaveService.Print(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.writePrint(w, body)
+}
+
+/// Функция GetDoctorSickLeaves получает открытые листки лечащего доктора \\\
+
+func (h *Handler) GetDoctorSickLeaves(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DOCTOR SICK LEAVES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	certificates, err := h.sickLeaveService.GetOpenByDoctor(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT DOCTOR SICK LEAVES")
+	response.JSON(w, http.StatusOK, certificates)
+}
+
+/// Функция writePrint отдает печатную форму в HTML \\\
+
+func (h *Handler) writePrint(w http.ResponseWriter, body []byte) {
+	w.Header().Set("Content-Type", printContentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		h.logger.Warnf("failed to write sick leave: %v", err)
+	}
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidSickLeave):
+		response.BadRequest(w, err.Error(), "")
+	case errors.Is(err, apperror.ErrSickLeaveState):
+		response.Error(w, http.StatusConflict, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
@@ -0,0 +1,332 @@
+package sickleave
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &SickLeaveStorage{}
+
+/// Структура SickLeaveStorage содержащая поля для работы с БД \\\
+
+type SickLeaveStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр SickLeaveStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &SickLeaveStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция Issue для сущности SickLeaveStorage выдает листок со следующим номером реестра года и его первым периодом \\\
+
+func (s *SickLeaveStorage) Issue(certificate *Certificate, prefix string, year int) (*Certificate, error) {
+	s.logger.Info("POSTGRES: ISSUE SICK LEAVE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin issue sick leave transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Счетчик реестра блокируется строкой, поэтому номера не повторяются при одновременной выдаче \\\
+	var number int
+	err = tx.QueryRow(ctx,
+		`INSERT INTO sick_leave_counter (year, last_number)
+			 VALUES($1,1)
+			 ON CONFLICT (year)
+			 DO UPDATE SET last_number = sick_leave_counter.last_number + 1
+			 RETURNING last_number`, year).Scan(&number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to increment sick leave counter: %v", err)
+	}
+	certificate.Number = fmt.Sprintf("%s-%d-%06d", prefix, year, number)
+
+	/// Выполнение запроса к БД \\\
+	created, err := scanCertificate(tx.QueryRow(ctx,
+		`INSERT INTO sick_leave (number, patient_id, doctor_id, record_id, diagnosis, start_date, end_date, status)
+			 VALUES($1,$2,$3,$4,$5,$6,$7,$8)
+			 RETURNING *`,
+		certificate.Number, certificate.PatientID, certificate.DoctorID, certificate.RecordID, certificate.Diagnosis,
+		certificate.StartDate, certificate.EndDate, StatusOpen))
+	if err != nil {
+		err = fmt.Errorf("failed to execute issue sick leave query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	period, err := insertPeriod(ctx, tx, created.ID, created.DoctorID, created.StartDate, created.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	created.Periods = []Period{*period}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit issue sick leave transaction: %v", err)
+	}
+	return created, nil
+}
+
+/// Функция FindById для сущности SickLeaveStorage получает листок по id \\\
+
+func (s *SickLeaveStorage) FindById(id int64) (*Certificate, error) {
+	s.logger.Info("POSTGRES: GET SICK LEAVE BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	certificate, err := scanCertificate(s.conn.QueryRow(ctx,
+		`SELECT * FROM sick_leave
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find sick leave by id query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	return certificate, nil
+}
+
+/// Функция FindPeriods для сущности SickLeaveStorage получает периоды листка по порядку \\\
+
+func (s *SickLeaveStorage) FindPeriods(certificateId int64) ([]Period, error) {
+	s.logger.Info("POSTGRES: GET SICK LEAVE PERIODS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := s.conn.Query(ctx,
+		`SELECT * FROM sick_leave_period
+			 WHERE certificate_id = $1
+			 ORDER BY start_date, id`, certificateId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения периодов \\\
+	periods := make([]Period, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		period, err := scanPeriod(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find sick leave periods query: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		periods = append(periods, *period)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+/// Функция FindOpenByDoctor для сущности SickLeaveStorage получает открытые листки лечащего доктора, ближайшие к окончанию первыми \\\
+
+func (s *SickLeaveStorage) FindOpenByDoctor(doctorId int64) ([]Certificate, error) {
+	s.logger.Info("POSTGRES: GET OPEN SICK LEAVES OF DOCTOR")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := s.conn.Query(ctx,
+		`SELECT * FROM sick_leave
+			 WHERE doctor_id = $1 AND status = $2
+			 ORDER BY end_date, id`, doctorId, StatusOpen)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения листков \\\
+	certificates := make([]Certificate, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		certificate, err := scanCertificate(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find open sick leaves query: %v", err)
+			s.logger.Error(err)
+			return nil, err
+		}
+		certificates = append(certificates, *certificate)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return certificates, nil
+}
+
+/// Функция Extend для сущности SickLeaveStorage продлевает открытый листок новым периодом со следующего дня \\\
+/// Продливший доктор становится лечащим доктором листка \\\
+
+func (s *SickLeaveStorage) Extend(input *ExtendDTO, doctorId int64) (*Certificate, error) {
+	s.logger.Info("POSTGRES: EXTEND SICK LEAVE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin extend sick leave transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockCertificate(ctx, tx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !input.EndDate.After(current.EndDate) {
+		return nil, apperror.ErrInvalidSickLeave
+	}
+
+	/// Выполнение запроса к БД \\\
+	certificate, err := scanCertificate(tx.QueryRow(ctx,
+		`UPDATE sick_leave SET end_date = $1, doctor_id = $2
+			 WHERE id = $3
+			 RETURNING *`, input.EndDate, doctorId, input.ID))
+	if err != nil {
+		err = fmt.Errorf("failed to execute extend sick leave query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+	if _, err = insertPeriod(ctx, tx, certificate.ID, doctorId, current.EndDate.AddDate(0, 0, 1), input.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit extend sick leave transaction: %v", err)
+	}
+	return certificate, nil
+}
+
+/// Функция Close для сущности SickLeaveStorage закрывает открытый листок с исходом и датой выхода на работу \\\
+
+func (s *SickLeaveStorage) Close(input *CloseDTO) (*Certificate, error) {
+	s.logger.Info("POSTGRES: CLOSE SICK LEAVE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), s.requestTimeout)
+	defer cancel()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin close sick leave transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	current, err := lockCertificate(ctx, tx, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	returnDate := current.EndDate.AddDate(0, 0, 1)
+	if input.ReturnDate != nil {
+		returnDate = *input.ReturnDate
+	}
+	if !returnDate.After(current.StartDate) {
+		return nil, apperror.ErrInvalidSickLeave
+	}
+
+	/// Выполнение запроса к БД \\\
+	certificate, err := scanCertificate(tx.QueryRow(ctx,
+		`UPDATE sick_leave SET status = $1, outcome = $2, return_date = $3, closed_at = now()
+			 WHERE id = $4
+			 RETURNING *`, StatusClosed, input.Outcome, returnDate, input.ID))
+	if err != nil {
+		err = fmt.Errorf("failed to execute close sick leave query: %v", err)
+		s.logger.Error(err)
+		return nil, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit close sick leave transaction: %v", err)
+	}
+	return certificate, nil
+}
+
+/// Функция lockCertificate блокирует листок и проверяет, что он открыт \\\
+
+func lockCertificate(ctx context.Context, tx pgx.Tx, id int64) (*Certificate, error) {
+	certificate, err := scanCertificate(tx.QueryRow(ctx,
+		`SELECT * FROM sick_leave WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to lock sick leave: %v", err)
+	}
+	if certificate.Status != StatusOpen {
+		return nil, apperror.ErrSickLeaveState
+	}
+	return certificate, nil
+}
+
+/// Функция insertPeriod добавляет период освобождения от работы \\\
+
+func insertPeriod(ctx context.Context, tx pgx.Tx, certificateId, doctorId int64, start, end time.Time) (*Period, error) {
+	period, err := scanPeriod(tx.QueryRow(ctx,
+		`INSERT INTO sick_leave_period (certificate_id, doctor_id, start_date, end_date)
+			 VALUES($1,$2,$3,$4)
+			 RETURNING *`, certificateId, doctorId, start, end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert sick leave period: %v", err)
+	}
+	return period, nil
+}
+
+/// Функция scanCertificate сканирует строку таблицы sick_leave \\\
+
+func scanCertificate(row pgx.Row) (*Certificate, error) {
+	certificate := &Certificate{}
+	err := row.Scan(&certificate.ID, &certificate.Number, &certificate.PatientID, &certificate.DoctorID,
+		&certificate.RecordID, &certificate.Diagnosis, &certificate.StartDate, &certificate.EndDate,
+		&certificate.Status, &certificate.Outcome, &certificate.ReturnDate, &certificate.ClosedAt, &certificate.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+/// Функция scanPeriod сканирует строку таблицы sick_leave_period \\\
+
+func scanPeriod(row pgx.Row) (*Period, error) {
+	period := &Period{}
+	err := row.Scan(&period.ID, &period.CertificateID, &period.DoctorID, &period.StartDate, &period.EndDate, &period.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return period, nil
+}
@@ -0,0 +1,89 @@
+package sickleave
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+/// Печатная форма листка нетрудоспособности, диагноз в нее не выводится \\\
+
+var printTemplate = template.Must(template.New("sick_leave").Funcs(template.FuncMap{
+	"date":   printDate,
+	"status": printStatus,
+}).Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Листок нетрудоспособности {{.Certificate.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #000; padding: 4px 8px; text-align: left; }
+</style>
+</head>
+<body>
+<h1>Листок нетрудоспособности № {{.Certificate.Number}}</h1>
+<p>Пациент: {{.Patient}}</p>
+<p>Лечащий врач: {{.Doctor}}</p>
+<p>Освобождение от работы: с {{date .Certificate.StartDate}} по {{date .Certificate.EndDate}}</p>
+<table>
+<tr><th>С</th><th>По</th></tr>
+{{range .Certificate.Periods}}<tr><td>{{date .StartDate}}</td><td>{{date .EndDate}}</td></tr>
+{{end}}</table>
+<p>Статус: {{status .Certificate.Status}}</p>
+{{if .Certificate.ReturnDate}}<p>Приступить к работе: {{date .Certificate.ReturnDate}}</p>
+{{end}}<p>Выдан: {{date .Certificate.CreatedAt}}</p>
+</body>
+</html>
+`))
+
+/// Функция render заполняет печатную форму листка \\\
+
+func render(certificate *Certificate, patient, doctor string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := printTemplate.Execute(&buf, struct {
+		Certificate *Certificate
+		Patient     string
+		Doctor      string
+	}{certificate, patient, doctor})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render sick leave: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+/// Функция printDate форматирует дату для печати, принимает time.Time и *time.Time \\\
+
+func printDate(value interface{}) string {
+	switch t := value.(type) {
+	case time.Time:
+		return t.Format("02.01.2006")
+	case *time.Time:
+		if t != nil {
+			return t.Format("02.01.2006")
+		}
+	}
+	return ""
+}
+
+/// Функция printStatus переводит статус листка для печати \\\
+
+func printStatus(status string) string {
+	if status == StatusClosed {
+		return "закрыт"
+	}
+	return "открыт"
+}
+
+/// Функция fullName собирает ФИО из фамилии, имени и отчества \\\
+
+func fullName(surname, name string, patronymic *string) string {
+	parts := []string{surname, name}
+	if patronymic != nil && *patronymic != "" {
+		parts = append(parts, *patronymic)
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,201 @@
+package sickleave
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"strings"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для листков нетрудоспособности \\\
+
+type Service interface {
+	Issue(ctx context.Context, input *IssueDTO) (*Certificate, error)
+	GetCertificate(ctx context.Context, id int64) (*Certificate, error)
+	GetOpenByDoctor(ctx context.Context, doctorId int64) (*[]Certificate, error)
+	Extend(ctx context.Context, input *ExtendDTO) (*Certificate, error)
+	Close(ctx context.Context, input *CloseDTO) (*Certificate, error)
+	Print(ctx context.Context, id int64) ([]byte, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service листков нетрудоспособности \\\
+
+type service struct {
+	logger    logger.Logger
+	storage   Storage
+	patients  user.Storage
+	doc       doctor.Storage
+	records   record.Storage
+	prefix    string
+	maxPeriod int
+	location  *time.Location
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, patients user.Storage, records record.Storage, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	s := &service{
+		logger:    logger,
+		storage:   storage,
+		patients:  patients,
+		doc:       doc,
+		records:   records,
+		prefix:    cfg.SickLeaves.NumberPrefix,
+		maxPeriod: cfg.SickLeaves.MaxPeriodDays,
+		location:  time.UTC,
+	}
+	if s.prefix == "" {
+		s.prefix = "LN"
+	}
+	if s.maxPeriod < 1 {
+		s.maxPeriod = 15
+	}
+	if cfg.SickLeaves.TimeZone != "" {
+		location, err := time.LoadLocation(cfg.SickLeaves.TimeZone)
+		if err != nil {
+			logger.Warnf("unknown sick leaves time zone %q, using UTC: %v", cfg.SickLeaves.TimeZone, err)
+		} else {
+			s.location = location
+		}
+	}
+	return s
+}
+
+/// Функция Issue выдает листок по состоявшемуся приему, пациент и доктор берутся из записи на прием \\\
+
+func (s *service) Issue(ctx context.Context, input *IssueDTO) (*Certificate, error) {
+	s.logger.Info("SERVICE: ISSUE SICK LEAVE")
+
+	visit, err := s.records.FindRecordById(input.RecordID)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Проверка входных данных \\\
+	certificate := Certificate{
+		PatientID: visit.PatientsID,
+		DoctorID:  visit.DoctorID,
+		RecordID:  &visit.ID,
+		Diagnosis: strings.TrimSpace(input.Diagnosis),
+		StartDate: s.date(visit.TimeRecord),
+		EndDate:   s.date(input.EndDate),
+	}
+	if input.StartDate != nil {
+		certificate.StartDate = s.date(*input.StartDate)
+	}
+	if visit.TimeRecord.After(time.Now()) || certificate.Diagnosis == "" || !s.validPeriod(certificate.StartDate, certificate.EndDate) {
+		return nil, apperror.ErrInvalidSickLeave
+	}
+	return s.storage.Issue(&certificate, s.prefix, s.date(time.Now()).Year())
+}
+
+/// Функция GetCertificate возвращает листок по id вместе с периодами \\\
+
+func (s *service) GetCertificate(ctx context.Context, id int64) (*Certificate, error) {
+	s.logger.Info("SERVICE: GET SICK LEAVE")
+
+	certificate, err := s.storage.FindById(id)
+	if err != nil {
+		return nil, err
+	}
+	certificate.Periods, err = s.storage.FindPeriods(id)
+	if err != nil {
+		return nil, err
+	}
+	return certificate, nil
+}
+
+/// Функция GetOpenByDoctor возвращает открытые листки лечащего доктора \\\
+
+func (s *service) GetOpenByDoctor(ctx context.Context, doctorId int64) (*[]Certificate, error) {
+	s.logger.Info("SERVICE: GET OPEN SICK LEAVES OF DOCTOR")
+
+	if _, err := s.doc.FindById(doctorId); err != nil {
+		return nil, err
+	}
+	certificates, err := s.storage.FindOpenByDoctor(doctorId)
+	if err != nil {
+		return nil, err
+	}
+	return &certificates, nil
+}
+
+/// Функция Extend продлевает открытый листок, новый период не может быть длиннее допустимого \\\
+
+func (s *service) Extend(ctx context.Context, input *ExtendDTO) (*Certificate, error) {
+	s.logger.Info("SERVICE: EXTEND SICK LEAVE")
+
+	current, err := s.storage.FindById(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	doctorId := current.DoctorID
+	if input.DoctorID != nil {
+		if _, err = s.doc.FindById(*input.DoctorID); err != nil {
+			return nil, err
+		}
+		doctorId = *input.DoctorID
+	}
+
+	/// Проверка входных данных \\\
+	input.EndDate = s.date(input.EndDate)
+	if !s.validPeriod(current.EndDate.AddDate(0, 0, 1), input.EndDate) {
+		return nil, apperror.ErrInvalidSickLeave
+	}
+	return s.storage.Extend(input, doctorId)
+}
+
+/// Функция Close закрывает открытый листок с исходом \\\
+
+func (s *service) Close(ctx context.Context, input *CloseDTO) (*Certificate, error) {
+	s.logger.Info("SERVICE: CLOSE SICK LEAVE")
+
+	/// Проверка входных данных \\\
+	if !validOutcome(input.Outcome) {
+		return nil, apperror.ErrInvalidSickLeave
+	}
+	if input.ReturnDate != nil {
+		returnDate := s.date(*input.ReturnDate)
+		input.ReturnDate = &returnDate
+	}
+	return s.storage.Close(input)
+}
+
+/// Функция Print возвращает печатную форму листка в HTML \\\
+
+func (s *service) Print(ctx context.Context, id int64) ([]byte, error) {
+	s.logger.Info("SERVICE: PRINT SICK LEAVE")
+
+	certificate, err := s.GetCertificate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	patient, err := s.patients.FindById(certificate.PatientID)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := s.doc.FindById(certificate.DoctorID)
+	if err != nil {
+		return nil, err
+	}
+	return render(certificate, fullName(patient.Surname, patient.Name, patient.Patronymic),
+		fullName(doc.Surname, doc.Name, doc.Patronymic))
+}
+
+/// Функция validPeriod проверяет, что период не пуст и не длиннее допустимого \\\
+
+func (s *service) validPeriod(start, end time.Time) bool {
+	return !end.Before(start) && days(start, end) <= s.maxPeriod
+}
+
+/// Функция date возвращает дату t по местному времени, даты листков хранятся без часового пояса \\\
+
+func (s *service) date(t time.Time) time.Time {
+	year, month, day := t.In(s.location).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
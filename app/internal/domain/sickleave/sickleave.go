@@ -0,0 +1,91 @@
+package sickleave
+
+import "time"
+
+/// Статусы листка нетрудоспособности \\\
+
+const (
+	StatusOpen   = "open"
+	StatusClosed = "closed"
+)
+
+/// Исходы закрытия листка: выздоровление, перевод в стационар или другую организацию, инвалидность и прочее \\\
+
+const (
+	OutcomeRecovered   = "recovered"
+	OutcomeTransferred = "transferred"
+	OutcomeDisability  = "disability"
+	OutcomeOther       = "other"
+)
+
+/// Структура листка нетрудоспособности, выданного по приему у доктора \\\
+/// Номер из реестра вида LN-2023-000001 нумеруется заново каждый год, при удалении приема листок сохраняется без него \\\
+
+type Certificate struct {
+	ID         int64      `json:"id" example:"1"`
+	Number     string     `json:"number" example:"LN-2023-000001"`
+	PatientID  int64      `json:"patient_id" example:"1"`
+	DoctorID   int64      `json:"doctor_id" example:"1"`
+	RecordID   *int64     `json:"record_id,omitempty" example:"1567"`
+	Diagnosis  string     `json:"diagnosis" example:"ORVI"`
+	StartDate  time.Time  `json:"start_date" example:"2023-07-27T00:00:00Z"`
+	EndDate    time.Time  `json:"end_date" example:"2023-08-02T00:00:00Z"`
+	Status     string     `json:"status" example:"open"`
+	Outcome    *string    `json:"outcome,omitempty" example:"recovered"`
+	ReturnDate *time.Time `json:"return_date,omitempty" example:"2023-08-03T00:00:00Z"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty" example:"2023-08-02T12:00:00Z"`
+	CreatedAt  time.Time  `json:"created_at" example:"2023-07-27T10:00:00Z"`
+	Periods    []Period   `json:"periods,omitempty"`
+}
+
+/// Структура периода освобождения от работы, продление добавляет период со следующего дня \\\
+
+type Period struct {
+	ID            int64     `json:"id" example:"1"`
+	CertificateID int64     `json:"certificate_id" example:"1"`
+	DoctorID      int64     `json:"doctor_id" example:"1"`
+	StartDate     time.Time `json:"start_date" example:"2023-07-27T00:00:00Z"`
+	EndDate       time.Time `json:"end_date" example:"2023-08-02T00:00:00Z"`
+	CreatedAt     time.Time `json:"created_at" example:"2023-07-27T10:00:00Z"`
+}
+
+/// Без даты начала листок открывается с дня приема \\\
+
+type IssueDTO struct {
+	RecordID  int64      `json:"record_id" example:"1567"`
+	Diagnosis string     `json:"diagnosis" example:"ORVI"`
+	StartDate *time.Time `json:"start_date,omitempty" example:"2023-07-27T00:00:00Z"`
+	EndDate   time.Time  `json:"end_date" example:"2023-08-02T00:00:00Z"`
+}
+
+/// Без доктора листок продлевает лечащий доктор \\\
+
+type ExtendDTO struct {
+	ID       int64     `json:"-"`
+	DoctorID *int64    `json:"doctor_id,omitempty" example:"2"`
+	EndDate  time.Time `json:"end_date" example:"2023-08-09T00:00:00Z"`
+}
+
+/// Без даты выхода на работу пациент выходит на следующий день после окончания листка \\\
+
+type CloseDTO struct {
+	ID         int64      `json:"-"`
+	Outcome    string     `json:"outcome" example:"recovered"`
+	ReturnDate *time.Time `json:"return_date,omitempty" example:"2023-08-03T00:00:00Z"`
+}
+
+/// Функция validOutcome проверяет, что outcome - известный исход \\\
+
+func validOutcome(outcome string) bool {
+	switch outcome {
+	case OutcomeRecovered, OutcomeTransferred, OutcomeDisability, OutcomeOther:
+		return true
+	}
+	return false
+}
+
+/// Функция days возвращает число дней периода включительно \\\
+
+func days(start, end time.Time) int {
+	return int(end.Sub(start).Hours()/24) + 1
+}
@@ -0,0 +1,10 @@
+package sickleave
+
+type Storage interface {
+	Issue(certificate *Certificate, prefix string, year int) (*Certificate, error)
+	FindById(id int64) (*Certificate, error)
+	FindPeriods(certificateId int64) ([]Period, error)
+	FindOpenByDoctor(doctorId int64) ([]Certificate, error)
+	Extend(input *ExtendDTO, doctorId int64) (*Certificate, error)
+	Close(input *CloseDTO) (*Certificate, error)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"time"
 )
 
@@ -16,13 +17,13 @@ var _ Storage = &SpecializationStorage{}
 
 type SpecializationStorage struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 /// Структура NewStorage возвращает новый экземпляр SpecializationStorage инициализируя переданные в него аргументы \\\
 
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &SpecializationStorage{
 		logger:         logger.GetLogger(),
 		conn:           storage,
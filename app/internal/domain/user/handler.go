@@ -144,10 +144,14 @@ func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	/// Вызов функции Create передавая ей полученные значения и ссылку на структуру input \\\
 	user, err := h.userService.Create(r.Context(), &input)
 	if err != nil {
-		if errors.Is(err, apperror.ErrRepeatedEmail) {
+		if errors.Is(err, apperror.ErrRepeatedEmail) || errors.Is(err, apperror.ErrInvalidPolicyNumber) {
 			response.BadRequest(w, err.Error(), "")
 			return
 		}
+		if errors.Is(err, apperror.ErrPolicyNotValid) {
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+			return
+		}
 		response.InternalError(w, fmt.Sprintf("cannot create user: %v", err), "")
 		return
 	}
@@ -183,6 +187,14 @@ func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 			response.NotFound(w)
 			return
 		}
+		if errors.Is(err, apperror.ErrInvalidPolicyNumber) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		if errors.Is(err, apperror.ErrPolicyNotValid) {
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+			return
+		}
 	}
 	h.logger.Info("USER UPDATED")
 	response.JSON(w, http.StatusOK, "USER UPDATED")
@@ -2,11 +2,13 @@ package user
 
 import (
 	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
 	"HospitalRecord/app/pkg/logger"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"strings"
 	"time"
 )
@@ -17,13 +19,13 @@ var _ Storage = &UserStorage{}
 
 type UserStorage struct {
 	logger         logger.Logger
-	conn           *pgx.Conn
+	conn           *pgxpool.Pool
 	requestTimeout time.Duration
 }
 
 /// Структура NewStorage возвращает новый экземпляр UserStorage инициализируя переданные в него аргументы \\\
 
-func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
 	return &UserStorage{
 		logger:         logger.GetLogger(),
 		conn:           storage,
@@ -31,7 +33,7 @@ func NewStorage(storage *pgx.Conn, requestTimeout int) Storage {
 	}
 }
 
-/// Функция Create для сущности UserStorage создает записи пациентов в БД \\\
+/// Функция Create для сущности UserStorage создает записи пациентов в БД вместе с событием patient.registered \\\
 
 func (d *UserStorage) Create(user *User) (*User, error) {
 	d.logger.Info("POSTGRES: CREATE USER")
@@ -40,20 +42,34 @@ func (d *UserStorage) Create(user *User) (*User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin create user transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	/// Выполнение запроса к БД \\\
-	row := d.conn.QueryRow(ctx,
+	row := tx.QueryRow(ctx,
 		`INSERT INTO patients (email, name, surname, age, gender, password, policy_number)
 			 VALUES($1,$2,$3,$4,$5,$6,$7) 
 			 RETURNING id, created_at`,
 		user.Email, user.Name, user.Surname, user.Age, user.Gender, user.Password, user.PolicyNumber)
 
 	/// Сканирование полученных значений из БД \\\
-	err := row.Scan(&user.ID, &user.CreatedAt)
+	err = row.Scan(&user.ID, &user.CreatedAt)
 	if err != nil {
 		err = fmt.Errorf("failed to execute create user query: %v", err)
 		d.logger.Error(err)
 		return nil, err
 	}
+
+	payload := outbox.PatientPayload{PatientID: user.ID, Email: user.Email, Name: user.Name, Surname: user.Surname}
+	if err = outbox.Write(ctx, tx, outbox.PatientRegistered, user.ID, payload); err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit create user transaction: %v", err)
+	}
 	return user, nil
 }
 
@@ -152,7 +168,7 @@ func (d *UserStorage) FindByPolicyNumber(policy string) (*User, error) {
 	return user, nil
 }
 
-/// Функция Update для сущности UserStorage обновляет записи о пациенте в БД \\\
+/// Функция Update для сущности UserStorage обновляет записи о пациенте в БД вместе с событием patient.updated \\\
 
 func (d *UserStorage) Update(user *UpdateUserDTO) error {
 	d.logger.Info("POSTGRES: UPDATE USER")
@@ -161,8 +177,14 @@ func (d *UserStorage) Update(user *UpdateUserDTO) error {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin update user transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	/// Выполнение запроса к БД \\\
-	result, err := d.conn.Exec(ctx,
+	result, err := tx.Exec(ctx,
 		`UPDATE patients
 			 SET email=$1, name=$2, surname=$3, patronymic=$4, age=$5, gender=$6, phone_number=$7, address=$8, password=$9, policy_number=$10, disease_id=$11
 			 WHERE id =$12`,
@@ -178,6 +200,21 @@ func (d *UserStorage) Update(user *UpdateUserDTO) error {
 	if result.RowsAffected() == 0 {
 		return apperror.ErrEmptyString
 	}
+
+	payload := outbox.PatientPayload{
+		PatientID: user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Surname:   user.Surname,
+		Fields: []string{"email", "name", "surname", "patronymic", "age", "gender", "phone_number",
+			"address", "password", "policy_number", "disease_id"},
+	}
+	if err = outbox.Write(ctx, tx, outbox.PatientUpdated, user.ID, payload); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update user transaction: %v", err)
+	}
 	return nil
 }
 
@@ -193,29 +230,37 @@ func (d *UserStorage) PartiallyUpdate(user *PartiallyUpdateUserDTO) error {
 	args := make([]interface{}, 0)
 	argId := 1
 
+	/// Создание пустого слайса для хранения имен измененных полей для события patient.updated \\\
+	fields := make([]string, 0)
+
 	/// Проверки на наличие новых значений \\\
 	if user.Email != nil {
 		values = append(values, fmt.Sprintf("email=$%d", argId))
+		fields = append(fields, "email")
 		args = append(args, *user.Email)
 		argId++
 	}
 	if user.PhoneNumber != nil {
 		values = append(values, fmt.Sprintf("phone_number=$%d", argId))
+		fields = append(fields, "phone_number")
 		args = append(args, *user.PhoneNumber)
 		argId++
 	}
 	if user.Address != nil {
 		values = append(values, fmt.Sprintf("address=$%d", argId))
+		fields = append(fields, "address")
 		args = append(args, *user.Address)
 		argId++
 	}
 	if user.Password != nil {
 		values = append(values, fmt.Sprintf("password=$%d", argId))
+		fields = append(fields, "password")
 		args = append(args, *user.Password)
 		argId++
 	}
 	if user.DiseaseID != nil {
 		values = append(values, fmt.Sprintf("disease_id=$%d", argId))
+		fields = append(fields, "disease_id")
 		args = append(args, *user.DiseaseID)
 		argId++
 	}
@@ -229,8 +274,14 @@ func (d *UserStorage) PartiallyUpdate(user *PartiallyUpdateUserDTO) error {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin update user transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	/// Выполнение запроса к БД \\\
-	result, err := d.conn.Exec(ctx, query, args...)
+	result, err := tx.Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update user partially: %v", err)
 	}
@@ -238,10 +289,21 @@ func (d *UserStorage) PartiallyUpdate(user *PartiallyUpdateUserDTO) error {
 	if result.RowsAffected() == 0 {
 		return apperror.ErrEmptyString
 	}
+
+	payload := outbox.PatientPayload{PatientID: user.ID, Fields: fields}
+	if user.Email != nil {
+		payload.Email = *user.Email
+	}
+	if err = outbox.Write(ctx, tx, outbox.PatientUpdated, user.ID, payload); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit update user transaction: %v", err)
+	}
 	return nil
 }
 
-/// Функция Delete для сущности UserStorage удаляет записи о пациентах из БД \\\
+/// Функция Delete для сущности UserStorage удаляет записи о пациентах из БД вместе с событием patient.deleted \\\
 
 func (d *UserStorage) Delete(id int64) error {
 	d.logger.Info("POSTGRES: DELETE USER")
@@ -250,8 +312,14 @@ func (d *UserStorage) Delete(id int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), d.requestTimeout)
 	defer cancel()
 
+	tx, err := d.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin delete user transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
 	/// Выполнение запроса к БД \\\
-	result, err := d.conn.Exec(ctx,
+	result, err := tx.Exec(ctx,
 		`DELETE FROM patients WHERE id = $1`, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete user: %v", err)
@@ -260,5 +328,12 @@ func (d *UserStorage) Delete(id int64) error {
 	if result.RowsAffected() == 0 {
 		return apperror.ErrEmptyString
 	}
+
+	if err = outbox.Write(ctx, tx, outbox.PatientDeleted, id, outbox.PatientPayload{PatientID: id}); err != nil {
+		return err
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit delete user transaction: %v", err)
+	}
 	return nil
 }
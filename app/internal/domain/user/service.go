@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 /// Интерфейс Service реализизирующий service и методы для обработки CRUD системы пациентов \\\
@@ -20,19 +21,27 @@ type Service interface {
 	Delete(id int64) error
 }
 
+/// Интерфейс Policies проверяет номер полиса ОМС и его действие в реестре страховщиков \\\
+
+type Policies interface {
+	Check(number string, at time.Time) error
+}
+
 /// Структура  service реализизирующая инфтерфейс Service пациентов \\\
 
 type service struct {
-	logger  logger.Logger
-	storage Storage
+	logger   logger.Logger
+	storage  Storage
+	policies Policies
 }
 
 /// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
 
-func NewService(storage Storage, logger logger.Logger) Service {
+func NewService(storage Storage, policies Policies, logger logger.Logger) Service {
 	return &service{
-		logger:  logger,
-		storage: storage,
+		logger:   logger,
+		storage:  storage,
+		policies: policies,
 	}
 }
 
@@ -41,6 +50,11 @@ func NewService(storage Storage, logger logger.Logger) Service {
 func (s *service) Create(ctx context.Context, input *CreateUserDTO) (*User, error) {
 	s.logger.Info("SERVICE: CREATE USER")
 
+	/// Проверка контрольной цифры и действия полиса на день регистрации \\\
+	if err := s.policies.Check(input.PolicyNumber, time.Now()); err != nil {
+		return nil, err
+	}
+
 	/// Проверка на уникальность email \\\
 	checkEmail, err := s.storage.FindByEmail(input.Email)
 	if err != nil {
@@ -153,6 +167,13 @@ func (s *service) Update(ctx context.Context, user *UpdateUserDTO) error {
 		return err
 	}
 
+	/// Проверка полиса, если пациент сменил номер \\\
+	if user.PolicyNumber != u.PolicyNumber {
+		if err = s.policies.Check(user.PolicyNumber, time.Now()); err != nil {
+			return err
+		}
+	}
+
 	/// Хэширование обновленного пароля \\\
 	u.Password = user.Password
 	err = user.HashPassword()
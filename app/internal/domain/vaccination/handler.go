@@ -0,0 +1,435 @@
+package vaccination
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"fmt"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	vaccinesURL                 = "/hospital_record/vaccines"
+	vaccineURL                  = "/hospital_record/vaccines/:id"
+	vaccinationsURL             = "/hospital_record/vaccinations"
+	vaccinationURL              = "/hospital_record/vaccinations/:id"
+	vaccinationsByPatientsIdURL = "/hospital_record/vaccination/patients/:id"
+	vaccinationsDueURL          = "/hospital_record/vaccination/due/:id"
+	scheduleURL                 = "/hospital_record/immunization_schedule"
+	scheduleItemURL             = "/hospital_record/immunization_schedule/:id"
+)
+
+/// Структура Handler представляющая собой обработчик объекта vaccinationService для вакцинации \\\
+
+type Handler struct {
+	logger             logger.Logger
+	vaccinationService Service
+	authorize          handler.Middleware
+	admin              handler.Middleware
+	staff              handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, vaccinationService Service, authorize, admin, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:             logger,
+		vaccinationService: vaccinationService,
+		authorize:          authorize,
+		admin:              admin,
+		staff:              staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для вакцинации \\\
+/// Каталог вакцин и календарь ведет администратор, дозы вносят сотрудники, пациент по токену доступа видит только свои прививки \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, vaccinesURL, h.admin(h.CreateVaccine))
+	router.HandlerFunc(http.MethodGet, vaccinesURL, h.FindAllVaccines)
+	router.HandlerFunc(http.MethodGet, vaccineURL, h.GetVaccineById)
+	router.HandlerFunc(http.MethodPut, vaccineURL, h.admin(h.UpdateVaccine))
+	router.HandlerFunc(http.MethodDelete, vaccineURL, h.admin(h.DeleteVaccine))
+	router.HandlerFunc(http.MethodPost, vaccinationsURL, h.staff(h.CreateDose))
+	router.HandlerFunc(http.MethodDelete, vaccinationURL, h.staff(h.DeleteDose))
+	router.HandlerFunc(http.MethodGet, vaccinationsByPatientsIdURL, h.authorize(h.GetDosesByPatientsId))
+	router.HandlerFunc(http.MethodGet, vaccinationsDueURL, h.authorize(h.GetDueByPatientsId))
+	router.HandlerFunc(http.MethodPost, scheduleURL, h.admin(h.CreateScheduleItem))
+	router.HandlerFunc(http.MethodGet, scheduleURL, h.GetSchedule)
+	router.HandlerFunc(http.MethodPut, scheduleItemURL, h.admin(h.UpdateScheduleItem))
+	router.HandlerFunc(http.MethodDelete, scheduleItemURL, h.admin(h.DeleteScheduleItem))
+}
+
+/// Функция CreateVaccine добавляет вакцину в каталог по полученным данным из input \\\
+
+func (h *Handler) CreateVaccine(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE VACCINE")
+	var input CreateVaccineDTO
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции CreateVaccine передавая ей полученные значения и ссылку на структуру input \\\
+	vaccine, err := h.vaccinationService.CreateVaccine(r.Context(), &input)
+	if err != nil {
+		response.InternalError(w, fmt.Sprintf("cannot create vaccine: %v", err), "")
+		return
+	}
+	h.logger.Info("VACCINE CREATED")
+	response.JSON(w, http.StatusCreated, vaccine)
+}
+
+/// Функция FindAllVaccines получает весь каталог вакцин \\\
+
+func (h *Handler) FindAllVaccines(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET ALL VACCINES")
+
+	/// Вызов функции FindAllVaccines \\\
+	vaccines, err := h.vaccinationService.FindAllVaccines(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT ALL VACCINES")
+	response.JSON(w, http.StatusOK, vaccines)
+}
+
+/// Функция GetVaccineById получает вакцину по ее id \\\
+
+func (h *Handler) GetVaccineById(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET VACCINE BY ID")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetVaccineById передавая ей id вакцины \\\
+	vaccine, err := h.vaccinationService.GetVaccineById(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		h.logger.Error(err)
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT VACCINE BY ID")
+	response.JSON(w, http.StatusOK, vaccine)
+}
+
+/// Функция UpdateVaccine обновляет вакцину по ее id и полученным данным из input \\\
+
+func (h *Handler) UpdateVaccine(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: UPDATE VACCINE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	var input UpdateVaccineDTO
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции UpdateVaccine передавая ей полученные значения и ссылку на структуру input \\\
+	err = h.vaccinationService.UpdateVaccine(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("VACCINE UPDATED")
+	response.JSON(w, http.StatusOK, "VACCINE UPDATED")
+}
+
+/// Функция DeleteVaccine удаляет вакцину из каталога по ее id \\\
+
+func (h *Handler) DeleteVaccine(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE VACCINE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции DeleteVaccine передавая ей полученное значение id \\\
+	err = h.vaccinationService.DeleteVaccine(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
+	}
+	h.logger.Info("VACCINE DELETED")
+	response.JSON(w, http.StatusOK, "VACCINE DELETED")
+}
+
+/// Функция CreateDose регистрирует введенную пациенту дозу вакцины по полученным данным из input \\\
+
+func (h *Handler) CreateDose(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE VACCINATION DOSE")
+	var input CreateDoseDTO
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции CreateDose передавая ей полученные значения и ссылку на структуру input \\\
+	dose, err := h.vaccinationService.CreateDose(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		if errors.Is(err, apperror.ErrInvalidDoseNumber) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		if errors.Is(err, apperror.ErrRepeatedDose) {
+			response.Error(w, http.StatusConflict, err.Error(), "")
+			return
+		}
+		response.InternalError(w, fmt.Sprintf("cannot create vaccination dose: %v", err), "")
+		return
+	}
+	h.logger.Info("VACCINATION DOSE CREATED")
+	response.JSON(w, http.StatusCreated, dose)
+}
+
+/// Функция GetDosesByPatientsId получает все введенные пациенту дозы вакцин по id пациента \\\
+
+func (h *Handler) GetDosesByPatientsId(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET VACCINATION DOSES BY PATIENTS ID")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Прививки другого пациента не раскрываются \\\
+	if id != user.ID {
+		response.NotFound(w)
+		return
+	}
+
+	/// Вызов функции GetDosesByPatientsId передавая ей id пациента \\\
+	doses, err := h.vaccinationService.GetDosesByPatientsId(r.Context(), id)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT VACCINATION DOSES BY PATIENTS ID")
+	response.JSON(w, http.StatusOK, doses)
+}
+
+/// Функция DeleteDose удаляет запись о введенной дозе по ее id \\\
+
+func (h *Handler) DeleteDose(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE VACCINATION DOSE")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции DeleteDose передавая ей полученное значение id \\\
+	err = h.vaccinationService.DeleteDose(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
+	}
+	h.logger.Info("VACCINATION DOSE DELETED")
+	response.JSON(w, http.StatusOK, "VACCINATION DOSE DELETED")
+}
+
+/// Функция GetDueByPatientsId получает положенные и просроченные прививки пациента по id пациента \\\
+
+func (h *Handler) GetDueByPatientsId(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET DUE VACCINATIONS BY PATIENTS ID")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Прививки другого пациента не раскрываются \\\
+	if id != user.ID {
+		response.NotFound(w)
+		return
+	}
+
+	/// Вызов функции GetDueByPatientsId передавая ей id пациента \\\
+	due, err := h.vaccinationService.GetDueByPatientsId(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT DUE VACCINATIONS BY PATIENTS ID")
+	response.JSON(w, http.StatusOK, due)
+}
+
+/// Функция CreateScheduleItem добавляет пункт национального календаря прививок по полученным данным из input \\\
+
+func (h *Handler) CreateScheduleItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE IMMUNIZATION SCHEDULE ITEM")
+	var input CreateScheduleItemDTO
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции CreateScheduleItem передавая ей полученные значения и ссылку на структуру input \\\
+	item, err := h.vaccinationService.CreateScheduleItem(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		if errors.Is(err, apperror.ErrInvalidDoseNumber) || errors.Is(err, apperror.ErrInvalidScheduleAge) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, fmt.Sprintf("cannot create immunization schedule item: %v", err), "")
+		return
+	}
+	h.logger.Info("IMMUNIZATION SCHEDULE ITEM CREATED")
+	response.JSON(w, http.StatusCreated, item)
+}
+
+/// Функция GetSchedule получает национальный календарь прививок \\\
+
+func (h *Handler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET IMMUNIZATION SCHEDULE")
+
+	/// Вызов функции GetSchedule \\\
+	schedule, err := h.vaccinationService.GetSchedule(r.Context())
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT IMMUNIZATION SCHEDULE")
+	response.JSON(w, http.StatusOK, schedule)
+}
+
+/// Функция UpdateScheduleItem обновляет пункт календаря прививок по его id и полученным данным из input \\\
+
+func (h *Handler) UpdateScheduleItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: UPDATE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+	var input UpdateScheduleItemDTO
+
+	/// Чтение JSON данных из тела входящего запроса r и декодирование их в переменную input \\\
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции UpdateScheduleItem передавая ей полученные значения и ссылку на структуру input \\\
+	err = h.vaccinationService.UpdateScheduleItem(r.Context(), &input)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		if errors.Is(err, apperror.ErrInvalidDoseNumber) || errors.Is(err, apperror.ErrInvalidScheduleAge) {
+			response.BadRequest(w, err.Error(), "")
+			return
+		}
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("IMMUNIZATION SCHEDULE ITEM UPDATED")
+	response.JSON(w, http.StatusOK, "IMMUNIZATION SCHEDULE ITEM UPDATED")
+}
+
+/// Функция DeleteScheduleItem удаляет пункт календаря прививок по его id \\\
+
+func (h *Handler) DeleteScheduleItem(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции DeleteScheduleItem передавая ей полученное значение id \\\
+	err = h.vaccinationService.DeleteScheduleItem(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
+	}
+	h.logger.Info("IMMUNIZATION SCHEDULE ITEM DELETED")
+	response.JSON(w, http.StatusOK, "IMMUNIZATION SCHEDULE ITEM DELETED")
+}
@@ -0,0 +1,392 @@
+package vaccination
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &VaccinationStorage{}
+
+/// Структура VaccinationStorage содержащая поля для работы с БД \\\
+
+type VaccinationStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр VaccinationStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &VaccinationStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция CreateVaccine для сущности VaccinationStorage создает записи вакцин в БД \\\
+
+func (v *VaccinationStorage) CreateVaccine(vaccine *Vaccine) (*Vaccine, error) {
+	v.logger.Info("POSTGRES: CREATE VACCINE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := v.conn.QueryRow(ctx,
+		`INSERT INTO vaccine (name, disease, manufacturer)
+			 VALUES($1,$2,$3)
+			 RETURNING id`,
+		vaccine.Name, vaccine.Disease, vaccine.Manufacturer)
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&vaccine.ID)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create vaccine query: %v", err)
+		v.logger.Error(err)
+		return nil, err
+	}
+	return vaccine, nil
+}
+
+/// Функция FindAllVaccines для сущности VaccinationStorage находит все вакцины каталога в БД \\\
+
+func (v *VaccinationStorage) FindAllVaccines() ([]Vaccine, error) {
+	v.logger.Info("POSTGRES: GET ALL VACCINES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := v.conn.Query(ctx,
+		`SELECT * FROM vaccine
+			 ORDER BY name ASC`)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		v.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения всех вакцин \\\
+	vaccines := make([]Vaccine, 0)
+
+	for rows.Next() {
+		var vaccine Vaccine
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&vaccine.ID, &vaccine.Name, &vaccine.Disease, &vaccine.Manufacturer)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find all vaccines query: %v", err)
+			v.logger.Error(err)
+			return nil, err
+		}
+		vaccines = append(vaccines, vaccine)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return vaccines, nil
+}
+
+/// Функция FindVaccineById для сущности VaccinationStorage получает вакцину из БД по id \\\
+
+func (v *VaccinationStorage) FindVaccineById(id int64) (*Vaccine, error) {
+	v.logger.Info("POSTGRES: GET VACCINE BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := v.conn.QueryRow(ctx,
+		`SELECT * FROM vaccine
+			 WHERE id = $1`, id)
+
+	vaccine := &Vaccine{}
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&vaccine.ID, &vaccine.Name, &vaccine.Disease, &vaccine.Manufacturer)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find vaccine by id query: %v", err)
+		v.logger.Error(err)
+		return nil, err
+	}
+	return vaccine, nil
+}
+
+/// Функция UpdateVaccine для сущности VaccinationStorage обновляет вакцину в БД \\\
+
+func (v *VaccinationStorage) UpdateVaccine(vaccine *UpdateVaccineDTO) error {
+	v.logger.Info("POSTGRES: UPDATE VACCINE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := v.conn.Exec(ctx,
+		`UPDATE vaccine
+			SET name=$1, disease=$2, manufacturer=$3
+			WHERE id =$4`,
+		vaccine.Name, vaccine.Disease, vaccine.Manufacturer, vaccine.ID)
+	if err != nil {
+		err = fmt.Errorf("failed to execute update vaccine query: %v", err)
+		v.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция DeleteVaccine для сущности VaccinationStorage удаляет вакцину из БД \\\
+
+func (v *VaccinationStorage) DeleteVaccine(id int64) error {
+	v.logger.Info("POSTGRES: DELETE VACCINE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := v.conn.Exec(ctx,
+		`DELETE FROM vaccine WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vaccine: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция CreateDose для сущности VaccinationStorage сохраняет введенную пациенту дозу вакцины в БД \\\
+
+func (v *VaccinationStorage) CreateDose(dose *Dose) (*Dose, error) {
+	v.logger.Info("POSTGRES: CREATE VACCINATION DOSE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := v.conn.QueryRow(ctx,
+		`INSERT INTO vaccination_dose (patients_id, vaccine_id, dose_number, batch_number, injection_site, doctor_id, administered_at)
+			 VALUES($1,$2,$3,$4,$5,$6,$7)
+			 ON CONFLICT (patients_id, vaccine_id, dose_number) DO NOTHING
+			 RETURNING id`,
+		dose.PatientsID, dose.VaccineID, dose.DoseNumber, dose.BatchNumber, dose.InjectionSite, dose.DoctorID, dose.AdministeredAt)
+
+	/// Сканирование полученных значений из БД, пустой результат - доза уже внесена параллельным запросом \\\
+	err := row.Scan(&dose.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrRepeatedDose
+		}
+		err = fmt.Errorf("failed to execute create vaccination dose query: %v", err)
+		v.logger.Error(err)
+		return nil, err
+	}
+	return dose, nil
+}
+
+/// Функция FindDosesByPatientsId для сущности VaccinationStorage получает все дозы вакцин пациента из БД \\\
+
+func (v *VaccinationStorage) FindDosesByPatientsId(id int64) ([]Dose, error) {
+	v.logger.Info("POSTGRES: GET VACCINATION DOSES BY PATIENTS ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := v.conn.Query(ctx,
+		`SELECT * FROM vaccination_dose
+			 WHERE patients_id = $1
+			 ORDER BY administered_at ASC`, id)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		v.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения всех доз \\\
+	doses := make([]Dose, 0)
+
+	for rows.Next() {
+		var dose Dose
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&dose.ID, &dose.PatientsID, &dose.VaccineID, &dose.DoseNumber,
+			&dose.BatchNumber, &dose.InjectionSite, &dose.DoctorID, &dose.AdministeredAt,
+		)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find vaccination doses by patients id query: %v", err)
+			v.logger.Error(err)
+			return nil, err
+		}
+		doses = append(doses, dose)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return doses, nil
+}
+
+/// Функция DeleteDose для сущности VaccinationStorage удаляет запись о введенной дозе из БД \\\
+
+func (v *VaccinationStorage) DeleteDose(id int64) error {
+	v.logger.Info("POSTGRES: DELETE VACCINATION DOSE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := v.conn.Exec(ctx,
+		`DELETE FROM vaccination_dose WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete vaccination dose: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция CreateScheduleItem для сущности VaccinationStorage добавляет пункт календаря прививок в БД \\\
+
+func (v *VaccinationStorage) CreateScheduleItem(item *ScheduleItem) (*ScheduleItem, error) {
+	v.logger.Info("POSTGRES: CREATE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := v.conn.QueryRow(ctx,
+		`INSERT INTO immunization_schedule (vaccine_id, dose_number, age_from_months, age_to_months)
+			 VALUES($1,$2,$3,$4)
+			 RETURNING id`,
+		item.VaccineID, item.DoseNumber, item.AgeFromMonths, item.AgeToMonths)
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&item.ID)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create immunization schedule item query: %v", err)
+		v.logger.Error(err)
+		return nil, err
+	}
+	return item, nil
+}
+
+/// Функция FindSchedule для сущности VaccinationStorage получает весь календарь прививок из БД \\\
+
+func (v *VaccinationStorage) FindSchedule() ([]ScheduleItem, error) {
+	v.logger.Info("POSTGRES: GET IMMUNIZATION SCHEDULE")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := v.conn.Query(ctx,
+		`SELECT * FROM immunization_schedule
+			 ORDER BY age_from_months ASC, vaccine_id ASC, dose_number ASC`)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		v.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения пунктов календаря \\\
+	schedule := make([]ScheduleItem, 0)
+
+	for rows.Next() {
+		var item ScheduleItem
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&item.ID, &item.VaccineID, &item.DoseNumber, &item.AgeFromMonths, &item.AgeToMonths)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find immunization schedule query: %v", err)
+			v.logger.Error(err)
+			return nil, err
+		}
+		schedule = append(schedule, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+/// Функция UpdateScheduleItem для сущности VaccinationStorage обновляет пункт календаря прививок в БД \\\
+
+func (v *VaccinationStorage) UpdateScheduleItem(item *UpdateScheduleItemDTO) error {
+	v.logger.Info("POSTGRES: UPDATE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := v.conn.Exec(ctx,
+		`UPDATE immunization_schedule
+			SET vaccine_id=$1, dose_number=$2, age_from_months=$3, age_to_months=$4
+			WHERE id =$5`,
+		item.VaccineID, item.DoseNumber, item.AgeFromMonths, item.AgeToMonths, item.ID)
+	if err != nil {
+		err = fmt.Errorf("failed to execute update immunization schedule item query: %v", err)
+		v.logger.Error(err)
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция DeleteScheduleItem для сущности VaccinationStorage удаляет пункт календаря прививок из БД \\\
+
+func (v *VaccinationStorage) DeleteScheduleItem(id int64) error {
+	v.logger.Info("POSTGRES: DELETE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), v.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := v.conn.Exec(ctx,
+		`DELETE FROM immunization_schedule WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete immunization schedule item: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
@@ -0,0 +1,382 @@
+package vaccination
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+)
+
+/// Интерфейс Service реализизирующий service и методы для обработки системы вакцинации пациентов \\\
+
+type Service interface {
+	CreateVaccine(ctx context.Context, input *CreateVaccineDTO) (*Vaccine, error)
+	FindAllVaccines(ctx context.Context) (*[]Vaccine, error)
+	GetVaccineById(ctx context.Context, id int64) (*Vaccine, error)
+	UpdateVaccine(ctx context.Context, vaccine *UpdateVaccineDTO) error
+	DeleteVaccine(id int64) error
+	CreateDose(ctx context.Context, input *CreateDoseDTO) (*Dose, error)
+	GetDosesByPatientsId(ctx context.Context, id int64) (*[]Dose, error)
+	DeleteDose(id int64) error
+	CreateScheduleItem(ctx context.Context, input *CreateScheduleItemDTO) (*ScheduleItem, error)
+	GetSchedule(ctx context.Context) (*[]ScheduleItem, error)
+	UpdateScheduleItem(ctx context.Context, item *UpdateScheduleItemDTO) error
+	DeleteScheduleItem(id int64) error
+	GetDueByPatientsId(ctx context.Context, id int64) (*[]DueVaccination, error)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service вакцинации \\\
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+	users   user.Storage
+	doc     doctor.Storage
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(users user.Storage, doc doctor.Storage, storage Storage, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+		users:   users,
+		doc:     doc,
+	}
+}
+
+/// Функция CreateVaccine добавляет вакцину в каталог через интерфейс Service принимая входные данные input \\\
+
+func (s *service) CreateVaccine(ctx context.Context, input *CreateVaccineDTO) (*Vaccine, error) {
+	s.logger.Info("SERVICE: CREATE VACCINE")
+
+	/// Создание структуры v на основе полученных данных \\\
+	v := Vaccine{
+		Name:         input.Name,
+		Disease:      input.Disease,
+		Manufacturer: input.Manufacturer,
+	}
+
+	/// Вызов функции CreateVaccine в хранилище вакцинации \\\
+	vaccine, err := s.storage.CreateVaccine(&v)
+	if err != nil {
+		return nil, err
+	}
+	return vaccine, nil
+}
+
+/// Функция FindAllVaccines осуществялет поиск всех вакцин каталога \\\
+
+func (s *service) FindAllVaccines(ctx context.Context) (*[]Vaccine, error) {
+	s.logger.Info("SERVICE: GET ALL VACCINES")
+
+	/// Вызов функции FindAllVaccines в хранилище вакцинации \\\
+	vaccines, err := s.storage.FindAllVaccines()
+	if err != nil {
+		s.logger.Warnf("cannot find vaccines: %v", err)
+		return nil, err
+	}
+	return &vaccines, nil
+}
+
+/// Функция GetVaccineById осуществялет поиск вакцины через интерфейс Service принимая входные данные id \\\
+
+func (s *service) GetVaccineById(ctx context.Context, id int64) (*Vaccine, error) {
+	s.logger.Info("SERVICE: GET VACCINE BY ID")
+
+	/// Вызов функции FindVaccineById в хранилище вакцинации \\\
+	vaccine, err := s.storage.FindVaccineById(id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			return nil, err
+		}
+		s.logger.Warnf("cannot find vaccine by id: %v", err)
+		return nil, err
+	}
+	return vaccine, nil
+}
+
+/// Функция UpdateVaccine обновляет вакцину через интерфейс Service принимая входные данные vaccine \\\
+
+func (s *service) UpdateVaccine(ctx context.Context, vaccine *UpdateVaccineDTO) error {
+	s.logger.Info("SERVICE: UPDATE VACCINE")
+
+	/// Вызов функции FindVaccineById в хранилище вакцинации \\\
+	_, err := s.storage.FindVaccineById(vaccine.ID)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Errorf("failed to get vaccine: %v", err)
+		}
+		return err
+	}
+
+	/// Вызов функции UpdateVaccine в хранилище вакцинации \\\
+	err = s.storage.UpdateVaccine(vaccine)
+	if err != nil {
+		s.logger.Errorf("failed to update vaccine: %v", err)
+		return err
+	}
+	return nil
+}
+
+/// Функция DeleteVaccine удаляет вакцину из каталога через интерфейс Service принимая входные данные id \\\
+
+func (s *service) DeleteVaccine(id int64) error {
+	s.logger.Info("SERVICE: DELETE VACCINE")
+
+	/// Вызов функции DeleteVaccine в хранилище вакцинации \\\
+	err := s.storage.DeleteVaccine(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("failed to delete vaccine: %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+/// Функция CreateDose регистрирует введенную пациенту дозу вакцины через интерфейс Service принимая входные данные input \\\
+
+func (s *service) CreateDose(ctx context.Context, input *CreateDoseDTO) (*Dose, error) {
+	s.logger.Info("SERVICE: CREATE VACCINATION DOSE")
+
+	/// Проверка номера дозы \\\
+	if input.DoseNumber < 1 {
+		return nil, apperror.ErrInvalidDoseNumber
+	}
+
+	/// Проверка что вакцина есть в каталоге, а пациент и доктор существуют \\\
+	_, err := s.storage.FindVaccineById(input.VaccineID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.users.FindById(input.PatientsID); err != nil {
+		return nil, err
+	}
+	if _, err = s.doc.FindById(input.DoctorID); err != nil {
+		return nil, err
+	}
+
+	/// Одна и та же доза вакцины вносится пациенту один раз \\\
+	doses, err := s.storage.FindDosesByPatientsId(input.PatientsID)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range doses {
+		if d.VaccineID == input.VaccineID && d.DoseNumber == input.DoseNumber {
+			return nil, apperror.ErrRepeatedDose
+		}
+	}
+
+	/// Создание структуры d на основе полученных данных \\\
+	d := Dose{
+		PatientsID:     input.PatientsID,
+		VaccineID:      input.VaccineID,
+		DoseNumber:     input.DoseNumber,
+		BatchNumber:    input.BatchNumber,
+		InjectionSite:  input.InjectionSite,
+		DoctorID:       input.DoctorID,
+		AdministeredAt: input.AdministeredAt,
+	}
+
+	/// Вызов функции CreateDose в хранилище вакцинации \\\
+	dose, err := s.storage.CreateDose(&d)
+	if err != nil {
+		return nil, err
+	}
+	return dose, nil
+}
+
+/// Функция GetDosesByPatientsId осуществялет поиск всех доз вакцин пациента через интерфейс Service принимая входные данные id пациента \\\
+
+func (s *service) GetDosesByPatientsId(ctx context.Context, id int64) (*[]Dose, error) {
+	s.logger.Info("SERVICE: GET VACCINATION DOSES BY PATIENTS ID")
+
+	/// Вызов функции FindDosesByPatientsId в хранилище вакцинации \\\
+	doses, err := s.storage.FindDosesByPatientsId(id)
+	if err != nil {
+		s.logger.Warnf("cannot find vaccination doses by patients id: %v", err)
+		return nil, err
+	}
+	return &doses, nil
+}
+
+/// Функция DeleteDose удаляет запись о введенной дозе через интерфейс Service принимая входные данные id \\\
+
+func (s *service) DeleteDose(id int64) error {
+	s.logger.Info("SERVICE: DELETE VACCINATION DOSE")
+
+	/// Вызов функции DeleteDose в хранилище вакцинации \\\
+	err := s.storage.DeleteDose(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("failed to delete vaccination dose: %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+/// Функция CreateScheduleItem добавляет пункт календаря прививок через интерфейс Service принимая входные данные input \\\
+
+func (s *service) CreateScheduleItem(ctx context.Context, input *CreateScheduleItemDTO) (*ScheduleItem, error) {
+	s.logger.Info("SERVICE: CREATE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Проверка корректности пункта календаря \\\
+	if input.DoseNumber < 1 {
+		return nil, apperror.ErrInvalidDoseNumber
+	}
+	if input.AgeFromMonths < 0 || input.AgeToMonths < input.AgeFromMonths {
+		return nil, apperror.ErrInvalidScheduleAge
+	}
+
+	/// Проверка что вакцина есть в каталоге \\\
+	_, err := s.storage.FindVaccineById(input.VaccineID)
+	if err != nil {
+		return nil, err
+	}
+
+	/// Создание структуры item на основе полученных данных \\\
+	item := ScheduleItem{
+		VaccineID:     input.VaccineID,
+		DoseNumber:    input.DoseNumber,
+		AgeFromMonths: input.AgeFromMonths,
+		AgeToMonths:   input.AgeToMonths,
+	}
+
+	/// Вызов функции CreateScheduleItem в хранилище вакцинации \\\
+	created, err := s.storage.CreateScheduleItem(&item)
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+/// Функция GetSchedule получает национальный календарь прививок \\\
+
+func (s *service) GetSchedule(ctx context.Context) (*[]ScheduleItem, error) {
+	s.logger.Info("SERVICE: GET IMMUNIZATION SCHEDULE")
+
+	/// Вызов функции FindSchedule в хранилище вакцинации \\\
+	schedule, err := s.storage.FindSchedule()
+	if err != nil {
+		s.logger.Warnf("cannot find immunization schedule: %v", err)
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+/// Функция UpdateScheduleItem обновляет пункт календаря прививок через интерфейс Service принимая входные данные item \\\
+
+func (s *service) UpdateScheduleItem(ctx context.Context, item *UpdateScheduleItemDTO) error {
+	s.logger.Info("SERVICE: UPDATE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Проверка корректности пункта календаря \\\
+	if item.DoseNumber < 1 {
+		return apperror.ErrInvalidDoseNumber
+	}
+	if item.AgeFromMonths < 0 || item.AgeToMonths < item.AgeFromMonths {
+		return apperror.ErrInvalidScheduleAge
+	}
+
+	/// Вызов функции UpdateScheduleItem в хранилище вакцинации \\\
+	err := s.storage.UpdateScheduleItem(item)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Errorf("failed to update immunization schedule item: %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+/// Функция DeleteScheduleItem удаляет пункт календаря прививок через интерфейс Service принимая входные данные id \\\
+
+func (s *service) DeleteScheduleItem(id int64) error {
+	s.logger.Info("SERVICE: DELETE IMMUNIZATION SCHEDULE ITEM")
+
+	/// Вызов функции DeleteScheduleItem в хранилище вакцинации \\\
+	err := s.storage.DeleteScheduleItem(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("failed to delete immunization schedule item: %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+/// Функция GetDueByPatientsId определяет положенные и просроченные прививки пациента по его возрасту \\\
+
+func (s *service) GetDueByPatientsId(ctx context.Context, id int64) (*[]DueVaccination, error) {
+	s.logger.Info("SERVICE: GET DUE VACCINATIONS BY PATIENTS ID")
+
+	/// Вызов функции FindById в хранилище пациентов для получения возраста \\\
+	patient, err := s.users.FindById(id)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Warnf("cannot find patient by id: %v", err)
+		}
+		return nil, err
+	}
+
+	schedule, err := s.storage.FindSchedule()
+	if err != nil {
+		return nil, err
+	}
+	doses, err := s.storage.FindDosesByPatientsId(id)
+	if err != nil {
+		return nil, err
+	}
+	vaccines, err := s.storage.FindAllVaccines()
+	if err != nil {
+		return nil, err
+	}
+
+	/// В карточке пациента хранится только полный возраст в годах: пациенту от 12*age до 12*age+11 месяцев \\\
+	fromMonths := int16(patient.Age) * 12
+	due := dueVaccinations(fromMonths, fromMonths+11, schedule, doses, vaccines)
+	return &due, nil
+}
+
+/// Функция dueVaccinations сопоставляет календарь прививок с введенными дозами \\\
+/// Пункт календаря считается выполненным, если введена доза этой вакцины с таким же или большим номером \\\
+/// Возраст в месяцах известен интервалом: прививка положена, если пациент мог до нее дорасти, и просрочена, только если он ее точно перерос \\\
+
+func dueVaccinations(minAgeMonths, maxAgeMonths int16, schedule []ScheduleItem, doses []Dose, vaccines []Vaccine) []DueVaccination {
+	/// Наибольший номер введенной дозы по каждой вакцине \\\
+	received := make(map[int64]int16)
+	for _, d := range doses {
+		if d.DoseNumber > received[d.VaccineID] {
+			received[d.VaccineID] = d.DoseNumber
+		}
+	}
+
+	names := make(map[int64]string)
+	for _, v := range vaccines {
+		names[v.ID] = v.Name
+	}
+
+	due := make([]DueVaccination, 0)
+	for _, item := range schedule {
+		if received[item.VaccineID] >= item.DoseNumber || maxAgeMonths < item.AgeFromMonths {
+			continue
+		}
+
+		status := StatusDue
+		if minAgeMonths > item.AgeToMonths {
+			status = StatusOverdue
+		}
+		due = append(due, DueVaccination{
+			VaccineID:     item.VaccineID,
+			VaccineName:   names[item.VaccineID],
+			DoseNumber:    item.DoseNumber,
+			AgeFromMonths: item.AgeFromMonths,
+			AgeToMonths:   item.AgeToMonths,
+			Status:        status,
+		})
+	}
+	return due
+}
@@ -0,0 +1,16 @@
+package vaccination
+
+type Storage interface {
+	CreateVaccine(vaccine *Vaccine) (*Vaccine, error)
+	FindAllVaccines() ([]Vaccine, error)
+	FindVaccineById(id int64) (*Vaccine, error)
+	UpdateVaccine(vaccine *UpdateVaccineDTO) error
+	DeleteVaccine(id int64) error
+	CreateDose(dose *Dose) (*Dose, error)
+	FindDosesByPatientsId(id int64) ([]Dose, error)
+	DeleteDose(id int64) error
+	CreateScheduleItem(item *ScheduleItem) (*ScheduleItem, error)
+	FindSchedule() ([]ScheduleItem, error)
+	UpdateScheduleItem(item *UpdateScheduleItemDTO) error
+	DeleteScheduleItem(id int64) error
+}
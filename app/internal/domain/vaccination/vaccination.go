@@ -0,0 +1,92 @@
+package vaccination
+
+import "time"
+
+/// Статусы прививок пациента по национальному календарю \\\
+
+const (
+	StatusDue     = "due"
+	StatusOverdue = "overdue"
+)
+
+/// Структура для создания и обновления вакцин из каталога \\\
+
+type Vaccine struct {
+	ID           int64  `json:"id" example:"1567"`
+	Name         string `json:"name" example:"Gam-COVID-Vac"`
+	Disease      string `json:"disease" example:"COVID-19"`
+	Manufacturer string `json:"manufacturer" example:"N. F. Gamaleya Research Center"`
+}
+
+type CreateVaccineDTO struct {
+	Name         string `json:"name" example:"Gam-COVID-Vac"`
+	Disease      string `json:"disease" example:"COVID-19"`
+	Manufacturer string `json:"manufacturer" example:"N. F. Gamaleya Research Center"`
+}
+
+type UpdateVaccineDTO struct {
+	ID           int64  `json:"id" example:"1567"`
+	Name         string `json:"name" example:"Gam-COVID-Vac"`
+	Disease      string `json:"disease" example:"COVID-19"`
+	Manufacturer string `json:"manufacturer" example:"N. F. Gamaleya Research Center"`
+}
+
+/// Структура для введенных пациенту доз вакцины \\\
+
+type Dose struct {
+	ID             int64     `json:"id" example:"1567"`
+	PatientsID     int64     `json:"patients_id" example:"1"`
+	VaccineID      int64     `json:"vaccine_id" example:"1"`
+	DoseNumber     int16     `json:"dose_number" example:"1"`
+	BatchNumber    string    `json:"batch_number" example:"I-220323"`
+	InjectionSite  string    `json:"injection_site" example:"left deltoid"`
+	DoctorID       int64     `json:"doctor_id" example:"1"`
+	AdministeredAt time.Time `json:"administered_at" example:"2023-07-27T15:30:00Z"`
+}
+
+type CreateDoseDTO struct {
+	PatientsID     int64     `json:"patients_id" example:"1"`
+	VaccineID      int64     `json:"vaccine_id" example:"1"`
+	DoseNumber     int16     `json:"dose_number" example:"1"`
+	BatchNumber    string    `json:"batch_number" example:"I-220323"`
+	InjectionSite  string    `json:"injection_site" example:"left deltoid"`
+	DoctorID       int64     `json:"doctor_id" example:"1"`
+	AdministeredAt time.Time `json:"administered_at" example:"2023-07-27T15:30:00Z"`
+}
+
+/// Структура для пунктов национального календаря прививок \\\
+/// Возраст указывается в месяцах: прививка положена с AgeFromMonths и считается просроченной после AgeToMonths \\\
+
+type ScheduleItem struct {
+	ID            int64 `json:"id" example:"1567"`
+	VaccineID     int64 `json:"vaccine_id" example:"1"`
+	DoseNumber    int16 `json:"dose_number" example:"1"`
+	AgeFromMonths int16 `json:"age_from_months" example:"12"`
+	AgeToMonths   int16 `json:"age_to_months" example:"15"`
+}
+
+type CreateScheduleItemDTO struct {
+	VaccineID     int64 `json:"vaccine_id" example:"1"`
+	DoseNumber    int16 `json:"dose_number" example:"1"`
+	AgeFromMonths int16 `json:"age_from_months" example:"12"`
+	AgeToMonths   int16 `json:"age_to_months" example:"15"`
+}
+
+type UpdateScheduleItemDTO struct {
+	ID            int64 `json:"id" example:"1567"`
+	VaccineID     int64 `json:"vaccine_id" example:"1"`
+	DoseNumber    int16 `json:"dose_number" example:"1"`
+	AgeFromMonths int16 `json:"age_from_months" example:"12"`
+	AgeToMonths   int16 `json:"age_to_months" example:"15"`
+}
+
+/// Структура для прививок, которые пациенту положено сделать или которые уже просрочены \\\
+
+type DueVaccination struct {
+	VaccineID     int64  `json:"vaccine_id" example:"1"`
+	VaccineName   string `json:"vaccine_name" example:"Gam-COVID-Vac"`
+	DoseNumber    int16  `json:"dose_number" example:"1"`
+	AgeFromMonths int16  `json:"age_from_months" example:"12"`
+	AgeToMonths   int16  `json:"age_to_months" example:"15"`
+	Status        string `json:"status" example:"due"`
+}
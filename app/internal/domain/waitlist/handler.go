@@ -0,0 +1,281 @@
+package waitlist
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	waitlistURL      = "/hospital_record/waitlist"
+	waitlistEntryURL = "/hospital_record/waitlist/:id"
+	offersURL        = "/hospital_record/waitlist_offers"
+	acceptOfferURL   = "/hospital_record/waitlist_offers/:id/accept"
+	declineOfferURL  = "/hospital_record/waitlist_offers/:id/decline"
+	slotsURL         = "/hospital_record/waitlist_slots"
+)
+
+/// Структура Handler представляющая собой обработчик объекта waitlistService для листа ожидания \\\
+
+type Handler struct {
+	logger          logger.Logger
+	waitlistService Service
+	authorize       handler.Middleware
+	staff           handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, waitlistService Service, authorize, staff handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:          logger,
+		waitlistService: waitlistService,
+		authorize:       authorize,
+		staff:           staff,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для листа ожидания, запросы пациента требуют токен доступа \\\
+/// Свободные слоты вручную предлагают только сотрудники \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, waitlistURL, h.authorize(h.JoinWaitlist))
+	router.HandlerFunc(http.MethodGet, waitlistURL, h.authorize(h.GetEntries))
+	router.HandlerFunc(http.MethodDelete, waitlistEntryURL, h.authorize(h.CancelEntry))
+	router.HandlerFunc(http.MethodGet, offersURL, h.authorize(h.GetOffers))
+	router.HandlerFunc(http.MethodPost, acceptOfferURL, h.authorize(h.AcceptOffer))
+	router.HandlerFunc(http.MethodPost, declineOfferURL, h.authorize(h.DeclineOffer))
+	router.HandlerFunc(http.MethodPost, slotsURL, h.staff(h.OfferSlot))
+}
+
+/// Функция JoinWaitlist добавляет авторизованного пациента в лист ожидания \\\
+
+func (h *Handler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: JOIN WAITLIST")
+
+	/// Пациент определяется по токену доступа, а не по телу запроса \\\
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и декодирует его в структуру input \\\
+	var input CreateEntryDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.PatientID = user.ID
+	h.logger.Printf("Input: %+v\n", &input)
+
+	/// Вызов функции Join передавая ей полученные значения \\\
+	entry, err := h.waitlistService.Join(r.Context(), &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidWaitlist), errors.Is(err, apperror.ErrWrongSpecialization):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrReferralRequired):
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "wrong on the server")
+		}
+		return
+	}
+	h.logger.Info("WAITLIST ENTRY CREATED")
+	response.JSON(w, http.StatusCreated, entry)
+}
+
+/// Функция GetEntries получает записи авторизованного пациента в листе ожидания \\\
+
+func (h *Handler) GetEntries(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WAITLIST ENTRIES")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetEntries передавая ей id пациента \\\
+	entries, err := h.waitlistService.GetEntries(r.Context(), user.ID)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT WAITLIST ENTRIES")
+	response.JSON(w, http.StatusOK, entries)
+}
+
+/// Функция CancelEntry удаляет авторизованного пациента из листа ожидания по id записи \\\
+
+func (h *Handler) CancelEntry(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CANCEL WAITLIST ENTRY")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции Cancel передавая ей id пациента и записи \\\
+	err = h.waitlistService.Cancel(r.Context(), user.ID, id)
+	if err != nil {
+		if errors.Is(err, apperror.ErrEmptyString) {
+			response.NotFound(w)
+			return
+		}
+		response.InternalError(w, err.Error(), "wrong on the server")
+		return
+	}
+	h.logger.Info("WAITLIST ENTRY CANCELLED")
+	response.JSON(w, http.StatusOK, "WAITLIST ENTRY CANCELLED")
+}
+
+/// Функция GetOffers получает предложения слотов авторизованному пациенту \\\
+
+func (h *Handler) GetOffers(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WAITLIST OFFERS")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Вызов функции GetOffers передавая ей id пациента \\\
+	offers, err := h.waitlistService.GetOffers(r.Context(), user.ID)
+	if err != nil {
+		response.InternalError(w, err.Error(), "")
+		return
+	}
+	h.logger.Info("GOT WAITLIST OFFERS")
+	response.JSON(w, http.StatusOK, offers)
+}
+
+/// Функция AcceptOffer принимает предложение слота и записывает пациента на прием \\\
+
+func (h *Handler) AcceptOffer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: ACCEPT WAITLIST OFFER")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции Accept передавая ей id пациента и предложения \\\
+	offer, err := h.waitlistService.Accept(r.Context(), user.ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrOfferNotPending), errors.Is(err, apperror.ErrSlotTaken),
+			errors.Is(err, apperror.ErrDoctorNotAvailable), errors.Is(err, apperror.ErrNoOfficeHours):
+			response.Error(w, http.StatusConflict, err.Error(), "")
+		case errors.Is(err, apperror.ErrWrongSpecialization), errors.Is(err, apperror.ErrInvalidPolicyNumber):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrReferralRequired), errors.Is(err, apperror.ErrPolicyNotValid):
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+		case errors.Is(err, apperror.ErrOfferExpired):
+			response.Error(w, http.StatusGone, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "wrong on the server")
+		}
+		return
+	}
+	h.logger.Info("WAITLIST OFFER ACCEPTED")
+	response.JSON(w, http.StatusOK, offer)
+}
+
+/// Функция DeclineOffer отклоняет предложение слота, пациент остается в листе ожидания \\\
+
+func (h *Handler) DeclineOffer(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DECLINE WAITLIST OFFER")
+
+	user, ok := auth.FromContext(r.Context())
+	if !ok {
+		response.Unauthorized(w, apperror.ErrUnauthorized.Error(), "")
+		return
+	}
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Вызов функции Decline передавая ей id пациента и предложения \\\
+	err = h.waitlistService.Decline(r.Context(), user.ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrOfferNotPending):
+			response.Error(w, http.StatusConflict, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "wrong on the server")
+		}
+		return
+	}
+	h.logger.Info("WAITLIST OFFER DECLINED")
+	response.JSON(w, http.StatusOK, "WAITLIST OFFER DECLINED")
+}
+
+/// Функция OfferSlot сообщает листу ожидания о новом свободном слоте доктора \\\
+/// Возвращает созданное предложение или 404, если подходящих пациентов в листе ожидания нет \\\
+
+func (h *Handler) OfferSlot(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: OFFER WAITLIST SLOT")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и декодирует его в структуру slot \\\
+	var slot record.Slot
+	if err := response.ReadJSON(w, r, &slot); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	h.logger.Printf("Input: %+v\n", &slot)
+
+	/// Вызов функции OfferSlot передавая ей полученный слот \\\
+	offer, err := h.waitlistService.OfferSlot(r.Context(), slot)
+	if err != nil {
+		switch {
+		case errors.Is(err, apperror.ErrEmptyString):
+			response.NotFound(w)
+		case errors.Is(err, apperror.ErrInvalidWaitlist), errors.Is(err, apperror.ErrWrongSpecialization):
+			response.BadRequest(w, err.Error(), "")
+		case errors.Is(err, apperror.ErrReferralRequired):
+			response.Error(w, http.StatusForbidden, err.Error(), "")
+		case errors.Is(err, apperror.ErrSlotTaken), errors.Is(err, apperror.ErrNoOfficeHours):
+			response.Error(w, http.StatusConflict, err.Error(), "")
+		default:
+			response.InternalError(w, err.Error(), "wrong on the server")
+		}
+		return
+	}
+	h.logger.Info("WAITLIST SLOT OFFERED")
+	response.JSON(w, http.StatusCreated, offer)
+}
@@ -0,0 +1,524 @@
+package waitlist
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"time"
+)
+
+var _ Storage = &WaitlistStorage{}
+
+/// Структура WaitlistStorage содержащая поля для работы с БД \\\
+
+type WaitlistStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр WaitlistStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &WaitlistStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция CreateEntry для сущности WaitlistStorage добавляет пациента в лист ожидания \\\
+
+func (w *WaitlistStorage) CreateEntry(entry *Entry) (*Entry, error) {
+	w.logger.Info("POSTGRES: CREATE WAITLIST ENTRY")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := w.conn.QueryRow(ctx,
+		`INSERT INTO waitlist_entry (patient_id, doctor_id, specialization_id, date_from, date_to, status)
+			 VALUES($1,$2,$3,$4,$5,$6)
+			 RETURNING id, created_at`,
+		entry.PatientID, entry.DoctorID, entry.SpecializationID, entry.DateFrom, entry.DateTo, entry.Status)
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create waitlist entry query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	return entry, nil
+}
+
+/// Функция FindEntryById для сущности WaitlistStorage получает запись листа ожидания по id \\\
+
+func (w *WaitlistStorage) FindEntryById(id int64) (*Entry, error) {
+	w.logger.Info("POSTGRES: GET WAITLIST ENTRY BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := w.conn.QueryRow(ctx,
+		`SELECT * FROM waitlist_entry
+			 WHERE id = $1`, id)
+
+	entry := &Entry{}
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&entry.ID, &entry.PatientID, &entry.DoctorID, &entry.SpecializationID,
+		&entry.DateFrom, &entry.DateTo, &entry.Status, &entry.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find waitlist entry by id query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	return entry, nil
+}
+
+/// Функция FindEntriesByPatient для сущности WaitlistStorage получает все записи пациента в листе ожидания \\\
+
+func (w *WaitlistStorage) FindEntriesByPatient(patientId int64) ([]Entry, error) {
+	w.logger.Info("POSTGRES: GET WAITLIST ENTRIES BY PATIENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := w.conn.Query(ctx,
+		`SELECT * FROM waitlist_entry
+			 WHERE patient_id = $1
+			 ORDER BY created_at DESC`, patientId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения записей листа ожидания \\\
+	entries := make([]Entry, 0)
+
+	for rows.Next() {
+		var entry Entry
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&entry.ID, &entry.PatientID, &entry.DoctorID, &entry.SpecializationID,
+			&entry.DateFrom, &entry.DateTo, &entry.Status, &entry.CreatedAt)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find waitlist entries query: %v", err)
+			w.logger.Error(err)
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+/// Функция CancelEntry для сущности WaitlistStorage отменяет ожидающую запись листа ожидания \\\
+/// Действующее предложение по записи отзывается, чтобы слот можно было предложить следующему пациенту \\\
+
+func (w *WaitlistStorage) CancelEntry(id int64) error {
+	w.logger.Info("POSTGRES: CANCEL WAITLIST ENTRY")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	tx, err := w.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin cancel waitlist entry transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	result, err := tx.Exec(ctx,
+		`UPDATE waitlist_entry SET status = $1
+			 WHERE id = $2 AND status IN ($3, $4)`, EntryCancelled, id, EntryWaiting, EntryOffered)
+	if err != nil {
+		return fmt.Errorf("failed to cancel waitlist entry: %v", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE waitlist_offer SET status = $1
+			 WHERE entry_id = $2 AND status = $3`, OfferDeclined, id, OfferPending)
+	if err != nil {
+		return fmt.Errorf("failed to decline offers of cancelled waitlist entry: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit cancel waitlist entry transaction: %v", err)
+	}
+	return nil
+}
+
+/// Функция OfferNext для сущности WaitlistStorage предлагает слот первому подходящему пациенту из листа ожидания \\\
+/// Пациенту, которому этот слот уже предлагался, он повторно не предлагается \\\
+
+func (w *WaitlistStorage) OfferNext(slot record.Slot, expiresAt time.Time) (*Offer, error) {
+	w.logger.Info("POSTGRES: OFFER WAITLIST SLOT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	tx, err := w.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin offer waitlist slot transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Поиск первой по времени подходящей записи листа ожидания \\\
+	/// Принятое предложение занимает слот, пока созданная по нему запись не отменена и не перенесена \\\
+	offer := &Offer{
+		DoctorID:   slot.DoctorID,
+		TimeRecord: slot.TimeRecord,
+		Status:     OfferPending,
+		ExpiresAt:  expiresAt,
+	}
+	err = tx.QueryRow(ctx,
+		`SELECT e.id, e.patient_id, coalesce(e.specialization_id, $3) FROM waitlist_entry e
+			 WHERE e.status = $4 AND $2 BETWEEN e.date_from AND e.date_to
+			 AND (e.doctor_id = $1 OR (e.doctor_id IS NULL AND e.specialization_id IN
+			     (SELECT ds.specialization_id FROM doctor_specialization ds WHERE ds.doctor_id = $1)))
+			 AND NOT EXISTS (SELECT 1 FROM waitlist_offer o
+			     WHERE o.entry_id = e.id AND o.doctor_id = $1 AND o.time_record = $2)
+			 AND NOT EXISTS (SELECT 1 FROM waitlist_offer o
+			     WHERE o.doctor_id = $1 AND o.time_record = $2
+			     AND (o.status = $5 OR (o.status = $6 AND EXISTS (SELECT 1 FROM record r
+			         WHERE r.id = o.record_id AND r.doctor_id = o.doctor_id AND r.time_record = o.time_record))))
+			 ORDER BY e.created_at, e.id
+			 LIMIT 1
+			 FOR UPDATE OF e SKIP LOCKED`,
+		slot.DoctorID, slot.TimeRecord, slot.SpecializationID, EntryWaiting, OfferPending, OfferAccepted,
+	).Scan(&offer.EntryID, &offer.PatientID, &offer.SpecializationID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find next waitlist entry query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+
+	/// Создание предложения и перевод записи листа ожидания в статус offered \\\
+	err = tx.QueryRow(ctx,
+		`INSERT INTO waitlist_offer (entry_id, patient_id, doctor_id, specialization_id, time_record, status, expires_at)
+			 VALUES($1,$2,$3,$4,$5,$6,$7)
+			 RETURNING id, created_at`,
+		offer.EntryID, offer.PatientID, offer.DoctorID, offer.SpecializationID, offer.TimeRecord, offer.Status, offer.ExpiresAt,
+	).Scan(&offer.ID, &offer.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create waitlist offer query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE waitlist_entry SET status = $1 WHERE id = $2`, EntryOffered, offer.EntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update waitlist entry status: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit offer waitlist slot transaction: %v", err)
+	}
+	return offer, nil
+}
+
+/// Функция FindOfferById для сущности WaitlistStorage получает предложение слота по id \\\
+
+func (w *WaitlistStorage) FindOfferById(id int64) (*Offer, error) {
+	w.logger.Info("POSTGRES: GET WAITLIST OFFER BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	row := w.conn.QueryRow(ctx,
+		`SELECT * FROM waitlist_offer
+			 WHERE id = $1`, id)
+
+	offer := &Offer{}
+
+	/// Сканирование полученных значений из БД \\\
+	err := row.Scan(&offer.ID, &offer.EntryID, &offer.PatientID, &offer.DoctorID, &offer.SpecializationID,
+		&offer.TimeRecord, &offer.Status, &offer.ExpiresAt, &offer.RecordID, &offer.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find waitlist offer by id query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	return offer, nil
+}
+
+/// Функция FindOffersByPatient для сущности WaitlistStorage получает все предложения слотов пациенту \\\
+
+func (w *WaitlistStorage) FindOffersByPatient(patientId int64) ([]Offer, error) {
+	w.logger.Info("POSTGRES: GET WAITLIST OFFERS BY PATIENT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := w.conn.Query(ctx,
+		`SELECT * FROM waitlist_offer
+			 WHERE patient_id = $1
+			 ORDER BY created_at DESC`, patientId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения предложений \\\
+	offers := make([]Offer, 0)
+
+	for rows.Next() {
+		var offer Offer
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&offer.ID, &offer.EntryID, &offer.PatientID, &offer.DoctorID, &offer.SpecializationID,
+			&offer.TimeRecord, &offer.Status, &offer.ExpiresAt, &offer.RecordID, &offer.CreatedAt)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find waitlist offers query: %v", err)
+			w.logger.Error(err)
+			return nil, err
+		}
+		offers = append(offers, offer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return offers, nil
+}
+
+/// Функция AcceptOffer для сущности WaitlistStorage принимает предложение и создает запись на прием в одной транзакции \\\
+/// Запись created уже проверена сервисом записей, здесь проверяется только что предложение действует и слот свободен \\\
+/// Возвращает id созданной записи на прием \\\
+
+func (w *WaitlistStorage) AcceptOffer(id int64, created *record.Record, slot time.Duration) (int64, error) {
+	w.logger.Info("POSTGRES: ACCEPT WAITLIST OFFER")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	tx, err := w.conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin accept waitlist offer transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Блокировка предложения и проверка его статуса \\\
+	var offer Offer
+	err = tx.QueryRow(ctx,
+		`SELECT entry_id, patient_id, doctor_id, specialization_id, time_record, status, expires_at
+			 FROM waitlist_offer WHERE id = $1 FOR UPDATE`, id,
+	).Scan(&offer.EntryID, &offer.PatientID, &offer.DoctorID, &offer.SpecializationID,
+		&offer.TimeRecord, &offer.Status, &offer.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, apperror.ErrEmptyString
+		}
+		return 0, fmt.Errorf("failed to find waitlist offer: %v", err)
+	}
+	if offer.Status != OfferPending {
+		return 0, apperror.ErrOfferNotPending
+	}
+	if !offer.ExpiresAt.After(time.Now()) {
+		return 0, apperror.ErrOfferExpired
+	}
+
+	/// Проверка под блокировкой доктора, что слот за время удержания не был занят \\\
+	if err = record.LockDoctors(ctx, tx, offer.DoctorID); err != nil {
+		return 0, err
+	}
+	taken, err := record.SlotTaken(ctx, tx, offer.DoctorID, offer.TimeRecord, slot)
+	if err != nil {
+		return 0, err
+	}
+	if taken {
+		return 0, apperror.ErrSlotTaken
+	}
+
+	/// Создание записи на прием \\\
+	err = tx.QueryRow(ctx,
+		`INSERT INTO record (hospital_address, doctor_office, patients_id, doctor_id, specialization_id, time_record, office_id)
+			 VALUES($1,$2,$3,$4,$5,$6,$7)
+			 RETURNING `+record.Columns,
+		created.HospitalAddress, created.DoctorOffice, offer.PatientID, offer.DoctorID, created.SpecializationID, offer.TimeRecord, created.OfficeID,
+	).Scan(&created.ID, &created.HospitalAddress, &created.DoctorOffice, &created.Tagging,
+		&created.PatientsID, &created.DoctorID, &created.SpecializationID, &created.TimeRecord, &created.OfficeID, &created.ReferralID)
+	if err != nil {
+		err = fmt.Errorf("failed to create record from waitlist offer: %v", err)
+		w.logger.Error(err)
+		return 0, err
+	}
+	recordId := created.ID
+	if err = outbox.Write(ctx, tx, outbox.RecordCreated, recordId, created.EventPayload()); err != nil {
+		return 0, err
+	}
+
+	/// Обновление статусов предложения и записи листа ожидания \\\
+	_, err = tx.Exec(ctx,
+		`UPDATE waitlist_offer SET status = $1, record_id = $2 WHERE id = $3`, OfferAccepted, recordId, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update waitlist offer status: %v", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE waitlist_entry SET status = $1 WHERE id = $2`, EntryBooked, offer.EntryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update waitlist entry status: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit accept waitlist offer transaction: %v", err)
+	}
+	return recordId, nil
+}
+
+/// Функция DeclineOffer для сущности WaitlistStorage отклоняет предложение, пациент остается в листе ожидания \\\
+
+func (w *WaitlistStorage) DeclineOffer(id int64) error {
+	w.logger.Info("POSTGRES: DECLINE WAITLIST OFFER")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	tx, err := w.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin decline waitlist offer transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	var entryId int64
+	err = tx.QueryRow(ctx,
+		`UPDATE waitlist_offer SET status = $1
+			 WHERE id = $2 AND status = $3
+			 RETURNING entry_id`, OfferDeclined, id, OfferPending).Scan(&entryId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.ErrOfferNotPending
+		}
+		return fmt.Errorf("failed to decline waitlist offer: %v", err)
+	}
+	_, err = tx.Exec(ctx,
+		`UPDATE waitlist_entry SET status = $1 WHERE id = $2 AND status = $3`, EntryWaiting, entryId, EntryOffered)
+	if err != nil {
+		return fmt.Errorf("failed to update waitlist entry status: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit decline waitlist offer transaction: %v", err)
+	}
+	return nil
+}
+
+/// Функция ExpireOffers для сущности WaitlistStorage помечает просроченные предложения и возвращает их \\\
+
+func (w *WaitlistStorage) ExpireOffers() ([]Offer, error) {
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	tx, err := w.conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin expire waitlist offers transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Выполнение запроса к БД \\\
+	rows, err := tx.Query(ctx,
+		`UPDATE waitlist_offer SET status = $1
+			 WHERE status = $2 AND expires_at <= now()
+			 RETURNING *`, OfferExpired, OfferPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire waitlist offers: %v", err)
+	}
+
+	/// Создание пустого слайса для хранения просроченных предложений \\\
+	offers := make([]Offer, 0)
+	entryIds := make([]int64, 0)
+	for rows.Next() {
+		var offer Offer
+		err = rows.Scan(&offer.ID, &offer.EntryID, &offer.PatientID, &offer.DoctorID, &offer.SpecializationID,
+			&offer.TimeRecord, &offer.Status, &offer.ExpiresAt, &offer.RecordID, &offer.CreatedAt)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan expired waitlist offer: %v", err)
+		}
+		offers = append(offers, offer)
+		entryIds = append(entryIds, offer.EntryID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(offers) == 0 {
+		return offers, nil
+	}
+
+	/// Пациенты с просроченными предложениями возвращаются в лист ожидания \\\
+	_, err = tx.Exec(ctx,
+		`UPDATE waitlist_entry SET status = $1 WHERE id = ANY($2) AND status = $3`, EntryWaiting, entryIds, EntryOffered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update waitlist entries of expired offers: %v", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit expire waitlist offers transaction: %v", err)
+	}
+	return offers, nil
+}
+
+/// Функция IsSlotHeld для сущности WaitlistStorage проверяет, удерживается ли слот доктора действующим предложением \\\
+
+func (w *WaitlistStorage) IsSlotHeld(doctorId int64, at time.Time, slot time.Duration) (bool, error) {
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	var held bool
+	err := w.conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM waitlist_offer
+			 WHERE doctor_id = $1 AND status = $4 AND expires_at > now()
+			 AND time_record > $2::timestamptz - $3::interval AND time_record < $2::timestamptz + $3::interval)`,
+		doctorId, at, slot, OfferPending).Scan(&held)
+	if err != nil {
+		err = fmt.Errorf("failed to execute check waitlist hold query: %v", err)
+		w.logger.Error(err)
+		return false, err
+	}
+	return held, nil
+}
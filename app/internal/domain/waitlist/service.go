@@ -0,0 +1,385 @@
+package waitlist
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+/// Интерфейс Service реализизирующий service и методы для работы с листом ожидания \\\
+/// Service также реализует record.Waitlist, через который запись на прием сообщает об освободившихся слотах \\\
+
+type Service interface {
+	record.Waitlist
+	Join(ctx context.Context, input *CreateEntryDTO) (*Entry, error)
+	GetEntries(ctx context.Context, patientId int64) (*[]Entry, error)
+	Cancel(ctx context.Context, patientId, id int64) error
+	GetOffers(ctx context.Context, patientId int64) (*[]Offer, error)
+	Accept(ctx context.Context, patientId, offerId int64) (*Offer, error)
+	Decline(ctx context.Context, patientId, offerId int64) error
+	OfferSlot(ctx context.Context, slot record.Slot) (*Offer, error)
+	UseRecords(records Records)
+	Run(ctx context.Context)
+}
+
+/// Интерфейс Records правил записи на прием: запись из предложения проходит те же проверки, что и обычная запись \\\
+/// Сервис записей сам зависит от листа ожидания, поэтому передается после создания через UseRecords \\\
+
+type Records interface {
+	Prepare(ctx context.Context, record *record.Record) error
+	ResolveOffice(ctx context.Context, record *record.Record) error
+	RequiresReferral(specializationId int64) bool
+	CheckPolicy(patientId int64, at time.Time) error
+}
+
+/// Структура  service реализизирующая инфтерфейс Service листа ожидания \\\
+
+type service struct {
+	logger  logger.Logger
+	storage Storage
+	doc     doctor.Storage
+	records record.Storage
+	offices record.Offices
+	rules   Records
+	hold    time.Duration
+	slot    time.Duration
+	sweep   time.Duration
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(doc doctor.Storage, records record.Storage, offices record.Offices, storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	return &service{
+		logger:  logger,
+		storage: storage,
+		doc:     doc,
+		records: records,
+		offices: offices,
+		hold:    time.Duration(cfg.Waitlist.HoldMinutes) * time.Minute,
+		slot:    time.Duration(cfg.Records.SlotMinutes) * time.Minute,
+		sweep:   time.Duration(cfg.Waitlist.SweepSeconds) * time.Second,
+	}
+}
+
+/// Функция Join добавляет пациента в лист ожидания доктора или специализации на интервал дат \\\
+
+func (s *service) Join(ctx context.Context, input *CreateEntryDTO) (*Entry, error) {
+	s.logger.Info("SERVICE: JOIN WAITLIST")
+
+	/// Проверка входных данных \\\
+	if input.DoctorID == nil && input.SpecializationID == nil {
+		return nil, apperror.ErrInvalidWaitlist
+	}
+	if input.DateFrom.IsZero() || !input.DateTo.After(input.DateFrom) || !input.DateTo.After(time.Now()) {
+		return nil, apperror.ErrInvalidWaitlist
+	}
+
+	/// Если указан доктор, он должен существовать и вести прием по указанной специализации \\\
+	var specializationId int64
+	if input.SpecializationID != nil {
+		specializationId = *input.SpecializationID
+	}
+	if input.DoctorID != nil {
+		doc, err := s.doc.FindById(*input.DoctorID)
+		if err != nil {
+			return nil, err
+		}
+		if input.SpecializationID != nil && !doc.HasSpecialization(*input.SpecializationID) {
+			return nil, apperror.ErrWrongSpecialization
+		}
+		if input.SpecializationID == nil {
+			specializationId = doc.SpecializationID
+		}
+	}
+
+	/// Запись по направлению погашает направление, поэтому в лист ожидания по такой специализации не встают \\\
+	if s.requiresReferral(specializationId) {
+		return nil, apperror.ErrReferralRequired
+	}
+
+	/// Создание структуры entry на основе полученных данных \\\
+	entry := Entry{
+		PatientID:        input.PatientID,
+		DoctorID:         input.DoctorID,
+		SpecializationID: input.SpecializationID,
+		DateFrom:         input.DateFrom,
+		DateTo:           input.DateTo,
+		Status:           EntryWaiting,
+	}
+	return s.storage.CreateEntry(&entry)
+}
+
+/// Функция GetEntries возвращает записи пациента в листе ожидания \\\
+
+func (s *service) GetEntries(ctx context.Context, patientId int64) (*[]Entry, error) {
+	s.logger.Info("SERVICE: GET WAITLIST ENTRIES")
+
+	entries, err := s.storage.FindEntriesByPatient(patientId)
+	if err != nil {
+		return nil, err
+	}
+	return &entries, nil
+}
+
+/// Функция Cancel удаляет пациента из листа ожидания, удерживаемый для него слот предлагается следующему \\\
+
+func (s *service) Cancel(ctx context.Context, patientId, id int64) error {
+	s.logger.Info("SERVICE: CANCEL WAITLIST ENTRY")
+
+	/// Запись листа ожидания может отменить только сам пациент \\\
+	entry, err := s.storage.FindEntryById(id)
+	if err != nil {
+		return err
+	}
+	if entry.PatientID != patientId {
+		return apperror.ErrEmptyString
+	}
+
+	/// Поиск предложения, которое удерживается по этой записи \\\
+	var held *Offer
+	if entry.Status == EntryOffered {
+		offers, err := s.storage.FindOffersByPatient(patientId)
+		if err != nil {
+			return err
+		}
+		for i := range offers {
+			if offers[i].EntryID == id && offers[i].Status == OfferPending {
+				held = &offers[i]
+				break
+			}
+		}
+	}
+
+	/// Вызов функции CancelEntry в хранилище листа ожидания \\\
+	if err = s.storage.CancelEntry(id); err != nil {
+		return err
+	}
+	if held != nil {
+		s.reoffer(*held)
+	}
+	return nil
+}
+
+/// Функция GetOffers возвращает предложения слотов пациенту \\\
+
+func (s *service) GetOffers(ctx context.Context, patientId int64) (*[]Offer, error) {
+	s.logger.Info("SERVICE: GET WAITLIST OFFERS")
+
+	offers, err := s.storage.FindOffersByPatient(patientId)
+	if err != nil {
+		return nil, err
+	}
+	return &offers, nil
+}
+
+/// Функция UseRecords подключает правила записи на прием \\\
+
+func (s *service) UseRecords(records Records) {
+	s.rules = records
+}
+
+/// Функция Accept принимает предложение пациента и записывает его на прием \\\
+/// Запись проверяется правилами сервиса записей, в транзакции хранилища остаются только блокировки и вставка \\\
+
+func (s *service) Accept(ctx context.Context, patientId, offerId int64) (*Offer, error) {
+	s.logger.Info("SERVICE: ACCEPT WAITLIST OFFER")
+
+	offer, err := s.ownOffer(patientId, offerId)
+	if err != nil {
+		return nil, err
+	}
+	if s.rules == nil {
+		return nil, errors.New("waitlist is not connected to record rules")
+	}
+
+	/// Создание записи на прием из предложения и проверка правил записи \\\
+	r := record.Record{
+		PatientsID:       offer.PatientID,
+		DoctorID:         offer.DoctorID,
+		SpecializationID: offer.SpecializationID,
+		TimeRecord:       offer.TimeRecord,
+	}
+	if err = s.rules.Prepare(ctx, &r); err != nil {
+		return nil, err
+	}
+	if s.rules.RequiresReferral(r.SpecializationID) {
+		return nil, apperror.ErrReferralRequired
+	}
+
+	/// Проверка что полис пациента действует на день приема \\\
+	if err = s.rules.CheckPolicy(r.PatientsID, r.TimeRecord); err != nil {
+		return nil, err
+	}
+
+	/// Адрес и кабинет берутся из расписания доктора, как при обычной записи \\\
+	if err = s.rules.ResolveOffice(ctx, &r); err != nil {
+		return nil, err
+	}
+
+	/// Вызов функции AcceptOffer в хранилище листа ожидания \\\
+	recordId, err := s.storage.AcceptOffer(offerId, &r, s.slot)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrOfferNotPending) && !errors.Is(err, apperror.ErrOfferExpired) &&
+			!errors.Is(err, apperror.ErrSlotTaken) && !errors.Is(err, apperror.ErrEmptyString) {
+			s.logger.Errorf("failed to accept waitlist offer: %v", err)
+		}
+		return nil, err
+	}
+	s.logger.Infof("waitlist offer %d accepted, record %d created", offerId, recordId)
+	return s.storage.FindOfferById(offerId)
+}
+
+/// Функция Decline отклоняет предложение пациента, слот предлагается следующему в листе ожидания \\\
+
+func (s *service) Decline(ctx context.Context, patientId, offerId int64) error {
+	s.logger.Info("SERVICE: DECLINE WAITLIST OFFER")
+
+	offer, err := s.ownOffer(patientId, offerId)
+	if err != nil {
+		return err
+	}
+
+	/// Вызов функции DeclineOffer в хранилище листа ожидания \\\
+	if err = s.storage.DeclineOffer(offerId); err != nil {
+		return err
+	}
+	s.reoffer(*offer)
+	return nil
+}
+
+/// Функция OfferSlot предлагает свободный слот доктора первому подходящему пациенту из листа ожидания \\\
+
+func (s *service) OfferSlot(ctx context.Context, slot record.Slot) (*Offer, error) {
+	s.logger.Info("SERVICE: OFFER WAITLIST SLOT")
+
+	if slot.DoctorID == 0 || !slot.TimeRecord.After(time.Now()) {
+		return nil, fmt.Errorf("%w: slot requires a doctor and a time in the future", apperror.ErrInvalidWaitlist)
+	}
+
+	/// Доктор должен существовать и вести прием по специализации слота \\\
+	doc, err := s.doc.FindById(slot.DoctorID)
+	if err != nil {
+		return nil, err
+	}
+	if slot.SpecializationID == 0 {
+		slot.SpecializationID = doc.SpecializationID
+	} else if !doc.HasSpecialization(slot.SpecializationID) {
+		return nil, apperror.ErrWrongSpecialization
+	}
+	if s.requiresReferral(slot.SpecializationID) {
+		return nil, apperror.ErrReferralRequired
+	}
+
+	/// Доктор должен принимать в это время в одном из кабинетов по расписанию \\\
+	if s.offices != nil {
+		if _, err = s.offices.LocateDoctor(slot.DoctorID, slot.TimeRecord); err != nil {
+			if errors.Is(err, apperror.ErrEmptyString) {
+				return nil, apperror.ErrNoOfficeHours
+			}
+			return nil, err
+		}
+	}
+
+	/// Слот не должен быть занят записью или удерживаться другим предложением \\\
+	taken, err := s.records.IsSlotTaken(slot.DoctorID, slot.TimeRecord, s.slot)
+	if err != nil {
+		return nil, err
+	}
+	if !taken {
+		taken, err = s.IsSlotHeld(slot.DoctorID, slot.TimeRecord)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if taken {
+		return nil, apperror.ErrSlotTaken
+	}
+
+	/// Вызов функции OfferNext в хранилище листа ожидания \\\
+	return s.storage.OfferNext(slot, time.Now().Add(s.hold))
+}
+
+/// Функция SlotReleased вызывается при отмене записи на прием и предлагает слот листу ожидания \\\
+/// Ошибки только логируются, чтобы не влиять на отмену записи \\\
+
+func (s *service) SlotReleased(slot record.Slot) {
+	offer, err := s.OfferSlot(context.Background(), slot)
+	if err != nil {
+		if !errors.Is(err, apperror.ErrEmptyString) && !errors.Is(err, apperror.ErrReferralRequired) {
+			s.logger.Warnf("failed to offer released slot: %v", err)
+		}
+		return
+	}
+	s.logger.Infof("released slot offered to patient %d, offer %d", offer.PatientID, offer.ID)
+}
+
+/// Функция requiresReferral проверяет, ведется ли запись по специализации только по направлению \\\
+
+func (s *service) requiresReferral(specializationId int64) bool {
+	return s.rules != nil && s.rules.RequiresReferral(specializationId)
+}
+
+/// Функция IsSlotHeld проверяет, удерживается ли слот доктора действующим предложением \\\
+
+func (s *service) IsSlotHeld(doctorId int64, at time.Time) (bool, error) {
+	return s.storage.IsSlotHeld(doctorId, at, s.slot)
+}
+
+/// Функция Run периодически помечает просроченные предложения и предлагает их слоты следующим пациентам \\\
+/// Работает до отмены ctx, при sweep_seconds <= 0 просроченные предложения не обрабатываются \\\
+
+func (s *service) Run(ctx context.Context) {
+	if s.sweep <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.sweep)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			offers, err := s.storage.ExpireOffers()
+			if err != nil {
+				s.logger.Warnf("failed to expire waitlist offers: %v", err)
+				continue
+			}
+			for _, offer := range offers {
+				s.reoffer(offer)
+			}
+		}
+	}
+}
+
+/// Функция ownOffer получает предложение и проверяет, что оно адресовано пациенту \\\
+
+func (s *service) ownOffer(patientId, offerId int64) (*Offer, error) {
+	offer, err := s.storage.FindOfferById(offerId)
+	if err != nil {
+		return nil, err
+	}
+	if offer.PatientID != patientId {
+		return nil, apperror.ErrEmptyString
+	}
+	return offer, nil
+}
+
+/// Функция reoffer предлагает слот отклоненного или просроченного предложения следующему пациенту \\\
+
+func (s *service) reoffer(offer Offer) {
+	if !offer.TimeRecord.After(time.Now()) {
+		return
+	}
+	s.SlotReleased(record.Slot{
+		DoctorID:         offer.DoctorID,
+		SpecializationID: offer.SpecializationID,
+		TimeRecord:       offer.TimeRecord,
+	})
+}
@@ -0,0 +1,20 @@
+package waitlist
+
+import (
+	"HospitalRecord/app/internal/domain/record"
+	"time"
+)
+
+type Storage interface {
+	CreateEntry(entry *Entry) (*Entry, error)
+	FindEntryById(id int64) (*Entry, error)
+	FindEntriesByPatient(patientId int64) ([]Entry, error)
+	CancelEntry(id int64) error
+	OfferNext(slot record.Slot, expiresAt time.Time) (*Offer, error)
+	FindOfferById(id int64) (*Offer, error)
+	FindOffersByPatient(patientId int64) ([]Offer, error)
+	AcceptOffer(id int64, created *record.Record, slot time.Duration) (int64, error)
+	DeclineOffer(id int64) error
+	ExpireOffers() ([]Offer, error)
+	IsSlotHeld(doctorId int64, at time.Time, slot time.Duration) (bool, error)
+}
@@ -0,0 +1,57 @@
+package waitlist
+
+import "time"
+
+/// Статусы записи в листе ожидания \\\
+
+const (
+	EntryWaiting   = "waiting"
+	EntryOffered   = "offered"
+	EntryBooked    = "booked"
+	EntryCancelled = "cancelled"
+)
+
+/// Статусы предложения слота \\\
+
+const (
+	OfferPending  = "pending"
+	OfferAccepted = "accepted"
+	OfferDeclined = "declined"
+	OfferExpired  = "expired"
+)
+
+/// Структура записи в листе ожидания: пациент ждет прием у доктора или по специализации в интервале дат \\\
+
+type Entry struct {
+	ID               int64     `json:"id" example:"1"`
+	PatientID        int64     `json:"patient_id" example:"1"`
+	DoctorID         *int64    `json:"doctor_id,omitempty" example:"1"`
+	SpecializationID *int64    `json:"specialization_id,omitempty" example:"1"`
+	DateFrom         time.Time `json:"date_from" example:"2023-07-27T00:00:00Z"`
+	DateTo           time.Time `json:"date_to" example:"2023-08-10T00:00:00Z"`
+	Status           string    `json:"status" example:"waiting"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+/// Структура предложения освободившегося слота пациенту, слот удерживается до ExpiresAt \\\
+
+type Offer struct {
+	ID               int64     `json:"id" example:"1"`
+	EntryID          int64     `json:"entry_id" example:"1"`
+	PatientID        int64     `json:"patient_id" example:"1"`
+	DoctorID         int64     `json:"doctor_id" example:"1"`
+	SpecializationID int64     `json:"specialization_id" example:"1"`
+	TimeRecord       time.Time `json:"time_record" example:"2023-07-27T15:30:00Z"`
+	Status           string    `json:"status" example:"pending"`
+	ExpiresAt        time.Time `json:"expires_at" example:"2023-07-20T12:30:00Z"`
+	RecordID         *int64    `json:"record_id,omitempty" example:"1"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type CreateEntryDTO struct {
+	PatientID        int64     `json:"-"`
+	DoctorID         *int64    `json:"doctor_id,omitempty" example:"1"`
+	SpecializationID *int64    `json:"specialization_id,omitempty" example:"1"`
+	DateFrom         time.Time `json:"date_from" example:"2023-07-27T00:00:00Z"`
+	DateTo           time.Time `json:"date_to" example:"2023-08-10T00:00:00Z"`
+}
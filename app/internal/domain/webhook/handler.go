@@ -0,0 +1,232 @@
+package webhook
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/handler"
+	"HospitalRecord/app/internal/domain/response"
+	"HospitalRecord/app/pkg/logger"
+	"errors"
+	"github.com/julienschmidt/httprouter"
+	"net/http"
+)
+
+const (
+	subscriptionsURL = "/hospital_record/webhooks"
+	subscriptionURL  = "/hospital_record/webhooks/:id"
+	deliveriesURL    = "/hospital_record/webhooks/:id/deliveries"
+	deliveryURL      = "/hospital_record/webhook_deliveries/:id"
+	replayURL        = "/hospital_record/webhook_deliveries/:id/replay"
+)
+
+/// Структура Handler представляющая собой обработчик объекта webhookService для webhook подписок \\\
+
+type Handler struct {
+	logger         logger.Logger
+	webhookService Service
+	admin          handler.Middleware
+}
+
+/// Структура NewHandler возвращает новый экземпляр Handler инициализируя переданные в него аргументы \\\
+
+func NewHandler(logger logger.Logger, webhookService Service, admin handler.Middleware) handler.Hand {
+	return &Handler{
+		logger:         logger,
+		webhookService: webhookService,
+		admin:          admin,
+	}
+}
+
+/// Структура Register регистрирует новые запросы для webhook, все запросы доступны только администратору \\\
+
+func (h *Handler) Register(router *httprouter.Router) {
+	router.HandlerFunc(http.MethodPost, subscriptionsURL, h.admin(h.CreateSubscription))
+	router.HandlerFunc(http.MethodGet, subscriptionsURL, h.admin(h.GetSubscriptions))
+	router.HandlerFunc(http.MethodGet, subscriptionURL, h.admin(h.GetSubscription))
+	router.HandlerFunc(http.MethodPatch, subscriptionURL, h.admin(h.PartiallyUpdateSubscription))
+	router.HandlerFunc(http.MethodDelete, subscriptionURL, h.admin(h.DeleteSubscription))
+	router.HandlerFunc(http.MethodGet, deliveriesURL, h.admin(h.GetDeliveries))
+	router.HandlerFunc(http.MethodGet, deliveryURL, h.admin(h.GetDelivery))
+	router.HandlerFunc(http.MethodPost, replayURL, h.admin(h.Replay))
+}
+
+/// Функция CreateSubscription создает подписку, секрет возвращается в ответе один раз \\\
+
+func (h *Handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: CREATE WEBHOOK SUBSCRIPTION")
+
+	/// Чтение тела запроса в структуру CreateSubscriptionDTO \\\
+	var input CreateSubscriptionDTO
+	if err := response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+
+	/// Вызов функции CreateSubscription передавая ей данные подписки \\\
+	subscription, err := h.webhookService.CreateSubscription(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("WEBHOOK SUBSCRIPTION CREATED")
+	response.JSON(w, http.StatusCreated, subscription)
+}
+
+/// Функция GetSubscriptions получает все подписки \\\
+
+func (h *Handler) GetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WEBHOOK SUBSCRIPTIONS")
+
+	subscriptions, err := h.webhookService.GetSubscriptions(r.Context())
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT WEBHOOK SUBSCRIPTIONS")
+	response.JSON(w, http.StatusOK, subscriptions)
+}
+
+/// Функция GetSubscription получает подписку по id \\\
+
+func (h *Handler) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WEBHOOK SUBSCRIPTION")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	subscription, err := h.webhookService.GetSubscription(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT WEBHOOK SUBSCRIPTION")
+	response.JSON(w, http.StatusOK, subscription)
+}
+
+/// Функция PartiallyUpdateSubscription изменяет адрес, типы событий, описание, активность или секрет подписки \\\
+
+func (h *Handler) PartiallyUpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: PARTIALLY UPDATE WEBHOOK SUBSCRIPTION")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	/// Чтение тела запроса в структуру PartiallyUpdateSubscriptionDTO \\\
+	var input PartiallyUpdateSubscriptionDTO
+	if err = response.ReadJSON(w, r, &input); err != nil {
+		response.BadRequest(w, err.Error(), apperror.ErrInvalidRequestBody.Error())
+		return
+	}
+	input.ID = id
+
+	subscription, err := h.webhookService.PartiallyUpdateSubscription(r.Context(), &input)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("WEBHOOK SUBSCRIPTION UPDATED")
+	response.JSON(w, http.StatusOK, subscription)
+}
+
+/// Функция DeleteSubscription удаляет подписку по id \\\
+
+func (h *Handler) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: DELETE WEBHOOK SUBSCRIPTION")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	if err = h.webhookService.DeleteSubscription(r.Context(), id); err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("WEBHOOK SUBSCRIPTION DELETED")
+	response.JSON(w, http.StatusOK, "WEBHOOK SUBSCRIPTION DELETED")
+}
+
+/// Функция GetDeliveries получает последние доставки подписки, параметр status фильтрует по статусу \\\
+
+func (h *Handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WEBHOOK DELIVERIES")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(r.Context(), id, r.URL.Query().Get("status"))
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT WEBHOOK DELIVERIES")
+	response.JSON(w, http.StatusOK, deliveries)
+}
+
+/// Функция GetDelivery получает доставку вместе с попытками \\\
+
+func (h *Handler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: GET WEBHOOK DELIVERY")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("GOT WEBHOOK DELIVERY")
+	response.JSON(w, http.StatusOK, delivery)
+}
+
+/// Функция Replay повторяет доставку \\\
+
+func (h *Handler) Replay(w http.ResponseWriter, r *http.Request) {
+	h.logger.Info("HANDLER: REPLAY WEBHOOK DELIVERY")
+
+	/// Принимает объект r, представляющий HTTP-запрос, и извлекает параметр ID из URL \\\
+	id, err := handler.ReadIdParam64(r)
+	if err != nil {
+		response.BadRequest(w, err.Error(), "")
+		return
+	}
+
+	delivery, err := h.webhookService.Replay(r.Context(), id)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+	h.logger.Info("WEBHOOK DELIVERY REPLAYED")
+	response.JSON(w, http.StatusAccepted, delivery)
+}
+
+/// Функция writeError отвечает клиенту кодом, соответствующим ошибке сервиса \\\
+
+func (h *Handler) writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, apperror.ErrEmptyString):
+		response.NotFound(w)
+	case errors.Is(err, apperror.ErrInvalidWebhook):
+		response.BadRequest(w, err.Error(), "")
+	default:
+		response.InternalError(w, err.Error(), "wrong on the server")
+	}
+}
@@ -0,0 +1,498 @@
+package webhook
+
+import (
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/pkg/logger"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+	"time"
+)
+
+var _ Storage = &WebhookStorage{}
+
+/// Колонки подписки и доставки в порядке сканирования scanSubscription и scanDelivery \\\
+
+const (
+	subscriptionColumns = `id, url, event_types, secret, description, active, created_at`
+	deliveryColumns     = `id, subscription_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+       last_status_code, last_error, delivered_at, created_at`
+)
+
+/// Структура WebhookStorage содержащая поля для работы с БД \\\
+
+type WebhookStorage struct {
+	logger         logger.Logger
+	conn           *pgxpool.Pool
+	requestTimeout time.Duration
+}
+
+/// Структура NewStorage возвращает новый экземпляр WebhookStorage инициализируя переданные в него аргументы \\\
+
+func NewStorage(storage *pgxpool.Pool, requestTimeout int) Storage {
+	return &WebhookStorage{
+		logger:         logger.GetLogger(),
+		conn:           storage,
+		requestTimeout: time.Duration(requestTimeout) * time.Second,
+	}
+}
+
+/// Функция CreateSubscription для сущности WebhookStorage создает подписку в БД \\\
+
+func (w *WebhookStorage) CreateSubscription(subscription *Subscription) (*Subscription, error) {
+	w.logger.Info("POSTGRES: CREATE WEBHOOK SUBSCRIPTION")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	created, err := scanSubscription(w.conn.QueryRow(ctx,
+		`INSERT INTO webhook_subscription (url, event_types, secret, description)
+			 VALUES($1,$2,$3,$4)
+			 RETURNING `+subscriptionColumns,
+		subscription.URL, subscription.EventTypes, subscription.Secret, subscription.Description))
+	if err != nil {
+		err = fmt.Errorf("failed to execute create webhook subscription query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	return created, nil
+}
+
+/// Функция FindSubscriptions для сущности WebhookStorage получает все подписки \\\
+
+func (w *WebhookStorage) FindSubscriptions() ([]Subscription, error) {
+	w.logger.Info("POSTGRES: GET WEBHOOK SUBSCRIPTIONS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := w.conn.Query(ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscription ORDER BY id`)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения подписок \\\
+	subscriptions := make([]Subscription, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find webhook subscriptions query: %v", err)
+			w.logger.Error(err)
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+/// Функция FindSubscriptionById для сущности WebhookStorage получает подписку по id \\\
+
+func (w *WebhookStorage) FindSubscriptionById(id int64) (*Subscription, error) {
+	w.logger.Info("POSTGRES: GET WEBHOOK SUBSCRIPTION BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	subscription, err := scanSubscription(w.conn.QueryRow(ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscription
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find webhook subscription query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	return subscription, nil
+}
+
+/// Функция PartiallyUpdateSubscription для сущности WebhookStorage частично обновляет подписку \\\
+
+func (w *WebhookStorage) PartiallyUpdateSubscription(subscription *PartiallyUpdateSubscriptionDTO) (*Subscription, error) {
+	w.logger.Info("POSTGRES: PARTIALLY UPDATE WEBHOOK SUBSCRIPTION")
+
+	/// Создание пустого слайса для хранения обновляемых строк \\\
+	values := make([]string, 0)
+
+	/// Создание пустого слайса для хранения аргументов запроса \\\
+	args := make([]interface{}, 0)
+	argId := 1
+
+	/// Проверки на наличие новых значений \\\
+	if subscription.URL != nil {
+		values = append(values, fmt.Sprintf("url=$%d", argId))
+		args = append(args, *subscription.URL)
+		argId++
+	}
+	if subscription.EventTypes != nil {
+		values = append(values, fmt.Sprintf("event_types=$%d", argId))
+		args = append(args, *subscription.EventTypes)
+		argId++
+	}
+	if subscription.Description != nil {
+		values = append(values, fmt.Sprintf("description=$%d", argId))
+		args = append(args, *subscription.Description)
+		argId++
+	}
+	if subscription.Active != nil {
+		values = append(values, fmt.Sprintf("active=$%d", argId))
+		args = append(args, *subscription.Active)
+		argId++
+	}
+	if subscription.Secret != nil {
+		values = append(values, fmt.Sprintf("secret=$%d", argId))
+		args = append(args, *subscription.Secret)
+		argId++
+	}
+	if len(values) == 0 {
+		return w.FindSubscriptionById(subscription.ID)
+	}
+
+	/// Формирование строки со всеми измененными полями и их значениями \\\
+	valuesQuery := strings.Join(values, ", ")
+	query := fmt.Sprintf("UPDATE webhook_subscription SET %s WHERE id = $%d RETURNING %s", valuesQuery, argId, subscriptionColumns)
+	args = append(args, subscription.ID)
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	updated, err := scanSubscription(w.conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to update webhook subscription partially: %v", err)
+	}
+	return updated, nil
+}
+
+/// Функция DeleteSubscription для сущности WebhookStorage удаляет подписку вместе с ее доставками \\\
+
+func (w *WebhookStorage) DeleteSubscription(id int64) error {
+	w.logger.Info("POSTGRES: DELETE WEBHOOK SUBSCRIPTION")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := w.conn.Exec(ctx,
+		`DELETE FROM webhook_subscription WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %v", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+	return nil
+}
+
+/// Функция Enqueue для сущности WebhookStorage создает доставки события всем активным подписками на его тип \\\
+/// Повторный вызов для того же события не создает новых доставок \\\
+
+func (w *WebhookStorage) Enqueue(event outbox.Event, payload []byte) (int64, error) {
+	w.logger.Info("POSTGRES: ENQUEUE WEBHOOK DELIVERIES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	result, err := w.conn.Exec(ctx,
+		`INSERT INTO webhook_delivery (subscription_id, event_id, event_type, payload)
+			 SELECT s.id, $1, $2, $3 FROM webhook_subscription s
+			 WHERE s.active AND (cardinality(s.event_types) = 0 OR $2 = ANY(s.event_types))
+			 ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		event.ID, event.Type, payload)
+	if err != nil {
+		err = fmt.Errorf("failed to execute enqueue webhook deliveries query: %v", err)
+		w.logger.Error(err)
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+/// Функция ClaimDue для сущности WebhookStorage захватывает до limit доставок, время попытки которых наступило \\\
+/// Захваченная доставка откладывается на lease, поэтому не будет выбрана повторно, пока не сохранена попытка \\\
+
+func (w *WebhookStorage) ClaimDue(now time.Time, lease time.Duration, limit int) ([]Outgoing, error) {
+	/// Опрос выполняется часто, поэтому пишется в журнал на уровне debug \\\
+	w.logger.Debug("POSTGRES: CLAIM WEBHOOK DELIVERIES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := w.conn.Query(ctx,
+		`WITH claimed AS (
+			 UPDATE webhook_delivery SET next_attempt_at = $1::timestamptz + $2::interval
+			 WHERE id IN (SELECT d.id FROM webhook_delivery d
+			              WHERE d.status = 'pending' AND d.next_attempt_at <= $1
+			              ORDER BY d.next_attempt_at, d.id
+			              LIMIT $3
+			              FOR UPDATE SKIP LOCKED)
+			 RETURNING `+deliveryColumns+`)
+		 SELECT c.id, c.subscription_id, c.event_id, c.event_type, c.payload, c.status, c.attempts, c.next_attempt_at,
+		        c.last_status_code, c.last_error, c.delivered_at, c.created_at, s.url, s.secret
+		 FROM claimed c
+		 INNER JOIN webhook_subscription s ON s.id = c.subscription_id
+		 ORDER BY c.id`, now, lease, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения доставок \\\
+	outgoing := make([]Outgoing, 0)
+
+	for rows.Next() {
+		var o Outgoing
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&o.ID, &o.SubscriptionID, &o.EventID, &o.EventType, &o.Payload, &o.Status, &o.Attempts,
+			&o.NextAttemptAt, &o.LastStatusCode, &o.LastError, &o.DeliveredAt, &o.CreatedAt, &o.URL, &o.Secret)
+		if err != nil {
+			err = fmt.Errorf("failed to execute claim webhook deliveries query: %v", err)
+			w.logger.Error(err)
+			return nil, err
+		}
+		outgoing = append(outgoing, o)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return outgoing, nil
+}
+
+/// Функция SaveAttempt для сущности WebhookStorage сохраняет попытку доставки и новый статус доставки в одной транзакции \\\
+/// Для статуса pending следующая попытка назначается на retryAt \\\
+
+func (w *WebhookStorage) SaveAttempt(attempt *Attempt, status string, retryAt *time.Time) error {
+	w.logger.Info("POSTGRES: SAVE WEBHOOK ATTEMPT")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	tx, err := w.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin save webhook attempt transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	/// Сохранение попытки \\\
+	err = tx.QueryRow(ctx,
+		`INSERT INTO webhook_attempt (delivery_id, status_code, error, duration_ms)
+			 VALUES($1,$2,$3,$4)
+			 RETURNING id, created_at`,
+		attempt.DeliveryID, attempt.StatusCode, attempt.Error, attempt.DurationMs).Scan(&attempt.ID, &attempt.CreatedAt)
+	if err != nil {
+		err = fmt.Errorf("failed to execute create webhook attempt query: %v", err)
+		w.logger.Error(err)
+		return err
+	}
+
+	/// Обновление доставки \\\
+	result, err := tx.Exec(ctx,
+		`UPDATE webhook_delivery
+			 SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4,
+			     next_attempt_at = coalesce($5, next_attempt_at),
+			     delivered_at = CASE WHEN $2 = 'delivered' THEN now() ELSE delivered_at END
+			 WHERE id = $1`,
+		attempt.DeliveryID, status, attempt.StatusCode, attempt.Error, retryAt)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %v", err)
+	}
+	if result.RowsAffected() == 0 {
+		return apperror.ErrEmptyString
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit save webhook attempt transaction: %v", err)
+	}
+	return nil
+}
+
+/// Функция FindDeliveries для сущности WebhookStorage получает последние доставки подписки, status фильтрует по статусу \\\
+
+func (w *WebhookStorage) FindDeliveries(subscriptionId int64, status string, limit int) ([]Delivery, error) {
+	w.logger.Info("POSTGRES: GET WEBHOOK DELIVERIES")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := w.conn.Query(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_delivery
+			 WHERE subscription_id = $1 AND ($2 = '' OR status = $2)
+			 ORDER BY id DESC
+			 LIMIT $3`, subscriptionId, status, limit)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения доставок \\\
+	deliveries := make([]Delivery, 0)
+
+	for rows.Next() {
+		/// Сканирование полученных значений из БД \\\
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find webhook deliveries query: %v", err)
+			w.logger.Error(err)
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+/// Функция FindDeliveryById для сущности WebhookStorage получает доставку по id \\\
+
+func (w *WebhookStorage) FindDeliveryById(id int64) (*Delivery, error) {
+	w.logger.Info("POSTGRES: GET WEBHOOK DELIVERY BY ID")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	delivery, err := scanDelivery(w.conn.QueryRow(ctx,
+		`SELECT `+deliveryColumns+` FROM webhook_delivery
+			 WHERE id = $1`, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		err = fmt.Errorf("failed to execute find webhook delivery query: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	return delivery, nil
+}
+
+/// Функция FindAttempts для сущности WebhookStorage получает попытки доставки в порядке их выполнения \\\
+
+func (w *WebhookStorage) FindAttempts(deliveryId int64) ([]Attempt, error) {
+	w.logger.Info("POSTGRES: GET WEBHOOK ATTEMPTS")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	rows, err := w.conn.Query(ctx,
+		`SELECT id, delivery_id, status_code, error, duration_ms, created_at FROM webhook_attempt
+			 WHERE delivery_id = $1
+			 ORDER BY id`, deliveryId)
+	if err != nil {
+		err = fmt.Errorf("failed to SELLECT: %v", err)
+		w.logger.Error(err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	/// Создание пустого слайса для хранения попыток \\\
+	attempts := make([]Attempt, 0)
+
+	for rows.Next() {
+		var a Attempt
+
+		/// Сканирование полученных значений из БД \\\
+		err = rows.Scan(&a.ID, &a.DeliveryID, &a.StatusCode, &a.Error, &a.DurationMs, &a.CreatedAt)
+		if err != nil {
+			err = fmt.Errorf("failed to execute find webhook attempts query: %v", err)
+			w.logger.Error(err)
+			return nil, err
+		}
+		attempts = append(attempts, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+/// Функция Replay для сущности WebhookStorage возвращает доставку в очередь с обнуленным счетчиком попыток \\\
+/// История прежних попыток сохраняется \\\
+
+func (w *WebhookStorage) Replay(id int64) (*Delivery, error) {
+	w.logger.Info("POSTGRES: REPLAY WEBHOOK DELIVERY")
+
+	/// Ограничение времени выполнения запроса \\\
+	ctx, cancel := context.WithTimeout(context.Background(), w.requestTimeout)
+	defer cancel()
+
+	/// Выполнение запроса к БД \\\
+	delivery, err := scanDelivery(w.conn.QueryRow(ctx,
+		`UPDATE webhook_delivery SET status = 'pending', attempts = 0, next_attempt_at = now(), delivered_at = NULL
+			 WHERE id = $1
+			 RETURNING `+deliveryColumns, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.ErrEmptyString
+		}
+		return nil, fmt.Errorf("failed to replay webhook delivery: %v", err)
+	}
+	return delivery, nil
+}
+
+/// Функция scanSubscription сканирует строку подписки в порядке subscriptionColumns \\\
+
+func scanSubscription(row pgx.Row) (*Subscription, error) {
+	s := &Subscription{}
+	err := row.Scan(&s.ID, &s.URL, &s.EventTypes, &s.Secret, &s.Description, &s.Active, &s.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+/// Функция scanDelivery сканирует строку доставки в порядке deliveryColumns \\\
+
+func scanDelivery(row pgx.Row) (*Delivery, error) {
+	d := &Delivery{}
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
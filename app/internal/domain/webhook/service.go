@@ -0,0 +1,399 @@
+package webhook
+
+import (
+	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/apperror"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/pkg/logger"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+/// Заголовки подписанной доставки \\\
+/// Подпись - HMAC-SHA256 секрета подписки от строки "<timestamp>.<тело запроса>" в шестнадцатеричном виде с префиксом v1= \\\
+
+const (
+	headerID        = "X-Webhook-Id"
+	headerEvent     = "X-Webhook-Event"
+	headerTimestamp = "X-Webhook-Timestamp"
+	headerSignature = "X-Webhook-Signature"
+	signatureScheme = "v1="
+)
+
+/// Время, на которое захватывается пачка доставок, предел истории в списке доставок и имя подписчика outbox \\\
+
+const (
+	claimLease      = 10 * time.Minute
+	deliveriesLimit = 100
+	subscriberName  = "webhooks"
+	maxBackoffStep  = 20
+)
+
+/// Интерфейс Service реализизирующий service и методы для webhook подписок партнеров \\\
+
+type Service interface {
+	outbox.Subscriber
+	CreateSubscription(ctx context.Context, input *CreateSubscriptionDTO) (*Subscription, error)
+	GetSubscriptions(ctx context.Context) (*[]Subscription, error)
+	GetSubscription(ctx context.Context, id int64) (*Subscription, error)
+	PartiallyUpdateSubscription(ctx context.Context, input *PartiallyUpdateSubscriptionDTO) (*Subscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+	GetDeliveries(ctx context.Context, subscriptionId int64, status string) (*[]Delivery, error)
+	GetDelivery(ctx context.Context, id int64) (*Delivery, error)
+	Replay(ctx context.Context, id int64) (*Delivery, error)
+	Run(ctx context.Context)
+}
+
+/// Структура  service реализизирующая инфтерфейс Service webhook \\\
+
+type service struct {
+	logger      logger.Logger
+	storage     Storage
+	client      *http.Client
+	poll        time.Duration
+	batchSize   int
+	maxAttempts int
+	retry       time.Duration
+	maxRetry    time.Duration
+}
+
+/// Структура NewService возвращает новый экземпляр Service инициализируя переданные в него аргументы \\\
+
+func NewService(storage Storage, cfg *config.Config, logger logger.Logger) Service {
+	return &service{
+		logger:      logger,
+		storage:     storage,
+		client:      &http.Client{Timeout: time.Duration(cfg.Webhooks.TimeoutSeconds) * time.Second},
+		poll:        time.Duration(cfg.Webhooks.PollSeconds) * time.Second,
+		batchSize:   cfg.Webhooks.BatchSize,
+		maxAttempts: cfg.Webhooks.MaxAttempts,
+		retry:       time.Duration(cfg.Webhooks.RetrySeconds) * time.Second,
+		maxRetry:    time.Duration(cfg.Webhooks.MaxRetryMinutes) * time.Minute,
+	}
+}
+
+/// Функция CreateSubscription создает подписку, без переданного секрета генерируется случайный \\\
+
+func (s *service) CreateSubscription(ctx context.Context, input *CreateSubscriptionDTO) (*Subscription, error) {
+	s.logger.Info("SERVICE: CREATE WEBHOOK SUBSCRIPTION")
+
+	if err := validate(&input.URL, &input.EventTypes); err != nil {
+		return nil, err
+	}
+	secret, err := secretOrRandom(input.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &Subscription{
+		URL:         input.URL,
+		EventTypes:  input.EventTypes,
+		Secret:      secret,
+		Description: input.Description,
+	}
+	if subscription.EventTypes == nil {
+		subscription.EventTypes = []string{}
+	}
+	return s.storage.CreateSubscription(subscription)
+}
+
+/// Функция GetSubscriptions возвращает все подписки без секретов \\\
+
+func (s *service) GetSubscriptions(ctx context.Context) (*[]Subscription, error) {
+	s.logger.Info("SERVICE: GET WEBHOOK SUBSCRIPTIONS")
+
+	subscriptions, err := s.storage.FindSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return &subscriptions, nil
+}
+
+/// Функция GetSubscription возвращает подписку по id без секрета \\\
+
+func (s *service) GetSubscription(ctx context.Context, id int64) (*Subscription, error) {
+	s.logger.Info("SERVICE: GET WEBHOOK SUBSCRIPTION")
+
+	subscription, err := s.storage.FindSubscriptionById(id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+/// Функция PartiallyUpdateSubscription частично обновляет подписку, новый секрет возвращается только при его замене \\\
+
+func (s *service) PartiallyUpdateSubscription(ctx context.Context, input *PartiallyUpdateSubscriptionDTO) (*Subscription, error) {
+	s.logger.Info("SERVICE: PARTIALLY UPDATE WEBHOOK SUBSCRIPTION")
+
+	if input.URL != nil {
+		if err := validate(input.URL, nil); err != nil {
+			return nil, err
+		}
+	}
+	if input.EventTypes != nil {
+		if *input.EventTypes == nil {
+			*input.EventTypes = []string{}
+		}
+		if err := validate(nil, input.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	input.Secret = nil
+	if input.RotateSecret {
+		secret, err := secretOrRandom(nil)
+		if err != nil {
+			return nil, err
+		}
+		input.Secret = &secret
+	}
+
+	subscription, err := s.storage.PartiallyUpdateSubscription(input)
+	if err != nil {
+		return nil, err
+	}
+	if !input.RotateSecret {
+		subscription.Secret = ""
+	}
+	return subscription, nil
+}
+
+/// Функция DeleteSubscription удаляет подписку вместе с историей ее доставок \\\
+
+func (s *service) DeleteSubscription(ctx context.Context, id int64) error {
+	s.logger.Info("SERVICE: DELETE WEBHOOK SUBSCRIPTION")
+
+	return s.storage.DeleteSubscription(id)
+}
+
+/// Функция GetDeliveries возвращает последние доставки подписки, status фильтрует по статусу доставки \\\
+
+func (s *service) GetDeliveries(ctx context.Context, subscriptionId int64, status string) (*[]Delivery, error) {
+	s.logger.Info("SERVICE: GET WEBHOOK DELIVERIES")
+
+	if status != "" && status != DeliveryPending && status != DeliveryDelivered && status != DeliveryDead {
+		return nil, fmt.Errorf("%w: unknown delivery status %q", apperror.ErrInvalidWebhook, status)
+	}
+	if _, err := s.storage.FindSubscriptionById(subscriptionId); err != nil {
+		return nil, err
+	}
+	deliveries, err := s.storage.FindDeliveries(subscriptionId, status, deliveriesLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &deliveries, nil
+}
+
+/// Функция GetDelivery возвращает доставку вместе с историей попыток \\\
+
+func (s *service) GetDelivery(ctx context.Context, id int64) (*Delivery, error) {
+	s.logger.Info("SERVICE: GET WEBHOOK DELIVERY")
+
+	delivery, err := s.storage.FindDeliveryById(id)
+	if err != nil {
+		return nil, err
+	}
+	delivery.History, err = s.storage.FindAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+/// Функция Replay возвращает доставку, в том числе из dead-letter, в очередь отправки \\\
+
+func (s *service) Replay(ctx context.Context, id int64) (*Delivery, error) {
+	s.logger.Info("SERVICE: REPLAY WEBHOOK DELIVERY")
+
+	return s.storage.Replay(id)
+}
+
+/// Функция Name возвращает имя подписчика outbox \\\
+
+func (s *service) Name() string {
+	return subscriberName
+}
+
+/// Функция Handle получает доменное событие из outbox и ставит его в очередь доставки подходящим подпискам \\\
+
+func (s *service) Handle(ctx context.Context, event outbox.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = s.storage.Enqueue(event, payload)
+	return err
+}
+
+/// Функция Run периодически отправляет наступившие доставки \\\
+/// Работает до отмены ctx, при poll_seconds <= 0 webhook не отправляются \\\
+
+func (s *service) Run(ctx context.Context) {
+	if s.poll <= 0 {
+		s.logger.Info("webhook deliveries are disabled")
+		return
+	}
+	ticker := time.NewTicker(s.poll)
+	defer ticker.Stop()
+
+	for {
+		s.dispatch(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/// Функция dispatch захватывает наступившие доставки пачками и отправляет их \\\
+
+func (s *service) dispatch(ctx context.Context) {
+	for ctx.Err() == nil {
+		outgoing, err := s.storage.ClaimDue(time.Now(), claimLease, s.batchSize)
+		if err != nil {
+			s.logger.Warnf("failed to claim webhook deliveries: %v", err)
+			return
+		}
+		for i := range outgoing {
+			s.deliver(ctx, &outgoing[i])
+		}
+		if len(outgoing) < s.batchSize {
+			return
+		}
+	}
+}
+
+/// Функция deliver выполняет одну попытку доставки и сохраняет ее результат \\\
+/// После max_attempts неудачных попыток доставка переходит в статус dead \\\
+
+func (s *service) deliver(ctx context.Context, o *Outgoing) {
+	started := time.Now()
+	statusCode, sendErr := s.send(ctx, o)
+	attempt := &Attempt{
+		DeliveryID: o.ID,
+		DurationMs: int(time.Since(started) / time.Millisecond),
+	}
+	if statusCode != 0 {
+		attempt.StatusCode = &statusCode
+	}
+
+	status := DeliveryDelivered
+	var retryAt *time.Time
+	if sendErr != nil {
+		reason := sendErr.Error()
+		attempt.Error = &reason
+		status = DeliveryDead
+		if o.Attempts+1 < s.maxAttempts {
+			next := time.Now().Add(s.backoff(o.Attempts + 1))
+			status, retryAt = DeliveryPending, &next
+		}
+		s.logger.Warnf("webhook delivery %d to %s failed: %v", o.ID, o.URL, sendErr)
+	}
+
+	if err := s.storage.SaveAttempt(attempt, status, retryAt); err != nil {
+		s.logger.Warnf("failed to save webhook delivery %d attempt: %v", o.ID, err)
+	}
+}
+
+/// Функция send отправляет подписанный запрос и возвращает код ответа, ответ вне диапазона 2xx считается ошибкой \\\
+
+func (s *service) send(ctx context.Context, o *Outgoing) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.URL, bytes.NewReader(o.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %v", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerID, strconv.FormatInt(o.ID, 10))
+	req.Header.Set(headerEvent, o.EventType)
+	req.Header.Set(headerTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(headerSignature, Sign(o.Secret, timestamp, o.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("partner responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+/// Функция backoff возвращает задержку перед попыткой attempts+1: retry, 2*retry, 4*retry... но не больше maxRetry \\\
+
+func (s *service) backoff(attempts int) time.Duration {
+	step := attempts - 1
+	if step < 0 {
+		step = 0
+	}
+	if step > maxBackoffStep {
+		step = maxBackoffStep
+	}
+	delay := s.retry << uint(step)
+	if s.maxRetry > 0 && delay > s.maxRetry {
+		delay = s.maxRetry
+	}
+	return delay
+}
+
+/// Функция Sign возвращает значение заголовка X-Webhook-Signature для тела body, отправленного в момент timestamp \\\
+/// Партнер проверяет подпись тем же способом и отклоняет запросы со старым timestamp \\\
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureScheme + hex.EncodeToString(mac.Sum(nil))
+}
+
+/// Функция validate проверяет адрес подписки и типы событий, nil аргумент не проверяется \\\
+
+func validate(rawURL *string, eventTypes *[]string) error {
+	if rawURL != nil {
+		u, err := url.Parse(*rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: invalid url %q", apperror.ErrInvalidWebhook, *rawURL)
+		}
+	}
+	if eventTypes != nil {
+		for _, t := range *eventTypes {
+			if !outbox.IsEventType(t) {
+				return fmt.Errorf("%w: unknown event type %q", apperror.ErrInvalidWebhook, t)
+			}
+		}
+	}
+	return nil
+}
+
+/// Функция secretOrRandom возвращает переданный секрет или новый случайный \\\
+
+func secretOrRandom(secret *string) (string, error) {
+	if secret != nil && *secret != "" {
+		return *secret, nil
+	}
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %v", err)
+	}
+	return "whsec_" + hex.EncodeToString(raw), nil
+}
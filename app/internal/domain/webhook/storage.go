@@ -0,0 +1,21 @@
+package webhook
+
+import (
+	"HospitalRecord/app/internal/domain/outbox"
+	"time"
+)
+
+type Storage interface {
+	CreateSubscription(subscription *Subscription) (*Subscription, error)
+	FindSubscriptions() ([]Subscription, error)
+	FindSubscriptionById(id int64) (*Subscription, error)
+	PartiallyUpdateSubscription(subscription *PartiallyUpdateSubscriptionDTO) (*Subscription, error)
+	DeleteSubscription(id int64) error
+	Enqueue(event outbox.Event, payload []byte) (int64, error)
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]Outgoing, error)
+	SaveAttempt(attempt *Attempt, status string, retryAt *time.Time) error
+	FindDeliveries(subscriptionId int64, status string, limit int) ([]Delivery, error)
+	FindDeliveryById(id int64) (*Delivery, error)
+	FindAttempts(deliveryId int64) ([]Attempt, error)
+	Replay(id int64) (*Delivery, error)
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+/// Статусы доставки webhook: dead - попытки исчерпаны, доставку можно повторить вручную \\\
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+/// Структура подписки партнера на события, пустой EventTypes означает все события \\\
+/// Секрет возвращается только при создании подписки и при его замене \\\
+
+type Subscription struct {
+	ID          int64     `json:"id" example:"1"`
+	URL         string    `json:"url" example:"https://partner.example.com/hooks/hospital"`
+	EventTypes  []string  `json:"event_types" example:"record.created,record.cancelled"`
+	Secret      string    `json:"secret,omitempty" example:"whsec_5f2b..."`
+	Description *string   `json:"description,omitempty" example:"Insurer appointments feed"`
+	Active      bool      `json:"active" example:"true"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CreateSubscriptionDTO struct {
+	URL         string   `json:"url" example:"https://partner.example.com/hooks/hospital"`
+	EventTypes  []string `json:"event_types" example:"record.created,record.cancelled"`
+	Secret      *string  `json:"secret,omitempty" example:"whsec_5f2b..."`
+	Description *string  `json:"description,omitempty" example:"Insurer appointments feed"`
+}
+
+/// При RotateSecret подписка получает новый случайный секрет \\\
+
+type PartiallyUpdateSubscriptionDTO struct {
+	ID           int64     `json:"-"`
+	URL          *string   `json:"url,omitempty" example:"https://partner.example.com/hooks/hospital"`
+	EventTypes   *[]string `json:"event_types,omitempty" example:"record.created"`
+	Description  *string   `json:"description,omitempty" example:"Insurer appointments feed"`
+	Active       *bool     `json:"active,omitempty" example:"false"`
+	RotateSecret bool      `json:"rotate_secret,omitempty" example:"false"`
+	Secret       *string   `json:"-"`
+}
+
+/// Структура доставки одного события одной подписке \\\
+
+type Delivery struct {
+	ID             int64           `json:"id" example:"1"`
+	SubscriptionID int64           `json:"subscription_id" example:"1"`
+	EventID        int64           `json:"event_id" example:"1"`
+	EventType      string          `json:"event_type" example:"record.created"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status" example:"pending"`
+	Attempts       int             `json:"attempts" example:"1"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode *int            `json:"last_status_code,omitempty" example:"503"`
+	LastError      *string         `json:"last_error,omitempty" example:"partner responded 503"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	History        []Attempt       `json:"history,omitempty"`
+}
+
+/// Структура одной попытки доставки: код ответа партнера или ошибка соединения \\\
+
+type Attempt struct {
+	ID         int64     `json:"id" example:"1"`
+	DeliveryID int64     `json:"delivery_id" example:"1"`
+	StatusCode *int      `json:"status_code,omitempty" example:"503"`
+	Error      *string   `json:"error,omitempty" example:"partner responded 503"`
+	DurationMs int       `json:"duration_ms" example:"120"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+/// Структура доставки, захваченной для отправки, с адресом и секретом подписки \\\
+
+type Outgoing struct {
+	Delivery
+	URL    string
+	Secret string
+}
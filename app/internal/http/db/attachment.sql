@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS attachment;
+
+CREATE TABLE IF NOT EXISTS attachment(
+ id             bigserial       primary key,
+ blob_key       char(64)        not null,
+ file_name      text            not null,
+ content_type   text            not null,
+ size           bigint          not null,
+ owner_type     text            not null check (owner_type in ('doctor', 'patient', 'record', 'lab_result')),
+ owner_id       bigint          not null,
+ uploaded_by    bigint,
+ created_at     timestamptz     not null default now(),
+
+ foreign key(uploaded_by) references patients(id) on delete set null
+);
+CREATE INDEX IF NOT EXISTS attachment_owner_idx ON attachment(owner_type, owner_id);
+CREATE INDEX IF NOT EXISTS attachment_blob_key_idx ON attachment(blob_key);
@@ -0,0 +1,68 @@
+DROP TABLE IF EXISTS payment;
+DROP TABLE IF EXISTS invoice_item;
+DROP TABLE IF EXISTS invoice;
+DROP TABLE IF EXISTS service_price;
+
+CREATE TABLE IF NOT EXISTS service_price(
+ id                 bigserial       primary key,
+ code               text            not null unique,
+ name               text            not null,
+ specialization_id  bigint,
+ amount             bigint          not null check (amount >= 0),
+ active             boolean         not null default true,
+ created_at         timestamptz     not null default now(),
+
+ foreign key(specialization_id) references specialization(id) on delete set null
+);
+CREATE UNIQUE INDEX IF NOT EXISTS service_price_specialization_idx ON service_price(specialization_id) WHERE active;
+
+CREATE TABLE IF NOT EXISTS invoice(
+ id                 bigserial       primary key,
+ patient_id         bigint          not null,
+ record_id          bigint          unique,
+ status             text            not null default 'issued' check (status in ('issued', 'partially_paid', 'paid')),
+ subtotal           bigint          not null default 0,
+ discount_percent   int             not null default 0 check (discount_percent between 0 and 100),
+ discount_reason    text,
+ total              bigint          not null default 0,
+ paid               bigint          not null default 0,
+ created_at         timestamptz     not null default now(),
+
+ check (paid <= total),
+ foreign key(patient_id) references patients(id) on delete cascade,
+ foreign key(record_id) references record(id) on delete set null
+);
+CREATE INDEX IF NOT EXISTS invoice_patient_idx ON invoice(patient_id, status);
+
+CREATE TABLE IF NOT EXISTS invoice_item(
+ id                 bigserial       primary key,
+ invoice_id         bigint          not null,
+ price_id           bigint,
+ name               text            not null,
+ quantity           int             not null check (quantity > 0),
+ unit_price         bigint          not null check (unit_price >= 0),
+ amount             bigint          not null,
+
+ foreign key(invoice_id) references invoice(id) on delete cascade,
+ foreign key(price_id) references service_price(id) on delete set null
+);
+
+CREATE TABLE IF NOT EXISTS payment(
+ id                 bigserial       primary key,
+ invoice_id         bigint          not null,
+ method             text            not null check (method in ('cash', 'card', 'insurance')),
+ amount             bigint          not null check (amount > 0),
+ reference          text,
+ created_at         timestamptz     not null default now(),
+
+ foreign key(invoice_id) references invoice(id) on delete cascade
+);
+
+INSERT INTO service_price (id, code, name, specialization_id, amount)
+VALUES ('1', 'B01.029.001', 'Priem vracha-oftalmologa', '1', '150000');
+INSERT INTO service_price (id, code, name, specialization_id, amount)
+VALUES ('2', 'B01.057.001', 'Priem vracha-hirurga', '2', '180000');
+INSERT INTO service_price (id, code, name, amount)
+VALUES ('3', 'A02.26.015', 'Tonometriya glaza', '50000');
+
+SELECT setval('service_price_id_seq', (SELECT max(id) FROM service_price));
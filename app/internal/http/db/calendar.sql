@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS calendar_token;
+
+CREATE TABLE IF NOT EXISTS calendar_token(
+ owner_type     text            not null check (owner_type in ('patient', 'doctor')),
+ owner_id       bigint          not null,
+ token_hash     text            not null unique,
+ created_at     timestamptz     not null default now(),
+
+ primary key(owner_type, owner_id)
+);
@@ -0,0 +1,40 @@
+DROP TABLE IF EXISTS claim_item;
+DROP TABLE IF EXISTS claim_batch;
+
+CREATE TABLE IF NOT EXISTS claim_batch(
+ id                 bigserial       primary key,
+ insurer_id         bigint          not null,
+ period_from        date            not null,
+ period_to          date            not null,
+ status             text            not null default 'draft' check (status in ('draft', 'submitted', 'accepted', 'rejected')),
+ rejection_reason   text,
+ total              bigint          not null default 0,
+ created_at         timestamptz     not null default now(),
+ submitted_at       timestamptz,
+ decided_at         timestamptz,
+
+ check (period_to >= period_from),
+ check (status <> 'rejected' or rejection_reason is not null),
+ foreign key(insurer_id) references insurer(id)
+);
+CREATE INDEX IF NOT EXISTS claim_batch_insurer_idx ON claim_batch(insurer_id, period_from);
+
+CREATE TABLE IF NOT EXISTS claim_item(
+ id                 bigserial       primary key,
+ batch_id           bigint          not null,
+ record_id          bigint          not null,
+ kind               text            not null check (kind in ('visit', 'procedure')),
+ patient_id         bigint          not null,
+ policy_number      text            not null,
+ doctor_id          bigint          not null,
+ specialization_id  bigint          not null,
+ service_code       text            not null,
+ service_name       text            not null,
+ quantity           int             not null check (quantity > 0),
+ amount             bigint          not null check (amount >= 0),
+ time_record        timestamptz     not null,
+
+ foreign key(batch_id) references claim_batch(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS claim_item_batch_idx ON claim_item(batch_id);
+CREATE INDEX IF NOT EXISTS claim_item_record_idx ON claim_item(record_id);
@@ -1,12 +1,12 @@
+DROP VIEW IF EXISTS patients_disease;
+DROP VIEW IF EXISTS doctor_specialization_portfolio;
+DROP VIEW IF EXISTS appointment_card;
 DROP TABLE IF EXISTS patients;
 DROP TABLE IF EXISTS specialization;
 DROP TABLE IF EXISTS portfolio;
 DROP TABLE IF EXISTS disease;
 DROP TABLE IF EXISTS doctors;
-DROP TABLE IF EXISTS patients_disease;
-DROP TABLE IF EXISTS doctor_specialization_portfolio;
 DROP TABLE IF EXISTS record;
-DROP TABLE IF EXISTS appointment_card;
 
 CREATE TABLE IF NOT EXISTS disease(
  id             bigserial       primary key,
@@ -40,20 +40,19 @@ INSERT INTO patients (id, email, name, surname, patronymic,
                       password, policy_number, disease_id, created_at)
 VALUES ('1', 'secondpatient@mail.ru','Julia','Vasilieva','Evgenievna','21',
         'female','89998887765','Moscow, Prospect Mira d. 5, kv. 201',
-        '123456','2194589700000051','{1}', now());
+        '123456','2194589700000056','{1}', now());
 
 INSERT INTO patients (id, email, name, surname, patronymic,
                       age, gender, phone_number, address,
                       password, policy_number, disease_id, created_at)
 VALUES ('2', 'firstpatient@mail.ru','Roman','Kochanov','Danilovich','21',
         'male','89998887766','Moscow, Prospect Mira d. 5, kv. 200',
-        '123456','2194589700000050','{2,3}', now());
-DROP TABLE IF EXISTS patients_disease;
-CREATE TABLE IF NOT EXISTS patients_disease AS(
-    SELECT p.email, p.name, p.surname, p.patronymic, p.age, p.gender, p.phone_number, p.address, p.password, p.policy_number,p.created_at, d.body_part, d.description
+        '123456','2194589700000049','{2,3}', now());
+CREATE OR REPLACE VIEW patients_disease AS
+    SELECT p.id AS patient_id, p.email, p.name, p.surname, p.patronymic, p.age, p.gender, p.phone_number, p.address, p.policy_number, p.created_at,
+           d.id AS disease_id, d.body_part, d.description
     FROM patients p
-             INNER JOIN disease d ON p.disease_id @> ARRAY[d.id]::bigint[]
-    ORDER BY p.surname ASC, p.name ASC, p.patronymic ASC);
+             LEFT JOIN disease d ON p.disease_id @> ARRAY[d.id]::bigint[];
 
 
 CREATE TABLE IF NOT EXISTS specialization(
@@ -66,15 +65,12 @@ INSERT INTO specialization (id, name_specialization)
 VALUES ('2','surgeon');
 
 CREATE TABLE IF NOT EXISTS portfolio(
- id               serial       primary key,
- education        text            not null,
- awards           text            not null,
- work_experience  int2            not null
+ id               serial       primary key
 );
-INSERT INTO portfolio (id, education, awards, work_experience)
-VALUES ('1','residency Institute of N. I. Pirogov','The best doctor of the hospital number 56','20');
-INSERT INTO portfolio (id, education, awards, work_experience)
-VALUES ('2','residency Institute of N. I. Pirogov','Advanced training course of 4 categories','15');
+INSERT INTO portfolio (id)
+VALUES ('1');
+INSERT INTO portfolio (id)
+VALUES ('2');
 
 CREATE TABLE IF NOT EXISTS doctors(
  id                        bigserial      primary key,
@@ -96,12 +92,29 @@ VALUES ('1', 'Boris', 'Semenov', 'Ivanovich','1','male','4.3','41','true','1','1
 INSERT INTO doctors (id, name, surname, patronymic, image_id, gender, rating, age, recording_is_available, specialization_id, portfolio_id)
 VALUES ('2', 'Oleg', 'Sidorov', 'Vitalievich','2','male','4.8','46','true','2','2');
 
-CREATE TABLE IF NOT EXISTS doctor_specialization_portfolio AS(
-SELECT d.name, d.surname, d.patronymic, d.image_id, d.gender ,d.rating ,d.age ,d.recording_is_available,s.name_specialization, p.education, p.awards, p.work_experience
+CREATE TABLE IF NOT EXISTS doctor_specialization(
+ doctor_id                 bigint         not null,
+ specialization_id         bigint         not null,
+ primary key(doctor_id, specialization_id),
+ foreign key(doctor_id) references doctors(id) on delete cascade,
+ foreign key(specialization_id) references specialization(id) on delete cascade
+);
+INSERT INTO doctor_specialization (doctor_id, specialization_id)
+SELECT id, specialization_id FROM doctors
+ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE VIEW doctor_specialization_portfolio AS
+SELECT d.id AS doctor_id, d.name, d.surname, d.patronymic, d.image_id, d.gender, d.rating, d.age, d.recording_is_available,
+       d.specialization_id, s.name_specialization,
+       ARRAY(SELECT ss.name_specialization
+             FROM doctor_specialization ds
+                      INNER JOIN specialization ss ON ds.specialization_id = ss.id
+             WHERE ds.doctor_id = d.id
+             ORDER BY ss.name_specialization) AS specializations,
+       p.id AS portfolio_id
 FROM doctors d
          INNER  JOIN  specialization s ON d.specialization_id = s.id
-         INNER  JOIN  portfolio p ON d.portfolio_id = p.id
-ORDER BY d.surname ASC, d.name ASC, d.patronymic ASC);
+         INNER  JOIN  portfolio p ON d.portfolio_id = p.id;
 
 
 DROP TABLE IF EXISTS record;
@@ -114,6 +127,8 @@ CREATE TABLE IF NOT EXISTS record(
  doctor_id          bigint          not null,
  specialization_id  bigint          not null,
  time_record        timestamptz     not null,
+ office_id          bigint,
+ referral_id        bigint,
 
  foreign key(patients_id) references patients(id) on delete cascade,
  foreign key(specialization_id) references specialization(id) on delete cascade,
@@ -124,11 +139,11 @@ VALUES ('1','1','1','2023-07-27 15:30:00 UTC');
 INSERT INTO record (patients_id, doctor_id, specialization_id,time_record)
 VALUES ('2','2','2','2023-07-27 15:30:00 UTC');
 
-DROP TABLE IF EXISTS appointment_card;
-CREATE TABLE IF NOT EXISTS appointment_card AS(
- SELECT r.hospital_address, r.doctor_office, r.tagging, p.name AS patient_name, p.surname AS patient_surname, d.surname AS doctor_surname, d.name AS doctor_name, d.patronymic, s.name_specialization
+CREATE OR REPLACE VIEW appointment_card AS
+ SELECT r.id AS record_id, r.patients_id, concat_ws(' ', p.surname, p.name, p.patronymic) AS patient_name,
+        r.doctor_id, concat_ws(' ', d.surname, d.name, d.patronymic) AS doctor_name,
+        r.specialization_id, s.name_specialization, r.hospital_address, r.doctor_office, r.tagging, r.time_record
  FROM record r
  INNER JOIN patients p ON r.patients_id = p.id
  INNER JOIN doctors d ON r.doctor_id = d.id
- INNER JOIN specialization s ON r.specialization_id = s.id
-);
\ No newline at end of file
+ INNER JOIN specialization s ON r.specialization_id = s.id;
\ No newline at end of file
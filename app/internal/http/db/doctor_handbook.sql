@@ -1,3 +1,6 @@
+DROP VIEW IF EXISTS disease_procedures_medications;
+DROP VIEW IF EXISTS medications_manufacturer_supplier;
+DROP VIEW IF EXISTS prescription_pacient;
 DROP TABLE IF EXISTS disease;
 DROP TABLE IF EXISTS description;
 DROP TABLE IF EXISTS medications;
@@ -5,10 +8,7 @@ DROP TABLE IF EXISTS supplier;
 DROP TABLE IF EXISTS manufacturer;
 DROP TABLE IF EXISTS procedures;
 DROP TABLE IF EXISTS description;
-DROP TABLE IF EXISTS disease_procedures_medications;
-DROP TABLE IF EXISTS medications_manufacturer_supplier;
 DROP TABLE IF EXISTS prescription;
-DROP TABLE IF EXISTS prescription_pacient;
 
 CREATE TABLE IF NOT EXISTS medications(
  id                     bigserial   primary key,
@@ -78,20 +78,18 @@ INSERT INTO procedures (id, name_procedures, description_procedures)
 VALUES ('2','Gastroskopia','jeludoc i kishechnic');
 
 
-CREATE TABLE IF NOT EXISTS disease_procedures_medications AS(
+CREATE OR REPLACE VIEW disease_procedures_medications AS
 SELECT d.*, p.name_procedures, p.description_procedures, m.name
 FROM disease d
          LEFT JOIN procedures p ON d.procedur_id @> ARRAY[p.id]
-         LEFT JOIN medications m ON d.medications_id @> ARRAY[m.id]
-ORDER BY d.name_disease ASC);
+         LEFT JOIN medications m ON d.medications_id @> ARRAY[m.id];
 
 
-CREATE TABLE IF NOT EXISTS medications_manufacturer_supplier AS(
+CREATE OR REPLACE VIEW medications_manufacturer_supplier AS
 SELECT d.*, m.name_manufacturer, s.name_supplier, s.price
 FROM medications d
         INNER JOIN supplier s ON d.supplier_id = s.id
-        INNER JOIN manufacturer m ON d.manufacturer_id = m.id
-ORDER BY d.name ASC);
+        INNER JOIN manufacturer m ON d.manufacturer_id = m.id;
 
 CREATE TABLE IF NOT EXISTS description(
  id              bigserial    primary key,
@@ -122,10 +120,9 @@ VALUES ('1','1','1','1', now());
 INSERT INTO prescription (id, disease_id, midications_id, description_id, created_at)
 VALUES ('2','2','2','2', now());
 
-CREATE TABLE IF NOT EXISTS prescription_pacient AS(
- SELECT p.created_at, d.name_disease, d.quanyity_medicat, m.name, m.interchangeability, f.appointed, f.instruction
+CREATE OR REPLACE VIEW prescription_pacient AS
+ SELECT p.id AS prescription_id, p.created_at, d.name_disease, d.quanyity_medicat, m.name, m.interchangeability, f.appointed, f.instruction
  FROM prescription p
  INNER JOIN disease d ON p.disease_id = d.id
  INNER JOIN medications m ON p.midications_id = m.id
- INNER JOIN description f ON p.description_id = f.id
-);
+ INNER JOIN description f ON p.description_id = f.id;
@@ -0,0 +1,75 @@
+ALTER TABLE IF EXISTS record DROP CONSTRAINT IF EXISTS record_office_fk;
+DROP TABLE IF EXISTS office_schedule;
+DROP TABLE IF EXISTS office;
+DROP TABLE IF EXISTS department;
+DROP TABLE IF EXISTS hospital;
+
+CREATE TABLE IF NOT EXISTS hospital(
+ id                 bigserial       primary key,
+ name               text            not null,
+ address            text            not null unique,
+ phone              text,
+ email              text,
+ working_hours      jsonb           not null default '[]'
+);
+
+CREATE TABLE IF NOT EXISTS department(
+ id                 bigserial       primary key,
+ hospital_id        bigint          not null,
+ name               text            not null,
+ specialization_id  bigint,
+ phone              text,
+
+ unique(hospital_id, name),
+ foreign key(hospital_id) references hospital(id) on delete cascade,
+ foreign key(specialization_id) references specialization(id) on delete set null
+);
+
+CREATE TABLE IF NOT EXISTS office(
+ id                 bigserial       primary key,
+ department_id      bigint          not null,
+ number             text            not null,
+ floor              integer,
+ name               text,
+
+ foreign key(department_id) references department(id) on delete cascade
+);
+
+CREATE TABLE IF NOT EXISTS office_schedule(
+ id                 bigserial       primary key,
+ office_id          bigint          not null,
+ doctor_id          bigint          not null,
+ weekday            integer         not null check (weekday between 1 and 7),
+ starts_at          time            not null,
+ ends_at            time            not null,
+ valid_from         date            not null default current_date,
+ valid_to           date,
+
+ check (ends_at > starts_at),
+ check (valid_to is null or valid_to >= valid_from),
+ foreign key(office_id) references office(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS office_schedule_office_idx ON office_schedule(office_id, weekday);
+CREATE INDEX IF NOT EXISTS office_schedule_doctor_idx ON office_schedule(doctor_id, weekday);
+
+ALTER TABLE record ADD CONSTRAINT record_office_fk foreign key(office_id) references office(id) on delete set null;
+CREATE INDEX IF NOT EXISTS record_office_idx ON record(office_id);
+
+INSERT INTO hospital (id, name, address, phone, working_hours)
+VALUES ('1', 'Gorodskaya poliklinika 1', 'Roterta, dom 12', '+74950000000',
+        '[{"weekday":1,"opens":"08:00","closes":"20:00"},{"weekday":2,"opens":"08:00","closes":"20:00"},{"weekday":3,"opens":"08:00","closes":"20:00"},{"weekday":4,"opens":"08:00","closes":"20:00"},{"weekday":5,"opens":"08:00","closes":"20:00"},{"weekday":6,"opens":"09:00","closes":"15:00"}]');
+INSERT INTO department (id, hospital_id, name, specialization_id)
+VALUES ('1', '1', 'Oftalmologiya', '1');
+INSERT INTO department (id, hospital_id, name, specialization_id)
+VALUES ('2', '1', 'Hirurgiya', '2');
+INSERT INTO office (id, department_id, number, floor)
+VALUES ('1', '1', '201B', '2');
+INSERT INTO office (id, department_id, number, floor)
+VALUES ('2', '2', '305', '3');
+INSERT INTO office_schedule (office_id, doctor_id, weekday, starts_at, ends_at, valid_from)
+SELECT o.office_id, o.doctor_id, w.weekday, '09:00', '18:00', '2023-01-01'
+FROM (VALUES (1, 1), (2, 2)) AS o(office_id, doctor_id), generate_series(1, 5) AS w(weekday);
+SELECT setval('hospital_id_seq', (SELECT max(id) FROM hospital));
+SELECT setval('department_id_seq', (SELECT max(id) FROM department));
+SELECT setval('office_id_seq', (SELECT max(id) FROM office));
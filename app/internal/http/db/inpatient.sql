@@ -0,0 +1,66 @@
+DROP TABLE IF EXISTS bed_assignment;
+DROP TABLE IF EXISTS admission;
+DROP TABLE IF EXISTS bed;
+DROP TABLE IF EXISTS ward;
+
+CREATE TABLE IF NOT EXISTS ward(
+ id                 bigserial       primary key,
+ department_id      bigint          not null,
+ number             text            not null,
+ name               text,
+
+ unique(department_id, number),
+ foreign key(department_id) references department(id) on delete cascade
+);
+
+CREATE TABLE IF NOT EXISTS bed(
+ id                 bigserial       primary key,
+ ward_id            bigint          not null,
+ label              text            not null,
+
+ unique(ward_id, label),
+ foreign key(ward_id) references ward(id) on delete cascade
+);
+
+CREATE TABLE IF NOT EXISTS admission(
+ id                 bigserial       primary key,
+ patient_id         bigint          not null,
+ doctor_id          bigint          not null,
+ disease_id         bigint,
+ diagnosis          text            not null,
+ department_id      bigint          not null,
+ bed_id             bigint,
+ status             text            not null default 'admitted' check (status in ('admitted', 'discharged')),
+ admitted_at        timestamptz     not null default now(),
+ discharged_at      timestamptz,
+ discharge_summary  text,
+
+ foreign key(patient_id) references patients(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id),
+ foreign key(disease_id) references disease(id) on delete set null,
+ foreign key(department_id) references department(id),
+ foreign key(bed_id) references bed(id) on delete set null
+);
+CREATE UNIQUE INDEX IF NOT EXISTS admission_patient_idx ON admission(patient_id) WHERE status = 'admitted';
+CREATE UNIQUE INDEX IF NOT EXISTS admission_bed_idx ON admission(bed_id) WHERE status = 'admitted';
+CREATE INDEX IF NOT EXISTS admission_department_idx ON admission(department_id, status);
+
+CREATE TABLE IF NOT EXISTS bed_assignment(
+ id                 bigserial       primary key,
+ admission_id       bigint          not null,
+ bed_id             bigint          not null,
+ assigned_at        timestamptz     not null default now(),
+ released_at        timestamptz,
+
+ foreign key(admission_id) references admission(id) on delete cascade,
+ foreign key(bed_id) references bed(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS bed_assignment_admission_idx ON bed_assignment(admission_id);
+
+INSERT INTO ward (id, department_id, number, name) VALUES
+ (1, 1, '12', NULL),
+ (2, 2, '31', 'Palata intensivnoy terapii');
+INSERT INTO bed (ward_id, label) VALUES
+ (1, '12-A'), (1, '12-B'), (1, '12-C'), (1, '12-D'),
+ (2, '31-A'), (2, '31-B');
+SELECT setval('ward_id_seq', (SELECT max(id) FROM ward));
@@ -0,0 +1,36 @@
+DROP TABLE IF EXISTS insurance_policy;
+DROP TABLE IF EXISTS insurer;
+
+CREATE TABLE IF NOT EXISTS insurer(
+ id           bigserial       primary key,
+ name         text            not null,
+ region       text            not null,
+ created_at   timestamptz     not null default now(),
+
+ unique (name, region)
+);
+
+CREATE TABLE IF NOT EXISTS insurance_policy(
+ number       text            primary key check (number ~ '^[0-9]{16}$'),
+ insurer_id   bigint          not null,
+ policy_type  text            not null check (policy_type in ('electronic', 'paper', 'temporary')),
+ valid_from   date            not null,
+ valid_until  date,
+ created_at   timestamptz     not null default now(),
+
+ check (valid_until >= valid_from),
+ check (policy_type <> 'temporary' or valid_until is not null),
+ foreign key(insurer_id) references insurer(id)
+);
+CREATE INDEX IF NOT EXISTS insurance_policy_insurer_idx ON insurance_policy(insurer_id);
+
+INSERT INTO insurer (id, name, region)
+VALUES ('1', 'AO "SOGAZ-Med"', 'Moskva');
+INSERT INTO insurer (id, name, region)
+VALUES ('2', 'OOO "Kapital MS"', 'Moskva');
+INSERT INTO insurance_policy (number, insurer_id, policy_type, valid_from)
+VALUES ('2194589700000056', '1', 'electronic', '2021-01-01');
+INSERT INTO insurance_policy (number, insurer_id, policy_type, valid_from)
+VALUES ('2194589700000049', '2', 'paper', '2019-06-01');
+
+SELECT setval('insurer_id_seq', (SELECT max(id) FROM insurer));
@@ -0,0 +1,27 @@
+DROP TABLE IF EXISTS outbox_delivery;
+DROP TABLE IF EXISTS outbox_event;
+
+CREATE TABLE IF NOT EXISTS outbox_event(
+ id                 bigserial       primary key,
+ event_type         text            not null,
+ aggregate_type     text            not null,
+ aggregate_id       bigint          not null,
+ payload            jsonb           not null,
+ attempts           int             not null default 0,
+ next_attempt_at    timestamptz     not null default now(),
+ last_error         text,
+ dispatched_at      timestamptz,
+ created_at         timestamptz     not null default now()
+);
+CREATE INDEX IF NOT EXISTS outbox_event_due_idx ON outbox_event(next_attempt_at, id) WHERE dispatched_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_event_dispatched_idx ON outbox_event(dispatched_at) WHERE dispatched_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS outbox_event_aggregate_idx ON outbox_event(aggregate_type, aggregate_id);
+
+CREATE TABLE IF NOT EXISTS outbox_delivery(
+ event_id           bigint          not null,
+ subscriber         text            not null,
+ delivered_at       timestamptz     not null default now(),
+
+ primary key(event_id, subscriber),
+ foreign key(event_id) references outbox_event(id) on delete cascade
+);
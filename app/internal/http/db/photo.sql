@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS doctor_photo_variant;
+
+CREATE TABLE IF NOT EXISTS doctor_photo_variant(
+ id             bigserial       primary key,
+ attachment_id  bigint          not null,
+ size           text            not null check (size in ('small', 'medium', 'large')),
+ blob_key       char(64)        not null,
+ content_type   text            not null,
+ width          int4            not null,
+ height         int4            not null,
+ byte_size      bigint          not null,
+
+ unique(attachment_id, size),
+ foreign key(attachment_id) references attachment(id) on delete cascade
+);
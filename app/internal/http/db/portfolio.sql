@@ -0,0 +1,82 @@
+DROP TABLE IF EXISTS portfolio_education;
+DROP TABLE IF EXISTS portfolio_certificate;
+DROP TABLE IF EXISTS portfolio_award;
+DROP TABLE IF EXISTS portfolio_publication;
+DROP TABLE IF EXISTS portfolio_workplace;
+
+CREATE TABLE IF NOT EXISTS portfolio_education(
+ id             bigserial       primary key,
+ portfolio_id   bigint          not null,
+ institution    text            not null,
+ degree         text            not null,
+ start_year     int2            not null,
+ end_year       int2            check (end_year >= start_year),
+
+ foreign key(portfolio_id) references portfolio(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS portfolio_education_portfolio_idx ON portfolio_education(portfolio_id);
+
+CREATE TABLE IF NOT EXISTS portfolio_certificate(
+ id             bigserial       primary key,
+ portfolio_id   bigint          not null,
+ title          text            not null,
+ issuer         text            not null,
+ issued_at      date            not null,
+ expires_at     date            check (expires_at >= issued_at),
+
+ foreign key(portfolio_id) references portfolio(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS portfolio_certificate_portfolio_idx ON portfolio_certificate(portfolio_id);
+CREATE INDEX IF NOT EXISTS portfolio_certificate_expires_idx ON portfolio_certificate(expires_at);
+
+CREATE TABLE IF NOT EXISTS portfolio_award(
+ id             bigserial       primary key,
+ portfolio_id   bigint          not null,
+ title          text            not null,
+ year           int2            not null,
+
+ foreign key(portfolio_id) references portfolio(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS portfolio_award_portfolio_idx ON portfolio_award(portfolio_id);
+
+CREATE TABLE IF NOT EXISTS portfolio_publication(
+ id             bigserial       primary key,
+ portfolio_id   bigint          not null,
+ title          text            not null,
+ venue          text            not null,
+ year           int2            not null,
+ url            text,
+
+ foreign key(portfolio_id) references portfolio(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS portfolio_publication_portfolio_idx ON portfolio_publication(portfolio_id);
+
+CREATE TABLE IF NOT EXISTS portfolio_workplace(
+ id             bigserial       primary key,
+ portfolio_id   bigint          not null,
+ organization   text            not null,
+ position       text            not null,
+ start_date     date            not null,
+ end_date       date            check (end_date >= start_date),
+
+ foreign key(portfolio_id) references portfolio(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS portfolio_workplace_portfolio_idx ON portfolio_workplace(portfolio_id);
+
+INSERT INTO portfolio_education (portfolio_id, institution, degree, start_year, end_year)
+VALUES ('1','Institute of N. I. Pirogov','residency','1998','2004');
+INSERT INTO portfolio_education (portfolio_id, institution, degree, start_year, end_year)
+VALUES ('2','Institute of N. I. Pirogov','residency','2003','2009');
+
+INSERT INTO portfolio_award (portfolio_id, title, year)
+VALUES ('1','The best doctor of the hospital number 56','2019');
+
+INSERT INTO portfolio_certificate (portfolio_id, title, issuer, issued_at, expires_at)
+VALUES ('1','Ophthalmology','Ministry of Health','2021-03-01','2026-03-01');
+INSERT INTO portfolio_certificate (portfolio_id, title, issuer, issued_at, expires_at)
+VALUES ('2','Advanced training course of 4 categories','Ministry of Health','2022-11-15','2027-11-15');
+
+INSERT INTO portfolio_workplace (portfolio_id, organization, position, start_date)
+VALUES ('1','City hospital number 56','ophthalmologist','2004-09-01');
+INSERT INTO portfolio_workplace (portfolio_id, organization, position, start_date)
+VALUES ('2','City hospital number 56','surgeon','2009-09-01');
@@ -0,0 +1,36 @@
+DROP TABLE IF EXISTS reception_ticket;
+DROP TABLE IF EXISTS reception_counter;
+
+CREATE TABLE IF NOT EXISTS reception_counter(
+ hospital_address   text            not null,
+ queue_date         date            not null,
+ category           text            not null,
+ last_number        integer         not null,
+
+ primary key(hospital_address, queue_date, category)
+);
+
+CREATE TABLE IF NOT EXISTS reception_ticket(
+ id                 bigserial       primary key,
+ number             text            not null,
+ category           text            not null check (category in ('emergency', 'elderly', 'booked', 'general')),
+ priority           integer         not null,
+ hospital_address   text            not null,
+ queue_date         date            not null,
+ patient_id         bigint,
+ record_id          bigint,
+ doctor_id          bigint,
+ doctor_office      text,
+ status             text            not null default 'waiting' check (status in ('waiting', 'called', 'served', 'skipped')),
+ issued_at          timestamptz     not null default now(),
+ called_at          timestamptz,
+ finished_at        timestamptz,
+
+ unique(hospital_address, queue_date, number),
+ foreign key(patient_id) references patients(id) on delete set null,
+ foreign key(record_id) references record(id) on delete set null,
+ foreign key(doctor_id) references doctors(id) on delete set null
+);
+CREATE INDEX IF NOT EXISTS reception_ticket_queue_idx ON reception_ticket(hospital_address, queue_date, status, priority, issued_at);
+CREATE INDEX IF NOT EXISTS reception_ticket_doctor_idx ON reception_ticket(doctor_id, queue_date) WHERE status IN ('waiting', 'called');
+CREATE UNIQUE INDEX IF NOT EXISTS reception_ticket_record_idx ON reception_ticket(record_id) WHERE status IN ('waiting', 'called');
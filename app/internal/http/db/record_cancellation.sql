@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS record_cancellation;
+
+CREATE TABLE IF NOT EXISTS record_cancellation(
+ id                 bigint          primary key,
+ hospital_address   text            not null,
+ doctor_office      text            not null,
+ tagging            text            not null,
+ patients_id        bigint          not null,
+ doctor_id          bigint          not null,
+ specialization_id  bigint          not null,
+ time_record        timestamptz     not null,
+ sequence           int             not null default 0,
+ cancelled_at       timestamptz     not null default now(),
+
+ foreign key(patients_id) references patients(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade,
+ foreign key(specialization_id) references specialization(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS record_cancellation_patient_idx ON record_cancellation(patients_id, time_record);
+CREATE INDEX IF NOT EXISTS record_cancellation_doctor_idx ON record_cancellation(doctor_id, time_record);
@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS record_reschedule;
+
+CREATE TABLE IF NOT EXISTS record_reschedule(
+ id                  bigserial       primary key,
+ record_id           bigint          not null,
+ previous_doctor_id  bigint          not null,
+ previous_time       timestamptz     not null,
+ doctor_id           bigint          not null,
+ time_record         timestamptz     not null,
+ reason              text,
+ created_at          timestamptz     not null default now(),
+
+ foreign key(record_id) references record(id) on delete cascade,
+ foreign key(previous_doctor_id) references doctors(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS record_reschedule_record_idx ON record_reschedule(record_id, created_at);
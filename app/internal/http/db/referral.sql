@@ -0,0 +1,25 @@
+ALTER TABLE IF EXISTS record DROP CONSTRAINT IF EXISTS record_referral_fk;
+DROP TABLE IF EXISTS referral;
+
+CREATE TABLE IF NOT EXISTS referral(
+ id                 bigserial       primary key,
+ patient_id         bigint          not null,
+ from_doctor_id     bigint          not null,
+ specialization_id  bigint          not null,
+ reason             text            not null,
+ urgency            text            not null default 'routine' check (urgency in ('routine', 'urgent', 'emergency')),
+ valid_from         date            not null default current_date,
+ valid_until        date            not null,
+ status             text            not null default 'issued' check (status in ('issued', 'used', 'cancelled')),
+ created_at         timestamptz     not null default now(),
+ used_at            timestamptz,
+
+ check (valid_until >= valid_from),
+ foreign key(patient_id) references patients(id) on delete cascade,
+ foreign key(from_doctor_id) references doctors(id) on delete cascade,
+ foreign key(specialization_id) references specialization(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS referral_patient_idx ON referral(patient_id, created_at);
+
+ALTER TABLE record ADD CONSTRAINT record_referral_fk foreign key(referral_id) references referral(id) on delete set null;
+CREATE UNIQUE INDEX IF NOT EXISTS record_referral_idx ON record(referral_id) WHERE referral_id IS NOT NULL;
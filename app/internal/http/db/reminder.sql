@@ -0,0 +1,23 @@
+DROP TABLE IF EXISTS reminder;
+
+CREATE TABLE IF NOT EXISTS reminder(
+ id                 bigserial       primary key,
+ record_id          bigint          not null,
+ time_record        timestamptz     not null,
+ offset_minutes     int             not null check (offset_minutes > 0),
+ channel            text            not null check (channel in ('email', 'sms')),
+ recipient          text            not null,
+ send_at            timestamptz     not null,
+ status             text            not null default 'pending' check (status in ('pending', 'sending', 'sent', 'failed', 'skipped')),
+ attempts           int             not null default 0,
+ retry_at           timestamptz,
+ claimed_at         timestamptz,
+ last_error         text,
+ sent_at            timestamptz,
+ created_at         timestamptz     not null default now(),
+
+ unique (record_id, time_record, offset_minutes, channel),
+ foreign key(record_id) references record(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS reminder_due_idx ON reminder(coalesce(retry_at, send_at)) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS reminder_sending_idx ON reminder(claimed_at) WHERE status = 'sending';
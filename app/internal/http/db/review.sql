@@ -0,0 +1,26 @@
+DROP TABLE IF EXISTS review;
+
+CREATE TABLE IF NOT EXISTS review(
+ id             bigserial       primary key,
+ record_id      bigint          not null unique,
+ doctor_id      bigint          not null,
+ patient_id     bigint          not null,
+ rating         int2            not null check (rating between 1 and 5),
+ comment        text,
+ status         text            not null default 'approved' check (status in ('pending', 'approved', 'rejected')),
+ created_at     timestamptz     not null default now(),
+
+ foreign key(record_id) references record(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade,
+ foreign key(patient_id) references patients(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS review_doctor_idx ON review(doctor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS review_status_idx ON review(status);
+
+INSERT INTO review (record_id, doctor_id, patient_id, rating, comment)
+VALUES ('1','1','1','5','Vnimatelniy vrach');
+INSERT INTO review (record_id, doctor_id, patient_id, rating)
+VALUES ('2','2','2','4');
+
+UPDATE doctors d
+SET rating = coalesce((SELECT round(avg(r.rating), 1) FROM review r WHERE r.doctor_id = d.id), 0);
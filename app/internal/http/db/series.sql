@@ -0,0 +1,33 @@
+DROP TABLE IF EXISTS record_series_occurrence;
+DROP TABLE IF EXISTS record_series;
+
+CREATE TABLE IF NOT EXISTS record_series(
+ id                 bigserial       primary key,
+ patients_id        bigint          not null,
+ doctor_id          bigint          not null,
+ specialization_id  bigint          not null,
+ frequency          text            not null check (frequency in ('daily', 'weekly')),
+ interval           int             not null default 1 check (interval > 0),
+ weekdays           text[],
+ start_date         timestamptz     not null,
+ count              int             not null default 0,
+ until              timestamptz,
+ created_at         timestamptz     not null default now(),
+
+ foreign key(patients_id) references patients(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade,
+ foreign key(specialization_id) references specialization(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS record_series_patient_idx ON record_series(patients_id);
+
+CREATE TABLE IF NOT EXISTS record_series_occurrence(
+ series_id          bigint          not null,
+ number             int             not null,
+ record_id          bigint,
+ time_record        timestamptz     not null,
+
+ primary key(series_id, number),
+ foreign key(series_id) references record_series(id) on delete cascade,
+ foreign key(record_id) references record(id) on delete set null
+);
+CREATE UNIQUE INDEX IF NOT EXISTS record_series_occurrence_record_idx ON record_series_occurrence(record_id);
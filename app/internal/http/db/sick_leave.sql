@@ -0,0 +1,44 @@
+DROP TABLE IF EXISTS sick_leave_period;
+DROP TABLE IF EXISTS sick_leave;
+DROP TABLE IF EXISTS sick_leave_counter;
+
+CREATE TABLE IF NOT EXISTS sick_leave_counter(
+ year         int         primary key,
+ last_number  int         not null
+);
+
+CREATE TABLE IF NOT EXISTS sick_leave(
+ id           bigserial       primary key,
+ number       text            not null unique,
+ patient_id   bigint          not null,
+ doctor_id    bigint          not null,
+ record_id    bigint,
+ diagnosis    text            not null,
+ start_date   date            not null,
+ end_date     date            not null,
+ status       text            not null default 'open' check (status in ('open', 'closed')),
+ outcome      text            check (outcome in ('recovered', 'transferred', 'disability', 'other')),
+ return_date  date,
+ closed_at    timestamptz,
+ created_at   timestamptz     not null default now(),
+
+ check (end_date >= start_date),
+ foreign key(patient_id) references patients(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id),
+ foreign key(record_id) references record(id) on delete set null
+);
+CREATE INDEX IF NOT EXISTS sick_leave_doctor_idx ON sick_leave(doctor_id, status);
+
+CREATE TABLE IF NOT EXISTS sick_leave_period(
+ id              bigserial       primary key,
+ certificate_id  bigint          not null,
+ doctor_id       bigint          not null,
+ start_date      date            not null,
+ end_date        date            not null,
+ created_at      timestamptz     not null default now(),
+
+ check (end_date >= start_date),
+ foreign key(certificate_id) references sick_leave(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id)
+);
+CREATE INDEX IF NOT EXISTS sick_leave_period_certificate_idx ON sick_leave_period(certificate_id, start_date);
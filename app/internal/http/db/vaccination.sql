@@ -0,0 +1,58 @@
+DROP TABLE IF EXISTS vaccination_dose;
+DROP TABLE IF EXISTS immunization_schedule;
+DROP TABLE IF EXISTS vaccine;
+
+CREATE TABLE IF NOT EXISTS vaccine(
+ id             bigserial       primary key,
+ name           text            not null,
+ disease        text            not null,
+ manufacturer   text            not null
+);
+INSERT INTO vaccine (id, name, disease, manufacturer)
+VALUES ('1', 'Engerix-B', 'hepatitis B', 'GlaxoSmithKline');
+INSERT INTO vaccine (id, name, disease, manufacturer)
+VALUES ('2', 'Priorix', 'measles, rubella, mumps', 'GlaxoSmithKline');
+INSERT INTO vaccine (id, name, disease, manufacturer)
+VALUES ('3', 'ADS-M', 'diphtheria, tetanus', 'Microgen');
+SELECT setval('vaccine_id_seq', (SELECT max(id) FROM vaccine));
+
+CREATE TABLE IF NOT EXISTS immunization_schedule(
+ id                 bigserial   primary key,
+ vaccine_id         bigint      not null,
+ dose_number        int2        not null,
+ age_from_months    int2        not null,
+ age_to_months      int2        not null,
+
+ unique(vaccine_id, dose_number),
+ foreign key(vaccine_id) references vaccine(id) on delete cascade
+);
+INSERT INTO immunization_schedule (vaccine_id, dose_number, age_from_months, age_to_months)
+VALUES ('1','1','0','1');
+INSERT INTO immunization_schedule (vaccine_id, dose_number, age_from_months, age_to_months)
+VALUES ('1','2','1','2');
+INSERT INTO immunization_schedule (vaccine_id, dose_number, age_from_months, age_to_months)
+VALUES ('1','3','6','7');
+INSERT INTO immunization_schedule (vaccine_id, dose_number, age_from_months, age_to_months)
+VALUES ('2','1','12','15');
+INSERT INTO immunization_schedule (vaccine_id, dose_number, age_from_months, age_to_months)
+VALUES ('2','2','72','84');
+INSERT INTO immunization_schedule (vaccine_id, dose_number, age_from_months, age_to_months)
+VALUES ('3','1','216','228');
+
+CREATE TABLE IF NOT EXISTS vaccination_dose(
+ id                 bigserial       primary key,
+ patients_id        bigint          not null,
+ vaccine_id         bigint          not null,
+ dose_number        int2            not null,
+ batch_number       text            not null,
+ injection_site     text            not null,
+ doctor_id          bigint          not null,
+ administered_at    timestamptz     not null,
+
+ unique(patients_id, vaccine_id, dose_number),
+ foreign key(patients_id) references patients(id) on delete cascade,
+ foreign key(vaccine_id) references vaccine(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade
+);
+INSERT INTO vaccination_dose (patients_id, vaccine_id, dose_number, batch_number, injection_site, doctor_id, administered_at)
+VALUES ('1','1','1','EB-1187','left thigh','1','2002-03-14 10:00:00 UTC');
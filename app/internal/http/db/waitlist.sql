@@ -0,0 +1,42 @@
+DROP TABLE IF EXISTS waitlist_offer;
+DROP TABLE IF EXISTS waitlist_entry;
+
+CREATE TABLE IF NOT EXISTS waitlist_entry(
+ id                 bigserial       primary key,
+ patient_id         bigint          not null,
+ doctor_id          bigint,
+ specialization_id  bigint,
+ date_from          timestamptz     not null,
+ date_to            timestamptz     not null,
+ status             text            not null default 'waiting' check (status in ('waiting', 'offered', 'booked', 'cancelled')),
+ created_at         timestamptz     not null default now(),
+
+ check (doctor_id is not null or specialization_id is not null),
+ check (date_to > date_from),
+ foreign key(patient_id) references patients(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade,
+ foreign key(specialization_id) references specialization(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS waitlist_entry_queue_idx ON waitlist_entry(status, created_at);
+CREATE INDEX IF NOT EXISTS waitlist_entry_patient_idx ON waitlist_entry(patient_id);
+
+CREATE TABLE IF NOT EXISTS waitlist_offer(
+ id                 bigserial       primary key,
+ entry_id           bigint          not null,
+ patient_id         bigint          not null,
+ doctor_id          bigint          not null,
+ specialization_id  bigint          not null,
+ time_record        timestamptz     not null,
+ status             text            not null default 'pending' check (status in ('pending', 'accepted', 'declined', 'expired')),
+ expires_at         timestamptz     not null,
+ record_id          bigint,
+ created_at         timestamptz     not null default now(),
+
+ foreign key(entry_id) references waitlist_entry(id) on delete cascade,
+ foreign key(patient_id) references patients(id) on delete cascade,
+ foreign key(doctor_id) references doctors(id) on delete cascade,
+ foreign key(record_id) references record(id) on delete set null
+);
+CREATE INDEX IF NOT EXISTS waitlist_offer_doctor_idx ON waitlist_offer(doctor_id, time_record);
+CREATE INDEX IF NOT EXISTS waitlist_offer_pending_idx ON waitlist_offer(expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS waitlist_offer_patient_idx ON waitlist_offer(patient_id);
@@ -0,0 +1,45 @@
+DROP TABLE IF EXISTS webhook_attempt;
+DROP TABLE IF EXISTS webhook_delivery;
+DROP TABLE IF EXISTS webhook_subscription;
+
+CREATE TABLE IF NOT EXISTS webhook_subscription(
+ id                 bigserial       primary key,
+ url                text            not null,
+ event_types        text[]          not null default '{}',
+ secret             text            not null,
+ description        text,
+ active             boolean         not null default true,
+ created_at         timestamptz     not null default now()
+);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery(
+ id                 bigserial       primary key,
+ subscription_id    bigint          not null,
+ event_id           bigint          not null,
+ event_type         text            not null,
+ payload            jsonb           not null,
+ status             text            not null default 'pending' check (status in ('pending', 'delivered', 'dead')),
+ attempts           int             not null default 0,
+ next_attempt_at    timestamptz     not null default now(),
+ last_status_code   int,
+ last_error         text,
+ delivered_at       timestamptz,
+ created_at         timestamptz     not null default now(),
+
+ unique (subscription_id, event_id),
+ foreign key(subscription_id) references webhook_subscription(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS webhook_delivery_due_idx ON webhook_delivery(next_attempt_at, id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_delivery_subscription_idx ON webhook_delivery(subscription_id, status, id);
+
+CREATE TABLE IF NOT EXISTS webhook_attempt(
+ id                 bigserial       primary key,
+ delivery_id        bigint          not null,
+ status_code        int,
+ error              text,
+ duration_ms        int             not null,
+ created_at         timestamptz     not null default now(),
+
+ foreign key(delivery_id) references webhook_delivery(id) on delete cascade
+);
+CREATE INDEX IF NOT EXISTS webhook_attempt_delivery_idx ON webhook_attempt(delivery_id, id);
@@ -2,17 +2,41 @@ package server
 
 import (
 	"HospitalRecord/app/internal/config"
+	"HospitalRecord/app/internal/domain/attachment"
 	"HospitalRecord/app/internal/domain/auth"
+	"HospitalRecord/app/internal/domain/billing"
+	"HospitalRecord/app/internal/domain/calendar"
+	"HospitalRecord/app/internal/domain/claim"
 	"HospitalRecord/app/internal/domain/disease"
 	"HospitalRecord/app/internal/domain/doctor"
+	"HospitalRecord/app/internal/domain/document"
+	"HospitalRecord/app/internal/domain/facility"
+	"HospitalRecord/app/internal/domain/fhir"
+	"HospitalRecord/app/internal/domain/inpatient"
+	"HospitalRecord/app/internal/domain/insurance"
+	"HospitalRecord/app/internal/domain/outbox"
+	"HospitalRecord/app/internal/domain/photo"
 	"HospitalRecord/app/internal/domain/portfolio"
+	"HospitalRecord/app/internal/domain/readmodel"
+	"HospitalRecord/app/internal/domain/realtime"
+	"HospitalRecord/app/internal/domain/reception"
 	"HospitalRecord/app/internal/domain/record"
+	"HospitalRecord/app/internal/domain/referral"
+	"HospitalRecord/app/internal/domain/reminder"
+	"HospitalRecord/app/internal/domain/review"
+	"HospitalRecord/app/internal/domain/series"
+	"HospitalRecord/app/internal/domain/sickleave"
 	"HospitalRecord/app/internal/domain/specialization"
 	"HospitalRecord/app/internal/domain/user"
+	"HospitalRecord/app/internal/domain/vaccination"
+	"HospitalRecord/app/internal/domain/waitlist"
+	"HospitalRecord/app/internal/domain/webhook"
+	"HospitalRecord/app/pkg/blobstore"
 	"HospitalRecord/app/pkg/logger"
+	"HospitalRecord/app/pkg/notify"
 	"context"
 	"fmt"
-	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"time"
@@ -23,11 +47,15 @@ type Server struct {
 	logger  *logger.Logger
 	cfg     *config.Config
 	handler *httprouter.Router
+	ctx     context.Context
+	stop    context.CancelFunc
 }
 
 /// Функция для создания нового объекта типа Server но основе полученных данных из server, logger, cfg, handler \\\
 
 func NewServer(cfg *config.Config, handler *httprouter.Router, logger *logger.Logger) *Server {
+	/// Контекст фоновых задач отменяется при завершении работы сервера \\\
+	ctx, stop := context.WithCancel(context.Background())
 	return &Server{
 		server: &http.Server{
 			Handler:      handler,
@@ -38,13 +66,15 @@ func NewServer(cfg *config.Config, handler *httprouter.Router, logger *logger.Lo
 		logger:  logger,
 		cfg:     cfg,
 		handler: handler,
+		ctx:     ctx,
+		stop:    stop,
 	}
 }
 
 /// Функция инициализирующая хранище storage, сервисы services и обработчики handler \\\
 /// Запускает сервер и начинает обрабатывать входящие HTTP запросы \\\
 
-func (s *Server) Run(dbConn *pgx.Conn) error {
+func (s *Server) Run(dbConn *pgxpool.Pool) error {
 
 	reqTimeout := s.cfg.PostgreSQL.RequestTimeout
 
@@ -54,7 +84,12 @@ func (s *Server) Run(dbConn *pgx.Conn) error {
 	/// Тот же принцип работы для заболеваний, портфолио докторов, специализации докторов, докторов \\\
 	///	записей на прием, регистрацию и авторизацию пользователей \\\
 	userStorage := user.NewStorage(dbConn, reqTimeout)
-	userService := user.NewService(userStorage, *s.logger)
+
+	/// Реестр полисов ОМС проверяет полис при регистрации пациента и записи на прием \\\
+	insuranceStorage := insurance.NewStorage(dbConn, reqTimeout)
+	insuranceService := insurance.NewService(userStorage, insuranceStorage, s.cfg, *s.logger)
+
+	userService := user.NewService(userStorage, insuranceService, *s.logger)
 	userHandler := user.NewHandler(*s.logger, userService)
 	userHandler.Register(s.handler)
 	s.logger.Info("initialized user routes")
@@ -83,24 +118,189 @@ func (s *Server) Run(dbConn *pgx.Conn) error {
 	specializationHandler.Register(s.handler)
 	s.logger.Info("initialized specialization routes")
 
+	/// Справочник больниц создается до сервиса записей: запись на прием получает адрес и кабинет из расписания доктора \\\
+	adminOnly := auth.NewAdminMiddleware(s.cfg)
+	staffOnly := auth.NewStaffMiddleware(s.cfg)
+	authorize := auth.NewMiddleware(s.cfg)
+	facilityStorage := facility.NewStorage(dbConn, reqTimeout)
+	facilityService := facility.NewService(doctorStorage, facilityStorage, s.cfg, *s.logger)
+	facilityHandler := facility.NewHandler(*s.logger, facilityService, adminOnly)
+	facilityHandler.Register(s.handler)
+	s.logger.Info("initialized facility routes")
+
+	/// Направления создаются до сервиса записей: запись по некоторым специализациям ведется только по направлению \\\
+	referralStorage := referral.NewStorage(dbConn, reqTimeout)
+	referralService := referral.NewService(doctorStorage, userStorage, specializationStorage, referralStorage, s.cfg, *s.logger)
+
+	/// Лист ожидания создается до сервиса записей: отмена записи предлагает слот пациентам из листа ожидания \\\
+	/// Правила записи на прием лист ожидания получает после создания сервиса записей \\\
 	recordStorage := record.NewStorage(dbConn, reqTimeout)
-	recordService := record.NewService(doctorStorage, recordStorage, *s.logger)
+	waitlistStorage := waitlist.NewStorage(dbConn, reqTimeout)
+	waitlistService := waitlist.NewService(doctorStorage, recordStorage, facilityService, waitlistStorage, s.cfg, *s.logger)
+	recordService := record.NewService(doctorStorage, recordStorage, waitlistService, facilityService, referralService, insuranceService, s.cfg, *s.logger)
+	waitlistService.UseRecords(recordService)
 	recordHandler := record.NewHandler(*s.logger, recordService)
 	recordHandler.Register(s.handler)
 	s.logger.Info("initialized record routes")
 
+	seriesStorage := series.NewStorage(dbConn, reqTimeout)
+	seriesService := series.NewService(doctorStorage, recordService, waitlistService, seriesStorage, s.cfg, *s.logger)
+	seriesHandler := series.NewHandler(*s.logger, seriesService)
+	seriesHandler.Register(s.handler)
+	s.logger.Info("initialized series routes")
+
+	vaccinationStorage := vaccination.NewStorage(dbConn, reqTimeout)
+	vaccinationService := vaccination.NewService(userStorage, doctorStorage, vaccinationStorage, *s.logger)
+	vaccinationHandler := vaccination.NewHandler(*s.logger, vaccinationService, authorize, adminOnly, staffOnly)
+	vaccinationHandler.Register(s.handler)
+	s.logger.Info("initialized vaccination routes")
+
+	blobStore, err := blobstore.NewLocalStore(s.cfg.Attachments.Root)
+	if err != nil {
+		return err
+	}
+
+	attachmentStorage := attachment.NewStorage(dbConn, reqTimeout)
+	attachmentService := attachment.NewService(doctorStorage, userStorage, recordStorage, attachmentStorage, blobStore, s.cfg, *s.logger)
+	attachmentHandler := attachment.NewHandler(*s.logger, attachmentService, authorize, staffOnly)
+	attachmentHandler.Register(s.handler)
+	s.logger.Info("initialized attachment routes")
+
+	photoStorage := photo.NewStorage(dbConn, reqTimeout)
+	photoService := photo.NewService(doctorStorage, attachmentService, photoStorage, blobStore, s.cfg, *s.logger)
+	photoHandler := photo.NewHandler(*s.logger, photoService, staffOnly)
+	photoHandler.Register(s.handler)
+	s.logger.Info("initialized photo routes")
+
+	reviewStorage := review.NewStorage(dbConn, reqTimeout)
+	reviewService := review.NewService(recordStorage, reviewStorage, s.cfg, *s.logger)
+	reviewHandler := review.NewHandler(*s.logger, reviewService, authorize, staffOnly)
+	reviewHandler.Register(s.handler)
+	s.logger.Info("initialized review routes")
+
+	waitlistHandler := waitlist.NewHandler(*s.logger, waitlistService, authorize, staffOnly)
+	waitlistHandler.Register(s.handler)
+	s.logger.Info("initialized waitlist routes")
+
+	calendarStorage := calendar.NewStorage(dbConn, reqTimeout)
+	calendarService := calendar.NewService(doctorStorage, calendarStorage, s.cfg, *s.logger)
+	calendarHandler := calendar.NewHandler(*s.logger, calendarService, authorize, staffOnly)
+	calendarHandler.Register(s.handler)
+	s.logger.Info("initialized calendar routes")
+
+	/// Каналы напоминаний включаются заполненными секциями smtp и sms конфигурации \\\
+	notifiers := make([]notify.Notifier, 0, 2)
+	if s.cfg.SMTP.Host != "" {
+		notifiers = append(notifiers, notify.NewSMTPNotifier(s.cfg.SMTP.Host, s.cfg.SMTP.Port, s.cfg.SMTP.Username, s.cfg.SMTP.Password, s.cfg.SMTP.From))
+	}
+	if s.cfg.SMS.URL != "" {
+		notifiers = append(notifiers, notify.NewSMSNotifier(s.cfg.SMS.URL, s.cfg.SMS.APIKey, s.cfg.SMS.Sender, time.Duration(s.cfg.SMS.TimeoutSeconds)*time.Second))
+	}
+	reminderStorage := reminder.NewStorage(dbConn, reqTimeout)
+	reminderService := reminder.NewService(recordStorage, reminderStorage, notifiers, s.cfg, *s.logger)
+	reminderHandler := reminder.NewHandler(*s.logger, reminderService, staffOnly)
+	reminderHandler.Register(s.handler)
+	s.logger.Info("initialized reminder routes")
+
+	/// Доменные события пишутся хранилищами в outbox_event, диспетчер рассылает их подписчикам и внешним приемникам \\\
+	outboxStorage := outbox.NewStorage(dbConn, reqTimeout)
+	outboxService := outbox.NewService(outboxStorage, s.cfg, *s.logger)
+	for _, url := range s.cfg.Outbox.SinkURLs {
+		outboxService.Subscribe(outbox.NewHTTPSink(url, time.Duration(s.cfg.Outbox.SinkTimeout)*time.Second))
+	}
+
+	/// Webhook подписки партнеров получают события из outbox и управляются администратором \\\
+	webhookStorage := webhook.NewStorage(dbConn, reqTimeout)
+	webhookService := webhook.NewService(webhookStorage, s.cfg, *s.logger)
+	outboxService.Subscribe(webhookService)
+	webhookHandler := webhook.NewHandler(*s.logger, webhookService, adminOnly)
+	webhookHandler.Register(s.handler)
+	s.logger.Info("initialized webhook routes")
+
+	/// Потоки событий для сотрудников получают события из outbox и повторяют пропущенные по Last-Event-ID \\\
+	realtimeStorage := realtime.NewStorage(dbConn, reqTimeout)
+	realtimeService := realtime.NewService(realtimeStorage, s.cfg, *s.logger)
+	outboxService.Subscribe(realtimeService)
+	realtimeHandler := realtime.NewHandler(*s.logger, realtimeService, staffOnly)
+	realtimeHandler.Register(s.handler)
+	s.logger.Info("initialized realtime routes")
+
+	receptionStorage := reception.NewStorage(dbConn, reqTimeout)
+	receptionService := reception.NewService(doctorStorage, userStorage, recordStorage, receptionStorage, s.cfg, *s.logger)
+	receptionHandler := reception.NewHandler(*s.logger, receptionService, staffOnly)
+	receptionHandler.Register(s.handler)
+	s.logger.Info("initialized reception routes")
+
+	referralHandler := referral.NewHandler(*s.logger, referralService, authorize, staffOnly)
+	referralHandler.Register(s.handler)
+	s.logger.Info("initialized referral routes")
+
+	inpatientStorage := inpatient.NewStorage(dbConn, reqTimeout)
+	inpatientService := inpatient.NewService(doctorStorage, userStorage, diseaseStorage, inpatientStorage, *s.logger)
+	inpatientHandler := inpatient.NewHandler(*s.logger, inpatientService, adminOnly, staffOnly)
+	inpatientHandler.Register(s.handler)
+	s.logger.Info("initialized inpatient routes")
+
+	sickLeaveStorage := sickleave.NewStorage(dbConn, reqTimeout)
+	sickLeaveService := sickleave.NewService(doctorStorage, userStorage, recordStorage, sickLeaveStorage, s.cfg, *s.logger)
+	sickLeaveHandler := sickleave.NewHandler(*s.logger, sickLeaveService, staffOnly)
+	sickLeaveHandler.Register(s.handler)
+	s.logger.Info("initialized sick leave routes")
+
+	insuranceHandler := insurance.NewHandler(*s.logger, insuranceService, authorize, adminOnly, staffOnly)
+	insuranceHandler.Register(s.handler)
+	s.logger.Info("initialized insurance routes")
+
+	readModelStorage := readmodel.NewStorage(dbConn, reqTimeout)
+	readModelService := readmodel.NewService(readModelStorage, *s.logger)
+	readModelHandler := readmodel.NewHandler(*s.logger, readModelService, authorize, staffOnly)
+	readModelHandler.Register(s.handler)
+	s.logger.Info("initialized read model routes")
+
+	documentStorage := document.NewStorage(dbConn, reqTimeout)
+	documentService := document.NewService(readModelService, documentStorage, s.cfg, *s.logger)
+	documentHandler := document.NewHandler(*s.logger, documentService, authorize, staffOnly)
+	documentHandler.Register(s.handler)
+	s.logger.Info("initialized document routes")
+
+	/// Счета по записям выставляются из outbox, когда пациента приняли по талону \\\
+	billingStorage := billing.NewStorage(dbConn, reqTimeout)
+	billingService := billing.NewService(recordStorage, userStorage, billingStorage, *s.logger)
+	outboxService.Subscribe(billingService, outbox.TicketServed)
+	billingHandler := billing.NewHandler(*s.logger, billingService, authorize, adminOnly, staffOnly)
+	billingHandler.Register(s.handler)
+	s.logger.Info("initialized billing routes")
+
+	claimStorage := claim.NewStorage(dbConn, reqTimeout)
+	claimService := claim.NewService(claimStorage, s.cfg, *s.logger)
+	claimHandler := claim.NewHandler(*s.logger, claimService, adminOnly)
+	claimHandler.Register(s.handler)
+	s.logger.Info("initialized claim routes")
+
+	fhirStorage := fhir.NewStorage(dbConn, reqTimeout)
+	fhirService := fhir.NewService(fhirStorage, s.cfg, *s.logger)
+	fhirHandler := fhir.NewHandler(*s.logger, fhirService, staffOnly)
+	fhirHandler.Register(s.handler)
+	s.logger.Info("initialized fhir routes")
+
 	authStorage := user.NewStorage(dbConn, reqTimeout)
-	authService := auth.NewService(authStorage, *s.logger, s.cfg)
+	authService := auth.NewService(authStorage, insuranceService, *s.logger, s.cfg)
 	authHandler := auth.NewHandler(*s.logger, authService)
 	authHandler.Register(s.handler)
 	s.logger.Info("initialized auth routes")
 
+	go waitlistService.Run(s.ctx)
+	go reminderService.Run(s.ctx)
+	go outboxService.Run(s.ctx)
+	go webhookService.Run(s.ctx)
+
 	return s.server.ListenAndServe()
 }
 
 /// Метоод Shutdown структуры Server. Функция для завершения работы сервера \\\
 
 func (s *Server) Shutdown(ctx context.Context) error {
+	s.stop()
 	return s.server.Shutdown(ctx)
 }
 
@@ -0,0 +1,119 @@
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+/// Ошибки хранилища файлов \\\
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+	ErrInvalidKey   = errors.New("invalid blob key")
+)
+
+/// Ключ файла - это sha256 его содержимого в шестнадцатеричном виде \\\
+
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+/// Интерфейс BlobStore описывает хранилище файлов, адресуемых по содержимому \\\
+
+type BlobStore interface {
+	Put(r io.Reader) (key string, size int64, err error)
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+var _ BlobStore = &LocalStore{}
+
+/// Структура LocalStore хранит файлы на локальном диске в каталоге root \\\
+
+type LocalStore struct {
+	root string
+}
+
+/// Функция NewLocalStore создает каталог root при необходимости и возвращает новый экземпляр LocalStore \\\
+
+func NewLocalStore(root string) (BlobStore, error) {
+	if err := os.MkdirAll(root, 0750); err != nil {
+		return nil, fmt.Errorf("cannot create blob store directory: %v", err)
+	}
+	return &LocalStore{root: root}, nil
+}
+
+/// Функция Put сохраняет содержимое r и возвращает его ключ и размер \\\
+/// Одинаковые файлы хранятся на диске в единственном экземпляре \\\
+
+func (s *LocalStore) Put(r io.Reader) (string, int64, error) {
+	/// Запись во временный файл с одновременным подсчетом хэша \\\
+	tmp, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot create temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot write blob: %v", err)
+	}
+
+	key := hex.EncodeToString(hash.Sum(nil))
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return "", 0, fmt.Errorf("cannot create blob directory: %v", err)
+	}
+
+	/// Файл с таким содержимым уже сохранен \\\
+	if _, err := os.Stat(path); err == nil {
+		return key, size, nil
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, fmt.Errorf("cannot move blob into place: %v", err)
+	}
+	return key, size, nil
+}
+
+/// Функция Open открывает сохраненный файл по его ключу \\\
+
+func (s *LocalStore) Open(key string) (io.ReadCloser, error) {
+	if !keyPattern.MatchString(key) {
+		return nil, ErrInvalidKey
+	}
+	file, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrBlobNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+/// Функция Delete удаляет сохраненный файл по его ключу \\\
+
+func (s *LocalStore) Delete(key string) error {
+	if !keyPattern.MatchString(key) {
+		return ErrInvalidKey
+	}
+	err := os.Remove(s.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+/// Файлы раскладываются по подкаталогам по первым символам ключа, чтобы не держать все в одном каталоге \\\
+
+func (s *LocalStore) path(key string) string {
+	return filepath.Join(s.root, key[:2], key[2:4], key)
+}
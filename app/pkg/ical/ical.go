@@ -0,0 +1,132 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/// Статусы события по RFC 5545 \\\
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+/// Формат DATE-TIME в UTC и максимальная длина строки контента в октетах \\\
+
+const (
+	dateTimeFormat = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+/// Структура события календаря VEVENT \\\
+
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Status      string
+}
+
+/// Структура календаря VCALENDAR \\\
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+/// Функция Encode записывает календарь в w в формате RFC 5545 \\\
+/// Строки завершаются CRLF, длинные строки переносятся, текстовые значения экранируются \\\
+
+func Encode(w io.Writer, c *Calendar, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	e := &encoder{w: bw}
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", c.ProdID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME", escape(c.Name))
+	}
+	stamp := now.UTC().Format(dateTimeFormat)
+	for _, event := range c.Events {
+		e.line("BEGIN", "VEVENT")
+		e.line("UID", event.UID)
+		e.line("DTSTAMP", stamp)
+		e.line("SEQUENCE", strconv.Itoa(event.Sequence))
+		e.line("DTSTART", event.Start.UTC().Format(dateTimeFormat))
+		e.line("DTEND", event.End.UTC().Format(dateTimeFormat))
+		e.line("SUMMARY", escape(event.Summary))
+		if event.Description != "" {
+			e.line("DESCRIPTION", escape(event.Description))
+		}
+		if event.Location != "" {
+			e.line("LOCATION", escape(event.Location))
+		}
+		if event.Status != "" {
+			e.line("STATUS", event.Status)
+		}
+		e.line("END", "VEVENT")
+	}
+	e.line("END", "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return bw.Flush()
+}
+
+/// Структура encoder записывает строки контента и запоминает первую ошибку записи \\\
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+/// Функция line записывает строку name:value, перенося ее по 75 октетов без разрыва символов UTF-8 \\\
+
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	content := name + ":" + value
+	limit := maxLineOctets
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(content[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		content = content[cut:]
+		/// Продолжение строки начинается с пробела, который тоже учитывается в длине \\\
+		limit = maxLineOctets - 1
+	}
+	_, e.err = e.w.WriteString(content + "\r\n")
+}
+
+/// Функция escape экранирует значение типа TEXT: обратную косую черту, точку с запятой, запятую и переводы строк \\\
+
+func escape(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+/// Каналы доставки уведомлений \\\
+
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
+/// Ошибка адреса получателя, который канал не может использовать \\\
+
+var ErrInvalidRecipient = errors.New("invalid notification recipient")
+
+/// Структура уведомления: адрес получателя в формате канала, тема и текст \\\
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+/// Интерфейс Notifier описывает канал доставки уведомлений \\\
+
+type Notifier interface {
+	Channel() string
+	Send(ctx context.Context, msg Message) error
+}
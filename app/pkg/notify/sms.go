@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+/// Номер телефона получателя SMS: цифры с необязательным плюсом \\\
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{10,15}$`)
+
+/// Структура SMSNotifier отправляет уведомления через HTTP шлюз SMS \\\
+/// Шлюз принимает POST с JSON {"from","to","text"} и ключом в заголовке Authorization: Bearer \\\
+
+type SMSNotifier struct {
+	url    string
+	apiKey string
+	sender string
+	client *http.Client
+}
+
+/// Структура NewSMSNotifier возвращает новый экземпляр SMSNotifier инициализируя переданные в него аргументы \\\
+
+func NewSMSNotifier(url, apiKey, sender string, timeout time.Duration) *SMSNotifier {
+	return &SMSNotifier{
+		url:    url,
+		apiKey: apiKey,
+		sender: sender,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+/// Функция Channel возвращает канал sms \\\
+
+func (n *SMSNotifier) Channel() string {
+	return ChannelSMS
+}
+
+/// Функция Send отправляет SMS, ответ шлюза вне диапазона 2xx считается ошибкой \\\
+
+func (n *SMSNotifier) Send(ctx context.Context, msg Message) error {
+	if !phonePattern.MatchString(msg.To) {
+		return fmt.Errorf("%w: %q is not a phone number", ErrInvalidRecipient, msg.To)
+	}
+
+	body, err := json.Marshal(struct {
+		From string `json:"from,omitempty"`
+		To   string `json:"to"`
+		Text string `json:"text"`
+	}{n.sender, msg.To, msg.Body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sms gateway request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+n.apiKey)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call sms gateway: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sms gateway responded %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSMSNotifierSend(t *testing.T) {
+	type payload struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Text string `json:"text"`
+	}
+	received := make(chan payload, 1)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method %s", r.Method)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("unexpected Authorization header %q", got)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("unexpected Content-Type header %q", got)
+		}
+		var p payload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		received <- p
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer gateway.Close()
+
+	notifier := NewSMSNotifier(gateway.URL, "secret", "Clinic", 5*time.Second)
+	err := notifier.Send(context.Background(), Message{To: "+79001234567", Subject: "ignored", Body: "Прием завтра в 10:00"})
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	got := <-received
+	want := payload{From: "Clinic", To: "+79001234567", Text: "Прием завтра в 10:00"}
+	if got != want {
+		t.Errorf("gateway received %+v, want %+v", got, want)
+	}
+}
+
+func TestSMSNotifierSendGatewayError(t *testing.T) {
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "insufficient balance", http.StatusPaymentRequired)
+	}))
+	defer gateway.Close()
+
+	notifier := NewSMSNotifier(gateway.URL, "", "", 5*time.Second)
+	err := notifier.Send(context.Background(), Message{To: "79001234567", Body: "text"})
+	if err == nil || !strings.Contains(err.Error(), "402") || !strings.Contains(err.Error(), "insufficient balance") {
+		t.Fatalf("expected gateway error with status and detail, got %v", err)
+	}
+}
+
+func TestSMSNotifierSendInvalidRecipient(t *testing.T) {
+	called := false
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer gateway.Close()
+
+	notifier := NewSMSNotifier(gateway.URL, "", "", 5*time.Second)
+	err := notifier.Send(context.Background(), Message{To: "patient@example.com", Body: "text"})
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Fatalf("expected ErrInvalidRecipient, got %v", err)
+	}
+	if called {
+		t.Error("gateway must not be called for an invalid recipient")
+	}
+}
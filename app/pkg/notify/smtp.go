@@ -0,0 +1,112 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+/// Структура SMTPNotifier отправляет уведомления письмом через SMTP сервер \\\
+
+type SMTPNotifier struct {
+	addr     string
+	host     string
+	from     string
+	username string
+	password string
+}
+
+/// Структура NewSMTPNotifier возвращает новый экземпляр SMTPNotifier, без username письма отправляются без авторизации \\\
+
+func NewSMTPNotifier(host string, port int, username, password, from string) *SMTPNotifier {
+	return &SMTPNotifier{
+		addr:     net.JoinHostPort(host, fmt.Sprint(port)),
+		host:     host,
+		from:     from,
+		username: username,
+		password: password,
+	}
+}
+
+/// Функция Channel возвращает канал email \\\
+
+func (n *SMTPNotifier) Channel() string {
+	return ChannelEmail
+}
+
+/// Функция Send отправляет письмо, STARTTLS используется если сервер его поддерживает \\\
+
+func (n *SMTPNotifier) Send(ctx context.Context, msg Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecipient, err)
+	}
+
+	/// Соединение с сервером ограничивается контекстом \\\
+	dialer := net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %v", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %v", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(nil); err != nil {
+			return fmt.Errorf("failed to start tls: %v", err)
+		}
+	}
+	if n.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", n.username, n.password, n.host)); err != nil {
+			return fmt.Errorf("failed to authenticate on smtp server: %v", err)
+		}
+	}
+
+	/// Передача письма \\\
+	if err = client.Mail(n.from); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %v", err)
+	}
+	if err = client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %v", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %v", err)
+	}
+	if _, err = w.Write(n.compose(to.Address, msg)); err != nil {
+		return fmt.Errorf("failed to write email: %v", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return client.Quit()
+}
+
+/// Функция compose формирует письмо в кодировке UTF-8 \\\
+
+func (n *SMTPNotifier) compose(to string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + n.from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
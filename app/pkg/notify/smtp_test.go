@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+/// Структура smtpSession хранит то, что фейковый SMTP сервер получил от клиента \\\
+
+type smtpSession struct {
+	from string
+	rcpt []string
+	data string
+}
+
+/// Функция startSMTP запускает SMTP сервер в процессе теста, rejectRcpt отклоняет RCPT TO кодом 550 \\\
+/// Сервер обслуживает одно соединение и отправляет полученную сессию в канал после QUIT \\\
+
+func startSMTP(t *testing.T, rejectRcpt bool) (*SMTPNotifier, <-chan smtpSession) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		var s smtpSession
+		reply("220 localhost ESMTP test")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			switch verb := strings.ToUpper(strings.SplitN(cmd, " ", 2)[0]); verb {
+			case "EHLO":
+				reply("250-localhost")
+				reply("250 8BITMIME")
+			case "HELO":
+				reply("250 localhost")
+			case "MAIL":
+				s.from = cmd
+				reply("250 OK")
+			case "RCPT":
+				if rejectRcpt {
+					reply("550 no such user")
+					continue
+				}
+				s.rcpt = append(s.rcpt, cmd)
+				reply("250 OK")
+			case "DATA":
+				reply("354 end data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				s.data = data.String()
+				reply("250 OK queued")
+			case "QUIT":
+				reply("221 bye")
+				sessions <- s
+				return
+			default:
+				reply("502 command not implemented")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return NewSMTPNotifier(host, p, "", "", "clinic@example.com"), sessions
+}
+
+func TestSMTPNotifierSend(t *testing.T) {
+	notifier, sessions := startSMTP(t, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := notifier.Send(ctx, Message{
+		To:      "Иван Петров <patient@example.com>",
+		Subject: "Напоминание о приеме",
+		Body:    "Прием завтра в 10:00\nКабинет 201B",
+	})
+	if err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	s := <-sessions
+	if s.from != "MAIL FROM:<clinic@example.com> BODY=8BITMIME" {
+		t.Errorf("unexpected MAIL FROM: %q", s.from)
+	}
+	if len(s.rcpt) != 1 || s.rcpt[0] != "RCPT TO:<patient@example.com>" {
+		t.Errorf("unexpected RCPT TO: %q", s.rcpt)
+	}
+	for _, want := range []string{
+		"From: clinic@example.com\r\n",
+		"To: patient@example.com\r\n",
+		"Subject: =?utf-8?q?",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nПрием завтра в 10:00\r\nКабинет 201B\r\n",
+	} {
+		if !strings.Contains(s.data, want) {
+			t.Errorf("email does not contain %q:\n%s", want, s.data)
+		}
+	}
+}
+
+func TestSMTPNotifierSendRejectedRecipient(t *testing.T) {
+	notifier, _ := startSMTP(t, true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := notifier.Send(ctx, Message{To: "patient@example.com", Subject: "s", Body: "b"})
+	if err == nil || !strings.Contains(err.Error(), "RCPT TO") {
+		t.Fatalf("expected RCPT TO error, got %v", err)
+	}
+}
+
+func TestSMTPNotifierSendInvalidRecipient(t *testing.T) {
+	notifier := NewSMTPNotifier("127.0.0.1", 1, "", "", "clinic@example.com")
+
+	err := notifier.Send(context.Background(), Message{To: "not an address", Subject: "s", Body: "b"})
+	if !errors.Is(err, ErrInvalidRecipient) {
+		t.Fatalf("expected ErrInvalidRecipient, got %v", err)
+	}
+}
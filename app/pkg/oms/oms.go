@@ -0,0 +1,38 @@
+package oms
+
+/// Длина номера полиса ОМС единого образца (единого номера полиса) \\\
+
+const NumberLength = 16
+
+/// Функция Valid проверяет, что номер полиса состоит из 16 цифр и последняя цифра совпадает с контрольной \\\
+
+func Valid(number string) bool {
+	if len(number) != NumberLength {
+		return false
+	}
+	for i := 0; i < NumberLength; i++ {
+		if number[i] < '0' || number[i] > '9' {
+			return false
+		}
+	}
+	return int(number[NumberLength-1]-'0') == CheckDigit(number[:NumberLength-1])
+}
+
+/// Функция CheckDigit считает контрольную цифру по первым 15 цифрам номера \\\
+/// Цифры на нечетных местах удваиваются, складываются все цифры результата и цифры на четных местах, \\\
+/// контрольная цифра дополняет сумму до ближайшего кратного 10 \\\
+
+func CheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
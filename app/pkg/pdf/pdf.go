@@ -0,0 +1,369 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+/// Размеры страницы A4 в пунктах \\\
+
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+/// Метрики шрифта считаются в единицах 1/1000 кегля, как принято в PDF \\\
+
+var em = fixed.I(1000)
+
+/// Структура Document собирает PDF с TrueType шрифтами, текст пишется глифами шрифта, поэтому кириллица печатается без системных шрифтов \\\
+/// Координаты задаются в пунктах от левого верхнего угла страницы, y - базовая линия текста \\\
+/// Первая ошибка запоминается и возвращается из Write \\\
+
+type Document struct {
+	pages   []*bytes.Buffer
+	page    *bytes.Buffer
+	fonts   []*ttfFont
+	current *ttfFont
+	size    float64
+	err     error
+}
+
+/// Структура ttfFont встроенного шрифта и использованных в документе глифов \\\
+
+type ttfFont struct {
+	resource string
+	name     string
+	data     []byte
+	face     *sfnt.Font
+	buf      sfnt.Buffer
+	used     map[sfnt.GlyphIndex]rune
+	widths   map[sfnt.GlyphIndex]int
+}
+
+/// Функция New возвращает пустой документ \\\
+
+func New() *Document {
+	return &Document{}
+}
+
+/// Функция AddFont добавляет TrueType шрифт ttf под именем name \\\
+
+func (d *Document) AddFont(name string, ttf []byte) error {
+	face, err := sfnt.Parse(ttf)
+	if err != nil {
+		return fmt.Errorf("failed to parse font %s: %v", name, err)
+	}
+	f := &ttfFont{
+		resource: "F" + strconv.Itoa(len(d.fonts)+1),
+		name:     name,
+		data:     ttf,
+		face:     face,
+		used:     make(map[sfnt.GlyphIndex]rune),
+		widths:   make(map[sfnt.GlyphIndex]int),
+	}
+	d.fonts = append(d.fonts, f)
+	return nil
+}
+
+/// Функция SetFont выбирает шрифт name и кегль size для следующего текста \\\
+
+func (d *Document) SetFont(name string, size float64) {
+	for _, f := range d.fonts {
+		if f.name == name {
+			d.current, d.size = f, size
+			return
+		}
+	}
+	d.fail(fmt.Errorf("unknown font %s", name))
+}
+
+/// Функция AddPage начинает новую страницу \\\
+
+func (d *Document) AddPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+}
+
+/// Функция Text печатает строку s с левого края x на базовой линии y \\\
+
+func (d *Document) Text(x, y float64, s string) {
+	if d.page == nil || d.current == nil {
+		d.fail(fmt.Errorf("text requires a page and a font"))
+		return
+	}
+	var hex strings.Builder
+	for _, r := range s {
+		fmt.Fprintf(&hex, "%04X", uint16(d.current.glyph(r)))
+	}
+	fmt.Fprintf(d.page, "BT /%s %s Tf %s %s Td <%s> Tj ET\n",
+		d.current.resource, number(d.size), number(x), number(PageHeight-y), hex.String())
+}
+
+/// Функция TextWidth возвращает ширину строки s текущим шрифтом в пунктах \\\
+
+func (d *Document) TextWidth(s string) float64 {
+	if d.current == nil {
+		return 0
+	}
+	width := 0
+	for _, r := range s {
+		width += d.current.widths[d.current.glyph(r)]
+	}
+	return float64(width) * d.size / 1000
+}
+
+/// Функция Wrap разбивает текст на строки не шире width, переводы строк сохраняются \\\
+
+func (d *Document) Wrap(s string, width float64) []string {
+	lines := make([]string, 0)
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && d.TextWidth(candidate) > width {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+/// Функция Line проводит линию толщиной 0.5 пункта \\\
+
+func (d *Document) Line(x1, y1, x2, y2 float64) {
+	if d.page == nil {
+		d.fail(fmt.Errorf("line requires a page"))
+		return
+	}
+	fmt.Fprintf(d.page, "0.5 w %s %s m %s %s l S\n",
+		number(x1), number(PageHeight-y1), number(x2), number(PageHeight-y2))
+}
+
+/// Функция Write записывает документ в w \\\
+
+func (d *Document) Write(w io.Writer) error {
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.pages) == 0 {
+		return fmt.Errorf("document has no pages")
+	}
+	o := &objects{}
+	catalog, pages := o.reserve(), o.reserve()
+
+	/// Шрифты встраиваются целиком, ширины и ToUnicode пишутся только для использованных глифов \\\
+	fontRefs := make([]string, 0, len(d.fonts))
+	for _, f := range d.fonts {
+		ref, err := f.write(o)
+		if err != nil {
+			return err
+		}
+		fontRefs = append(fontRefs, fmt.Sprintf("/%s %d 0 R", f.resource, ref))
+	}
+	resources := fmt.Sprintf("<< /Font << %s >> >>", strings.Join(fontRefs, " "))
+
+	kids := make([]string, 0, len(d.pages))
+	for _, page := range d.pages {
+		contents, err := o.stream("", page.Bytes())
+		if err != nil {
+			return err
+		}
+		id := o.add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pages, number(PageWidth), number(PageHeight), resources, contents))
+		kids = append(kids, fmt.Sprintf("%d 0 R", id))
+	}
+	o.set(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	o.set(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+
+	_, err := w.Write(o.encode(catalog))
+	return err
+}
+
+/// Функция fail запоминает первую ошибку построения документа \\\
+
+func (d *Document) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+/// Функция glyph возвращает глиф символа r и запоминает его ширину, отсутствующий символ печатается пустым глифом \\\
+
+func (f *ttfFont) glyph(r rune) sfnt.GlyphIndex {
+	gid, err := f.face.GlyphIndex(&f.buf, r)
+	if err != nil {
+		gid = 0
+	}
+	if _, ok := f.widths[gid]; !ok {
+		advance, err := f.face.GlyphAdvance(&f.buf, gid, em, font.HintingNone)
+		if err != nil {
+			advance = 0
+		}
+		f.widths[gid] = advance.Round()
+	}
+	if gid != 0 {
+		f.used[gid] = r
+	}
+	return gid
+}
+
+/// Функция write записывает шрифт как Type0 с CIDFontType2 в кодировке Identity-H и возвращает номер объекта шрифта \\\
+
+func (f *ttfFont) write(o *objects) (int, error) {
+	baseName, err := f.face.Name(&f.buf, sfnt.NameIDPostScript)
+	if err != nil || baseName == "" {
+		baseName = f.name
+	}
+	metrics, err := f.face.Metrics(&f.buf, em, font.HintingNone)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read font metrics: %v", err)
+	}
+	bounds, err := f.face.Bounds(&f.buf, em, font.HintingNone)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read font bounds: %v", err)
+	}
+
+	file, err := o.stream(fmt.Sprintf("/Length1 %d", len(f.data)), f.data)
+	if err != nil {
+		return 0, err
+	}
+	/// В sfnt ось y направлена вниз, в PDF - вверх \\\
+	descriptor := o.add(fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		baseName, bounds.Min.X.Round(), -bounds.Max.Y.Round(), bounds.Max.X.Round(), -bounds.Min.Y.Round(),
+		metrics.Ascent.Round(), -metrics.Descent.Round(), metrics.CapHeight.Round(), file))
+
+	gids := make([]int, 0, len(f.widths))
+	for gid := range f.widths {
+		gids = append(gids, int(gid))
+	}
+	sort.Ints(gids)
+	var widths strings.Builder
+	for _, gid := range gids {
+		fmt.Fprintf(&widths, "%d [%d] ", gid, f.widths[sfnt.GlyphIndex(gid)])
+	}
+	cid := o.add(fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW 1000 /W [%s] /CIDToGIDMap /Identity >>",
+		baseName, descriptor, strings.TrimSpace(widths.String())))
+
+	toUnicode, err := o.stream("", f.toUnicode(gids))
+	if err != nil {
+		return 0, err
+	}
+	return o.add(fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		baseName, cid, toUnicode)), nil
+}
+
+/// Функция toUnicode строит CMap глифов в Unicode, чтобы текст из PDF копировался и искался \\\
+
+func (f *ttfFont) toUnicode(gids []int) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	mapped := make([]int, 0, len(gids))
+	for _, gid := range gids {
+		if _, ok := f.used[sfnt.GlyphIndex(gid)]; ok {
+			mapped = append(mapped, gid)
+		}
+	}
+	/// В одном блоке bfchar допускается не больше 100 записей \\\
+	for start := 0; start < len(mapped); start += 100 {
+		end := start + 100
+		if end > len(mapped) {
+			end = len(mapped)
+		}
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, gid := range mapped[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", gid)
+			for _, unit := range utf16.Encode([]rune{f.used[sfnt.GlyphIndex(gid)]}) {
+				fmt.Fprintf(&b, "%04X", unit)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
+
+/// Структура objects нумерует объекты PDF и собирает файл с таблицей перекрестных ссылок \\\
+
+type objects struct {
+	bodies []string
+}
+
+/// Функция reserve резервирует номер объекта, тело задается позже через set \\\
+
+func (o *objects) reserve() int {
+	o.bodies = append(o.bodies, "")
+	return len(o.bodies)
+}
+
+/// Функция set задает тело зарезервированного объекта \\\
+
+func (o *objects) set(id int, body string) {
+	o.bodies[id-1] = body
+}
+
+/// Функция add добавляет объект и возвращает его номер \\\
+
+func (o *objects) add(body string) int {
+	id := o.reserve()
+	o.set(id, body)
+	return id
+}
+
+/// Функция stream добавляет поток с данными data, сжатыми FlateDecode \\\
+
+func (o *objects) stream(dict string, data []byte) (int, error) {
+	var b bytes.Buffer
+	zw := zlib.NewWriter(&b)
+	if _, err := zw.Write(data); err != nil {
+		return 0, fmt.Errorf("failed to compress pdf stream: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		return 0, fmt.Errorf("failed to compress pdf stream: %v", err)
+	}
+	return o.add(fmt.Sprintf("<< /Length %d /Filter /FlateDecode %s >>\nstream\n%s\nendstream", b.Len(), dict, b.Bytes())), nil
+}
+
+/// Функция encode собирает файл PDF с корнем root \\\
+
+func (o *objects) encode(root int) []byte {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.7\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(o.bodies))
+	for i, body := range o.bodies {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(o.bodies)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(o.bodies)+1, root, xref)
+	return b.Bytes()
+}
+
+/// Функция number форматирует число для PDF без лишних нулей \\\
+
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}